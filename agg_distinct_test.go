@@ -0,0 +1,100 @@
+package gobi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAggCountDistinct_NullsAndMerge(t *testing.T) {
+	s := buildInt64Series("id", []int64{3, 1, 3, 0, 2, 1, 0}, []bool{true, true, true, false, true, true, true})
+	agg := AggCountDistinct()
+	// 0 appears once as a value and once under a null; only the value counts.
+	if v, err := agg.Aggregate(s, []int{0, 1, 2, 3, 4, 5, 6}); err != nil || v != int64(4) {
+		t.Fatalf("count_distinct = %v, %v; want 4", v, err)
+	}
+	if v, _ := agg.Aggregate(s, []int{3}); v != int64(0) {
+		t.Fatalf("all-null group = %v, want 0", v)
+	}
+
+	left, right := agg.Clone(), agg.Clone()
+	if err := left.Update(s, []int{0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := right.Update(s, []int{2, 4, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := left.Merge(right); err != nil {
+		t.Fatal(err)
+	}
+	if v := left.Finalize(); v != int64(3) {
+		t.Fatalf("merged = %v, want 3 ({1, 2, 3})", v)
+	}
+	if err := left.Merge(AggApproxNUnique()); err == nil {
+		t.Fatal("merge with a different aggregator: expected error")
+	}
+}
+
+func TestAggApproxNUnique_AccuracyAndMerge(t *testing.T) {
+	const n = 200_000
+	vals := make([]int64, n)
+	for i := range vals {
+		vals[i] = int64(i) * 7919
+	}
+	s := buildInt64Series("id", vals, nil)
+	rows := func(lo, hi int) []int {
+		out := make([]int, 0, hi-lo)
+		for i := lo; i < hi; i++ {
+			out = append(out, i)
+		}
+		return out
+	}
+
+	whole := AggApproxNUnique()
+	got, err := whole.Aggregate(s, rows(0, n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est := got.(int64); math.Abs(float64(est-n)) > 0.03*n {
+		t.Fatalf("estimate %d, want %d ± 3%%", est, n)
+	}
+
+	// Overlapping halves plus a small sparse peer: register-wise max
+	// makes the union sketch identical to one fed everything.
+	a, b, small := whole.Clone(), whole.Clone(), whole.Clone()
+	if err := a.Update(s, rows(0, 120_000)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Update(s, rows(80_000, n)); err != nil {
+		t.Fatal(err)
+	}
+	if err := small.Update(s, rows(0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	for _, peer := range []IncrementalAggregator{small, b} {
+		if err := a.Merge(peer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.Finalize() != got {
+		t.Fatalf("merged estimate %v, single-sketch estimate %v", a.Finalize(), got)
+	}
+
+	// A sparse sketch merged into an empty one stays exact, and
+	// promotes once the union outgrows the sparse limit.
+	x, y := whole.Clone(), whole.Clone()
+	if err := y.Update(s, rows(0, hllSparseMax)); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Merge(y); err != nil || x.Finalize() != int64(hllSparseMax) {
+		t.Fatalf("sparse merge = %v, %v; want %d", x.Finalize(), err, hllSparseMax)
+	}
+	if err := x.Update(s, rows(hllSparseMax, 3*hllSparseMax)); err != nil {
+		t.Fatal(err)
+	}
+	if x.(*hllAgg).registers == nil {
+		t.Fatal("sketch should be dense past hllSparseMax distinct values")
+	}
+	if est := x.Finalize().(int64); math.Abs(float64(est-3*hllSparseMax)) > 0.03*3*hllSparseMax {
+		t.Fatalf("dense estimate %d, want %d ± 3%%", est, 3*hllSparseMax)
+	}
+}
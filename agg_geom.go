@@ -0,0 +1,694 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/zoobst/gobi/geometry"
+)
+
+// -----------------------------------------------------------------------------
+// Geometry aggregators — per-group union, collect, extent, convex hull
+// and centroid over a WKB geometry column.
+//
+// Series.GeomDissolve and geometry.Dissolve reduce a whole series to
+// one geometry; these do the same reduction per group, which is what
+// GeoPandas' dissolve(by=...) is:
+//
+//	gb.Agg(gobi.Aggregation{Column: "geometry", Fn: gobi.AggGeomUnion(), Alias: "geometry"})
+//
+// Every one is an IncrementalAggregator with a real Merge, so they run
+// on the eager GroupBy, the aligned fast path and the parallel
+// streaming aggregate alike. Output is WKB in a GeometryField tagged
+// with the input column's CRS (via fieldAggregator), so the result is
+// still a geometry column downstream. Null geometries are skipped; a
+// group with none emits null.
+// -----------------------------------------------------------------------------
+
+// geomAgg carries what every geometry aggregator shares: the input CRS
+// (read from the column's field metadata on first Update) and the
+// Binary / GeometryField output shape.
+type geomAgg struct {
+	crs geometry.CRS
+}
+
+func (geomAgg) Type() arrow.DataType { return arrow.BinaryTypes.Binary }
+
+func (geomAgg) outputField(name string, input arrow.Field) arrow.Field {
+	return GeometryField(name, geometryCRSFromField(input))
+}
+
+// each parses every non-null geometry in col[rows], attaches the
+// column's CRS, and hands it to fn.
+func (a *geomAgg) each(col Series, rows []int, fn func(geometry.Geometry) error) error {
+	if !col.IsGeometry() {
+		return fmt.Errorf("%w: column %q", ErrNotGeometry, col.Name())
+	}
+	a.crs, _ = geometry.LookupCRS(geometryCRSFromField(col.field))
+	for _, row := range rows {
+		wkb, err := binaryAt(col, row)
+		if err != nil {
+			return err
+		}
+		if wkb == nil {
+			continue
+		}
+		g, err := geometry.ParseWKB(wkb)
+		if err != nil {
+			return err
+		}
+		if err := fn(attachCRS(g, a.crs)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeCRS adopts a peer's CRS when this side never saw a row.
+func (a *geomAgg) mergeCRS(o geomAgg) {
+	if a.crs.Zero() {
+		a.crs = o.crs
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Union
+// -----------------------------------------------------------------------------
+
+// AggGeomUnion returns an aggregator dissolving each group's polygons
+// into their union — geometry.Dissolve (Martinez-Rueda clipping) per
+// group. Output is a Polygon, or a MultiPolygon when the union is
+// disjoint. Inputs must be Polygon or MultiPolygon, and like
+// Series.GeomDissolve the column must be in a projected CRS (or have
+// none): geographic CRSes fail with geometry.ErrGeographicCRS —
+// reproject with GeomToCRS first.
+//
+// State is the group's union so far, kept as its disjoint component
+// polygons. Each Update dissolves the batch's rows into it in one
+// pass, and Merge dissolves two partial unions, so errors surface
+// from Update / Merge rather than Finalize.
+func AggGeomUnion() IncrementalAggregator {
+	return &geomUnionAgg{}
+}
+
+type geomUnionAgg struct {
+	geomAgg
+	parts []geometry.Polygon
+	seen  bool // at least one non-null row, even if its area was empty
+}
+
+func (a *geomUnionAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.parts, a.seen = nil, false
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomUnionAgg) Update(col Series, rows []int) error {
+	var batch []geometry.Geometry
+	err := a.each(col, rows, func(g geometry.Geometry) error {
+		switch g.(type) {
+		case geometry.Polygon, geometry.MultiPolygon:
+		default:
+			return fmt.Errorf("%s: %w: want Polygon or MultiPolygon, got %s",
+				a.Name(), ErrColumnTypeMismatch, g.Type())
+		}
+		batch = append(batch, g)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return a.absorb(batch)
+}
+
+// absorb dissolves geoms into the running union. The current parts go
+// in as separate inputs so Dissolve's bbox clustering only re-sweeps
+// the components the new geometries actually touch.
+func (a *geomUnionAgg) absorb(geoms []geometry.Geometry) error {
+	if len(geoms) == 0 {
+		return nil
+	}
+	a.seen = true
+	for _, p := range a.parts {
+		geoms = append(geoms, p)
+	}
+	u, err := geometry.Dissolve(geoms)
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Name(), err)
+	}
+	a.parts = a.parts[:0]
+	switch t := u.(type) {
+	case geometry.Polygon:
+		if len(t.Rings) > 0 {
+			a.parts = append(a.parts, t)
+		}
+	case geometry.MultiPolygon:
+		a.parts = append(a.parts, t.Polygons...)
+	}
+	return nil
+}
+
+func (a *geomUnionAgg) Finalize() any {
+	switch {
+	case !a.seen:
+		return nil
+	case len(a.parts) == 1:
+		return geometry.WKB(a.parts[0])
+	case len(a.parts) == 0:
+		return geometry.WKB(geometry.Polygon{CRSValue: a.crs})
+	}
+	return geometry.WKB(geometry.MultiPolygon{Polygons: a.parts, CRSValue: a.crs})
+}
+
+func (a *geomUnionAgg) Clone() IncrementalAggregator { return &geomUnionAgg{} }
+
+func (a *geomUnionAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomUnionAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.mergeCRS(o.geomAgg)
+	if !o.seen {
+		return nil
+	}
+	a.seen = true
+	peer := make([]geometry.Geometry, len(o.parts))
+	for i, p := range o.parts {
+		peer[i] = p
+	}
+	return a.absorb(peer)
+}
+
+func (a *geomUnionAgg) Name() string { return "union" }
+
+// -----------------------------------------------------------------------------
+// Collect
+// -----------------------------------------------------------------------------
+
+// AggGeomCollect returns an aggregator gathering each group's
+// geometries into one multi-geometry without any overlay, like
+// PostGIS ST_Collect: a MultiPoint when every input is a Point or
+// MultiPoint, a MultiLineString for lines, a MultiPolygon for
+// polygons (multi inputs are flattened into the result), and a
+// GeometryCollection of the inputs as-is when the group mixes
+// dimensions. Overlapping polygons stay overlapping — use
+// AggGeomUnion to dissolve them. The output is 3D only when every
+// input is.
+func AggGeomCollect() IncrementalAggregator {
+	return &geomCollectAgg{}
+}
+
+type geomCollectAgg struct {
+	geomAgg
+	geoms []geometry.Geometry
+}
+
+func (a *geomCollectAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.geoms = nil
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomCollectAgg) Update(col Series, rows []int) error {
+	return a.each(col, rows, func(g geometry.Geometry) error {
+		a.geoms = append(a.geoms, g)
+		return nil
+	})
+}
+
+func (a *geomCollectAgg) Finalize() any {
+	if len(a.geoms) == 0 {
+		return nil
+	}
+	hasZ := true
+	var pts []geometry.Point
+	var lines []geometry.LineString
+	var polys []geometry.Polygon
+	for _, g := range a.geoms {
+		hasZ = hasZ && g.Is3D()
+		switch t := g.(type) {
+		case geometry.Point:
+			pts = append(pts, t)
+		case geometry.MultiPoint:
+			pts = append(pts, t.Points...)
+		case geometry.LineString:
+			lines = append(lines, t)
+		case geometry.MultiLineString:
+			lines = append(lines, t.Lines...)
+		case geometry.Polygon:
+			polys = append(polys, t)
+		case geometry.MultiPolygon:
+			polys = append(polys, t.Polygons...)
+		}
+	}
+	var out geometry.Geometry
+	switch {
+	case hasCollection(a.geoms):
+		out = geometry.GeometryCollection{Geometries: a.geoms, CRSValue: a.crs, HasZ: hasZ}
+	case len(lines) == 0 && len(polys) == 0:
+		out = geometry.MultiPoint{Points: pts, CRSValue: a.crs, HasZ: hasZ}
+	case len(pts) == 0 && len(polys) == 0:
+		out = geometry.MultiLineString{Lines: lines, CRSValue: a.crs, HasZ: hasZ}
+	case len(pts) == 0 && len(lines) == 0:
+		out = geometry.MultiPolygon{Polygons: polys, CRSValue: a.crs, HasZ: hasZ}
+	default:
+		out = geometry.GeometryCollection{Geometries: a.geoms, CRSValue: a.crs, HasZ: hasZ}
+	}
+	return geometry.WKB(out)
+}
+
+// hasCollection reports whether any input is itself a
+// GeometryCollection, which forces a GeometryCollection output.
+func hasCollection(geoms []geometry.Geometry) bool {
+	for _, g := range geoms {
+		if g.Type() == geometry.TypeGeometryCollection {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *geomCollectAgg) Clone() IncrementalAggregator { return &geomCollectAgg{} }
+
+func (a *geomCollectAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomCollectAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.mergeCRS(o.geomAgg)
+	a.geoms = append(a.geoms, o.geoms...)
+	return nil
+}
+
+func (a *geomCollectAgg) Name() string { return "collect" }
+
+// -----------------------------------------------------------------------------
+// Extent
+// -----------------------------------------------------------------------------
+
+// AggGeomExtent returns an aggregator emitting each group's bounding
+// box as a 5-vertex envelope Polygon (geometry.Envelope of the group's
+// combined Bounds). State is a single Bounds, so it is the cheapest way
+// to get per-group spatial coverage.
+func AggGeomExtent() IncrementalAggregator {
+	return &geomExtentAgg{bounds: geometry.EmptyBounds()}
+}
+
+type geomExtentAgg struct {
+	geomAgg
+	bounds geometry.Bounds
+	seen   bool
+}
+
+func (a *geomExtentAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.bounds, a.seen = geometry.EmptyBounds(), false
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomExtentAgg) Update(col Series, rows []int) error {
+	return a.each(col, rows, func(g geometry.Geometry) error {
+		a.bounds = a.bounds.Union(g.Bounds())
+		a.seen = true
+		return nil
+	})
+}
+
+func (a *geomExtentAgg) Finalize() any {
+	if !a.seen {
+		return nil
+	}
+	corners := geometry.MultiPoint{CRSValue: a.crs, Points: []geometry.Point{
+		{X: a.bounds.MinX, Y: a.bounds.MinY}, {X: a.bounds.MaxX, Y: a.bounds.MaxY},
+	}}
+	return geometry.WKB(geometry.Envelope(corners))
+}
+
+func (a *geomExtentAgg) Clone() IncrementalAggregator {
+	return &geomExtentAgg{bounds: geometry.EmptyBounds()}
+}
+
+func (a *geomExtentAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomExtentAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.mergeCRS(o.geomAgg)
+	a.bounds = a.bounds.Union(o.bounds)
+	a.seen = a.seen || o.seen
+	return nil
+}
+
+func (a *geomExtentAgg) Name() string { return "extent" }
+
+// -----------------------------------------------------------------------------
+// Convex hull
+// -----------------------------------------------------------------------------
+
+// geomHullCompactAt is how many buffered vertices trigger an
+// intermediate hull. Only hull vertices can be on the final hull, so
+// compacting keeps a group's state near its hull size however many
+// rows it has.
+const geomHullCompactAt = 4096
+
+// AggGeomConvexHull returns an aggregator emitting the convex hull of
+// every vertex in each group as a Polygon — geometry.ConvexHull over
+// the group. As with Series.GeomConvexHull, a group with fewer than
+// three distinct vertices (or only collinear ones) yields an empty
+// Polygon.
+func AggGeomConvexHull() IncrementalAggregator {
+	return &geomHullAgg{}
+}
+
+type geomHullAgg struct {
+	geomAgg
+	pts  []geometry.Point
+	seen bool
+}
+
+func (a *geomHullAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.pts, a.seen = nil, false
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomHullAgg) Update(col Series, rows []int) error {
+	return a.each(col, rows, func(g geometry.Geometry) error {
+		a.seen = true
+		a.pts = append(a.pts, geomVertices(g)...)
+		if len(a.pts) >= geomHullCompactAt {
+			a.compact()
+		}
+		return nil
+	})
+}
+
+// compact replaces the buffer with its hull. A degenerate hull means
+// every buffered vertex is collinear (or identical), and its two
+// extreme vertices then carry the whole answer.
+func (a *geomHullAgg) compact() {
+	mp := geometry.NewMultiPoint(a.pts, a.crs)
+	if hull := geometry.ConvexHull(mp); len(hull.Rings) > 0 {
+		a.pts = append(a.pts[:0], hull.Rings[0]...)
+		return
+	}
+	a.pts = appendExtremes(nil, mp)
+}
+
+// appendExtremes appends the lexicographically smallest and largest
+// (x, y) vertices of mp — the endpoints of a collinear point set.
+func appendExtremes(dst []geometry.Point, mp geometry.MultiPoint) []geometry.Point {
+	if len(mp.Points) == 0 {
+		return dst
+	}
+	lo, hi := mp.Points[0], mp.Points[0]
+	for _, p := range mp.Points[1:] {
+		if p.X < lo.X || (p.X == lo.X && p.Y < lo.Y) {
+			lo = p
+		}
+		if p.X > hi.X || (p.X == hi.X && p.Y > hi.Y) {
+			hi = p
+		}
+	}
+	return append(dst, lo, hi)
+}
+
+func (a *geomHullAgg) Finalize() any {
+	if !a.seen {
+		return nil
+	}
+	return geometry.WKB(geometry.ConvexHull(geometry.NewMultiPoint(a.pts, a.crs)))
+}
+
+func (a *geomHullAgg) Clone() IncrementalAggregator { return &geomHullAgg{} }
+
+func (a *geomHullAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomHullAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.mergeCRS(o.geomAgg)
+	a.seen = a.seen || o.seen
+	a.pts = append(a.pts, o.pts...)
+	if len(a.pts) >= geomHullCompactAt {
+		a.compact()
+	}
+	return nil
+}
+
+func (a *geomHullAgg) Name() string { return "convex_hull" }
+
+// geomVertices flattens every vertex of g.
+func geomVertices(g geometry.Geometry) []geometry.Point {
+	switch t := g.(type) {
+	case geometry.Point:
+		return []geometry.Point{t}
+	case geometry.MultiPoint:
+		return t.Points
+	case geometry.LineString:
+		return t.Points
+	case geometry.MultiLineString:
+		var out []geometry.Point
+		for _, l := range t.Lines {
+			out = append(out, l.Points...)
+		}
+		return out
+	case geometry.Polygon:
+		var out []geometry.Point
+		for _, r := range t.Rings {
+			out = append(out, r...)
+		}
+		return out
+	case geometry.MultiPolygon:
+		var out []geometry.Point
+		for _, p := range t.Polygons {
+			for _, r := range p.Rings {
+				out = append(out, r...)
+			}
+		}
+		return out
+	case geometry.GeometryCollection:
+		var out []geometry.Point
+		for _, inner := range t.Geometries {
+			out = append(out, geomVertices(inner)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Centroid
+// -----------------------------------------------------------------------------
+
+// AggGeomCentroid returns an aggregator emitting the centroid of each
+// group's geometries as a Point, computed the way GEOS treats a
+// mixed collection: only the group's highest-dimension parts count.
+// Polygons are weighted by planar area, lines by planar length, and
+// points equally; a group with any non-empty polygon ignores its lines
+// and points, and one with lines ignores its points. Zero-area
+// polygons fall back to their boundary and zero-length lines to their
+// vertices, so a degenerate group still gets a centroid.
+//
+// The result equals the centroid of the group's union for
+// non-overlapping polygons; overlaps are counted once per input, as
+// they would be in a MultiPolygon. Like Polygon.Centroid the
+// arithmetic is planar in the CRS's coordinates. State is three
+// weighted sums, so Merge is exact.
+func AggGeomCentroid() IncrementalAggregator {
+	return &geomCentroidAgg{}
+}
+
+// centroidSum accumulates Σw·x, Σw·y and Σw for one dimension.
+type centroidSum struct {
+	x, y, w float64
+}
+
+func (s *centroidSum) add(x, y, w float64) {
+	s.x += x * w
+	s.y += y * w
+	s.w += w
+}
+
+type geomCentroidAgg struct {
+	geomAgg
+	dims [3]centroidSum // indexed by topological dimension
+}
+
+func (a *geomCentroidAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.dims = [3]centroidSum{}
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomCentroidAgg) Update(col Series, rows []int) error {
+	return a.each(col, rows, func(g geometry.Geometry) error {
+		a.add(g)
+		return nil
+	})
+}
+
+func (a *geomCentroidAgg) add(g geometry.Geometry) {
+	switch t := g.(type) {
+	case geometry.Point:
+		a.dims[0].add(t.X, t.Y, 1)
+	case geometry.MultiPoint:
+		for _, p := range t.Points {
+			a.dims[0].add(p.X, p.Y, 1)
+		}
+	case geometry.LineString:
+		a.addLine(t.Points)
+	case geometry.MultiLineString:
+		for _, l := range t.Lines {
+			a.addLine(l.Points)
+		}
+	case geometry.Polygon:
+		a.addPolygon(t)
+	case geometry.MultiPolygon:
+		for _, p := range t.Polygons {
+			a.addPolygon(p)
+		}
+	case geometry.GeometryCollection:
+		for _, inner := range t.Geometries {
+			a.add(inner)
+		}
+	}
+}
+
+// addLine adds each segment's midpoint weighted by its length.
+func (a *geomCentroidAgg) addLine(pts []geometry.Point) {
+	var total float64
+	for i := 1; i < len(pts); i++ {
+		l := math.Hypot(pts[i].X-pts[i-1].X, pts[i].Y-pts[i-1].Y)
+		a.dims[1].add((pts[i].X+pts[i-1].X)/2, (pts[i].Y+pts[i-1].Y)/2, l)
+		total += l
+	}
+	if total == 0 {
+		for _, p := range pts {
+			a.dims[0].add(p.X, p.Y, 1)
+		}
+	}
+}
+
+// addPolygon adds the shoelace centroid of every ring, holes with
+// negative weight.
+func (a *geomCentroidAgg) addPolygon(p geometry.Polygon) {
+	var sum centroidSum
+	for i, ring := range p.Rings {
+		cx, cy, area := ringCentroid(ring)
+		if i > 0 {
+			area = -area
+		}
+		sum.add(cx, cy, area)
+	}
+	if sum.w == 0 {
+		for _, ring := range p.Rings {
+			a.addLine(ring)
+		}
+		return
+	}
+	a.dims[2].x += sum.x
+	a.dims[2].y += sum.y
+	a.dims[2].w += sum.w
+}
+
+// ringCentroid returns a ring's shoelace centroid and unsigned area.
+func ringCentroid(ring []geometry.Point) (cx, cy, area float64) {
+	n := len(ring)
+	if n < 3 {
+		return 0, 0, 0
+	}
+	var twice float64
+	for i := range n {
+		p, q := ring[i], ring[(i+1)%n]
+		cross := p.X*q.Y - q.X*p.Y
+		twice += cross
+		cx += (p.X + q.X) * cross
+		cy += (p.Y + q.Y) * cross
+	}
+	if twice == 0 {
+		return 0, 0, 0
+	}
+	return cx / (3 * twice), cy / (3 * twice), math.Abs(twice) / 2
+}
+
+func (a *geomCentroidAgg) Finalize() any {
+	for d := 2; d >= 0; d-- {
+		if s := a.dims[d]; s.w > 0 {
+			return geometry.WKB(geometry.Point{X: s.x / s.w, Y: s.y / s.w, CRSValue: a.crs})
+		}
+	}
+	return nil
+}
+
+func (a *geomCentroidAgg) Clone() IncrementalAggregator { return &geomCentroidAgg{} }
+
+func (a *geomCentroidAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomCentroidAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.mergeCRS(o.geomAgg)
+	for d := range a.dims {
+		a.dims[d].x += o.dims[d].x
+		a.dims[d].y += o.dims[d].y
+		a.dims[d].w += o.dims[d].w
+	}
+	return nil
+}
+
+func (a *geomCentroidAgg) Name() string { return "centroid" }
+
+// -----------------------------------------------------------------------------
+// Dissolve
+// -----------------------------------------------------------------------------
+
+// Dissolve is GeoPandas' dissolve(by=...): group f by the key columns
+// and union each group's polygons with AggGeomUnion. The dissolved
+// column keeps its name and CRS; every other column keeps its group's
+// first value (AggFirst), as GeoPandas' default aggfunc does. Output
+// columns are the keys, the geometry, then the rest in input order,
+// with one row per group in GroupBy's sorted-key order.
+//
+// The geometry column is the first geometry column that isn't a key;
+// a frame without one returns ErrNotGeometry. At least one key is
+// required — Series.GeomDissolve covers the whole-frame case. Custom
+// per-column aggregations go through GroupBy directly.
+func (f *Frame) Dissolve(by ...string) (*Frame, error) {
+	isKey := make(map[string]bool, len(by))
+	for _, k := range by {
+		isKey[k] = true
+	}
+	geom := ""
+	for _, fld := range f.Schema().Fields() {
+		if !isKey[fld.Name] && isGeometryField(fld) {
+			geom = fld.Name
+			break
+		}
+	}
+	if geom == "" {
+		return nil, fmt.Errorf("gobi: Dissolve: %w: no geometry column outside the keys", ErrNotGeometry)
+	}
+	aggs := []Aggregation{{Column: geom, Fn: AggGeomUnion(), Alias: geom}}
+	for _, fld := range f.Schema().Fields() {
+		if !isKey[fld.Name] && fld.Name != geom {
+			aggs = append(aggs, Aggregation{Column: fld.Name, Kind: AggFirst, Alias: fld.Name})
+		}
+	}
+	gb, err := f.GroupBy(by...)
+	if err != nil {
+		return nil, err
+	}
+	return gb.Agg(aggs...)
+}
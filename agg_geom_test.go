@@ -0,0 +1,283 @@
+package gobi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// parcelFrame is (region, pop, geometry) in Pseudo-Mercator: region
+// "a" has two overlapping 10×10 squares and a disjoint one, "b" one
+// square plus a null geometry, and "c" only a null.
+func parcelFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		Region string `gobi:"region"`
+		Pop    int64  `gobi:"pop"`
+	}
+	df, err := FromStructs([]row{{"a", 1}, {"b", 2}, {"a", 3}, {"b", 4}, {"a", 5}, {"c", 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	geoms := geomSeries(t, "geometry", int32(geometry.PseudoMercator.EPSG), []geometry.Geometry{
+		projectedSquare(0, 0, 10),
+		projectedSquare(100, 100, 10),
+		projectedSquare(5, 0, 10),
+		nil,
+		projectedSquare(40, 0, 10),
+		nil,
+	})
+	df, err = df.WithColumn("geometry", geoms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+// geomsOf decodes a geometry column, nil for null rows.
+func geomsOf(t *testing.T, f *Frame, name string) []geometry.Geometry {
+	t.Helper()
+	s := mustColumn(t, f, name)
+	if !s.IsGeometry() || geometryCRSFromField(s.field) != geometry.PseudoMercator.EPSG {
+		t.Fatalf("%s: field %v is not a Pseudo-Mercator geometry field", name, s.field)
+	}
+	out := make([]geometry.Geometry, s.Len())
+	for i := range out {
+		wkb, err := binaryAt(s, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wkb == nil {
+			continue
+		}
+		if out[i], err = geometry.ParseWKB(wkb); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return out
+}
+
+func TestAggGeom_PerGroup(t *testing.T) {
+	gb, err := parcelFrame(t).GroupBy("region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := gb.Agg(
+		Aggregation{Column: "geometry", Fn: AggGeomUnion()},
+		Aggregation{Column: "geometry", Fn: AggGeomCollect()},
+		Aggregation{Column: "geometry", Fn: AggGeomExtent()},
+		Aggregation{Column: "geometry", Fn: AggGeomConvexHull()},
+		Aggregation{Column: "geometry", Fn: AggGeomCentroid()},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strValues(t, out, "region"); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Fatalf("regions = %v", got)
+	}
+
+	// a: [0,15]×[0,10] (150) ∪ [40,50]×[0,10] (100).
+	union := geomsOf(t, out, "geometry_union")
+	if mp, ok := union[0].(geometry.MultiPolygon); !ok || len(mp.Polygons) != 2 || polygonArea(t, mp) != 250 {
+		t.Fatalf("union a = %v", union[0])
+	}
+	if polygonArea(t, union[1]) != 100 || union[2] != nil {
+		t.Fatalf("union b, c = %v, %v", union[1], union[2])
+	}
+
+	collect := geomsOf(t, out, "geometry_collect")
+	if mp, ok := collect[0].(geometry.MultiPolygon); !ok || len(mp.Polygons) != 3 || polygonArea(t, mp) != 300 {
+		t.Fatalf("collect a = %v (overlap must be kept)", collect[0])
+	}
+
+	extent := geomsOf(t, out, "geometry_extent")
+	if b := extent[0].Bounds(); b != (geometry.Bounds{MinX: 0, MinY: 0, MaxX: 50, MaxY: 10}) || polygonArea(t, extent[0]) != 500 {
+		t.Fatalf("extent a = %v", extent[0])
+	}
+
+	if hull := geomsOf(t, out, "geometry_convex_hull"); polygonArea(t, hull[0]) != 500 || polygonArea(t, hull[1]) != 100 || hull[2] != nil {
+		t.Fatalf("hull = %v", hull)
+	}
+
+	// Area-weighted over the inputs: (100·5 + 100·10 + 100·45) / 300.
+	centroid := geomsOf(t, out, "geometry_centroid")
+	if p, ok := centroid[0].(geometry.Point); !ok || math.Abs(p.X-20) > 1e-9 || math.Abs(p.Y-5) > 1e-9 {
+		t.Fatalf("centroid a = %v", centroid[0])
+	}
+	if centroid[2] != nil {
+		t.Fatalf("all-null group centroid = %v", centroid[2])
+	}
+}
+
+func TestAggGeom_CollectAndCentroidMixedDimensions(t *testing.T) {
+	line := geometry.LineString{Points: []geometry.Point{{X: 0, Y: 0}, {X: 4, Y: 0}}}
+	pt := geometry.Point{X: 100, Y: 100}
+	s := geomSeries(t, "g", 0, []geometry.Geometry{line, pt, geometry.MultiPoint{Points: []geometry.Point{{X: 1, Y: 1}, {X: 3, Y: 3}}}})
+
+	collect := AggGeomCollect()
+	v, err := collect.Aggregate(s, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, _ := geometry.ParseWKB(v.([]byte)); g.Type() != geometry.TypeGeometryCollection {
+		t.Fatalf("line + point collect = %v", g)
+	}
+	v, _ = collect.Aggregate(s, []int{1, 2})
+	if g, _ := geometry.ParseWKB(v.([]byte)); g.Type() != geometry.TypeMultiPoint || len(g.(geometry.MultiPoint).Points) != 3 {
+		t.Fatalf("point + multipoint collect = %v", g)
+	}
+
+	// The line outranks the point: only its midpoint counts.
+	centroid := AggGeomCentroid()
+	v, _ = centroid.Aggregate(s, []int{0, 1})
+	if g, _ := geometry.ParseWKB(v.([]byte)); g.(geometry.Point).X != 2 || g.(geometry.Point).Y != 0 {
+		t.Fatalf("line + point centroid = %v", g)
+	}
+	v, _ = centroid.Aggregate(s, []int{1, 2})
+	if g, _ := geometry.ParseWKB(v.([]byte)); g.(geometry.Point).X != 104.0/3 {
+		t.Fatalf("point centroid = %v", g)
+	}
+}
+
+func TestAggGeom_Errors(t *testing.T) {
+	pts := geomSeries(t, "g", 0, []geometry.Geometry{geometry.Point{X: 1, Y: 1}})
+	if _, err := AggGeomUnion().Aggregate(pts, []int{0}); !errors.Is(err, ErrColumnTypeMismatch) {
+		t.Fatalf("union of points: err = %v", err)
+	}
+	geographic := geomSeries(t, "g", 4326, []geometry.Geometry{projectedSquare(0, 0, 1)})
+	if _, err := AggGeomUnion().Aggregate(geographic, []int{0}); !errors.Is(err, geometry.ErrGeographicCRS) {
+		t.Fatalf("union in EPSG:4326: err = %v", err)
+	}
+	if _, err := AggGeomExtent().Aggregate(buildInt64Series("x", []int64{1}, nil), []int{0}); !errors.Is(err, ErrNotGeometry) {
+		t.Fatalf("extent of Int64: err = %v", err)
+	}
+	if err := AggGeomUnion().Merge(AggGeomCollect()); err == nil {
+		t.Fatal("merge with a different aggregator: expected error")
+	}
+}
+
+// tileFrame is a 60×60 grid of unit squares in Pseudo-Mercator, keyed
+// by 4 shuffled groups — enough vertices per group that the hull
+// buffer compacts.
+func tileFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		G string `gobi:"g"`
+	}
+	r := rand.New(rand.NewPCG(16, 16))
+	var rows []row
+	var geoms []geometry.Geometry
+	for x := range 60 {
+		for y := range 60 {
+			rows = append(rows, row{fmt.Sprintf("g%d", r.IntN(4))})
+			geoms = append(geoms, projectedSquare(float64(x), float64(y), 1))
+		}
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	df, err = df.WithColumn("geometry", geomSeries(t, "geometry", int32(geometry.PseudoMercator.EPSG), geoms))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+// TestAggGeom_StreamingParallelAndEager runs the geometry aggregators
+// through the streaming executor at several worker counts and checks
+// the output field and every group's geometry against the eager path.
+func TestAggGeom_StreamingParallelAndEager(t *testing.T) {
+	df := tileFrame(t)
+	aggs := []Aggregation{
+		{Column: "geometry", Fn: AggGeomUnion(), Alias: "union"},
+		{Column: "geometry", Fn: AggGeomExtent(), Alias: "extent"},
+		{Column: "geometry", Fn: AggGeomConvexHull(), Alias: "hull"},
+		{Column: "geometry", Fn: AggGeomCentroid(), Alias: "centroid"},
+	}
+	gb, err := df.GroupBy("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager, err := gb.Agg(aggs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildAgg := func() *aggregateNode {
+		return df.Lazy().GroupBy("g").Agg(aggs...).Plan().(*aggregateNode)
+	}
+	if f := buildAgg().Schema().Field(1); !isGeometryField(f) || geometryCRSFromField(f) != geometry.PseudoMercator.EPSG {
+		t.Fatalf("plan field = %v", f)
+	}
+	for _, w := range []int{1, 2, 4} {
+		got := runAggWithWorkers(t, buildAgg(), w)
+		if !slices.Equal(strValues(t, got, "g"), strValues(t, eager, "g")) {
+			t.Fatalf("workers=%d: group order %v", w, strValues(t, got, "g"))
+		}
+		for _, c := range []string{"union", "extent", "hull"} {
+			want, have := geomsOf(t, eager, c), geomsOf(t, got, c)
+			for i := range want {
+				if math.Abs(polygonArea(t, want[i])-polygonArea(t, have[i])) > 1e-9 || want[i].Bounds() != have[i].Bounds() {
+					t.Fatalf("workers=%d %s group %d: %v, want %v", w, c, i, have[i], want[i])
+				}
+			}
+		}
+		want, have := geomsOf(t, eager, "centroid"), geomsOf(t, got, "centroid")
+		for i := range want {
+			p, q := want[i].(geometry.Point), have[i].(geometry.Point)
+			if math.Abs(p.X-q.X) > 1e-9 || math.Abs(p.Y-q.Y) > 1e-9 {
+				t.Fatalf("workers=%d centroid group %d: %v, want %v", w, i, q, p)
+			}
+		}
+	}
+
+	// Every tile of a group survives the union, and the union of all
+	// groups' unions is the whole 60×60 grid.
+	var total float64
+	var parts []geometry.Geometry
+	for _, g := range geomsOf(t, eager, "union") {
+		total += polygonArea(t, g)
+		parts = append(parts, g)
+	}
+	if total != 3600 {
+		t.Fatalf("sum of group union areas = %v, want 3600", total)
+	}
+	whole, err := geometry.Dissolve(parts)
+	if err != nil || polygonArea(t, whole) != 3600 {
+		t.Fatalf("grid dissolve = %v, %v", polygonArea(t, whole), err)
+	}
+	for _, h := range geomsOf(t, eager, "hull") {
+		if b := h.Bounds(); b.MaxX-b.MinX > 60 || b.MaxY-b.MinY > 60 || polygonArea(t, h) < 3000 {
+			t.Fatalf("hull %v", h)
+		}
+	}
+}
+
+func TestFrame_Dissolve(t *testing.T) {
+	out, err := parcelFrame(t).Dissolve("region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.Schema().Fields(); len(got) != 3 || got[0].Name != "region" || got[1].Name != "geometry" || got[2].Name != "pop" {
+		t.Fatalf("columns = %v", got)
+	}
+	geoms := geomsOf(t, out, "geometry")
+	if polygonArea(t, geoms[0]) != 250 || polygonArea(t, geoms[1]) != 100 || geoms[2] != nil {
+		t.Fatalf("dissolved = %v", geoms)
+	}
+	if pop, _ := mustColumn(t, out, "pop").Int64s(); !slices.Equal(pop, []int64{1, 2, 6}) {
+		t.Fatalf("pop (first) = %v", pop)
+	}
+
+	if _, err := parcelFrame(t).Dissolve("geometry"); !errors.Is(err, ErrNotGeometry) {
+		t.Fatalf("keyed on the only geometry column: err = %v", err)
+	}
+	if _, err := parcelFrame(t).Dissolve(); err == nil {
+		t.Fatal("no keys: expected error")
+	}
+}
@@ -0,0 +1,362 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// -----------------------------------------------------------------------------
+// Quantile aggregators — exact (buffered) and approximate (t-digest).
+//
+// Both are IncrementalAggregators, so they run on the eager GroupBy
+// path, the streaming / parallel aggregate executor, and — through
+// Expr.Quantile / Expr.ApproxQuantile — inside Over. Users pass them
+// as Aggregation.Fn:
+//
+//	Aggregation{Column: "latency_ms", Fn: gobi.AggQuantile(0.95, gobi.QuantileLinear)}
+//	Aggregation{Column: "latency_ms", Fn: gobi.AggApproxQuantile(0.99)}
+//
+// The exact form buffers every non-null value of the group (like
+// AggMedian); the t-digest keeps O(compression) centroids per group
+// whatever the group size, and two digests fed disjoint halves of a
+// group Merge into one that answers for the whole group.
+// -----------------------------------------------------------------------------
+
+// QuantileInterpolation picks how AggQuantile resolves a quantile that
+// falls between two order statistics. With the n sorted non-null
+// values indexed 0..n-1, the q-th quantile sits at position q·(n-1);
+// when that position is fractional:
+//
+//   - QuantileLinear interpolates between its two neighbours.
+//   - QuantileLower / QuantileHigher take the neighbour below / above.
+//   - QuantileNearest takes the closer neighbour, ties to the even
+//     index (numpy / polars "nearest").
+//   - QuantileMidpoint averages the two neighbours.
+//
+// Every mode but Linear and Midpoint returns an observed value.
+type QuantileInterpolation uint8
+
+const (
+	QuantileLinear QuantileInterpolation = iota
+	QuantileLower
+	QuantileHigher
+	QuantileNearest
+	QuantileMidpoint
+)
+
+func (m QuantileInterpolation) String() string {
+	switch m {
+	case QuantileLinear:
+		return "linear"
+	case QuantileLower:
+		return "lower"
+	case QuantileHigher:
+		return "higher"
+	case QuantileNearest:
+		return "nearest"
+	case QuantileMidpoint:
+		return "midpoint"
+	}
+	return "unknown"
+}
+
+// quantileName renders q as a percentile label: 0.95 → "p95",
+// 0.999 → "p99.9". Used for the default output column suffix.
+func quantileName(q float64) string {
+	return "p" + strconv.FormatFloat(q*100, 'g', 10, 64)
+}
+
+// checkQuantile validates a quantile argument. Constructors park the
+// error and report it from Aggregate / Update, so the fluent
+// Aggregation literal stays error-free.
+func checkQuantile(q float64) error {
+	if math.IsNaN(q) || q < 0 || q > 1 {
+		return fmt.Errorf("gobi: quantile %v not in [0, 1]", q)
+	}
+	return nil
+}
+
+// AggQuantile returns an exact quantile aggregator: the q-th quantile
+// (0 <= q <= 1) of each group's non-null numeric values under interp.
+// Output is Float64; empty groups emit null. NaN values are skipped.
+// On NaN-free input, q = 0.5 with QuantileLinear matches AggMedian.
+//
+// Memory is proportional to group size — every value is buffered
+// until Finalize. For large groups where a small rank error is
+// acceptable, AggApproxQuantile runs in bounded memory.
+func AggQuantile(q float64, interp QuantileInterpolation) IncrementalAggregator {
+	return &quantileAgg{q: q, interp: interp, err: checkQuantile(q)}
+}
+
+type quantileAgg struct {
+	q      float64
+	interp QuantileInterpolation
+	err    error
+	vals   []float64
+}
+
+func (a *quantileAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.vals = a.vals[:0]
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *quantileAgg) Update(col Series, rows []int) error {
+	if a.err != nil {
+		return a.err
+	}
+	return appendNumericRows(col, rows, func(v float64) { a.vals = append(a.vals, v) })
+}
+
+// Finalize sorts the buffered values in place. That reorders state but
+// doesn't change it — a later Update or Merge still sees the same
+// multiset.
+func (a *quantileAgg) Finalize() any {
+	n := len(a.vals)
+	if n == 0 {
+		return nil
+	}
+	slices.Sort(a.vals)
+	pos := a.q * float64(n-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	switch a.interp {
+	case QuantileLower:
+		return a.vals[lo]
+	case QuantileHigher:
+		return a.vals[hi]
+	case QuantileNearest:
+		return a.vals[int(math.RoundToEven(pos))]
+	case QuantileMidpoint:
+		return (a.vals[lo] + a.vals[hi]) / 2
+	}
+	return a.vals[lo] + (a.vals[hi]-a.vals[lo])*(pos-float64(lo))
+}
+
+func (a *quantileAgg) Clone() IncrementalAggregator {
+	return &quantileAgg{q: a.q, interp: a.interp, err: a.err}
+}
+
+func (a *quantileAgg) Merge(other Aggregator) error {
+	o, ok := other.(*quantileAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.vals = append(a.vals, o.vals...)
+	return nil
+}
+
+func (a *quantileAgg) SizeBytes() int64 { return 8 * int64(cap(a.vals)) }
+
+func (a *quantileAgg) Type() arrow.DataType { return arrow.PrimitiveTypes.Float64 }
+
+// Name is the percentile label ("p95"), with the interpolation
+// appended when it isn't the default ("p95_nearest").
+func (a *quantileAgg) Name() string {
+	if a.interp == QuantileLinear {
+		return quantileName(a.q)
+	}
+	return quantileName(a.q) + "_" + a.interp.String()
+}
+
+// appendNumericRows feeds every non-null, non-NaN numeric value of
+// col[rows] to add.
+func appendNumericRows(col Series, rows []int, add func(float64)) error {
+	if a, arr, ok := col.singleF64(); ok {
+		for _, r := range rows {
+			if !arr.IsNull(r) && !math.IsNaN(a[r]) {
+				add(a[r])
+			}
+		}
+		return nil
+	}
+	for _, r := range rows {
+		v, ok, err := col.numericAt(r)
+		if err != nil {
+			return err
+		}
+		if ok && !math.IsNaN(v) {
+			add(v)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// t-digest
+// -----------------------------------------------------------------------------
+
+// tdigestCompression bounds the centroid count: a merged digest keeps
+// at most ~compression centroids. 100 is the reference
+// implementation's default, giving well under 1% rank error in the
+// body of the distribution and much better at the tails.
+const tdigestCompression = 100
+
+// AggApproxQuantile returns an approximate quantile aggregator backed
+// by a merging t-digest (Dunning & Ertl). Each group keeps at most a
+// few hundred centroids, so memory is bounded per group regardless of
+// its size, and Merge combines two digests without revisiting the
+// input. Accuracy is best at the tails — exactly where p95 / p99
+// latency-style queries look. Output is Float64; empty groups emit
+// null; NaN values are skipped.
+func AggApproxQuantile(q float64) IncrementalAggregator {
+	return &tdigestAgg{q: q, err: checkQuantile(q), min: math.Inf(1), max: math.Inf(-1)}
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+// tdigestAgg buffers incoming points unmerged and folds them into the
+// sorted centroid list once the buffer fills — the "merging digest"
+// variant, which needs no tree and amortizes the sort.
+type tdigestAgg struct {
+	q         float64
+	err       error
+	centroids []centroid // sorted by mean after compress
+	pending   []centroid // unmerged points and peer centroids
+	total     float64    // weight of centroids + pending
+	min, max  float64
+}
+
+func (a *tdigestAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.centroids, a.pending = a.centroids[:0], a.pending[:0]
+	a.total, a.min, a.max = 0, math.Inf(1), math.Inf(-1)
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *tdigestAgg) Update(col Series, rows []int) error {
+	if a.err != nil {
+		return a.err
+	}
+	return appendNumericRows(col, rows, a.add)
+}
+
+func (a *tdigestAgg) add(v float64) {
+	a.pending = append(a.pending, centroid{mean: v, weight: 1})
+	a.total++
+	a.min = min(a.min, v)
+	a.max = max(a.max, v)
+	if len(a.pending) >= 5*tdigestCompression {
+		a.compress()
+	}
+}
+
+// tdigestK is the k1 scale function: centroids near q = 0 and q = 1
+// are kept small, those near the median may grow large.
+func tdigestK(q float64) float64 {
+	return tdigestCompression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func tdigestKInv(k float64) float64 {
+	return (math.Sin(k*2*math.Pi/tdigestCompression) + 1) / 2
+}
+
+// compress merges pending into centroids: one sort, then a greedy
+// left-to-right pass that grows the current centroid while its
+// quantile span stays within one unit of the scale function.
+func (a *tdigestAgg) compress() {
+	if len(a.pending) == 0 {
+		return
+	}
+	all := append(a.pending, a.centroids...)
+	slices.SortFunc(all, func(x, y centroid) int {
+		switch {
+		case x.mean < y.mean:
+			return -1
+		case x.mean > y.mean:
+			return 1
+		}
+		return 0
+	})
+	out := a.centroids[:0]
+	cur := all[0]
+	var before float64 // weight of every emitted centroid
+	limit := a.total * tdigestKInv(tdigestK(0)+1)
+	for _, c := range all[1:] {
+		if before+cur.weight+c.weight <= limit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		out = append(out, cur)
+		before += cur.weight
+		limit = a.total * tdigestKInv(tdigestK(before/a.total)+1)
+		cur = c
+	}
+	a.centroids = append(out, cur)
+	a.pending = all[:0]
+}
+
+// Finalize compresses pending points and interpolates between
+// centroid midpoints, anchored at the observed min and max.
+func (a *tdigestAgg) Finalize() any {
+	a.compress()
+	cs := a.centroids
+	if len(cs) == 0 {
+		return nil
+	}
+	switch {
+	case a.q == 0:
+		return a.min
+	case a.q == 1:
+		return a.max
+	case len(cs) == 1:
+		return cs[0].mean
+	}
+	target := a.q * a.total
+	var cum float64
+	for i, c := range cs {
+		mid := cum + c.weight/2
+		if target < mid {
+			if i == 0 {
+				return a.min + (c.mean-a.min)*target/mid
+			}
+			prev := cs[i-1]
+			prevMid := cum - prev.weight/2
+			return prev.mean + (c.mean-prev.mean)*(target-prevMid)/(mid-prevMid)
+		}
+		cum += c.weight
+	}
+	last := cs[len(cs)-1]
+	lastMid := a.total - last.weight/2
+	return last.mean + (a.max-last.mean)*(target-lastMid)/(a.total-lastMid)
+}
+
+func (a *tdigestAgg) Clone() IncrementalAggregator {
+	return &tdigestAgg{q: a.q, err: a.err, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// Merge folds a peer digest in by treating its centroids as weighted
+// pending points; the next compress re-balances them.
+func (a *tdigestAgg) Merge(other Aggregator) error {
+	o, ok := other.(*tdigestAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.pending = append(a.pending, o.centroids...)
+	a.pending = append(a.pending, o.pending...)
+	a.total += o.total
+	a.min = min(a.min, o.min)
+	a.max = max(a.max, o.max)
+	a.compress()
+	return nil
+}
+
+// SizeBytes counts both centroid slices; pending holds up to
+// 5·compression points between compressions.
+func (a *tdigestAgg) SizeBytes() int64 {
+	return 16 * int64(cap(a.centroids)+cap(a.pending))
+}
+
+func (a *tdigestAgg) Type() arrow.DataType { return arrow.PrimitiveTypes.Float64 }
+func (a *tdigestAgg) Name() string         { return "approx_" + quantileName(a.q) }
@@ -0,0 +1,267 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// quantileFrame is (g, v) with v = 1, 2, 3, 4, 10 in group "a" —
+// shuffled, plus a null and a NaN that must be skipped — and an
+// all-null group "b".
+func quantileFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		G string   `gobi:"g"`
+		V *float64 `gobi:"v"`
+	}
+	f := func(x float64) *float64 { return &x }
+	df, err := FromStructs([]row{
+		{"a", f(4)}, {"a", nil}, {"a", f(1)}, {"b", nil}, {"a", f(10)},
+		{"a", f(math.NaN())}, {"a", f(3)}, {"a", f(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+func TestAggQuantile_Interpolations(t *testing.T) {
+	df := quantileFrame(t)
+	gb, err := df.GroupBy("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sorted a = [1 2 3 4 10]. q=0.3 sits at position 1.2, q=0.625 at
+	// 2.5 (where nearest rounds to the even index 2).
+	cases := []struct {
+		q      float64
+		interp QuantileInterpolation
+		col    string
+		want   float64
+	}{
+		{0.3, QuantileLinear, "v_p30", 2.2},
+		{0.3, QuantileLower, "v_p30_lower", 2},
+		{0.3, QuantileHigher, "v_p30_higher", 3},
+		{0.3, QuantileNearest, "v_p30_nearest", 2},
+		{0.3, QuantileMidpoint, "v_p30_midpoint", 2.5},
+		{0.625, QuantileNearest, "v_p62.5_nearest", 3},
+		{0, QuantileLinear, "v_p0", 1},
+		{1, QuantileLinear, "v_p100", 10},
+	}
+	var aggs []Aggregation
+	for _, tc := range cases {
+		aggs = append(aggs, Aggregation{Column: "v", Fn: AggQuantile(tc.q, tc.interp)})
+	}
+	out, err := gb.Agg(aggs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted, err := out.SortBy(SortKey{Column: "g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range cases {
+		got := floatsOrNaN(t, sorted, tc.col)
+		if math.Abs(got[0]-tc.want) > 1e-12 || !math.IsNaN(got[1]) {
+			t.Errorf("%s = %v, want [%v null]", tc.col, got, tc.want)
+		}
+	}
+
+	if _, err := gb.Agg(Aggregation{Column: "v", Fn: AggQuantile(1.5, QuantileLinear)}); err == nil {
+		t.Fatal("q = 1.5: expected error")
+	}
+	if _, err := gb.Agg(Aggregation{Column: "g", Fn: AggApproxQuantile(0.5)}); err == nil {
+		t.Fatal("quantile of a String column: expected error")
+	}
+}
+
+// TestAggApproxQuantile_RankError feeds a shuffled 0..n-1 so a value
+// is its own rank, and checks the digest's rank error both for one
+// digest and for four merged partial digests.
+func TestAggApproxQuantile_RankError(t *testing.T) {
+	const n = 100_000
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = float64(i)
+	}
+	r := rand.New(rand.NewPCG(15, 15))
+	r.Shuffle(n, func(i, j int) { vals[i], vals[j] = vals[j], vals[i] })
+	s := buildFloat64Series("v", vals, nil)
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+
+	for _, q := range []float64{0.001, 0.01, 0.25, 0.5, 0.95, 0.99, 0.999} {
+		whole := AggApproxQuantile(q)
+		got, err := whole.Aggregate(s, all)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		merged := whole.Clone()
+		for part := range 4 {
+			peer := merged.Clone()
+			if err := peer.Update(s, all[part*n/4:(part+1)*n/4]); err != nil {
+				t.Fatal(err)
+			}
+			if err := merged.Merge(peer); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The k1 scale bounds a centroid's share of the data by its
+		// distance from the tails, so allow 0.5% rank error mid-body
+		// and a tenth of that at the extremes.
+		tol := 0.005 * n * math.Min(1, 20*q*(1-q)+0.1)
+		for name, v := range map[string]any{"single": got, "merged": merged.Finalize()} {
+			if diff := math.Abs(v.(float64) - q*(n-1)); diff > tol {
+				t.Errorf("%s q=%v: got %v, want %v ± %v", name, q, v, q*(n-1), tol)
+			}
+		}
+		if c := len(whole.(*tdigestAgg).centroids); c > 2*tdigestCompression {
+			t.Errorf("q=%v: %d centroids, want <= %d", q, c, 2*tdigestCompression)
+		}
+	}
+
+	if v, _ := AggApproxQuantile(0.5).Aggregate(s, nil); v != nil {
+		t.Fatalf("empty group = %v, want nil", v)
+	}
+}
+
+// sketchAggFrame is 20k rows over 7 groups; "id" repeats so the
+// distinct counts differ from the row counts. Groups g0 and g1 have at
+// most 1000 distinct ids and stay sparse in the HyperLogLog; the rest
+// go dense.
+func sketchAggFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		G  string  `gobi:"g"`
+		ID int64   `gobi:"id"`
+		V  float64 `gobi:"v"`
+	}
+	r := rand.New(rand.NewPCG(7, 7))
+	rows := make([]row, 20_000)
+	for i := range rows {
+		g := r.IntN(7)
+		rows[i] = row{G: fmt.Sprintf("g%d", g), ID: int64(r.IntN(500 * (g + 1))), V: r.ExpFloat64()}
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+// TestSketchAggs_StreamingParallelAndEager runs the new aggregators
+// through the streaming executor at several worker counts and checks
+// every result against the eager GroupBy.
+func TestSketchAggs_StreamingParallelAndEager(t *testing.T) {
+	df := sketchAggFrame(t)
+	aggs := []Aggregation{
+		{Column: "v", Fn: AggQuantile(0.9, QuantileLinear)},
+		{Column: "v", Fn: AggApproxQuantile(0.9)},
+		{Column: "id", Fn: AggCountDistinct()},
+		{Column: "id", Fn: AggApproxNUnique()},
+	}
+	cols := []string{"v_p90", "v_approx_p90", "id_count_distinct", "id_approx_n_unique"}
+	gb, err := df.GroupBy("g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager, err := gb.Agg(aggs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager, err = eager.SortBy(SortKey{Column: "g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildAgg := func() *aggregateNode {
+		return df.Lazy().GroupBy("g").Agg(aggs...).Plan().(*aggregateNode)
+	}
+	for _, w := range []int{1, 2, 4} {
+		got := runAggWithWorkers(t, buildAgg(), w)
+		if got.NumRows() != 7 {
+			t.Fatalf("workers=%d: %d groups", w, got.NumRows())
+		}
+		if !slices.Equal(strValues(t, got, "g"), strValues(t, eager, "g")) {
+			t.Fatalf("workers=%d: group order %v", w, strValues(t, got, "g"))
+		}
+		for _, c := range cols {
+			compareSeriesValues(t, fmt.Sprintf("workers=%d %s", w, c), mustColumn(t, got, c), mustColumn(t, eager, c))
+		}
+	}
+
+	exact := floatsOrNaN(t, eager, "v_p90")
+	approx := floatsOrNaN(t, eager, "v_approx_p90")
+	counts, _ := mustColumn(t, eager, "id_count_distinct").Int64s()
+	estimates, _ := mustColumn(t, eager, "id_approx_n_unique").Int64s()
+	for i := range exact {
+		if math.Abs(exact[i]-approx[i]) > 0.02*exact[i] {
+			t.Errorf("group %d: approx p90 %v, exact %v", i, approx[i], exact[i])
+		}
+		if !distinctEstimateOK(counts[i], estimates[i]) {
+			t.Errorf("group %d: approx n_unique %d, exact %d", i, estimates[i], counts[i])
+		}
+	}
+}
+
+func TestSketchAggs_OverExpr(t *testing.T) {
+	df := sketchAggFrame(t)
+	out := df
+	for _, c := range []struct {
+		name string
+		expr Expr
+	}{
+		{"median", Col("v").Median().Over("g")},
+		{"p50", Col("v").Quantile(0.5).Over("g")},
+		{"lower", Col("v").QuantileWith(0.5, QuantileLower).Over("g")},
+		{"approx", Col("v").ApproxQuantile(0.5).Over("g")},
+		{"nd", Col("id").CountDistinct().Over("g")},
+		{"and", Col("id").ApproxNUnique().Over("g")},
+	} {
+		var err error
+		if out, err = out.WithColumnExpr(c.name, c.expr); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+	}
+	median, p50 := floatsOrNaN(t, out, "median"), floatsOrNaN(t, out, "p50")
+	lower, approx := floatsOrNaN(t, out, "lower"), floatsOrNaN(t, out, "approx")
+	for i := range median {
+		if math.Abs(median[i]-p50[i]) > 1e-12 || lower[i] > p50[i] || math.Abs(approx[i]-p50[i]) > 0.05*p50[i] {
+			t.Fatalf("row %d: median %v p50 %v lower %v approx %v", i, median[i], p50[i], lower[i], approx[i])
+		}
+	}
+	nd, _ := mustColumn(t, out, "nd").Int64s()
+	and, _ := mustColumn(t, out, "and").Int64s()
+	for i := range nd {
+		if !distinctEstimateOK(nd[i], and[i]) {
+			t.Fatalf("row %d: approx n_unique %d, exact %d", i, and[i], nd[i])
+		}
+	}
+
+	if got := Col("v").Quantile(0.95).Over("g").String(); got != `p95(col("v")).over([g])` {
+		t.Fatalf("String = %s", got)
+	}
+	if _, err := df.WithColumnExpr("x", Col("v").Quantile(-0.1)); err == nil {
+		t.Fatal("q = -0.1: expected error")
+	}
+	// Sketches have no sliding form.
+	if _, err := df.WithColumnExpr("x", Col("v").Quantile(0.5).OverOrdered([]string{"g"}, SortKey{Column: "id"}).Rows(1, 0)); err == nil {
+		t.Fatal("framed quantile: expected error")
+	}
+}
+
+// distinctEstimateOK: a sparse sketch (<= hllSparseMax distinct) is
+// exact; a dense one must land within 3% (~4 standard errors).
+func distinctEstimateOK(exact, estimate int64) bool {
+	if exact <= hllSparseMax {
+		return exact == estimate
+	}
+	return math.Abs(float64(estimate-exact)) <= 0.03*float64(exact)
+}
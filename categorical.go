@@ -0,0 +1,248 @@
+package gobi
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// -----------------------------------------------------------------------------
+// Categorical (dictionary-encoded string) columns
+//
+// A categorical column is an Arrow Dictionary whose values are String:
+// each row stores a small integer code into a per-chunk dictionary of
+// distinct strings. For low-cardinality columns — country, status,
+// vehicle id, land-use class — that is a fraction of a String column's
+// memory, and anything that only needs equality can work on the codes:
+//
+//   - GroupBy / Agg and the streaming aggregate bucket rows by code and
+//     resolve each distinct category once per chunk, not once per row.
+//   - Join builds and probes its hash index per category.
+//   - Col(c).Eq(Lit("x")) / Ne look "x" up in the dictionary once per
+//     chunk and compare codes.
+//
+// Everything else that accepts a String column accepts a categorical
+// one — Str() functions, IsIn, SortBy (by value, or by code when the
+// type is Ordered), FillNull, Shift, Head / Take — reading through the
+// dictionary. Keys and values compare as strings, so a categorical
+// column joins, groups and compares against a plain String column
+// holding the same values.
+//
+// Build one with Cast(gobi.Categorical) (and back with
+// Cast(arrow.BinaryTypes.String)), csvio.ReadOptions.Categorical, or
+// by reading a Parquet file: dictionary columns written by gobi come
+// back dictionary-encoded, and parquetio.ReadOptions.Categorical asks
+// for it on files from other writers. The CSV, GeoJSON and GeoPackage
+// writers store each row's category as a plain string.
+// -----------------------------------------------------------------------------
+
+// Categorical is the default categorical type: Int32 codes into a
+// String dictionary. Cast to it to dictionary-encode a String column.
+// Any Dictionary type with String values is treated as categorical —
+// Int8 / Int16 codes, or Ordered dictionaries whose code order is the
+// sort order, can be built with arrow.DictionaryType directly and
+// passed to Cast the same way.
+var Categorical arrow.DataType = &arrow.DictionaryType{
+	IndexType: arrow.PrimitiveTypes.Int32,
+	ValueType: arrow.BinaryTypes.String,
+}
+
+// isCategoricalType reports whether dt is a Dictionary over String
+// values — the dictionary shape gobi reads through.
+func isCategoricalType(dt arrow.DataType) bool {
+	d, ok := dt.(*arrow.DictionaryType)
+	return ok && d.ValueType.ID() == arrow.STRING
+}
+
+// isStringOrCategorical reports whether dt is String or categorical —
+// the operand types string equality accepts.
+func isStringOrCategorical(dt arrow.DataType) bool {
+	return dt.ID() == arrow.STRING || isCategoricalType(dt)
+}
+
+// IsCategorical reports whether s is a dictionary-encoded String
+// column.
+func (s Series) IsCategorical() bool {
+	return s.col != nil && isCategoricalType(s.DataType())
+}
+
+// catChunk is one chunk of a categorical column with its dictionary
+// resolved to *array.String.
+type catChunk struct {
+	*array.Dictionary
+	dict *array.String
+}
+
+// asCatChunk views arr as a categorical chunk; ok is false for any
+// other array.
+func asCatChunk(arr arrow.Array) (catChunk, bool) {
+	d, ok := arr.(*array.Dictionary)
+	if !ok {
+		return catChunk{}, false
+	}
+	dict, ok := d.Dictionary().(*array.String)
+	if !ok {
+		return catChunk{}, false
+	}
+	return catChunk{Dictionary: d, dict: dict}, true
+}
+
+// value is row i's category. The caller checks IsNull first.
+func (c catChunk) value(i int) string { return c.dict.Value(c.GetValueIndex(i)) }
+
+// lookup is value's code in the chunk's dictionary, or -1.
+func (c catChunk) lookup(value string) int {
+	for code := range c.dict.Len() {
+		if c.dict.IsValid(code) && c.dict.Value(code) == value {
+			return code
+		}
+	}
+	return -1
+}
+
+// ranks maps each dictionary code to its sort position: code order
+// for an Ordered type, otherwise the order of the values themselves.
+func (c catChunk) ranks() []int {
+	n := c.dict.Len()
+	out := make([]int, n)
+	if c.DataType().(*arrow.DictionaryType).Ordered {
+		for code := range out {
+			out[code] = code
+		}
+		return out
+	}
+	codes := make([]int, n)
+	for code := range codes {
+		codes[code] = code
+	}
+	slices.SortStableFunc(codes, func(a, b int) int {
+		switch va, vb := c.dict.Value(a), c.dict.Value(b); {
+		case va < vb:
+			return -1
+		case va > vb:
+			return 1
+		}
+		return 0
+	})
+	for rank, code := range codes {
+		out[code] = rank
+	}
+	return out
+}
+
+// takeDictionaryCodes gathers a's rows at indexes (-1 for null) by
+// code alone: the output shares a's dictionary, so a take, sort or
+// join over a single-chunk categorical column never touches a string.
+func takeDictionaryCodes(pool memory.Allocator, a *array.Dictionary, indexes []int) arrow.Array {
+	b := array.NewDictionaryBuilderWithDict(pool, a.DataType().(*arrow.DictionaryType), a.Dictionary())
+	defer b.Release()
+	codes := make([]int, len(indexes))
+	valid := make([]bool, len(indexes))
+	for i, idx := range indexes {
+		if idx >= 0 && a.IsValid(idx) {
+			codes[i], valid[i] = a.GetValueIndex(idx), true
+		}
+	}
+	b.AppendIndices(codes, valid)
+	return b.NewArray()
+}
+
+// encodeCategorical dictionary-encodes a String, LargeString or
+// categorical s as dt. The result is one chunk with one dictionary,
+// categories in order of first appearance (or dt's code order, for an
+// Ordered type re-encoded from a categorical with the same values).
+func encodeCategorical(s Series, dt *arrow.DictionaryType) (Series, error) {
+	switch dt.IndexType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+	default:
+		return Series{}, fmt.Errorf("%w: Cast: categorical index type %s is not an integer type",
+			ErrExprTypeMismatch, dt.IndexType)
+	}
+	id := s.DataType().ID()
+	if id != arrow.STRING && id != arrow.LARGE_STRING && !s.IsCategorical() {
+		return Series{}, fmt.Errorf("%w: Cast: %s to %s, want a String source",
+			ErrExprTypeMismatch, s.DataType(), dt)
+	}
+	b := array.NewDictionaryBuilder(memory.DefaultAllocator, dt).(*array.BinaryDictionaryBuilder)
+	defer b.Release()
+	for row := range s.Len() {
+		if err := appendPrimitiveAt(s, row, b); err != nil {
+			return Series{}, fmt.Errorf("gobi: Cast to %s: %w", dt, err)
+		}
+	}
+	f := s.field
+	f.Type = dt
+	return seriesFromBuilder(f, b), nil
+}
+
+// decodeCategorical expands a categorical s into target, a String or
+// LargeString type.
+func decodeCategorical(s Series, target arrow.DataType) (Series, error) {
+	b, err := builderForType(memory.DefaultAllocator, target)
+	if err != nil {
+		return Series{}, err
+	}
+	defer b.Release()
+	for row := range s.Len() {
+		if err := appendPrimitiveAt(s, row, b); err != nil {
+			return Series{}, fmt.Errorf("gobi: Cast %s to %s: %w", s.DataType(), target, err)
+		}
+	}
+	f := s.field
+	f.Type = target
+	return seriesFromBuilder(f, b), nil
+}
+
+// decodedForString returns s as a plain String column when it is
+// categorical, else s unchanged — the entry point for kernels that
+// only read *array.String.
+func decodedForString(s Series) (Series, error) {
+	if !s.IsCategorical() {
+		return s, nil
+	}
+	return decodeCategorical(s, arrow.BinaryTypes.String)
+}
+
+// appendStringTo appends v to any of the string-valued builders: a
+// String, LargeString or String-dictionary builder.
+func appendStringTo(b array.Builder, v string) error {
+	switch tb := b.(type) {
+	case *array.StringBuilder:
+		tb.Append(v)
+	case *array.LargeStringBuilder:
+		tb.Append(v)
+	case *array.BinaryDictionaryBuilder:
+		return tb.AppendString(v)
+	default:
+		return fmt.Errorf("%w: string value into %T", ErrColumnTypeMismatch, b)
+	}
+	return nil
+}
+
+// categoricalEqLiteral evaluates s == lit (s != lit when negate) over
+// a categorical s: one dictionary lookup per chunk, then an integer
+// compare per row. Null rows stay null.
+func categoricalEqLiteral(s Series, lit string, negate bool) (Series, error) {
+	b := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer b.Release()
+	b.Reserve(s.Len())
+	for _, chunk := range s.col.Data().Chunks() {
+		c, ok := asCatChunk(chunk)
+		if !ok {
+			return Series{}, fmt.Errorf("%w: categorical chunk %T", ErrColumnTypeMismatch, chunk)
+		}
+		code := c.lookup(lit)
+		for i := range c.Len() {
+			if c.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.UnsafeAppend((code >= 0 && c.GetValueIndex(i) == code) != negate)
+		}
+	}
+	return arrayToSeries(memory.DefaultAllocator, "", arrow.FixedWidthTypes.Boolean, b.NewArray())
+}
@@ -0,0 +1,325 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// catFrame is a two-chunk frame whose "city" column is categorical in
+// both chunks, with dictionaries numbering the cities differently, and
+// whose "city_str" column holds the same values as plain String.
+//
+//	city: oslo rome null oslo | rome lima oslo
+//	v:    1    2    3    4    | 5    6    7
+func catFrame(t *testing.T) *Frame {
+	t.Helper()
+	pool := memory.DefaultAllocator
+	dt := Categorical.(*arrow.DictionaryType)
+	chunk := func(vals ...string) (arrow.Array, arrow.Array) {
+		cb := array.NewDictionaryBuilder(pool, dt).(*array.BinaryDictionaryBuilder)
+		defer cb.Release()
+		sb := array.NewStringBuilder(pool)
+		defer sb.Release()
+		for _, v := range vals {
+			if v == "" {
+				cb.AppendNull()
+				sb.AppendNull()
+				continue
+			}
+			if err := cb.AppendString(v); err != nil {
+				t.Fatal(err)
+			}
+			sb.Append(v)
+		}
+		return cb.NewArray(), sb.NewArray()
+	}
+	c1, s1 := chunk("oslo", "rome", "", "oslo")
+	c2, s2 := chunk("rome", "lima", "oslo")
+	vb := array.NewInt64Builder(pool)
+	defer vb.Release()
+	vb.AppendValues([]int64{1, 2, 3, 4}, nil)
+	v1 := vb.NewArray()
+	vb.AppendValues([]int64{5, 6, 7}, nil)
+	v2 := vb.NewArray()
+
+	fields := []arrow.Field{
+		{Name: "city", Type: dt, Nullable: true},
+		{Name: "city_str", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "v", Type: arrow.PrimitiveTypes.Int64},
+	}
+	cols := make([]arrow.Column, len(fields))
+	for i, chunks := range [][]arrow.Array{{c1, c2}, {s1, s2}, {v1, v2}} {
+		chunked := arrow.NewChunked(fields[i].Type, chunks)
+		cols[i] = *arrow.NewColumn(fields[i], chunked)
+		chunked.Release()
+		for _, c := range chunks {
+			c.Release()
+		}
+	}
+	f, err := NewFrame(arrow.NewSchema(fields, nil), cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestCategorical_Cast(t *testing.T) {
+	f := catFrame(t)
+	enc, err := f.WithColumnExpr("enc", Col("city_str").Cast(Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := mustColumn(t, enc, "enc")
+	if !s.IsCategorical() || mustColumn(t, enc, "city_str").IsCategorical() {
+		t.Fatal("IsCategorical: want enc categorical, city_str not")
+	}
+	if got, want := valuesOrNull(t, enc, "enc"), valuesOrNull(t, f, "city_str"); !slices.Equal(got, want) {
+		t.Fatalf("Cast(Categorical) = %v, want %v", got, want)
+	}
+	if n := s.col.Data().Chunk(0).(*array.Dictionary).Dictionary().Len(); n != 3 {
+		t.Fatalf("dictionary has %d entries, want 3", n)
+	}
+
+	dec, err := f.WithColumnExpr("dec", Col("city").Cast(arrow.BinaryTypes.String))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mustColumn(t, dec, "dec").DataType().ID() != arrow.STRING {
+		t.Fatalf("Cast(String) type = %s", mustColumn(t, dec, "dec").DataType())
+	}
+	if got := valuesOrNull(t, dec, "dec"); !slices.Equal(got, []string{"oslo", "rome", "null", "oslo", "rome", "lima", "oslo"}) {
+		t.Fatalf("Cast(String) = %v", got)
+	}
+	if _, err := f.WithColumnExpr("x", Col("v").Cast(Categorical)); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("Int64 → Categorical: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := f.WithColumnExpr("x", Col("city").Cast(arrow.PrimitiveTypes.Float64)); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("Categorical → Float64: err = %v, want ErrExprTypeMismatch", err)
+	}
+}
+
+// TestCategorical_GroupBy — the eager fast path (single chunk), the
+// eager general path (multi-chunk), the streaming path and a composite
+// key all group by value and agree with the String column.
+func TestCategorical_GroupBy(t *testing.T) {
+	f := catFrame(t)
+	single, err := f.WithColumnExpr("city", Col("city_str").Cast(Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := Aggregation{Column: "v", Kind: AggSum}
+	wantKeys := []string{"null", "lima", "oslo", "rome"}
+	wantSums := []string{"3", "6", "12", "7"}
+	check := func(name string, out *Frame, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !mustColumn(t, out, "city").IsCategorical() {
+			t.Fatalf("%s: key column is %s, want categorical", name, mustColumn(t, out, "city").DataType())
+		}
+		if got := valuesOrNull(t, out, "city"); !slices.Equal(got, wantKeys) {
+			t.Fatalf("%s keys = %v, want %v", name, got, wantKeys)
+		}
+		if got := valuesOrNull(t, out, "v_sum"); !slices.Equal(got, wantSums) {
+			t.Fatalf("%s sums = %v, want %v", name, got, wantSums)
+		}
+	}
+	for name, fr := range map[string]*Frame{"single-chunk": single, "multi-chunk": f} {
+		g, err := fr.GroupBy("city")
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := g.Agg(sum)
+		check("eager "+name, out, err)
+		out, err = fr.Lazy().GroupBy("city").Agg(sum).Collect()
+		check("lazy "+name, out, err)
+	}
+
+	g, err := f.GroupBy("city", "city_str")
+	if err != nil {
+		t.Fatal(err)
+	}
+	both, err := g.Agg(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, both, "city"); !slices.Equal(got, valuesOrNull(t, both, "city_str")) {
+		t.Fatalf("composite key: city %v vs city_str %v", got, valuesOrNull(t, both, "city_str"))
+	}
+}
+
+func TestCategorical_Join(t *testing.T) {
+	f := catFrame(t)
+	lookup, err := FromStructs([]struct {
+		City string `gobi:"city"`
+		Pop  int64  `gobi:"pop"`
+	}{{"oslo", 700}, {"rome", 2800}, {"paris", 2100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	catLookup, err := lookup.WithColumnExpr("city", Col("city").Cast(Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"700", "2800", "null", "700", "2800", "null", "700"}
+	for name, right := range map[string]*Frame{"categorical": catLookup, "string": lookup} {
+		out, err := f.Join(right, "city", "city", JoinLeft)
+		if err != nil {
+			t.Fatalf("%s right: %v", name, err)
+		}
+		if got := valuesOrNull(t, out, "pop"); !slices.Equal(got, want) {
+			t.Fatalf("%s right: pop = %v, want %v", name, got, want)
+		}
+		if !mustColumn(t, out, "city").IsCategorical() {
+			t.Fatalf("%s right: key column lost its categorical type", name)
+		}
+	}
+
+	// Categorical on the probe side, String on the build side, and a
+	// right join, which builds on the left.
+	out, err := lookup.Join(f, "city", "city", JoinInner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "v"); !slices.Equal(got, []string{"1", "4", "7", "2", "5"}) {
+		t.Fatalf("String ⋈ categorical v = %v", got)
+	}
+	out, err = catLookup.Join(f, "city", "city", JoinRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "pop"); !slices.Equal(got, want) {
+		t.Fatalf("right join pop = %v, want %v", got, want)
+	}
+	lazy, err := f.Lazy().Join(catLookup.Lazy(), "city", "city", JoinLeft).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, lazy, "pop"); !slices.Equal(got, want) {
+		t.Fatalf("lazy join pop = %v, want %v", got, want)
+	}
+
+	ints, _ := FromStructs([]struct {
+		City int64 `gobi:"city"`
+	}{{1}})
+	if _, err := f.Join(ints, "city", "city", JoinInner); !errors.Is(err, ErrColumnTypeMismatch) {
+		t.Fatalf("categorical ⋈ Int64: err = %v, want ErrColumnTypeMismatch", err)
+	}
+}
+
+// TestCategorical_Filter — literal equality goes through the
+// dictionary; every comparison agrees with the same filter on the
+// String column.
+func TestCategorical_Filter(t *testing.T) {
+	f := catFrame(t)
+	cases := []struct {
+		name string
+		pred func(col string) Expr
+		want []string
+	}{
+		{"eq", func(c string) Expr { return Col(c).Eq(Lit("oslo")) }, []string{"1", "4", "7"}},
+		{"eq lit first", func(c string) Expr { return Lit("rome").Eq(Col(c)) }, []string{"2", "5"}},
+		{"ne", func(c string) Expr { return Col(c).Ne(Lit("oslo")) }, []string{"2", "5", "6"}},
+		{"eq absent", func(c string) Expr { return Col(c).Eq(Lit("paris")) }, nil},
+		{"ne absent", func(c string) Expr { return Col(c).Ne(Lit("paris")) }, []string{"1", "2", "4", "5", "6", "7"}},
+		{"eq column", func(c string) Expr { return Col(c).Eq(Col("city_str")) }, []string{"1", "2", "4", "5", "6", "7"}},
+		{"is_in", func(c string) Expr { return Col(c).IsIn([]string{"lima", "rome"}) }, []string{"2", "5", "6"}},
+		{"str", func(c string) Expr { return Col(c).Str().StartsWith("o") }, []string{"1", "4", "7"}},
+	}
+	for _, c := range cases {
+		for _, col := range []string{"city", "city_str"} {
+			out, err := f.FilterExpr(c.pred(col))
+			if err != nil {
+				t.Fatalf("%s on %s: %v", c.name, col, err)
+			}
+			if got := valuesOrNull(t, out, "v"); !slices.Equal(got, c.want) {
+				t.Fatalf("%s on %s: v = %v, want %v", c.name, col, got, c.want)
+			}
+		}
+	}
+
+	lazy, err := f.Lazy().Filter(Col("city").Eq(Lit("oslo"))).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, lazy, "v"); !slices.Equal(got, []string{"1", "4", "7"}) {
+		t.Fatalf("lazy Eq: v = %v", got)
+	}
+	if !mustColumn(t, lazy, "city").IsCategorical() {
+		t.Fatal("Filter output lost the categorical type")
+	}
+}
+
+func TestCategorical_SortBy(t *testing.T) {
+	// SortBy wants single-chunk keys; Take compacts every column,
+	// re-encoding city under one dictionary.
+	f, err := catFrame(t).Take([]int{0, 1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := f.SortBy(SortKey{Column: "city"}, SortKey{Column: "v", Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "city"); !slices.Equal(got, []string{"lima", "oslo", "oslo", "oslo", "rome", "rome", "null"}) {
+		t.Fatalf("SortBy categorical = %v", got)
+	}
+
+	// An Ordered type sorts by code: first appearance here.
+	ordered := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int16, ValueType: arrow.BinaryTypes.String, Ordered: true}
+	byCode, err := f.WithColumnExpr("city", Col("city_str").Cast(ordered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = byCode.SortBy(SortKey{Column: "city", Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "city"); !slices.Equal(got, []string{"lima", "rome", "rome", "oslo", "oslo", "oslo", "null"}) {
+		t.Fatalf("SortBy ordered categorical desc = %v", got)
+	}
+}
+
+// TestCategorical_RowKernels — take, fills and shifts keep the type
+// and, on one chunk, the dictionary.
+func TestCategorical_RowKernels(t *testing.T) {
+	f := catFrame(t)
+	single, err := f.WithColumnExpr("city", Col("city_str").Cast(Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	taken, err := single.Take([]int{5, 0, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := mustColumn(t, single, "city").col.Data().Chunk(0).(*array.Dictionary).Dictionary()
+	got := mustColumn(t, taken, "city").col.Data().Chunk(0).(*array.Dictionary).Dictionary()
+	if got != src && !array.Equal(got, src) {
+		t.Fatal("Take rebuilt the dictionary")
+	}
+	if v := valuesOrNull(t, taken, "city"); !slices.Equal(v, []string{"lima", "oslo", "null"}) {
+		t.Fatalf("Take = %v", v)
+	}
+
+	city := mustColumn(t, f, "city")
+	filled, err := city.FillNull("unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := filled.Strings(); !filled.IsCategorical() || v[2] != "unknown" {
+		t.Fatalf("FillNull = %v (%s)", v, filled.DataType())
+	}
+	ffill, err := city.FillNullWith(FillForward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := ffill.Strings(); v[2] != "rome" {
+		t.Fatalf("FillNullWith(forward) = %v", v)
+	}
+}
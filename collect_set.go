@@ -37,11 +37,13 @@ type setAggregator[T comparable] struct {
 	extract func(chunk arrow.Array, i int) (T, bool, error)
 	less    func(a, b T) bool
 	name    string
+	bytes   int64 // see SizeBytes
 }
 
 func (a *setAggregator[T]) Aggregate(s Series, rows []int) (any, error) {
 	// Reset per group — eager engine reuses one instance across groups.
 	a.seen = make(map[T]struct{}, len(rows))
+	a.bytes = 0
 	if err := a.Update(s, rows); err != nil {
 		return nil, err
 	}
@@ -68,11 +70,23 @@ func (a *setAggregator[T]) Update(col Series, rows []int) error {
 		if !notNull {
 			continue
 		}
-		a.seen[v] = struct{}{}
+		a.add(v)
 	}
 	return nil
 }
 
+// add inserts v, charging it to bytes when it is new.
+func (a *setAggregator[T]) add(v T) {
+	if _, ok := a.seen[v]; ok {
+		return
+	}
+	a.seen[v] = struct{}{}
+	a.bytes += mapEntryBytes
+	if s, ok := any(v).(string); ok {
+		a.bytes += int64(len(s))
+	}
+}
+
 // Finalize returns the group's collected set as a sorted []T. Safe to
 // call repeatedly; state isn't cleared here (Clone provides fresh
 // state for the next group).
@@ -101,11 +115,15 @@ func (a *setAggregator[T]) Merge(other Aggregator) error {
 		a.seen = make(map[T]struct{}, len(o.seen))
 	}
 	for k := range o.seen {
-		a.seen[k] = struct{}{}
+		a.add(k)
 	}
 	return nil
 }
 
+// SizeBytes is mapEntryBytes per collected value, plus the bytes of
+// string values.
+func (a *setAggregator[T]) SizeBytes() int64 { return a.bytes }
+
 func (a *setAggregator[T]) Type() arrow.DataType { return arrow.ListOf(a.elemType) }
 func (a *setAggregator[T]) Name() string         { return a.name }
 
@@ -21,18 +21,38 @@ import (
 // their goroutine on construction, so callers should always follow
 // Compile with Execute or the operator's Close to avoid leaks.
 func Compile(p LogicalPlan) (ExecOperator, error) {
-	op, err := compileNode(p)
+	return CompileWith(p, nil)
+}
+
+// CompileWith is Compile with CollectOptions. A MemoryLimit swaps the
+// blocking operators that can spill — Sort (on mergeable key types),
+// keyed streaming Aggregate and hash Join — for their budgeted
+// variants; see spill.go. A nil opts is Compile.
+func CompileWith(p LogicalPlan, opts *CollectOptions) (ExecOperator, error) {
+	c := &compiler{spill: newSpillConfig(opts)}
+	op, err := c.compileNode(p)
 	if err != nil {
 		return nil, err
 	}
 	return fuseStreamChains(op), nil
 }
 
-// compileNode is the raw plan-to-exec translation. Compile wraps it
-// with fuseStreamChains, a post-pass that coalesces adjacent
+// compiler carries the per-Compile settings down the recursive
+// translation.
+type compiler struct {
+	spill spillConfig
+}
+
+// compileNode translates p with no memory budget.
+func compileNode(p LogicalPlan) (ExecOperator, error) {
+	return (&compiler{}).compileNode(p)
+}
+
+// compileNode is the raw plan-to-exec translation. CompileWith wraps
+// it with fuseStreamChains, a post-pass that coalesces adjacent
 // streaming batch-transforms into a single fusedStreamExecOp — saves
 // one batch↔Frame conversion cycle per fused op.
-func compileNode(p LogicalPlan) (ExecOperator, error) {
+func (c *compiler) compileNode(p LogicalPlan) (ExecOperator, error) {
 	if p == nil {
 		return nil, fmt.Errorf("gobi: Compile: nil plan")
 	}
@@ -47,13 +67,14 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		return &emptyExecOp{schema: n.Schema()}, nil
 
 	case *filterNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
 		// Over in a filter predicate would slice partitions across
 		// batch boundaries. Force materialize before per-batch filter.
-		if exprContainsOver(n.cond.node) {
+		streamCond, ok := streamingFills(n.cond)
+		if !ok || exprContainsOver(n.cond.node) {
 			cond := n.cond
 			return &materializeExecOp{
 				input:     child,
@@ -63,16 +84,19 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 				},
 			}, nil
 		}
-		return &filterExecOp{input: child, cond: n.cond}, nil
+		return &filterExecOp{input: child, cond: streamCond}, nil
 
 	case *projectNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
 		// Same Over-crossing-batches concern for Select expressions.
-		for _, e := range n.exprs {
-			if exprContainsOver(e.node) {
+		streamExprs := make([]Expr, len(n.exprs))
+		for i, e := range n.exprs {
+			var ok bool
+			streamExprs[i], ok = streamingFills(e)
+			if !ok || exprContainsOver(e.node) {
 				exprs := n.exprs
 				return &materializeExecOp{
 					input:     child,
@@ -83,10 +107,10 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 				}, nil
 			}
 		}
-		return &projectExecOp{input: child, exprs: n.exprs, outSchema: n.outSchema}, nil
+		return &projectExecOp{input: child, exprs: streamExprs, outSchema: n.outSchema}, nil
 
 	case *withColumnNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -94,7 +118,8 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		// preserving), it needs to see the whole input Frame at once
 		// — per-batch eval would slice partitions at batch boundaries
 		// and produce wrong results. Route through materialize.
-		if exprContainsOver(n.expr.node) {
+		streamExpr, ok := streamingFills(n.expr)
+		if !ok || exprContainsOver(n.expr.node) {
 			name, expr := n.name, n.expr
 			inputMeta := n.input.PartitionMetadata()
 			return &materializeExecOp{
@@ -111,7 +136,7 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		return &withColumnExecOp{
 			input:     child,
 			name:      n.name,
-			expr:      n.expr,
+			expr:      streamExpr,
 			outSchema: n.outSchema,
 			// Capture the input's partition claim at Compile time so
 			// per-batch expression Eval (Over in particular) can see
@@ -120,14 +145,14 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		}, nil
 
 	case *dropNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
 		return &dropExecOp{input: child, name: n.name, outSchema: n.outSchema}, nil
 
 	case *limitNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -138,19 +163,40 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 	// still see a streaming source.
 
 	case *sortNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
 		keys := n.keys
+		if c.spill.enabled() && canMergeSortKeys(n.input.Schema(), keys) {
+			return &externalSortExec{
+				input:     child,
+				keys:      keys,
+				outSchema: n.Schema(),
+				spill:     c.spill,
+			}, nil
+		}
 		return &materializeExecOp{
 			input:     child,
 			outSchema: n.Schema(),
 			compute:   func(f *Frame) (*Frame, error) { return f.SortBy(keys...) },
 		}, nil
 
+	case *topKNode:
+		child, err := c.compileNode(n.input)
+		if err != nil {
+			return nil, err
+		}
+		return &topKExec{
+			input:     child,
+			keys:      n.keys,
+			n:         n.n,
+			outSchema: n.Schema(),
+			workers:   resolveWorkers(),
+		}, nil
+
 	case *aggregateNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -160,6 +206,11 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		// Custom Fn aggregators expect all rows at once via their
 		// Aggregate(Series, []int) signature, which can't be
 		// incrementalized without changing the interface.
+		// Under a memory budget a keyed aggregate runs serially so
+		// it can freeze its one group table and spill the rest.
+		if c.spill.enabled() && canSpillAggregate(n) {
+			return newAggregateExec(child, keys, aggs, n.outSchema, pickKeyMode(n), c.spill, 0), nil
+		}
 		if allBuiltInAggs(aggs) {
 			// Worker count for the partitioned build. resolveWorkers
 			// returns >=1 and folds SetMaxParallelism + GOMAXPROCS in
@@ -188,17 +239,17 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		}, nil
 
 	case *joinNode:
-		left, err := compileNode(n.input)
+		left, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
-		right, err := compileNode(n.right)
+		right, err := c.compileNode(n.right)
 		if err != nil {
 			return nil, err
 		}
 		// Alignment-aware Inner fast path: when both sides carry
 		// PartitionMetadata proving same-key rows are colocated AND
-		// each side is sorted on the join key with SortEnforced=true,
+		// each side is sorted on the join keys with SortEnforced=true,
 		// swap in the sort-merge executor. Eliminates the hash-index
 		// build entirely — see exec_join_merge.go for the mechanics.
 		//
@@ -208,11 +259,24 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 			return &sortMergeJoinExec{
 				left:      left,
 				right:     right,
-				leftKey:   n.leftKey,
-				rightKey:  n.rightKey,
+				leftKeys:  n.leftKeys,
+				rightKeys: n.rightKeys,
 				outSchema: n.outSchema,
 			}, nil
 		}
+		// Under a memory budget every kind goes through the grace
+		// join, which decides at run time whether the build side fits.
+		if c.spill.enabled() {
+			return &graceJoinExec{
+				left:      left,
+				right:     right,
+				leftKeys:  n.leftKeys,
+				rightKeys: n.rightKeys,
+				kind:      n.kind,
+				outSchema: n.outSchema,
+				spill:     c.spill,
+			}, nil
+		}
 		// Left-driven kinds (Inner, Left, Semi, Anti) stream the
 		// probe side against a materialized build. Right and Full
 		// need a second-phase pass to emit unmatched right rows,
@@ -222,8 +286,8 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 			return &streamingJoinExec{
 				left:      left,
 				right:     right,
-				leftKey:   n.leftKey,
-				rightKey:  n.rightKey,
+				leftKeys:  n.leftKeys,
+				rightKeys: n.rightKeys,
 				kind:      n.kind,
 				outSchema: n.outSchema,
 			}, nil
@@ -235,17 +299,86 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 			left.Close()
 			return nil, err
 		}
-		leftKey, rightKey, kind := n.leftKey, n.rightKey, n.kind
+		leftKeys, rightKeys, kind := n.leftKeys, n.rightKeys, n.kind
 		return &materializeExecOp{
 			input:     left,
 			outSchema: n.outSchema,
 			compute: func(f *Frame) (*Frame, error) {
-				return f.Join(rightFrame, leftKey, rightKey, kind)
+				return f.JoinOn(rightFrame, leftKeys, rightKeys, kind)
+			},
+		}, nil
+
+	case *asofJoinNode:
+		left, err := c.compileNode(n.input)
+		if err != nil {
+			return nil, err
+		}
+		right, err := c.compileNode(n.right)
+		if err != nil {
+			left.Close()
+			return nil, err
+		}
+		// Every left row maps to exactly one output row and needs
+		// only the (materialized) right side, so the probe side always
+		// streams — no fallback needed for any strategy.
+		return &streamingAsofJoinExec{
+			left:      left,
+			right:     right,
+			leftOn:    n.leftOn,
+			rightOn:   n.rightOn,
+			by:        n.by,
+			opts:      n.opts,
+			outSchema: n.outSchema,
+		}, nil
+
+	case *sjoinNode:
+		m, err := n.pred.matcher()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := sjoinKind(n.opts...); err != nil {
+			return nil, err
+		}
+		left, err := c.compileNode(n.input)
+		if err != nil {
+			return nil, err
+		}
+		right, err := c.compileNode(n.right)
+		if err != nil {
+			left.Close()
+			return nil, err
+		}
+		if canStreamJoin(n.kind) {
+			return &streamingSJoinExec{
+				left:      left,
+				right:     right,
+				leftGeom:  n.leftGeom,
+				rightGeom: n.rightGeom,
+				matcher:   m,
+				kind:      n.kind,
+				workers:   resolveWorkers(n.opts...),
+				outSchema: n.outSchema,
+			}, nil
+		}
+		// Right / Full: unmatched right rows are only known once every
+		// left row has probed, so materialize both sides and delegate
+		// to the eager Frame.SJoin.
+		rightFrame, err := Execute(context.Background(), right)
+		if err != nil {
+			left.Close()
+			return nil, err
+		}
+		leftGeom, rightGeom, pred, opts := n.leftGeom, n.rightGeom, n.pred, n.opts
+		return &materializeExecOp{
+			input:     left,
+			outSchema: n.outSchema,
+			compute: func(f *Frame) (*Frame, error) {
+				return f.SJoin(rightFrame, leftGeom, rightGeom, pred, opts...)
 			},
 		}, nil
 
 	case *tailNode:
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -267,7 +400,7 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		// per-batch through explodeExecOp; output batches may exceed
 		// the batch-size soft cap when dense multi-part geometries or
 		// long lists arrive.
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -280,7 +413,7 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 	case *renameNode:
 		// Rename is schema-only — a per-batch relabel, no buffering
 		// required. Streams like Filter / Project / Drop.
-		child, err := compileNode(n.input)
+		child, err := c.compileNode(n.input)
 		if err != nil {
 			return nil, err
 		}
@@ -296,7 +429,7 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 		// directly, no executor node needed. The metadata claim is
 		// consumed at plan time (by alignment predicates), not at
 		// runtime.
-		return compileNode(n.input)
+		return c.compileNode(n.input)
 	}
 	return nil, fmt.Errorf("gobi: Compile: unknown plan node %T", p)
 }
@@ -309,13 +442,23 @@ func compileNode(p LogicalPlan) (ExecOperator, error) {
 // returns true.
 //
 // Applies to every Over shape (scalar-aggregate and shape-preserving)
-// because both have cross-batch partition semantics.
+// because both have cross-batch partition semantics, and to the
+// window functions (CumSum, RowNumber, Rank, …) even without Over:
+// run per batch, a running sum would restart at every batch.
+// Backward and mean fills and interpolation need rows after the
+// current batch the same way. A forward fill doesn't: streamingFills
+// gives it the previous batches' last value instead.
 func exprContainsOver(node ExprNode) bool {
 	if node == nil {
 		return false
 	}
-	if _, ok := node.(*overNode); ok {
+	switch n := node.(type) {
+	case *overNode, *windowNode, *interpolateNode:
 		return true
+	case *fillStrategyNode:
+		if n.strategy != FillForward {
+			return true
+		}
 	}
 	for _, c := range node.Children() {
 		if exprContainsOver(c.node) {
@@ -325,6 +468,45 @@ func exprContainsOver(node ExprNode) bool {
 	return false
 }
 
+// streamingFills returns e with every FillForward replaced by a fresh
+// streamingFillForwardNode, so a streaming operator's forward fills
+// carry their last value from one batch to the next. Each call builds
+// new state; the plan's own expression is left untouched. false when
+// a fill sits under a node withChildren can't rebuild (Custom); the
+// caller materializes instead.
+func streamingFills(e Expr) (Expr, bool) {
+	n, ok := streamingFillsNode(e.node)
+	return Expr{node: n}, ok
+}
+
+func streamingFillsNode(node ExprNode) (ExprNode, bool) {
+	if node == nil {
+		return nil, true
+	}
+	if f, ok := node.(*fillStrategyNode); ok && f.strategy == FillForward {
+		inner, ok := streamingFillsNode(f.inner)
+		if !ok {
+			return nil, false
+		}
+		return &streamingFillForwardNode{inner: inner}, true
+	}
+	children := node.Children()
+	kids := make([]ExprNode, len(children))
+	changed := false
+	for i, c := range children {
+		k, ok := streamingFillsNode(c.node)
+		if !ok {
+			return nil, false
+		}
+		kids[i] = k
+		changed = changed || k != c.node
+	}
+	if !changed {
+		return node, true
+	}
+	return withChildren(node, kids)
+}
+
 // allBuiltInAggs reports whether every Aggregation is runnable through
 // the streaming aggregate executor. That's true when either the
 // aggregation uses a built-in Kind (no custom Fn) or the custom Fn
@@ -425,6 +607,12 @@ func fuseStreamChains(op ExecOperator) ExecOperator {
 	case *sortMergeJoinExec:
 		e.left = fuseStreamChains(e.left)
 		e.right = fuseStreamChains(e.right)
+	case *streamingAsofJoinExec:
+		e.left = fuseStreamChains(e.left)
+		e.right = fuseStreamChains(e.right)
+	case *streamingSJoinExec:
+		e.left = fuseStreamChains(e.left)
+		e.right = fuseStreamChains(e.right)
 	}
 
 	// Try to fuse op with its input. Only frameApplier-implementing
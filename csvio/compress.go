@@ -7,16 +7,18 @@ import (
 	"io"
 	"strings"
 
+	dsbzip2 "github.com/dsnet/compress/bzip2"
 	"github.com/klauspost/compress/zstd"
 )
 
-// Codec selects the stream-compression codec used when reading a CSV.
+// Codec selects the stream-compression codec used when reading or
+// writing a CSV.
 type Codec string
 
 const (
 	// CodecAuto is the zero value: infer the codec from the filename in
-	// ReadFile, or treat as uncompressed when the source is a plain
-	// io.Reader (Read).
+	// ReadFile / WriteFile, or treat as uncompressed when the source is
+	// a plain io.Reader / io.Writer (Read / Write).
 	CodecAuto Codec = ""
 	// CodecNone forces no decompression, regardless of filename.
 	CodecNone Codec = "none"
@@ -24,8 +26,9 @@ const (
 	CodecGzip Codec = "gzip"
 	// CodecZstd decompresses via klauspost/compress/zstd (".zst" files).
 	CodecZstd Codec = "zstd"
-	// CodecBzip2 decompresses via compress/bzip2 (".bz2" files). Read-only;
-	// the Go stdlib doesn't ship a bzip2 writer.
+	// CodecBzip2 decompresses via compress/bzip2 (".bz2" files). The Go
+	// stdlib doesn't ship a bzip2 writer, so the write path compresses
+	// via dsnet/compress/bzip2 (pure Go).
 	CodecBzip2 Codec = "bzip2"
 )
 
@@ -75,3 +78,31 @@ func wrapCodec(r io.Reader, codec Codec) (io.Reader, func(), error) {
 		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownCodec, codec)
 	}
 }
+
+// wrapWriteCodec returns a writer that compresses into w using codec,
+// plus a close function that flushes the codec's trailer. Callers must
+// invoke close exactly once after the last Write — skipping it leaves
+// a truncated stream on disk. The close function does NOT close w.
+func wrapWriteCodec(w io.Writer, codec Codec) (io.Writer, func() error, error) {
+	switch codec {
+	case CodecNone, CodecAuto:
+		return w, func() error { return nil }, nil
+	case CodecGzip:
+		gz := gzip.NewWriter(w)
+		return gz, gz.Close, nil
+	case CodecZstd:
+		z, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, nil, fmt.Errorf("csvio: zstd: %w", err)
+		}
+		return z, z.Close, nil
+	case CodecBzip2:
+		bz, err := dsbzip2.NewWriter(w, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("csvio: bzip2: %w", err)
+		}
+		return bz, bz.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownCodec, codec)
+	}
+}
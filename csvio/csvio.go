@@ -17,6 +17,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -93,6 +94,14 @@ type ReadOptions struct {
 	UseCRLF bool
 	// ChunkRows overrides DefaultChunkRows. Values ≤ 0 use the default.
 	ChunkRows int
+	// Categorical names string fields of T to read dictionary-encoded,
+	// as gobi.Categorical: each distinct value is stored once and rows
+	// hold a code. Use it for low-cardinality columns (status, country,
+	// category) that feed GroupBy, Join or equality filters. ReadFile /
+	// Read produce one dictionary per column; the streaming API one per
+	// chunk. Names that aren't a plain string field of T return
+	// ErrUnsupportedFieldType.
+	Categorical []string
 }
 
 func (o *ReadOptions) hasHeader() bool {
@@ -150,6 +159,9 @@ func Read[T any](r io.Reader, opts *ReadOptions) (*gobi.Frame, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := planCategorical(plan, opts.Categorical); err != nil {
+		return nil, err
+	}
 
 	// SkipRows is applied to the raw stream before Arrow's reader sees
 	// it, so the header row (if any) still counts against Arrow's own
@@ -254,6 +266,12 @@ func Read[T any](r io.Reader, opts *ReadOptions) (*gobi.Frame, error) {
 				return nil, fmt.Errorf("csvio: column %q: %w", p.outputField.Name, err)
 			}
 			outArrs[i] = ts
+		case xformStringToCategorical:
+			cat, err := transformCategoricalColumn(pool, readArrs[i])
+			if err != nil {
+				return nil, fmt.Errorf("csvio: column %q: %w", p.outputField.Name, err)
+			}
+			outArrs[i] = cat
 		default:
 			// No transform: the read array becomes the output array. Retain
 			// it so both defers can release safely.
@@ -415,6 +433,9 @@ func setupReader[T any](r io.Reader, opts *ReadOptions) (*setupContext, error) {
 	}
 
 	plan, err := planStruct[T](opts.CRSHint)
+	if err == nil {
+		err = planCategorical(plan, opts.Categorical)
+	}
 	if err != nil {
 		sc.close()
 		return nil, err
@@ -494,6 +515,12 @@ func recordToFrame(
 				return nil, fmt.Errorf("csvio: column %q: %w", p.outputField.Name, err)
 			}
 			outArrs[i] = a
+		case xformStringToCategorical:
+			a, err := transformCategoricalColumn(pool, col)
+			if err != nil {
+				return nil, fmt.Errorf("csvio: column %q: %w", p.outputField.Name, err)
+			}
+			outArrs[i] = a
 		default:
 			col.Retain()
 			outArrs[i] = col
@@ -525,6 +552,9 @@ const (
 	// xformStringToTimestamp: parse each cell via time.Parse and emit
 	// Timestamp[ns].
 	xformStringToTimestamp
+	// xformStringToCategorical: dictionary-encode the String column as
+	// gobi.Categorical (ReadOptions.Categorical).
+	xformStringToCategorical
 )
 
 // columnPlan describes one column both as it should be read by Arrow's
@@ -599,6 +629,21 @@ func planStruct[T any](crsHint int32) ([]columnPlan, error) {
 	return out, nil
 }
 
+// planCategorical retargets the named plain String columns to
+// gobi.Categorical.
+func planCategorical(plan []columnPlan, names []string) error {
+	for _, name := range names {
+		i := slices.IndexFunc(plan, func(p columnPlan) bool { return p.outputField.Name == name })
+		if i < 0 || plan[i].transform != xformNone || plan[i].readField.Type.ID() != arrow.STRING {
+			return fmt.Errorf("%w: Categorical column %q is not a string field of T",
+				ErrUnsupportedFieldType, name)
+		}
+		plan[i].outputField.Type = gobi.Categorical
+		plan[i].transform = xformStringToCategorical
+	}
+	return nil
+}
+
 func arrowTypeFor(t reflect.Type) (arrow.DataType, error) {
 	switch t.Kind() {
 	case reflect.String:
@@ -652,6 +697,28 @@ func transformWKTColumn(pool memory.Allocator, src arrow.Array) (arrow.Array, er
 	return b.NewArray(), nil
 }
 
+// transformCategoricalColumn dictionary-encodes a String array as
+// gobi.Categorical.
+func transformCategoricalColumn(pool memory.Allocator, src arrow.Array) (arrow.Array, error) {
+	strArr, ok := src.(*array.String)
+	if !ok {
+		return nil, fmt.Errorf("%w: categorical column not String, got %T",
+			ErrUnsupportedFieldType, src)
+	}
+	b := array.NewDictionaryBuilder(pool, gobi.Categorical.(*arrow.DictionaryType)).(*array.BinaryDictionaryBuilder)
+	defer b.Release()
+	for i := range strArr.Len() {
+		if strArr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		if err := b.AppendString(strArr.Value(i)); err != nil {
+			return nil, err
+		}
+	}
+	return b.NewArray(), nil
+}
+
 // transformTimeColumn walks a String array and emits a Timestamp[ns]
 // array by parsing each cell with parseTime.
 func transformTimeColumn(pool memory.Allocator, src arrow.Array, layout string) (arrow.Array, error) {
@@ -1,11 +1,13 @@
 package csvio_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/apache/arrow-go/v18/arrow/array"
 
+	"github.com/zoobst/gobi"
 	"github.com/zoobst/gobi/csvio"
 )
 
@@ -166,3 +168,44 @@ func TestOption_ChunkRows_SmallBatchProducesSameOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestOption_Categorical(t *testing.T) {
+	src := "name,n,f\n" +
+		"alpha,1,1.5\n" +
+		"bravo,2,2.5\n" +
+		",3,3.5\n" +
+		"alpha,4,4.5\n"
+	opts := &csvio.ReadOptions{Categorical: []string{"name"}, ChunkRows: 2}
+	df, err := csvio.Read[numericRow](strings.NewReader(src), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, _ := df.Column("name")
+	if !name.IsCategorical() {
+		t.Fatalf("name is %s, want categorical", name.DataType())
+	}
+	dict := name.Column().Data().Chunks()[0].(*array.Dictionary)
+	if dict.Dictionary().Len() != 2 || !dict.IsNull(2) {
+		t.Fatalf("dictionary = %v, nulls = %d", dict.Dictionary(), dict.NullN())
+	}
+	if vals, _ := name.Strings(); vals[0] != "alpha" || vals[1] != "bravo" || vals[3] != "alpha" {
+		t.Fatalf("name = %q", vals)
+	}
+
+	// The streaming reader encodes per chunk.
+	chunks := 0
+	err = csvio.ReadChunksFunc[numericRow](strings.NewReader(src), opts, func(f *gobi.Frame) error {
+		chunks++
+		if s, _ := f.Column("name"); !s.IsCategorical() {
+			t.Fatalf("chunk %d: name is %s", chunks, s.DataType())
+		}
+		return nil
+	})
+	if err != nil || chunks != 2 {
+		t.Fatalf("ReadChunksFunc: %d chunks, err = %v", chunks, err)
+	}
+
+	if _, err := csvio.Read[numericRow](strings.NewReader(src), &csvio.ReadOptions{Categorical: []string{"n"}}); !errors.Is(err, csvio.ErrUnsupportedFieldType) {
+		t.Fatalf("Categorical on int64: err = %v, want ErrUnsupportedFieldType", err)
+	}
+}
@@ -0,0 +1,91 @@
+package csvio
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/zoobst/gobi"
+)
+
+// SinkFile runs lf and streams its output to path as CSV, one batch at
+// a time, instead of Collect-ing it first. opts is honored as in
+// WriteFile — including the codec inferred from a `.gz` / `.zst` /
+// `.bz2` extension — and the header row is written once, from the
+// plan's schema, so a plan that yields no rows still produces a
+// header-only file. Cells render exactly as Write renders them.
+//
+// On any error — including cancellation of ctx — the partial file is
+// removed.
+func SinkFile(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions) error {
+	return SinkFileWith(ctx, lf, path, opts, nil)
+}
+
+// SinkFileWith is SinkFile with collect options — a memory budget
+// past which the plan's Sort, Aggregate and Join spill to disk (see
+// gobi.LazyFrame.CollectWith). A nil collect is SinkFile.
+func SinkFileWith(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions, collect *gobi.CollectOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	if opts.Compression == CodecAuto {
+		local := *opts
+		local.Compression = detectCodecFromPath(path)
+		opts = &local
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = lf.SinkWith(ctx, &fileSink{out: out, opts: opts}, collect)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(path))
+	}
+	return nil
+}
+
+// fileSink is the gobi.BatchSink behind SinkFile. enc is nil once a
+// failing encoder method has already closed the codec.
+type fileSink struct {
+	out  *os.File
+	opts *WriteOptions
+	enc  *encoder
+}
+
+func (s *fileSink) Open(schema *arrow.Schema) error {
+	enc, err := newEncoder(s.out, s.opts)
+	if err != nil {
+		return err
+	}
+	if s.opts.hasHeader() {
+		names := make([]string, schema.NumFields())
+		for i, f := range schema.Fields() {
+			names[i] = f.Name
+		}
+		if err := enc.writeHeader(names); err != nil {
+			return err
+		}
+	}
+	s.enc = enc
+	return nil
+}
+
+func (s *fileSink) Write(batch *gobi.Frame) error {
+	if err := s.enc.writeFrame(batch); err != nil {
+		s.enc = nil
+		return err
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	if s.enc == nil {
+		return nil
+	}
+	return s.enc.close()
+}
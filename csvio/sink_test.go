@@ -0,0 +1,78 @@
+package csvio_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/csvio"
+)
+
+func TestSinkFile(t *testing.T) {
+	type row struct {
+		ID   int64  `gobi:"id"`
+		Kind string `gobi:"kind"`
+	}
+	// Enough rows for the lazy scan to emit several batches.
+	rows := make([]row, 150_000)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Kind: string(rune('a' + i%3))}
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := df.Lazy().Filter(gobi.Col("kind").Ne(gobi.Lit("b")))
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"out.csv", "out.csv.gz"} {
+		t.Run(name, func(t *testing.T) {
+			sunk, written := filepath.Join(dir, "sink-"+name), filepath.Join(dir, "write-"+name)
+			if err := csvio.SinkFile(context.Background(), lf, sunk, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := csvio.WriteFile(want, written, nil); err != nil {
+				t.Fatal(err)
+			}
+			a, _ := os.ReadFile(sunk)
+			b, _ := os.ReadFile(written)
+			if name == "out.csv" && !bytes.Equal(a, b) {
+				t.Fatalf("SinkFile output (%d bytes) differs from WriteFile (%d bytes)", len(a), len(b))
+			}
+			back, err := csvio.ReadFile[row](sunk, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.NumRows() != want.NumRows() {
+				t.Fatalf("read back %d rows, want %d", back.NumRows(), want.NumRows())
+			}
+		})
+	}
+
+	// No rows: the header alone.
+	empty := filepath.Join(dir, "empty.csv")
+	if err := csvio.SinkFile(context.Background(), df.Lazy().Filter(gobi.Col("id").Lt(gobi.Lit(int64(0)))), empty, nil); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(empty); string(raw) != "id,kind\n" {
+		t.Fatalf("empty sink = %q", raw)
+	}
+
+	// A cancelled run removes the partial file.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	partial := filepath.Join(dir, "partial.csv")
+	if err := csvio.SinkFile(ctx, lf, partial, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Fatalf("partial file left behind: %v", err)
+	}
+}
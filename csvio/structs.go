@@ -17,8 +17,8 @@ import (
 //	}
 //	rows, err := csvio.ReadStructs[Row]("data.csv", nil)
 //
-// Wraps ReadFile[T] + gobi.ToStructs. No writing counterpart because
-// csvio itself is read-only; write via parquetio or another sink.
+// Wraps ReadFile[T] + gobi.ToStructs. WriteStructs is the writing
+// counterpart.
 func ReadStructs[T any](path string, opts *ReadOptions) ([]T, error) {
 	f, err := ReadFile[T](path, opts)
 	if err != nil {
@@ -35,3 +35,19 @@ func ReadStructsReader[T any](r io.Reader, opts *ReadOptions) ([]T, error) {
 	}
 	return gobi.ToStructs[T](f, gobi.StructTagFormat("csv"))
 }
+
+// WriteStructs encodes rows into a CSV file at path. Column names come
+// from the "csv" tag namespace (see ReadStructs); string fields tagged
+// `geom:"true"` are parsed as WKT and written back out per
+// opts.Geometry. Compression is inferred from the extension exactly
+// like WriteFile.
+//
+// Wraps gobi.FromStructs + WriteFile.
+func WriteStructs[T any](rows []T, path string, opts *WriteOptions) error {
+	f, err := gobi.FromStructs(rows, gobi.StructTagFormat("csv"))
+	if err != nil {
+		return err
+	}
+	defer f.Release()
+	return WriteFile(f, path, opts)
+}
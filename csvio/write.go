@@ -0,0 +1,354 @@
+package csvio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geometry"
+)
+
+// GeometryEncoding selects how geometry columns are rendered as CSV
+// cells by the writer.
+type GeometryEncoding uint8
+
+const (
+	// GeometryWKT renders each geometry as OGC Well-Known Text
+	// ("POINT (1 2)"). The zero value, and the encoding the reader's
+	// `geom:"true"` path parses back.
+	GeometryWKT GeometryEncoding = iota
+	// GeometryWKBHex renders each geometry as upper-case hex-encoded
+	// WKB — the shape PostGIS' COPY and QGIS' delimited-text importer
+	// accept. Lossless (no float-formatting round trip) but not
+	// readable by csvio.Read, which only parses WKT.
+	GeometryWKBHex
+)
+
+// DefaultWriteTimeLayout is the layout used to render Timestamp cells
+// when WriteOptions.TimeLayout is empty. RFC 3339 with nanoseconds is
+// the first entry in DefaultTimeLayouts, so files written with the
+// default round-trip through the reader untagged.
+const DefaultWriteTimeLayout = time.RFC3339Nano
+
+// WriteOptions controls CSV encoding.
+type WriteOptions struct {
+	// HasHeader controls whether a header row with the column names is
+	// written first. Defaults to true.
+	HasHeader *bool
+	// Delimiter overrides the default comma, e.g. '\t' for TSV.
+	Delimiter rune
+	// NullToken is the cell text written for null values. The zero
+	// value writes an empty cell, which the reader always decodes as
+	// null; set it to e.g. "NA" and pass the same token in
+	// ReadOptions.NullTokens for a lossless round trip.
+	NullToken string
+	// TimeLayout is the time.Format layout used for Timestamp columns.
+	// Empty means DefaultWriteTimeLayout. Timestamps render in the
+	// column's timezone when the arrow type carries one, UTC otherwise;
+	// a timezone time.LoadLocation doesn't know fails the write.
+	TimeLayout string
+	// Geometry selects the cell encoding for geometry columns (those
+	// tagged via gobi.GeometryField). Defaults to GeometryWKT.
+	Geometry GeometryEncoding
+	// Compression selects the stream-compression codec used to encode
+	// the output. The zero value (CodecAuto) means "infer from the
+	// filename in WriteFile, or write uncompressed in Write." Set to
+	// CodecNone to force uncompressed output regardless of extension.
+	Compression Codec
+	// UseCRLF terminates records with "\r\n" instead of "\n".
+	UseCRLF bool
+}
+
+func (o *WriteOptions) hasHeader() bool {
+	if o == nil || o.HasHeader == nil {
+		return true
+	}
+	return *o.HasHeader
+}
+
+// WriteFile writes f to path as CSV. If opts.Compression is CodecAuto
+// (the default), the codec is inferred from the filename's extension
+// (`.gz`, `.zst`, `.bz2`) — the same rules ReadFile uses.
+func WriteFile(f *gobi.Frame, path string, opts *WriteOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	if opts.Compression == CodecAuto {
+		local := *opts
+		local.Compression = detectCodecFromPath(path)
+		opts = &local
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, out, opts); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Write encodes f to w as CSV. Streams reaching this function are
+// written uncompressed unless opts.Compression is set explicitly
+// (Write has no filename to inspect).
+//
+// Column types map to cell text as follows: integers and floats via
+// strconv (shortest round-trip float formatting), Boolean as
+// "true"/"false", String verbatim, Timestamp via opts.TimeLayout,
+// geometry columns per opts.Geometry, and any other Binary column as
+// lower-case hex. List and Struct columns have no CSV representation
+// and return ErrUnsupportedFieldType.
+func Write(f *gobi.Frame, w io.Writer, opts *WriteOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	enc, err := newEncoder(w, opts)
+	if err != nil {
+		return err
+	}
+	if opts.hasHeader() {
+		if err := enc.writeHeader(f.ColumnNames()); err != nil {
+			return err
+		}
+	}
+	if err := enc.writeFrame(f); err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// encoder is the incremental CSV writer behind Write and SinkFile: the
+// codec, buffer and csv.Writer are set up once, then any number of
+// Frames with the same columns are appended. Every failing method
+// closes the codec before returning, so the caller only calls close
+// on success.
+type encoder struct {
+	opts       *WriteOptions
+	bw         *bufio.Writer
+	cw         *csv.Writer
+	closeCodec func() error
+	// rows counts the rows written so far, so error messages name a
+	// row of the whole output rather than of the current Frame.
+	rows int
+}
+
+func newEncoder(w io.Writer, opts *WriteOptions) (*encoder, error) {
+	enc, closeCodec, err := wrapWriteCodec(w, opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriter(enc)
+	cw := csv.NewWriter(bw)
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+	cw.UseCRLF = opts.UseCRLF
+	return &encoder{opts: opts, bw: bw, cw: cw, closeCodec: closeCodec}, nil
+}
+
+func (e *encoder) writeHeader(names []string) error {
+	if err := e.cw.Write(names); err != nil {
+		_ = e.closeCodec()
+		return fmt.Errorf("csvio: write header: %w", err)
+	}
+	return nil
+}
+
+// writeFrame appends f's rows.
+func (e *encoder) writeFrame(f *gobi.Frame) error {
+	formatters := make([]cellFormatter, f.NumCols())
+	for i := range f.NumCols() {
+		s, err := f.ColumnAt(i)
+		if err == nil {
+			formatters[i], err = newCellFormatter(s, e.opts)
+		}
+		if err != nil {
+			_ = e.closeCodec()
+			return err
+		}
+	}
+	record := make([]string, len(formatters))
+	for range f.NumRows() {
+		row := e.rows
+		e.rows++
+		for i := range formatters {
+			v, ok, err := formatters[i].next()
+			if err != nil {
+				_ = e.closeCodec()
+				return fmt.Errorf("csvio: column %q row %d: %w", f.ColumnNames()[i], row, err)
+			}
+			if !ok {
+				v = e.opts.NullToken
+			}
+			record[i] = v
+		}
+		if err := e.cw.Write(record); err != nil {
+			_ = e.closeCodec()
+			return fmt.Errorf("csvio: write row %d: %w", row, err)
+		}
+	}
+	return nil
+}
+
+// close flushes the csv and buffer layers and finishes the codec
+// stream.
+func (e *encoder) close() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		_ = e.closeCodec()
+		return fmt.Errorf("csvio: %w", err)
+	}
+	if err := e.bw.Flush(); err != nil {
+		_ = e.closeCodec()
+		return err
+	}
+	return e.closeCodec()
+}
+
+// -----------------------------------------------------------------------------
+// Cell formatting
+// -----------------------------------------------------------------------------
+
+// cellFormatter walks one column's chunks in row order, rendering each
+// cell as text. Rows are visited strictly sequentially by Write, so a
+// (chunk, offset) cursor replaces the per-row chunk search that
+// random-access helpers would need.
+type cellFormatter struct {
+	chunks []arrow.Array
+	chunk  int
+	local  int
+	format func(a arrow.Array, i int) (string, error)
+}
+
+// next returns the text of the next cell and ok=false for nulls.
+func (c *cellFormatter) next() (string, bool, error) {
+	for c.chunk < len(c.chunks) && c.local >= c.chunks[c.chunk].Len() {
+		c.chunk++
+		c.local = 0
+	}
+	if c.chunk >= len(c.chunks) {
+		return "", false, fmt.Errorf("%w: cursor past end of column", gobi.ErrRowOutOfRange)
+	}
+	a, i := c.chunks[c.chunk], c.local
+	c.local++
+	if a.IsNull(i) {
+		return "", false, nil
+	}
+	v, err := c.format(a, i)
+	if err != nil {
+		return "", false, err
+	}
+	return v, true, nil
+}
+
+// newCellFormatter picks the per-cell renderer for s once, up front,
+// so the row loop never re-dispatches on the arrow type.
+func newCellFormatter(s gobi.Series, opts *WriteOptions) (cellFormatter, error) {
+	cf := cellFormatter{chunks: s.Column().Data().Chunks()}
+	if s.IsGeometry() {
+		switch opts.Geometry {
+		case GeometryWKBHex:
+			cf.format = func(a arrow.Array, i int) (string, error) {
+				return fmt.Sprintf("%X", a.(*array.Binary).Value(i)), nil
+			}
+		default:
+			cf.format = func(a arrow.Array, i int) (string, error) {
+				g, err := geometry.ParseWKB(a.(*array.Binary).Value(i))
+				if err != nil {
+					return "", err
+				}
+				return g.WKT(), nil
+			}
+		}
+		return cf, nil
+	}
+
+	switch dt := s.DataType().(type) {
+	case *arrow.StringType:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return a.(*array.String).Value(i), nil
+		}
+	case *arrow.LargeStringType:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return a.(*array.LargeString).Value(i), nil
+		}
+	case *arrow.BooleanType:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatBool(a.(*array.Boolean).Value(i)), nil
+		}
+	case *arrow.Int64Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatInt(a.(*array.Int64).Value(i), 10), nil
+		}
+	case *arrow.Int32Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatInt(int64(a.(*array.Int32).Value(i)), 10), nil
+		}
+	case *arrow.Uint64Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatUint(a.(*array.Uint64).Value(i), 10), nil
+		}
+	case *arrow.Uint32Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatUint(uint64(a.(*array.Uint32).Value(i)), 10), nil
+		}
+	case *arrow.Float64Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatFloat(a.(*array.Float64).Value(i), 'g', -1, 64), nil
+		}
+	case *arrow.Float32Type:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return strconv.FormatFloat(float64(a.(*array.Float32).Value(i)), 'g', -1, 32), nil
+		}
+	case *arrow.TimestampType:
+		toTime, err := dt.GetToTimeFunc()
+		if err != nil {
+			return cf, fmt.Errorf("csvio: column %q: %w", s.Name(), err)
+		}
+		// An unknown zone is an error rather than a silent UTC
+		// fallback: the written wall-clock times would be off by the
+		// zone's offset with nothing in the file to say so.
+		loc := time.UTC
+		if dt.TimeZone != "" {
+			if loc, err = time.LoadLocation(dt.TimeZone); err != nil {
+				return cf, fmt.Errorf("csvio: column %q: timezone %q: %w", s.Name(), dt.TimeZone, err)
+			}
+		}
+		layout := opts.TimeLayout
+		if layout == "" {
+			layout = DefaultWriteTimeLayout
+		}
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return toTime(a.(*array.Timestamp).Value(i)).In(loc).Format(layout), nil
+		}
+	case *arrow.BinaryType:
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			return hex.EncodeToString(a.(*array.Binary).Value(i)), nil
+		}
+	case *arrow.DictionaryType:
+		// Categorical columns write each row's category, so the file
+		// reads back the same with or without ReadOptions.Categorical.
+		if !s.IsCategorical() {
+			return cf, fmt.Errorf("%w: column %q has type %s",
+				ErrUnsupportedFieldType, s.Name(), s.DataType())
+		}
+		cf.format = func(a arrow.Array, i int) (string, error) {
+			d := a.(*array.Dictionary)
+			return d.Dictionary().(*array.String).Value(d.GetValueIndex(i)), nil
+		}
+	default:
+		return cf, fmt.Errorf("%w: column %q has type %s",
+			ErrUnsupportedFieldType, s.Name(), s.DataType())
+	}
+	return cf, nil
+}
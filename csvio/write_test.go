@@ -0,0 +1,251 @@
+package csvio_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/csvio"
+	"github.com/zoobst/gobi/geometry"
+)
+
+func TestWrite_RoundTrip(t *testing.T) {
+	df, err := csvio.Read[city](strings.NewReader(citiesCSV), &csvio.ReadOptions{CRSHint: 4326})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := csvio.Write(df, &buf, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := "name,population,geometry\n" +
+		"New York,8804190,POINT (-74.006 40.7128)\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Fatalf("output:\n%s\nwant prefix:\n%s", buf.String(), want)
+	}
+
+	back, err := csvio.Read[city](&buf, &csvio.ReadOptions{CRSHint: 4326})
+	if err != nil {
+		t.Fatalf("re-read: %v", err)
+	}
+	if rows, _ := back.Shape(); rows != 3 {
+		t.Fatalf("rows = %d, want 3", rows)
+	}
+	g, err := back.Geometry("geometry", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := g.(geometry.Point); !ok || p.X != -87.6298 || p.Y != 41.8781 {
+		t.Fatalf("row 2 geometry = %#v", g)
+	}
+}
+
+func TestWrite_DelimiterNullTokenNoHeader(t *testing.T) {
+	type row struct {
+		ID    int64   `csv:"id"`
+		Score *string `csv:"score"`
+	}
+	s := "x"
+	df, err := gobi.FromStructs([]row{{1, &s}, {2, nil}}, gobi.StructTagFormat("csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	noHeader := false
+	var buf bytes.Buffer
+	err = csvio.Write(df, &buf, &csvio.WriteOptions{
+		HasHeader: &noHeader,
+		Delimiter: '\t',
+		NullToken: "NA",
+	})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got, want := buf.String(), "1\tx\n2\tNA\n"; got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestWrite_TimestampLayout(t *testing.T) {
+	type row struct {
+		At time.Time `csv:"at"`
+	}
+	at := time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC)
+	df, err := gobi.FromStructs([]row{{at}}, gobi.StructTagFormat("csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := csvio.Write(df, &buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "at\n2024-03-09T12:30:00Z\n"; got != want {
+		t.Fatalf("default layout: got %q want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := csvio.Write(df, &buf, &csvio.WriteOptions{TimeLayout: "2006-01-02"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "at\n2024-03-09\n"; got != want {
+		t.Fatalf("custom layout: got %q want %q", got, want)
+	}
+}
+
+func TestWrite_UnknownTimezone(t *testing.T) {
+	ts := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "Mars/Olympus_Mons"}
+	b := array.NewTimestampBuilder(memory.DefaultAllocator, ts)
+	defer b.Release()
+	b.Append(0)
+	arr := b.NewArray()
+	defer arr.Release()
+	field := arrow.Field{Name: "at", Type: ts}
+	df, err := gobi.NewFrame(arrow.NewSchema([]arrow.Field{field}, nil),
+		[]arrow.Column{*arrow.NewColumn(field, arrow.NewChunked(ts, []arrow.Array{arr}))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = csvio.Write(df, &buf, nil)
+	if err == nil {
+		t.Fatalf("expected an error, wrote %q", buf.String())
+	}
+	if msg := err.Error(); !strings.Contains(msg, `"at"`) || !strings.Contains(msg, "Mars/Olympus_Mons") {
+		t.Fatalf("error %q doesn't name the column and timezone", msg)
+	}
+}
+
+func TestWrite_CategoricalRoundTrip(t *testing.T) {
+	src := "name,n,f\n" +
+		"alpha,1,1.5\n" +
+		"bravo,2,2.5\n" +
+		",3,3.5\n" +
+		"alpha,4,4.5\n"
+	opts := &csvio.ReadOptions{Categorical: []string{"name"}}
+	df, err := csvio.Read[numericRow](strings.NewReader(src), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := csvio.Write(df, &buf, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != src {
+		t.Fatalf("got %q want %q", got, src)
+	}
+
+	// The streaming sink writes the same bytes.
+	path := filepath.Join(t.TempDir(), "cat.csv")
+	if err := csvio.SinkFile(context.Background(), df.Lazy(), path, nil); err != nil {
+		t.Fatalf("SinkFile: %v", err)
+	}
+	back, err := csvio.ReadFile[numericRow](path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, _ := back.Column("name")
+	if !name.IsCategorical() {
+		t.Fatalf("name is %s, want categorical", name.DataType())
+	}
+	if vals, _ := name.Strings(); vals[0] != "alpha" || vals[1] != "bravo" || vals[3] != "alpha" || !name.Nulls()[2] {
+		t.Fatalf("name = %q, nulls = %v", vals, name.Nulls())
+	}
+}
+
+func TestWrite_GeometryWKBHex(t *testing.T) {
+	df, err := csvio.Read[city](strings.NewReader(citiesCSV), &csvio.ReadOptions{CRSHint: 4326})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := csvio.Write(df, &buf, &csvio.WriteOptions{Geometry: csvio.GeometryWKBHex}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	first := strings.Split(lines[1], ",")
+	if len(first) != 3 {
+		t.Fatalf("row 1 = %q", lines[1])
+	}
+	// Little-endian WKB point header: byte order 01, type 1 as uint32.
+	if !strings.HasPrefix(first[2], "0101000000") {
+		t.Fatalf("geometry cell %q is not hex WKB", first[2])
+	}
+}
+
+func TestWriteFile_CompressionByExtension(t *testing.T) {
+	df, err := csvio.Read[city](strings.NewReader(citiesCSV), &csvio.ReadOptions{CRSHint: 4326})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, ext := range []string{".csv.gz", ".csv.zst", ".csv.bz2"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(dir, "cities"+ext)
+			if err := csvio.WriteFile(df, path, nil); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.HasPrefix(raw, []byte("name,")) {
+				t.Fatalf("%s written uncompressed", ext)
+			}
+			back, err := csvio.ReadFile[city](path, &csvio.ReadOptions{CRSHint: 4326})
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if rows, _ := back.Shape(); rows != 3 {
+				t.Fatalf("rows = %d, want 3", rows)
+			}
+		})
+	}
+}
+
+func TestWriteFile_CodecNoneOverridesExtension(t *testing.T) {
+	df, err := csvio.Read[city](strings.NewReader(citiesCSV), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "plain.csv.gz")
+	if err := csvio.WriteFile(df, path, &csvio.WriteOptions{Compression: csvio.CodecNone}); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw, []byte("name,population,geometry\n")) {
+		t.Fatalf("expected plain CSV, got %q", raw[:min(len(raw), 32)])
+	}
+}
+
+func TestWriteStructs_RoundTrip(t *testing.T) {
+	in := []city{
+		{Name: "Paris", Population: 2102650, Geom: "POINT (2.3522 48.8566)"},
+		{Name: "Lyon", Population: 522250, Geom: "POINT (4.8357 45.764)"},
+	}
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	if err := csvio.WriteStructs(in, path, nil); err != nil {
+		t.Fatalf("WriteStructs: %v", err)
+	}
+	out, err := csvio.ReadStructs[city](path, nil)
+	if err != nil {
+		t.Fatalf("ReadStructs: %v", err)
+	}
+	if len(out) != 2 || out[1].Name != "Lyon" || out[1].Population != 522250 {
+		t.Fatalf("got %+v", out)
+	}
+	if out[0].Geom != "POINT (2.3522 48.8566)" {
+		t.Fatalf("geometry = %q", out[0].Geom)
+	}
+}
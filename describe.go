@@ -0,0 +1,598 @@
+package gobi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/zoobst/gobi/geometry"
+)
+
+// -----------------------------------------------------------------------------
+// Describe — per-column summary statistics
+//
+// Describe answers the first questions asked of a new dataset: how
+// many values each column has, how many are null, their range and
+// spread, how many distinct values there are and which are most
+// common — and, for geometry columns, their extent, geometry types,
+// invalid count and CRS.
+//
+// Every statistic is an Aggregation, and all of them run as one
+// key-less GroupBy().Agg through the streaming aggregate: a single
+// pass over the input, split across workers and merged at EOF (see
+// the key-less section of exec_aggregate_parallel.go). On a LazyFrame
+// that means a parquet scan streams through Describe in bounded
+// memory, with projection pushdown reading only the described
+// columns. The defaults keep memory bounded per column (t-digest
+// quantiles, HyperLogLog distinct counts, a capped top-value table);
+// DescribeOptions.Exact trades that for exact answers.
+// -----------------------------------------------------------------------------
+
+// DescribeOptions tunes Frame.Describe and LazyFrame.Describe. A nil
+// *DescribeOptions uses the defaults.
+type DescribeOptions struct {
+	// Columns limits the summary to these columns, in this order.
+	// Empty describes every column. An unknown name returns
+	// ErrColumnNotFound.
+	Columns []string
+
+	// Quantiles are the percentiles reported for numeric columns,
+	// each as a Float64 column named like AggQuantile's ("p25").
+	// Nil uses 0.25, 0.5 and 0.75; an empty non-nil slice reports
+	// none.
+	Quantiles []float64
+
+	// Exact computes quantiles and distinct counts exactly
+	// (AggQuantile, AggCountDistinct) and counts every distinct
+	// value for top. Memory is then O(rows) for quantiles and
+	// O(distinct values) for the rest. The default uses
+	// AggApproxQuantile, AggApproxNUnique and a top-value table
+	// capped at describeTopCapacity entries. Once a column overflows
+	// that table its top counts are lower bounds and print as
+	// "US (>=120)"; a column that never overflowed prints exact
+	// counts either way.
+	Exact bool
+
+	// TopN is how many of the most frequent values top lists. Zero
+	// or negative uses 3.
+	TopN int
+}
+
+// defaultDescribeQuantiles are the percentiles Describe reports when
+// DescribeOptions.Quantiles is nil — pandas' describe() set.
+var defaultDescribeQuantiles = []float64{0.25, 0.5, 0.75}
+
+const defaultDescribeTopN = 3
+
+// Describe returns one row of summary statistics per column of f:
+//
+//	column, dtype        name and Arrow type ("geometry" for WKB columns)
+//	count, null_count    non-null and null rows
+//	mean, std, min,
+//	p25, p50, p75, max   numeric (integer / float) columns; Float64
+//	n_unique             distinct non-null values, for hashable columns
+//	top                  most frequent values with their counts, as
+//	                     "US (120), CA (40), MX (7)", for String,
+//	                     categorical and Boolean columns; ">=" marks
+//	                     counts that are lower bounds (see Exact)
+//
+// When any described column is a geometry column, six more follow,
+// null for non-geometry rows:
+//
+//	geom_types           geometry.TypeString histogram, "Polygon (90), MultiPolygon (3)"
+//	invalid              rows geometry.IsValid rejects
+//	min_x, min_y,
+//	max_x, max_y         extent of every geometry, in the column's CRS
+//	crs                  the column's CRS ("EPSG:4326"), null when unset
+//
+// Statistics a column's type doesn't support are null. Quantiles,
+// distinct counts and top are approximate unless opts.Exact is set —
+// see DescribeOptions. A geometry column holding undecodable WKB
+// returns the parse error.
+//
+// Describe is LazyFrame.Describe over f.Lazy().
+func (f *Frame) Describe(opts *DescribeOptions) (*Frame, error) {
+	if f == nil {
+		return nil, fmt.Errorf("gobi: Frame.Describe on nil frame")
+	}
+	return f.Lazy().Describe(opts)
+}
+
+// Describe executes lf and summarizes its output columns exactly as
+// Frame.Describe does, without materializing lf: the statistics are
+// aggregations of one streaming GroupBy().Agg over the plan, so a
+// ScanFile source is read once, batch by batch, and only the
+// described columns are read.
+func (lf *LazyFrame) Describe(opts *DescribeOptions) (*Frame, error) {
+	var o DescribeOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Quantiles == nil {
+		o.Quantiles = defaultDescribeQuantiles
+	}
+	for _, q := range o.Quantiles {
+		if err := checkQuantile(q); err != nil {
+			return nil, fmt.Errorf("gobi: Describe: %w", err)
+		}
+	}
+	if o.TopN <= 0 {
+		o.TopN = defaultDescribeTopN
+	}
+
+	schema := lf.Schema()
+	names := o.Columns
+	if len(names) == 0 {
+		for _, fld := range schema.Fields() {
+			names = append(names, fld.Name)
+		}
+	}
+	cols := make([]arrow.Field, len(names))
+	for i, name := range names {
+		idx := schema.FieldIndices(name)
+		if len(idx) == 0 {
+			return nil, fmt.Errorf("gobi: Describe: %w: %q", ErrColumnNotFound, name)
+		}
+		cols[i] = schema.Field(idx[0])
+		if cols[i].Type == nil {
+			return nil, fmt.Errorf("gobi: Describe: column %q has no static type", name)
+		}
+	}
+
+	d := &describePlan{opts: o, cols: cols}
+	summary, err := lf.GroupBy().Agg(d.aggregations()...).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("gobi: Describe: %w", err)
+	}
+	return d.assemble(summary)
+}
+
+// describePlan maps the described columns to their aggregations and
+// back. Each aggregation's alias is "<column index>/<stat>", so
+// column names never collide with each other or with a stat name.
+type describePlan struct {
+	opts DescribeOptions
+	cols []arrow.Field
+}
+
+func describeAlias(i int, stat string) string { return strconv.Itoa(i) + "/" + stat }
+
+// describeNumeric, describeTop: which statistics a column type gets.
+// n_unique follows isHashable and the geometry set isGeometryField.
+func describeNumeric(f arrow.Field) bool {
+	return isNumericType(f.Type) && !isGeometryField(f)
+}
+
+func describeTop(f arrow.Field) bool {
+	switch f.Type.ID() {
+	case arrow.STRING, arrow.LARGE_STRING, arrow.BOOL:
+		return true
+	}
+	return isCategoricalType(f.Type)
+}
+
+func (d *describePlan) aggregations() []Aggregation {
+	aggs := []Aggregation{{Kind: AggCount, Alias: "rows"}}
+	add := func(i int, stat string, a Aggregation) {
+		a.Column, a.Alias = d.cols[i].Name, describeAlias(i, stat)
+		aggs = append(aggs, a)
+	}
+	for i, f := range d.cols {
+		add(i, "count", Aggregation{Kind: AggCount})
+		if describeNumeric(f) {
+			add(i, "mean", Aggregation{Kind: AggMean})
+			add(i, "std", Aggregation{Kind: AggStd})
+			add(i, "min", Aggregation{Kind: AggMin})
+			for _, q := range d.opts.Quantiles {
+				fn := AggApproxQuantile(q)
+				if d.opts.Exact {
+					fn = AggQuantile(q, QuantileLinear)
+				}
+				add(i, quantileName(q), Aggregation{Fn: fn})
+			}
+			add(i, "max", Aggregation{Kind: AggMax})
+		}
+		if isHashable(f.Type) {
+			fn := AggApproxNUnique()
+			if d.opts.Exact {
+				fn = AggCountDistinct()
+			}
+			add(i, "n_unique", Aggregation{Fn: fn})
+		}
+		if describeTop(f) {
+			limit := describeTopCapacity
+			if d.opts.Exact {
+				limit = 0
+			}
+			add(i, "top", Aggregation{Fn: &topValuesAgg{n: d.opts.TopN, limit: limit}})
+		}
+		if isGeometryField(f) {
+			add(i, "geom_types", Aggregation{Fn: &geomTypesAgg{}})
+			add(i, "invalid", Aggregation{Fn: &geomInvalidAgg{}})
+			add(i, "extent", Aggregation{Fn: AggGeomExtent()})
+		}
+	}
+	return aggs
+}
+
+// assemble turns the one-row aggregate (zero rows when the input was
+// empty) into the one-row-per-column summary.
+func (d *describePlan) assemble(summary *Frame) (*Frame, error) {
+	str, i64, f64 := arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64, arrow.PrimitiveTypes.Float64
+	fields := []arrow.Field{
+		{Name: "column", Type: str},
+		{Name: "dtype", Type: str},
+		{Name: "count", Type: i64},
+		{Name: "null_count", Type: i64},
+		{Name: "mean", Type: f64, Nullable: true},
+		{Name: "std", Type: f64, Nullable: true},
+		{Name: "min", Type: f64, Nullable: true},
+	}
+	for _, q := range d.opts.Quantiles {
+		fields = append(fields, arrow.Field{Name: quantileName(q), Type: f64, Nullable: true})
+	}
+	fields = append(fields,
+		arrow.Field{Name: "max", Type: f64, Nullable: true},
+		arrow.Field{Name: "n_unique", Type: i64, Nullable: true},
+		arrow.Field{Name: "top", Type: str, Nullable: true},
+	)
+	if slices.ContainsFunc(d.cols, isGeometryField) {
+		fields = append(fields,
+			arrow.Field{Name: "geom_types", Type: str, Nullable: true},
+			arrow.Field{Name: "invalid", Type: i64, Nullable: true},
+			arrow.Field{Name: "min_x", Type: f64, Nullable: true},
+			arrow.Field{Name: "min_y", Type: f64, Nullable: true},
+			arrow.Field{Name: "max_x", Type: f64, Nullable: true},
+			arrow.Field{Name: "max_y", Type: f64, Nullable: true},
+			arrow.Field{Name: "crs", Type: str, Nullable: true},
+		)
+	}
+
+	// stat reads one aggregate output, nil when the column didn't get
+	// that statistic or the input was empty.
+	stat := func(name string) (any, error) {
+		s, err := summary.Column(name)
+		if err != nil || s.Len() == 0 {
+			return nil, nil
+		}
+		return readScalarAt(s, 0)
+	}
+	rows, err := stat("rows")
+	if err != nil {
+		return nil, err
+	}
+	nRows, _ := rows.(int64)
+
+	out := make([]map[string]any, len(d.cols))
+	for i, f := range d.cols {
+		vals := map[string]any{"column": f.Name, "dtype": f.Type.String()}
+		if isGeometryField(f) {
+			vals["dtype"] = "geometry"
+		}
+		for _, fld := range fields[2:] {
+			v, err := stat(describeAlias(i, fld.Name))
+			if err != nil {
+				return nil, err
+			}
+			vals[fld.Name] = v
+		}
+		count, _ := vals["count"].(int64)
+		vals["count"], vals["null_count"] = count, nRows-count
+		if isHashable(f.Type) && vals["n_unique"] == nil {
+			vals["n_unique"] = int64(0)
+		}
+		if isGeometryField(f) {
+			if err := describeExtent(vals, summary, i); err != nil {
+				return nil, err
+			}
+			if epsg := geometryCRSFromField(f); epsg != 0 {
+				vals["crs"] = "EPSG:" + strconv.Itoa(int(epsg))
+			}
+			if vals["invalid"] == nil {
+				vals["invalid"] = int64(0)
+			}
+		}
+		out[i] = vals
+	}
+
+	cols := make([]arrow.Column, len(fields))
+	for j, fld := range fields {
+		b, err := builderForType(memory.DefaultAllocator, fld.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, vals := range out {
+			if err := appendCustomValue(b, vals[fld.Name]); err != nil {
+				b.Release()
+				return nil, fmt.Errorf("gobi: Describe: column %s: %w", fld.Name, err)
+			}
+		}
+		a := b.NewArray()
+		b.Release()
+		chunked := arrow.NewChunked(fld.Type, []arrow.Array{a})
+		a.Release()
+		cols[j] = *arrow.NewColumn(fld, chunked)
+		chunked.Release()
+	}
+	return NewFrame(arrow.NewSchema(fields, nil), cols)
+}
+
+// describeExtent unpacks column i's AggGeomExtent envelope into the
+// min_x / min_y / max_x / max_y cells.
+func describeExtent(vals map[string]any, summary *Frame, i int) error {
+	s, err := summary.Column(describeAlias(i, "extent"))
+	if err != nil || s.Len() == 0 {
+		return nil
+	}
+	wkb, err := binaryAt(s, 0)
+	if err != nil || wkb == nil {
+		return err
+	}
+	g, err := geometry.ParseWKB(wkb)
+	if err != nil {
+		return err
+	}
+	b := g.Bounds()
+	vals["min_x"], vals["min_y"], vals["max_x"], vals["max_y"] = b.MinX, b.MinY, b.MaxX, b.MaxY
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Describe's aggregators
+// -----------------------------------------------------------------------------
+
+// describeTopCapacity bounds the default top-value table. A column
+// with more distinct values than this is pruned Misra-Gries style:
+// when the table reaches twice the capacity, the (capacity+1)th
+// largest count is subtracted from every entry and entries that drop
+// to zero are removed. Any value occurring in more than 1/capacity of
+// the rows survives, and a surviving count is low by at most the
+// total subtracted — exact when the column never overflowed. A pruned
+// table can't be recounted without a second pass, so its counts are
+// printed as lower bounds instead.
+const describeTopCapacity = 4096
+
+// topValuesAgg reports a column's n most frequent non-null values as
+// "value (count), …", most frequent first, ties by value. limit is
+// the Misra-Gries capacity, 0 for exact counting; pruned records that
+// prune has lowered the counts.
+type topValuesAgg struct {
+	n, limit int
+	counts   map[string]int64
+	pruned   bool
+}
+
+func (a *topValuesAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.counts, a.pruned = nil, false
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *topValuesAgg) Update(col Series, rows []int) error {
+	if a.counts == nil {
+		a.counts = make(map[string]int64)
+	}
+	for _, row := range rows {
+		v, err := readScalarAt(col, row)
+		if err != nil {
+			return err
+		}
+		var key string
+		switch x := v.(type) {
+		case nil:
+			continue
+		case string:
+			key = x
+		case bool:
+			key = strconv.FormatBool(x)
+		default:
+			return fmt.Errorf("%w: top: %s column %q", ErrColumnTypeMismatch, col.DataType(), col.Name())
+		}
+		if _, ok := a.counts[key]; ok {
+			a.counts[key]++
+			continue
+		}
+		// The value may alias the batch's buffers; the table outlives
+		// the batch.
+		a.counts[strings.Clone(key)] = 1
+		a.prune()
+	}
+	return nil
+}
+
+// prune applies the Misra-Gries decrement once the table holds
+// 2×limit entries, amortizing its sort over limit insertions.
+func (a *topValuesAgg) prune() {
+	if a.limit == 0 || len(a.counts) < 2*a.limit {
+		return
+	}
+	cs := make([]int64, 0, len(a.counts))
+	for _, c := range a.counts {
+		cs = append(cs, c)
+	}
+	slices.SortFunc(cs, func(x, y int64) int { return cmpOrd(y, x) })
+	floor := cs[a.limit]
+	a.pruned = true
+	for k, c := range a.counts {
+		if c <= floor {
+			delete(a.counts, k)
+		} else {
+			a.counts[k] = c - floor
+		}
+	}
+}
+
+func (a *topValuesAgg) Finalize() any {
+	if len(a.counts) == 0 {
+		return nil
+	}
+	return formatValueCounts(a.counts, a.n, a.pruned)
+}
+
+func (a *topValuesAgg) Clone() IncrementalAggregator {
+	return &topValuesAgg{n: a.n, limit: a.limit}
+}
+
+func (a *topValuesAgg) Merge(other Aggregator) error {
+	o, ok := other.(*topValuesAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	if a.counts == nil {
+		a.counts = make(map[string]int64, len(o.counts))
+	}
+	for k, c := range o.counts {
+		a.counts[k] += c
+	}
+	a.pruned = a.pruned || o.pruned
+	a.prune()
+	return nil
+}
+
+func (a *topValuesAgg) Type() arrow.DataType { return arrow.BinaryTypes.String }
+func (a *topValuesAgg) Name() string         { return "top" }
+
+// formatValueCounts renders the n largest counts as "value (count)"
+// joined by ", ", largest first and ties by value; n <= 0 renders all.
+// lowerBound writes each count as ">=count".
+func formatValueCounts(counts map[string]int64, n int, lowerBound bool) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(x, y string) int {
+		if c := cmpOrd(counts[y], counts[x]); c != 0 {
+			return c
+		}
+		return strings.Compare(x, y)
+	})
+	if n > 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		if lowerBound {
+			fmt.Fprintf(&sb, "%s (>=%d)", k, counts[k])
+		} else {
+			fmt.Fprintf(&sb, "%s (%d)", k, counts[k])
+		}
+	}
+	return sb.String()
+}
+
+// eachGeometry parses every non-null WKB value in col[rows].
+func eachGeometry(col Series, rows []int, fn func(geometry.Geometry)) error {
+	if !col.IsGeometry() {
+		return fmt.Errorf("%w: column %q", ErrNotGeometry, col.Name())
+	}
+	for _, row := range rows {
+		wkb, err := binaryAt(col, row)
+		if err != nil {
+			return err
+		}
+		if wkb == nil {
+			continue
+		}
+		g, err := geometry.ParseWKB(wkb)
+		if err != nil {
+			return err
+		}
+		fn(g)
+	}
+	return nil
+}
+
+// geomTypesAgg is the geometry-type histogram: geometry.TypeString of
+// every non-null geometry, rendered like topValuesAgg with every type
+// listed.
+type geomTypesAgg struct {
+	counts map[string]int64
+}
+
+func (a *geomTypesAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.counts = nil
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomTypesAgg) Update(col Series, rows []int) error {
+	if a.counts == nil {
+		a.counts = make(map[string]int64)
+	}
+	return eachGeometry(col, rows, func(g geometry.Geometry) {
+		a.counts[geometry.TypeString(g)]++
+	})
+}
+
+func (a *geomTypesAgg) Finalize() any {
+	if len(a.counts) == 0 {
+		return nil
+	}
+	return formatValueCounts(a.counts, 0, false)
+}
+
+func (a *geomTypesAgg) Clone() IncrementalAggregator { return &geomTypesAgg{} }
+
+func (a *geomTypesAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomTypesAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	if a.counts == nil {
+		a.counts = make(map[string]int64, len(o.counts))
+	}
+	for k, c := range o.counts {
+		a.counts[k] += c
+	}
+	return nil
+}
+
+func (a *geomTypesAgg) Type() arrow.DataType { return arrow.BinaryTypes.String }
+func (a *geomTypesAgg) Name() string         { return "geom_types" }
+
+// geomInvalidAgg counts the non-null geometries geometry.IsValid
+// rejects.
+type geomInvalidAgg struct {
+	n int64
+}
+
+func (a *geomInvalidAgg) Aggregate(s Series, rows []int) (any, error) {
+	a.n = 0
+	if err := a.Update(s, rows); err != nil {
+		return nil, err
+	}
+	return a.Finalize(), nil
+}
+
+func (a *geomInvalidAgg) Update(col Series, rows []int) error {
+	return eachGeometry(col, rows, func(g geometry.Geometry) {
+		if !geometry.IsValid(g) {
+			a.n++
+		}
+	})
+}
+
+func (a *geomInvalidAgg) Finalize() any                { return a.n }
+func (a *geomInvalidAgg) Clone() IncrementalAggregator { return &geomInvalidAgg{} }
+
+func (a *geomInvalidAgg) Merge(other Aggregator) error {
+	o, ok := other.(*geomInvalidAgg)
+	if !ok {
+		return fmt.Errorf("%s.Merge: peer is %T", a.Name(), other)
+	}
+	a.n += o.n
+	return nil
+}
+
+func (a *geomInvalidAgg) Type() arrow.DataType { return arrow.PrimitiveTypes.Int64 }
+func (a *geomInvalidAgg) Name() string         { return "invalid" }
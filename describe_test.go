@@ -0,0 +1,226 @@
+package gobi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// describeFrame is 6 rows: an Int64 with one null, a Float64, a
+// String with one null and a Bool.
+func describeFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		N    *int64  `gobi:"n"`
+		X    float64 `gobi:"x"`
+		City *string `gobi:"city"`
+		Ok   bool    `gobi:"ok"`
+	}
+	i := func(v int64) *int64 { return &v }
+	s := func(v string) *string { return &v }
+	df, err := FromStructs([]row{
+		{i(1), 0.5, s("oslo"), true},
+		{i(2), 1.5, s("rome"), true},
+		{nil, 2.5, s("oslo"), false},
+		{i(4), 3.5, nil, true},
+		{i(5), 4.5, s("oslo"), true},
+		{i(6), 5.5, s("rome"), false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+func TestDescribe_Columns(t *testing.T) {
+	out, err := describeFrame(t).Describe(&DescribeOptions{Exact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"column":     {"n", "x", "city", "ok"},
+		"dtype":      {"int64", "float64", "utf8", "bool"},
+		"count":      {"5", "6", "5", "6"},
+		"null_count": {"1", "0", "1", "0"},
+		"mean":       {"3.6", "3", "null", "null"},
+		"min":        {"1", "0.5", "null", "null"},
+		"p50":        {"4", "3", "null", "null"},
+		"max":        {"6", "5.5", "null", "null"},
+		"n_unique":   {"5", "6", "2", "2"},
+		"top":        {"null", "null", "oslo (3), rome (2)", "true (4), false (2)"},
+	}
+	for col, vals := range want {
+		if got := valuesOrNull(t, out, col); !slices.Equal(got, vals) {
+			t.Errorf("%s = %v, want %v", col, got, vals)
+		}
+	}
+	if _, err := out.Column("geom_types"); err == nil {
+		t.Error("geometry statistics present without a geometry column")
+	}
+	std := floatsOrNaN(t, out, "std")
+	if math.Abs(std[1]-math.Sqrt(3.5)) > 1e-12 {
+		t.Errorf("std(x) = %v, want sqrt(3.5)", std[1])
+	}
+
+	// Default (approximate) statistics agree on a frame this small.
+	approx, err := describeFrame(t).Describe(&DescribeOptions{Columns: []string{"city", "n"}, TopN: 1, Quantiles: []float64{0.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, approx, "top"); !slices.Equal(got, []string{"oslo (3)", "null"}) {
+		t.Errorf("TopN 1: top = %v", got)
+	}
+	if got := valuesOrNull(t, approx, "n_unique"); !slices.Equal(got, []string{"2", "5"}) {
+		t.Errorf("approx n_unique = %v", got)
+	}
+	if _, err := approx.Column("p25"); err == nil {
+		t.Error("Quantiles {0.5}: p25 still present")
+	}
+
+	if _, err := describeFrame(t).Describe(&DescribeOptions{Columns: []string{"nope"}}); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("unknown column: err = %v, want ErrColumnNotFound", err)
+	}
+	if _, err := describeFrame(t).Describe(&DescribeOptions{Quantiles: []float64{1.5}}); err == nil {
+		t.Error("quantile 1.5: expected error")
+	}
+}
+
+// TestDescribe_TopOverflowIsLowerBound overflows the default top-value
+// table: the pruned counts are labelled as lower bounds, while Exact
+// still reports the true count.
+func TestDescribe_TopOverflowIsLowerBound(t *testing.T) {
+	const distinct = 3 * describeTopCapacity
+	type row struct {
+		Tag string `gobi:"tag"`
+	}
+	var rows []row
+	for i := range distinct {
+		rows = append(rows, row{Tag: "v" + strconv.Itoa(i)}, row{Tag: "hot"})
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approx, err := df.Describe(&DescribeOptions{TopN: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, approx, "top"); !strings.HasPrefix(got[0], "hot (>=") {
+		t.Errorf("pruned top = %v, want a hot (>=n) lower bound", got)
+	}
+	exact, err := df.Describe(&DescribeOptions{TopN: 1, Exact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := valuesOrNull(t, exact, "top"), "hot ("+strconv.Itoa(distinct)+")"; got[0] != want {
+		t.Errorf("exact top = %v, want %s", got, want)
+	}
+}
+
+func TestDescribe_Geometry(t *testing.T) {
+	df := parcelFrame(t)
+	bad := geometry.LineString{Points: []geometry.Point{{X: 1, Y: 1}}}
+	geoms := geomSeries(t, "geometry", int32(geometry.PseudoMercator.EPSG), []geometry.Geometry{
+		projectedSquare(0, 0, 10), bad, projectedSquare(100, 100, 10), nil, projectedSquare(40, 0, 10), nil,
+	})
+	df, err := df.WithColumn("geometry", geoms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := df.Describe(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"column":     {"region", "pop", "geometry"},
+		"dtype":      {"utf8", "int64", "geometry"},
+		"count":      {"6", "6", "4"},
+		"null_count": {"0", "0", "2"},
+		"geom_types": {"null", "null", "Polygon (3), LineString (1)"},
+		"invalid":    {"null", "null", "1"},
+		"min_x":      {"null", "null", "0"},
+		"max_y":      {"null", "null", "110"},
+		"crs":        {"null", "null", "EPSG:3857"},
+		"n_unique":   {"3", "6", "null"},
+	}
+	for col, vals := range want {
+		if got := valuesOrNull(t, out, col); !slices.Equal(got, vals) {
+			t.Errorf("%s = %v, want %v", col, got, vals)
+		}
+	}
+}
+
+func TestDescribe_LazyAndEmpty(t *testing.T) {
+	// Lazy: Describe runs after the plan's own steps.
+	lf := describeFrame(t).Lazy().Filter(Col("x").Gt(Lit(2.0)))
+	out, err := lf.Describe(&DescribeOptions{Columns: []string{"x"}, Exact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "count"); !slices.Equal(got, []string{"4"}) {
+		t.Errorf("filtered count = %v", got)
+	}
+	if got := valuesOrNull(t, out, "min"); !slices.Equal(got, []string{"2.5"}) {
+		t.Errorf("filtered min = %v", got)
+	}
+
+	empty, err := describeFrame(t).Lazy().Filter(Col("x").Gt(Lit(99.0))).Describe(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.NumRows() != 4 {
+		t.Fatalf("empty input: %d summary rows, want 4", empty.NumRows())
+	}
+	if got := valuesOrNull(t, empty, "count"); strings.Join(got, ",") != "0,0,0,0" {
+		t.Errorf("empty count = %v", got)
+	}
+	if got := valuesOrNull(t, empty, "mean"); got[0] != "null" {
+		t.Errorf("empty mean = %v", got)
+	}
+}
+
+// TestDescribe_ParallelMatchesSerial runs Describe over enough rows
+// that the key-less aggregate splits batches across workers, and
+// checks the merged statistics against a serial run.
+func TestDescribe_ParallelMatchesSerial(t *testing.T) {
+	const n = 100_000
+	type row struct {
+		V    float64 `gobi:"v"`
+		Kind string  `gobi:"kind"`
+	}
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i] = row{V: float64(i%1000) / 10, Kind: string(rune('a' + i%7))}
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(workers int) *Frame {
+		prev := MaxParallelism()
+		SetMaxParallelism(workers)
+		defer SetMaxParallelism(prev)
+		out, err := df.Describe(&DescribeOptions{Exact: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	serial, parallel := run(1), run(8)
+	for _, col := range []string{"count", "min", "p25", "p50", "max", "n_unique", "top"} {
+		if a, b := valuesOrNull(t, serial, col), valuesOrNull(t, parallel, col); !slices.Equal(a, b) {
+			t.Errorf("%s: serial %v, parallel %v", col, a, b)
+		}
+	}
+	for _, col := range []string{"mean", "std"} {
+		a, b := floatsOrNaN(t, serial, col), floatsOrNaN(t, parallel, col)
+		if math.Abs(a[0]-b[0]) > 1e-9 {
+			t.Errorf("%s: serial %v, parallel %v", col, a[0], b[0])
+		}
+	}
+}
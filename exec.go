@@ -54,8 +54,9 @@ const defaultBatchRows = 64 * 1024
 //
 // This is the terminal entry point for the streaming executor:
 // LazyFrame.Collect calls Compile + Execute. Callers that want to
-// consume batches directly (streaming ETL) should walk op.Next in a
-// loop themselves — but that's a lower-level API not yet exposed.
+// consume batches directly (streaming ETL) use LazyFrame.Stream,
+// which drives the same operator tree through streamBatches and
+// hands each batch out as it is produced.
 func Execute(ctx context.Context, op ExecOperator) (*Frame, error) {
 	defer op.Close()
 
@@ -88,6 +89,48 @@ func Execute(ctx context.Context, op ExecOperator) (*Frame, error) {
 	return concatBatchesToFrame(schema, batches)
 }
 
+// streamBatches drives op to EOF like Execute, but yields each
+// non-empty batch as its own Frame instead of gathering them. Stops
+// early — closing op — when yield returns false, ctx is cancelled or
+// an operator fails; a failure is yielded once as (nil, err). Closes
+// op unconditionally.
+//
+// Each Frame owns its column refs (batchToFrame), so the caller may
+// keep it past the next iteration; the batch itself is released here.
+func streamBatches(ctx context.Context, op ExecOperator, yield func(*Frame, error) bool) {
+	defer op.Close()
+	for {
+		if err := ctx.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+		batch, err := op.Next(ctx)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if batch == nil {
+			continue
+		}
+		if batch.NumRows() == 0 {
+			batch.Release()
+			continue
+		}
+		f, err := batchToFrame(batch)
+		batch.Release()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(f, nil) {
+			return
+		}
+	}
+}
+
 // concatBatchesToFrame stitches record batches into one Frame with a
 // single Arrow chunk per column. Concatenates via array.Concatenate.
 //
@@ -70,6 +70,17 @@ type streamingAggregateExec struct {
 	// recompute keys off the batch with their own local scratch on
 	// the receive side, so nothing key-shaped crosses the channel.
 	dispatchScratch []byte
+	// nextWorker rotates the first worker a key-less (global)
+	// aggregate's batch slices go to, so a stream of small batches
+	// still spreads across every worker. Reader-only, like
+	// dispatchScratch.
+	nextWorker int
+	// accBytes, when trackAccBytes is set, is the running total of
+	// every group's SizedAggregator accumulators as of the last
+	// consumeBatch. The memory-budgeted aggregate sets the flag; the
+	// serial path skips the bookkeeping otherwise.
+	trackAccBytes bool
+	accBytes      int64
 	// One-shot emit: buildIfNeeded produces the whole result batch,
 	// Next hands it out, subsequent Next calls return io.EOF.
 	resultBatch arrow.RecordBatch
@@ -107,6 +118,10 @@ func pickKeyMode(n *aggregateNode) keyMode {
 	case arrow.INT64, arrow.INT32, arrow.UINT64, arrow.UINT32,
 		arrow.TIMESTAMP, arrow.BOOL:
 		return keyModeInt641
+	case arrow.DICTIONARY:
+		if isCategoricalType(fs[0].Type) {
+			return keyModeCategorical1
+		}
 	}
 	return keyModeComposite
 }
@@ -143,6 +158,13 @@ const (
 	// used directly as a `map[int64]*aggGroup` key. Faster hash
 	// (word-at-a-time vs byte-loop) and no scratch allocation.
 	keyModeInt641
+	// keyModeCategorical1 is the single-column categorical path.
+	// Groups live in the composite map under the same value-based
+	// encoding, but each batch resolves a dictionary code to its
+	// group once and every other row with that code indexes a
+	// code → group slice: one hash per category per batch, not per
+	// row. The parallel consumer treats it as composite.
+	keyModeCategorical1
 )
 
 // aggGroup holds one group's key values and one accumulator per
@@ -154,6 +176,18 @@ type aggGroup struct {
 	accs    []aggAccumulator // one per Aggregation
 }
 
+// sizeBytes sums the group's accumulators that report a size (see
+// SizedAggregator); the rest count as fixed state.
+func (g *aggGroup) sizeBytes() int64 {
+	var n int64
+	for _, acc := range g.accs {
+		if s, ok := acc.(SizedAggregator); ok {
+			n += s.SizeBytes()
+		}
+	}
+	return n
+}
+
 // aggAccumulator is the streaming counterpart to the built-in Aggregation
 // Kinds. Update() consumes a batch's rows for one aggregation column;
 // Finalize() produces the group's aggregated value.
@@ -198,7 +232,11 @@ func (e *streamingAggregateExec) buildIfNeeded(ctx context.Context) error {
 	}
 	e.built = true
 
-	if e.workers > 1 {
+	// A key-less aggregate has a single group, so the hash partition
+	// has nothing to shard on: the parallel path instead hands each
+	// worker whole slices of rows and merges the per-worker partial
+	// states at EOF, which needs every accumulator to be mergeable.
+	if e.workers > 1 && (len(e.keys) > 0 || globalMergeable(e.aggs)) {
 		if err := e.buildParallel(ctx); err != nil {
 			return err
 		}
@@ -246,12 +284,18 @@ func (e *streamingAggregateExec) buildSerial(ctx context.Context) error {
 		}
 		batch.Release()
 	}
+	e.sortGroups()
+	return nil
+}
+
+// sortGroups puts the group order into key order for
+// buildResultBatch.
+func (e *streamingAggregateExec) sortGroups() {
 	if e.keyMode == keyModeInt641 {
 		sort.Slice(e.orderInt64, func(i, j int) bool { return e.orderInt64[i] < e.orderInt64[j] })
 	} else {
 		sort.Strings(e.order)
 	}
-	return nil
 }
 
 // consumeBatch buckets one batch's rows by composite key, then updates
@@ -294,17 +338,19 @@ func (e *streamingAggregateExec) consumeBatch(batch arrow.RecordBatch) error {
 		aggCols[i] = s
 	}
 
-	// Bucket rows by group pointer. Three paths depending on keyMode:
+	// Bucket rows by group pointer. Four paths depending on keyMode:
 	//   - keyModeString1: read the arrow value directly, use as
 	//     `map[string]*aggGroup` key. Skips composite encoding.
 	//   - keyModeInt641: widen the arrow value to int64, use as
 	//     `map[int64]*aggGroup` key. Skips composite encoding AND
 	//     the string-hash path — Go's runtime uses word-at-a-time
 	//     hashing on int keys.
+	//   - keyModeCategorical1: composite lookup for the first row of
+	//     each dictionary code, a slice index for the rest.
 	//   - keyModeComposite: build encoded bytes into e.keyScratch,
 	//     use string(scratch) as `map[string]*aggGroup` key.
 	//
-	// All three paths use *aggGroup as the bucket key so the per-row
+	// All four paths use *aggGroup as the bucket key so the per-row
 	// map write doesn't allocate a string (the compiler's
 	// map[string(bytes)] optimization only applies to reads).
 	buckets := make(map[*aggGroup][]int)
@@ -370,29 +416,46 @@ func (e *streamingAggregateExec) consumeBatch(batch arrow.RecordBatch) error {
 			}
 			buckets[g] = append(buckets[g], row)
 		}
+	case keyModeCategorical1:
+		offset := 0
+		for _, chunk := range keySeries[0].col.Data().Chunks() {
+			c, ok := asCatChunk(chunk)
+			if !ok {
+				return fmt.Errorf("gobi: key type %s not hashable", chunk.DataType())
+			}
+			byCode := make([]*aggGroup, c.dict.Len())
+			var nullGroup *aggGroup
+			for i := range c.Len() {
+				slot := &nullGroup
+				if c.IsValid(i) {
+					slot = &byCode[c.GetValueIndex(i)]
+				}
+				if *slot == nil {
+					g, err := e.compositeGroup(keySeries, offset+i)
+					if err != nil {
+						return err
+					}
+					*slot = g
+				}
+				buckets[*slot] = append(buckets[*slot], offset+i)
+			}
+			offset += c.Len()
+		}
 	default:
 		for row := 0; row < rows; row++ {
-			scratch, err := composeCompositeKeyInto(e.keyScratch[:0], keySeries, row)
+			g, err := e.compositeGroup(keySeries, row)
 			if err != nil {
 				return err
 			}
-			e.keyScratch = scratch
-			g, ok := e.groups[string(scratch)]
-			if !ok {
-				g, err = newAggGroup(keySeries, row, e.aggs)
-				if err != nil {
-					return err
-				}
-				ks := string(scratch)
-				e.groups[ks] = g
-				e.order = append(e.order, ks)
-			}
 			buckets[g] = append(buckets[g], row)
 		}
 	}
 
 	// Update each group's accumulators with its bucket's rows.
 	for g, groupRows := range buckets {
+		if e.trackAccBytes {
+			e.accBytes -= g.sizeBytes()
+		}
 		for i, a := range e.aggs {
 			src := aggCols[i]
 			if a.Column == "" {
@@ -406,10 +469,34 @@ func (e *streamingAggregateExec) consumeBatch(batch arrow.RecordBatch) error {
 				return err
 			}
 		}
+		if e.trackAccBytes {
+			e.accBytes += g.sizeBytes()
+		}
 	}
 	return nil
 }
 
+// compositeGroup returns row's group under the composite key
+// encoding, creating it on first touch.
+func (e *streamingAggregateExec) compositeGroup(keySeries []Series, row int) (*aggGroup, error) {
+	scratch, err := composeCompositeKeyInto(e.keyScratch[:0], keySeries, row)
+	if err != nil {
+		return nil, err
+	}
+	e.keyScratch = scratch
+	g, ok := e.groups[string(scratch)]
+	if !ok {
+		g, err = newAggGroup(keySeries, row, e.aggs)
+		if err != nil {
+			return nil, err
+		}
+		ks := string(scratch)
+		e.groups[ks] = g
+		e.order = append(e.order, ks)
+	}
+	return g, nil
+}
+
 // composeCompositeKey builds a byte-encoded composite of multi-column
 // key values for a single row. Reuses the same encoding as
 // GroupBy.rowKey so the streaming and eager engines agree on group
@@ -734,6 +821,15 @@ func (a *customIncrementalAcc) Update(col Series, rows []int) error {
 func (a *customIncrementalAcc) Finalize() any             { return a.inner.Finalize() }
 func (a *customIncrementalAcc) OutputType() arrow.DataType { return a.outType }
 
+// SizeBytes forwards to the inner aggregator when it is a
+// SizedAggregator; otherwise its state counts as fixed.
+func (a *customIncrementalAcc) SizeBytes() int64 {
+	if s, ok := a.inner.(SizedAggregator); ok {
+		return s.SizeBytes()
+	}
+	return 0
+}
+
 // buildResultBatch produces the single result RecordBatch by iterating
 // groups in sorted order and appending to per-column builders. Uses
 // the same builder types as the existing eager Agg path so the two
@@ -857,6 +953,10 @@ func readScalarAt(s Series, row int) (any, error) {
 				return a.Value(local), nil
 			case *array.Timestamp:
 				return a.Value(local), nil
+			case *array.Dictionary:
+				if c, ok := asCatChunk(a); ok {
+					return c.value(local), nil
+				}
 			}
 			return nil, fmt.Errorf("readScalarAt: unsupported type %T", chunk)
 		}
@@ -890,6 +990,20 @@ func (a *countAcc) Update(col Series, rows []int) error {
 		a.n += int64(len(rows))
 		return nil
 	}
+	if !isNumericType(col.DataType()) {
+		// Strings, geometry, categorical: only nullness matters, and
+		// numericAt would build an error per row to say so.
+		for _, row := range rows {
+			null, err := isNullAtSeries(col, row)
+			if err != nil {
+				return err
+			}
+			if !null {
+				a.n++
+			}
+		}
+		return nil
+	}
 	for _, row := range rows {
 		_, ok, err := col.numericAt(row)
 		if err != nil {
@@ -1274,8 +1388,14 @@ func (a *stdVarAcc) OutputType() arrow.DataType { return arrow.PrimitiveTypes.Fl
 type nUniqueAcc struct {
 	seen    map[string]struct{}
 	scratch []byte
+	bytes   int64 // key bytes plus mapEntryBytes per entry in seen
 }
 
+// mapEntryBytes approximates what one entry of a string-keyed Go map
+// costs beyond its key bytes: the slot, the string header and the
+// table's slack. Accumulators that grow a map charge it per insert.
+const mapEntryBytes = 48
+
 func (a *nUniqueAcc) Update(col Series, rows []int) error {
 	for _, row := range rows {
 		null, err := isNullAtSeries(col, row)
@@ -1292,6 +1412,7 @@ func (a *nUniqueAcc) Update(col Series, rows []int) error {
 		a.scratch = buf
 		if _, ok := a.seen[string(buf)]; !ok {
 			a.seen[string(buf)] = struct{}{}
+			a.bytes += int64(len(buf)) + mapEntryBytes
 		}
 	}
 	return nil
@@ -1299,6 +1420,7 @@ func (a *nUniqueAcc) Update(col Series, rows []int) error {
 
 func (a *nUniqueAcc) Finalize() any             { return int64(len(a.seen)) }
 func (a *nUniqueAcc) OutputType() arrow.DataType { return arrow.PrimitiveTypes.Int64 }
+func (a *nUniqueAcc) SizeBytes() int64           { return a.bytes + int64(cap(a.scratch)) }
 
 // medianAcc buffers every non-null numeric value in the group and
 // finalizes to the sample median (Float64). On even-sized groups
@@ -1339,6 +1461,7 @@ func (a *medianAcc) Finalize() any {
 	return (a.values[n/2-1] + a.values[n/2]) / 2
 }
 func (a *medianAcc) OutputType() arrow.DataType { return arrow.PrimitiveTypes.Float64 }
+func (a *medianAcc) SizeBytes() int64           { return 8 * int64(cap(a.values)) }
 
 // modeAcc tracks per-value counts and finalizes to the most-frequent
 // non-null value. Ties are broken by first-seen order (deterministic
@@ -1360,6 +1483,9 @@ type modeAcc struct {
 	values  []any
 	scratch []byte
 	nextIdx int64
+	// bytes is the key bytes plus the entries in counts, firstIdx
+	// and values for every distinct value seen.
+	bytes int64
 }
 
 func (a *modeAcc) Update(col Series, rows []int) error {
@@ -1388,6 +1514,7 @@ func (a *modeAcc) Update(col Series, rows []int) error {
 			a.firstIdx[key] = a.nextIdx
 			a.values = append(a.values, v)
 			a.nextIdx++
+			a.bytes += int64(len(key)) + 2*mapEntryBytes + 16
 		}
 		a.counts[key]++
 	}
@@ -1414,6 +1541,8 @@ func (a *modeAcc) Finalize() any {
 // in buildResultBatch (mode preserves the source column type).
 func (a *modeAcc) OutputType() arrow.DataType { return arrow.PrimitiveTypes.Float64 }
 
+func (a *modeAcc) SizeBytes() int64 { return a.bytes + int64(cap(a.scratch)) }
+
 // isNullAtSeries: null-check for a row without knowing the column
 // type. Used by countAcc when the source is non-numeric (e.g.
 // counting non-null string values).
@@ -25,6 +25,7 @@ func TestPickKeyMode_Int641Detection(t *testing.T) {
 		{"bool", arrow.FixedWidthTypes.Boolean, keyModeInt641},
 		{"float64", arrow.PrimitiveTypes.Float64, keyModeComposite},
 		{"string", arrow.BinaryTypes.String, keyModeString1},
+		{"categorical", Categorical, keyModeCategorical1},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
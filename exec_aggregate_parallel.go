@@ -2,6 +2,7 @@ package gobi
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
@@ -178,10 +179,19 @@ func (e *streamingAggregateExec) buildParallel(ctx context.Context) error {
 	e.groups = make(map[string]*aggGroup, total)
 	e.order = make([]string, 0, total)
 	for i := range workerGroups {
-		for k, g := range workerGroups[i] {
+		for _, k := range workerOrder[i] {
+			g := workerGroups[i][k]
+			// Only a key-less aggregate sees the same key on two
+			// workers: its one group was split across all of them.
+			if prev, ok := e.groups[k]; ok {
+				if err := mergeAggGroup(prev, g); err != nil {
+					return err
+				}
+				continue
+			}
 			e.groups[k] = g
+			e.order = append(e.order, k)
 		}
-		e.order = append(e.order, workerOrder[i]...)
 	}
 	sort.Strings(e.order)
 	return nil
@@ -190,6 +200,8 @@ func (e *streamingAggregateExec) buildParallel(ctx context.Context) error {
 // dispatchBatch partitions one batch's rows across worker inboxes by
 // hash(compositeKey) mod N, retains the batch once per recipient,
 // and sends the resulting messages. Returns early on ctx cancel.
+// A key-less aggregate has no key to hash; its rows are dealt out in
+// contiguous slices instead (see globalSliceRows).
 //
 // The reader uses e.dispatchScratch to build each row's composite
 // key without allocating (single-goroutine reuse — no race). The
@@ -222,8 +234,23 @@ func (e *streamingAggregateExec) dispatchBatch(ctx context.Context, batch arrow.
 		partRows[i] = make([]int, 0, guess)
 	}
 
-	switch e.keyMode {
-	case keyModeString1:
+	switch {
+	case len(e.keys) == 0:
+		// Key-less aggregate: every row is in the one group, so split
+		// the batch into contiguous slices — at least globalSliceRows
+		// rows each — and deal them out starting at e.nextWorker.
+		// buildIfNeeded only takes this path when the accumulators
+		// can merge the per-worker partials afterwards.
+		slices := min(workers, max(1, nRows/globalSliceRows))
+		for i := range slices {
+			w := (e.nextWorker + i) % workers
+			lo, hi := i*nRows/slices, (i+1)*nRows/slices
+			for row := lo; row < hi; row++ {
+				partRows[w] = append(partRows[w], row)
+			}
+		}
+		e.nextWorker = (e.nextWorker + slices) % workers
+	case e.keyMode == keyModeString1:
 		// Fast path: hash arrow string bytes directly, no scratch,
 		// no tag byte. Uses the same fnvHashBytes function as the
 		// composite path via an unsafe []byte view of the string —
@@ -238,7 +265,7 @@ func (e *streamingAggregateExec) dispatchBatch(ctx context.Context, batch arrow.
 			w := int(fnvHashString1(s) % uint64(workers))
 			partRows[w] = append(partRows[w], row)
 		}
-	case keyModeInt641:
+	case e.keyMode == keyModeInt641:
 		// Fast path: widen arrow value to int64, splatter through
 		// FNV so ints with a tiny value range still spread across
 		// workers. (A raw `key mod N` would collide all consecutive
@@ -483,3 +510,117 @@ func fnvHashInt64(k int64) uint64 {
 	return h
 }
 
+// -----------------------------------------------------------------------------
+// Key-less (global) aggregates
+//
+// GroupBy() with no keys reduces the whole input to one row —
+// Frame.Describe is built on it. Hash partitioning would route every
+// row to a single worker, so dispatchBatch deals contiguous row
+// slices round-robin instead, each worker grows its own partial copy
+// of the one group, and the merge step folds the partials together
+// with mergeableAcc. Accumulators whose answer depends on row order
+// (AggFirst / AggLast, AggMode's first-seen tie-break) don't merge;
+// an aggregate using one keeps the serial build.
+// -----------------------------------------------------------------------------
+
+// globalSliceRows is the smallest row slice dispatchBatch hands one
+// worker for a key-less aggregate. Below it the channel round-trip
+// costs more than the accumulator work it parallelizes.
+const globalSliceRows = 16 << 10
+
+// mergeableAcc is implemented by accumulators whose partial state,
+// built over a disjoint slice of a group's rows, folds into another
+// partial of the same aggregation.
+type mergeableAcc interface {
+	merge(other aggAccumulator) error
+}
+
+// globalMergeable reports whether every aggregation's accumulator
+// implements mergeableAcc — the precondition for splitting a key-less
+// aggregate across workers.
+func globalMergeable(aggs []Aggregation) bool {
+	for _, a := range aggs {
+		acc, err := newAccumulator(a)
+		if err != nil {
+			return false
+		}
+		if _, ok := acc.(mergeableAcc); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeAggGroup folds src's accumulators into dst's, aggregation by
+// aggregation.
+func mergeAggGroup(dst, src *aggGroup) error {
+	for i, acc := range dst.accs {
+		m, ok := acc.(mergeableAcc)
+		if !ok {
+			return fmt.Errorf("gobi: streaming aggregate: %T cannot merge partial groups", acc)
+		}
+		if err := m.merge(src.accs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *countAcc) merge(other aggAccumulator) error {
+	a.n += other.(*countAcc).n
+	return nil
+}
+
+func (a *sumAcc) merge(other aggAccumulator) error {
+	o := other.(*sumAcc)
+	a.sum += o.sum
+	a.seen = a.seen || o.seen
+	return nil
+}
+
+func (a *meanAcc) merge(other aggAccumulator) error {
+	o := other.(*meanAcc)
+	a.sum += o.sum
+	a.n += o.n
+	return nil
+}
+
+func (a *minMaxAcc) merge(other aggAccumulator) error {
+	if o := other.(*minMaxAcc); o.seen {
+		a.update(o.extreme)
+	}
+	return nil
+}
+
+// merge combines two Welford states with Chan et al.'s pairwise
+// update, so the merged variance is as stable as a single pass.
+func (a *stdVarAcc) merge(other aggAccumulator) error {
+	o := other.(*stdVarAcc)
+	if o.n == 0 {
+		return nil
+	}
+	n := a.n + o.n
+	delta := o.mean - a.mean
+	a.mean += delta * float64(o.n) / float64(n)
+	a.m2 += o.m2 + delta*delta*float64(a.n)*float64(o.n)/float64(n)
+	a.n = n
+	return nil
+}
+
+func (a *nUniqueAcc) merge(other aggAccumulator) error {
+	for k := range other.(*nUniqueAcc).seen {
+		a.seen[k] = struct{}{}
+	}
+	return nil
+}
+
+func (a *medianAcc) merge(other aggAccumulator) error {
+	a.values = append(a.values, other.(*medianAcc).values...)
+	return nil
+}
+
+// merge defers to the user's IncrementalAggregator.Merge — the
+// contract that method was declared for.
+func (a *customIncrementalAcc) merge(other aggAccumulator) error {
+	return a.inner.Merge(other.(*customIncrementalAcc).inner)
+}
@@ -2,6 +2,7 @@ package gobi
 
 import (
 	"context"
+	"math"
 	"strings"
 	"testing"
 
@@ -87,6 +88,60 @@ func TestParallelAggregate_FewerKeysThanWorkers(t *testing.T) {
 	}
 }
 
+// TestParallelAggregate_KeylessMergesPartials covers GroupBy() with
+// no keys: rows are dealt to workers in slices and the per-worker
+// partial groups merged, so the one output row must match the serial
+// build. AggFirst can't merge and keeps the serial build.
+func TestParallelAggregate_KeylessMergesPartials(t *testing.T) {
+	type row struct {
+		V float64 `gobi:"v"`
+	}
+	rows := make([]row, 3*globalSliceRows+17)
+	for i := range rows {
+		rows[i].V = float64(i%97) - 40
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildAgg := func(extra ...Aggregation) *aggregateNode {
+		aggs := append([]Aggregation{
+			{Column: "v", Kind: AggSum},
+			{Column: "v", Kind: AggMin, Alias: "lo"},
+			{Column: "v", Kind: AggMax, Alias: "hi"},
+			{Column: "v", Kind: AggVar, Alias: "var"},
+			{Column: "v", Kind: AggNUnique, Alias: "distinct"},
+			{Kind: AggCount, Alias: "n"},
+		}, extra...)
+		return df.Lazy().GroupBy().Agg(aggs...).Plan().(*aggregateNode)
+	}
+	if !globalMergeable(buildAgg().aggs) || globalMergeable(buildAgg(Aggregation{Column: "v", Kind: AggFirst}).aggs) {
+		t.Fatal("globalMergeable: want true for the built-ins, false with AggFirst")
+	}
+
+	serial := runAggWithWorkers(t, buildAgg(), 1)
+	for _, w := range []int{2, 8} {
+		got := runAggWithWorkers(t, buildAgg(), w)
+		if got.NumRows() != 1 {
+			t.Fatalf("workers=%d: %d rows, want 1", w, got.NumRows())
+		}
+		for _, col := range []string{"lo", "hi", "distinct", "n"} {
+			compareSeriesValues(t, col, mustColumn(t, got, col), mustColumn(t, serial, col))
+		}
+		for _, col := range []string{"v_sum", "var"} {
+			a, b := floatsOrNaN(t, got, col)[0], floatsOrNaN(t, serial, col)[0]
+			if math.Abs(a-b) > 1e-9*math.Max(1, math.Abs(b)) {
+				t.Errorf("workers=%d %s = %v, serial %v", w, col, a, b)
+			}
+		}
+	}
+
+	first := runAggWithWorkers(t, buildAgg(Aggregation{Column: "v", Kind: AggFirst, Alias: "first"}), 8)
+	if got := floatsOrNaN(t, first, "first")[0]; got != -40 {
+		t.Errorf("AggFirst under workers=8 = %v, want -40", got)
+	}
+}
+
 // TestParallelAggregate_CancellationStopsWorkers verifies that a
 // cancelled parent context propagates through the parallel build and
 // returns an error without deadlocking. -race enforces the goroutine
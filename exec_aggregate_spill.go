@@ -0,0 +1,432 @@
+package gobi
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// spillingAggregateExec is the keyed streaming aggregate under a
+// memory budget — a hybrid hash aggregate.
+//
+// It aggregates in memory with the serial streamingAggregateExec
+// until the group table's estimated size passes the budget. From then
+// on the table is frozen: rows whose key already has a group keep
+// updating it in memory, and rows of any other key are hash-
+// partitioned to spillPartitions files on disk. A key's rows
+// therefore all land in exactly one place — the frozen table or one
+// partition — so no group has to be combined afterwards. At EOF the
+// in-memory groups are written out as one sorted run, then each
+// partition is aggregated on its own (spilling again, one level
+// deeper, if it is still too big) into another, and the runs are
+// k-way merged. Each run is in the aggregate's key order and no key
+// is in two runs, so the output is in exactly the order the
+// unbudgeted aggregate emits.
+//
+// The table's size is estimated as a fixed cost per group and per
+// accumulator, plus the group's key bytes, plus whatever its
+// accumulators report through SizedAggregator — the growing buffers
+// of Median, Mode and NUnique, and the sets and sketches of
+// AggCountDistinct, AggApproxNUnique and AggApproxQuantile.
+type spillingAggregateExec struct {
+	input     ExecOperator
+	keys      []string
+	aggs      []Aggregation
+	outSchema *arrow.Schema
+	keyMode   keyMode
+	spill     spillConfig
+	depth     int
+
+	started  bool
+	mem      *streamingAggregateExec
+	spilling bool
+	parts    []*spillFile
+	router   spillPartitioner
+	scratch  []byte
+	// groupBytes is the fixed and key part of the table's size; the
+	// accumulators' part is mem.accBytes.
+	groupBytes int64
+
+	// Emit state: the in-memory result when nothing spilled, the
+	// merge of the sorted runs when something did.
+	pending arrow.RecordBatch
+	runs    []*spillFile
+	merge   *runMerger
+	closed  bool
+}
+
+// aggGroupBytes estimates a new group's in-memory footprint outside
+// its accumulators' reported state: the map entry and group struct,
+// the map key's bytes (mapKey is 0 for int64 keys), a boxed scalar
+// per key plus the bytes of string key values, and a fixed cost per
+// accumulator.
+func aggGroupBytes(g *aggGroup, mapKey int) int64 {
+	n := 128 + int64(mapKey) + 48*int64(len(g.keyVals)) + 64*int64(len(g.accs))
+	for _, v := range g.keyVals {
+		if s, ok := v.(string); ok {
+			n += int64(len(s))
+		}
+	}
+	return n
+}
+
+func (e *spillingAggregateExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *spillingAggregateExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if !e.started {
+		e.started = true
+		if err := e.build(ctx); err != nil {
+			return nil, err
+		}
+		if e.spilling {
+			if err := e.mergeRuns(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if e.merge != nil {
+		return e.merge.next(ctx)
+	}
+	if e.pending != nil {
+		out := e.pending
+		e.pending = nil
+		return out, nil
+	}
+	return nil, io.EOF
+}
+
+// mergeRuns turns the in-memory result and every aggregated partition
+// into a sorted run, then starts the merge over them.
+func (e *spillingAggregateExec) mergeRuns(ctx context.Context) error {
+	if e.pending != nil {
+		run, err := createSpillFile(e.spill.dir, e.outSchema)
+		if err != nil {
+			return err
+		}
+		e.runs = append(e.runs, run)
+		err = run.write(e.pending)
+		e.pending.Release()
+		e.pending = nil
+		if err != nil {
+			return err
+		}
+	}
+	for i := range e.parts {
+		if e.parts[i] == nil {
+			continue
+		}
+		src, err := openSpillPartition(e.parts, i, e.input.Schema())
+		if err != nil {
+			return err
+		}
+		agg := newAggregateExec(src, e.keys, e.aggs, e.outSchema, e.keyMode, e.spill.child(e.depth), e.depth+1)
+		err = e.spillAggregated(ctx, agg)
+		if cerr := agg.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	runs := e.runs
+	e.runs = nil
+	m, err := newRankedRunMerger(ctx, e.outSchema, e.rankGroups, runs)
+	if err != nil {
+		return err
+	}
+	e.merge = m
+	return nil
+}
+
+// spillAggregated drains agg, whose output is one sorted run, into a
+// new run file.
+func (e *spillingAggregateExec) spillAggregated(ctx context.Context, agg ExecOperator) error {
+	run, err := createSpillFile(e.spill.dir, e.outSchema)
+	if err != nil {
+		return err
+	}
+	e.runs = append(e.runs, run)
+	for {
+		batch, err := agg.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = run.write(batch)
+		batch.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rankGroups maps each output row of f to a string whose byte order is
+// streamingAggregateExec's group order: numeric for a single Int64
+// key (sign bit flipped so big-endian bytes sort like the ints),
+// the value itself for a single string key, the composite key
+// encoding otherwise.
+func (e *spillingAggregateExec) rankGroups(f *Frame) ([]string, error) {
+	keySeries := make([]Series, len(e.keys))
+	for i, k := range e.keys {
+		var err error
+		if keySeries[i], err = f.Column(k); err != nil {
+			return nil, err
+		}
+	}
+	n := f.NumRows()
+	ranks := make([]string, n)
+	switch e.keyMode {
+	case keyModeInt641:
+		ints, err := resolveIntArray(keySeries[0])
+		if err != nil {
+			return nil, err
+		}
+		var buf []byte
+		for row := range n {
+			v, _ := ints.value(row)
+			buf = appendI64BE(buf[:0], v^math.MinInt64)
+			ranks[row] = string(buf)
+		}
+	case keyModeString1:
+		strs, err := resolveStringArray(keySeries[0])
+		if err != nil {
+			return nil, err
+		}
+		for row := range n {
+			ranks[row] = strs.value(row)
+		}
+	default:
+		var buf []byte
+		for row := range n {
+			var err error
+			if buf, err = composeCompositeKeyInto(buf[:0], keySeries, row); err != nil {
+				return nil, err
+			}
+			ranks[row] = string(buf)
+		}
+	}
+	return ranks, nil
+}
+
+// newAggregateExec is the keyed aggregate for a spilled partition:
+// spilling again while the budget allows, serial in memory once the
+// recursion bottoms out.
+func newAggregateExec(input ExecOperator, keys []string, aggs []Aggregation, outSchema *arrow.Schema, mode keyMode, spill spillConfig, depth int) ExecOperator {
+	if !spill.enabled() {
+		return &streamingAggregateExec{
+			input:     input,
+			keys:      keys,
+			aggs:      aggs,
+			outSchema: outSchema,
+			workers:   1,
+			keyMode:   mode,
+		}
+	}
+	return &spillingAggregateExec{
+		input:     input,
+		keys:      keys,
+		aggs:      aggs,
+		outSchema: outSchema,
+		keyMode:   mode,
+		spill:     spill,
+		depth:     depth,
+	}
+}
+
+// build drains the input into the in-memory table and the partition
+// files, then produces the in-memory result batch.
+func (e *spillingAggregateExec) build(ctx context.Context) error {
+	e.mem = &streamingAggregateExec{
+		keys:      e.keys,
+		aggs:      e.aggs,
+		outSchema: e.outSchema,
+		workers:   1,
+		keyMode:   e.keyMode,
+
+		trackAccBytes: true,
+	}
+	if e.keyMode == keyModeInt641 {
+		e.mem.groupsInt64 = make(map[int64]*aggGroup)
+	} else {
+		e.mem.groups = make(map[string]*aggGroup)
+	}
+	e.parts = make([]*spillFile, spillPartitions)
+	e.router = newSpillPartitioner()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := e.input.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if e.spilling {
+			err = e.route(batch)
+		} else {
+			err = e.consumeResident(batch)
+		}
+		batch.Release()
+		if err != nil {
+			return err
+		}
+	}
+	e.input.Close()
+	e.mem.sortGroups()
+	rb, err := e.mem.buildResultBatch()
+	if err != nil {
+		return err
+	}
+	e.pending = rb
+	e.mem = nil
+	return nil
+}
+
+// consumeResident feeds batch to the in-memory table, charges the
+// groups it creates to groupBytes, and freezes the table once the
+// estimate passes the budget.
+func (e *spillingAggregateExec) consumeResident(batch arrow.RecordBatch) error {
+	nStr, nInt := len(e.mem.order), len(e.mem.orderInt64)
+	if err := e.mem.consumeBatch(batch); err != nil {
+		return err
+	}
+	for _, k := range e.mem.order[nStr:] {
+		e.groupBytes += aggGroupBytes(e.mem.groups[k], len(k))
+	}
+	for _, k := range e.mem.orderInt64[nInt:] {
+		e.groupBytes += aggGroupBytes(e.mem.groupsInt64[k], 0)
+	}
+	e.spilling = e.groupBytes+e.mem.accBytes > e.spill.limit
+	return nil
+}
+
+// route splits a batch between the frozen table (rows of keys it
+// already holds) and the partition files (everything else).
+func (e *spillingAggregateExec) route(batch arrow.RecordBatch) error {
+	if batch.NumRows() == 0 {
+		return nil
+	}
+	f, err := batchToFrame(batch)
+	if err != nil {
+		return err
+	}
+	defer f.Release()
+	keySeries := make([]Series, len(e.keys))
+	for i, k := range e.keys {
+		if keySeries[i], err = f.Column(k); err != nil {
+			return err
+		}
+	}
+	var (
+		strArr stringArrayView
+		intArr intArrayView
+	)
+	switch e.keyMode {
+	case keyModeString1:
+		if strArr, err = resolveStringArray(keySeries[0]); err != nil {
+			return err
+		}
+	case keyModeInt641:
+		if intArr, err = resolveIntArray(keySeries[0]); err != nil {
+			return err
+		}
+	}
+	n := f.NumRows()
+	resident := make([]int, 0, n)
+	spilled := make([]int, 0)
+	part := make([]int, 0)
+	for row := range n {
+		var ok, encoded bool
+		switch e.keyMode {
+		case keyModeString1:
+			_, ok = e.mem.groups[strArr.value(row)]
+		case keyModeInt641:
+			// Null keys stay with the table, whose consumeBatch
+			// drops them exactly as the unbudgeted aggregate does.
+			v, valid := intArr.value(row)
+			ok = !valid
+			if valid {
+				_, ok = e.mem.groupsInt64[v]
+			}
+		default:
+			if e.scratch, err = composeCompositeKeyInto(e.scratch[:0], keySeries, row); err != nil {
+				return err
+			}
+			encoded = true
+			_, ok = e.mem.groups[string(e.scratch)]
+		}
+		if ok {
+			resident = append(resident, row)
+			continue
+		}
+		if !encoded {
+			if e.scratch, err = composeCompositeKeyInto(e.scratch[:0], keySeries, row); err != nil {
+				return err
+			}
+		}
+		spilled = append(spilled, row)
+		part = append(part, e.router.partition(e.scratch))
+	}
+	if len(resident) == n {
+		return e.mem.consumeBatch(batch)
+	}
+	if len(resident) > 0 {
+		sub, err := f.take(resident)
+		if err != nil {
+			return err
+		}
+		rec := frameToBatch(sub)
+		sub.Release()
+		err = e.mem.consumeBatch(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+	if len(spilled) == n {
+		return writePartitioned(f, part, e.parts, e.spill.dir)
+	}
+	sub, err := f.take(spilled)
+	if err != nil {
+		return err
+	}
+	defer sub.Release()
+	return writePartitioned(sub, part, e.parts, e.spill.dir)
+}
+
+func (e *spillingAggregateExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.pending != nil {
+		e.pending.Release()
+		e.pending = nil
+	}
+	removeSpillFiles(e.parts)
+	e.parts = nil
+	removeSpillFiles(e.runs)
+	e.runs = nil
+	var err error
+	if e.merge != nil {
+		err = e.merge.close()
+		e.merge = nil
+	}
+	if cerr := e.input.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// canSpillAggregate reports whether an aggregate can run as
+// spillingAggregateExec: the streaming aggregate's aggregations, and
+// at least one key to partition on.
+func canSpillAggregate(n *aggregateNode) bool {
+	return len(n.keys) > 0 && allBuiltInAggs(n.aggs)
+}
@@ -28,15 +28,15 @@ import (
 // the process OOMs (per the design rule). The probe side never
 // materializes as a whole.
 type streamingJoinExec struct {
-	left, right       ExecOperator
-	leftKey, rightKey string
-	kind              JoinType
-	outSchema         *arrow.Schema
+	left, right         ExecOperator
+	leftKeys, rightKeys []string
+	kind                JoinType
+	outSchema           *arrow.Schema
 
 	built      bool
-	buildFrame *Frame              // right side, materialized on first Next
-	rightIndex map[string][]int    // right key → rows, built once and reused
-	rightKeyS  Series              // right's key column, cached for the per-batch join
+	buildFrame *Frame           // right side, materialized on first Next
+	rightIndex map[string][]int // right key → rows, built once and reused
+	rightKeyS  []Series         // right's key columns, cached for the per-batch join
 	closed     bool
 }
 
@@ -64,18 +64,18 @@ func (e *streamingJoinExec) Next(ctx context.Context) (arrow.RecordBatch, error)
 		if err != nil {
 			return nil, err
 		}
-		// Grab the probe's key column each batch (schema is the same
+		// Grab the probe's key columns each batch (schema is the same
 		// but the column arrays differ per batch); reuse the cached
 		// right index built once in buildIfNeeded so we don't rebuild
 		// the whole right-side hash table per probe batch — the
 		// original bug this exec was accidentally hitting.
-		lKey, err := probeFrame.Column(e.leftKey)
+		lKeys, _, err := resolveJoinKeys(probeFrame, e.buildFrame, e.leftKeys, e.rightKeys)
 		if err != nil {
 			probeFrame.Release()
 			return nil, err
 		}
 		joined, err := probeFrame.joinHashRightWithIndex(
-			e.buildFrame, e.leftKey, e.rightKey, lKey, e.rightKeyS, e.kind, e.rightIndex)
+			e.buildFrame, e.leftKeys, e.rightKeys, lKeys, e.rightKeyS, e.kind, e.rightIndex)
 		probeFrame.Release()
 		if err != nil {
 			return nil, err
@@ -106,12 +106,15 @@ func (e *streamingJoinExec) buildIfNeeded(ctx context.Context) error {
 	// Build the right-side hash index once here (rather than
 	// per-probe-batch inside the join loop). Big-O drops from
 	// O(right rows × probe batches) to O(right rows + probe rows).
-	rKey, err := rf.Column(e.rightKey)
-	if err != nil {
-		return err
+	rKeys := make([]Series, len(e.rightKeys))
+	for i, name := range e.rightKeys {
+		rKeys[i], err = rf.Column(name)
+		if err != nil {
+			return err
+		}
 	}
-	e.rightKeyS = rKey
-	e.rightIndex, err = buildKeyIndex(rKey, rf.NumRows())
+	e.rightKeyS = rKeys
+	e.rightIndex, err = buildKeyIndex(rKeys, rf.NumRows())
 	if err != nil {
 		return err
 	}
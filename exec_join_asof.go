@@ -0,0 +1,105 @@
+package gobi
+
+import (
+	"context"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// streamingAsofJoinExec is the streaming form of Frame.JoinAsof.
+//
+// Same shape as streamingJoinExec: the right side materializes once
+// on first Next() and is indexed (by-key buckets, each sorted by
+// time); the left side streams a batch at a time, each probed
+// against the cached index. Because an as-of join emits exactly one
+// row per left row in left order, every strategy streams — there is
+// no unmatched-right second phase like JoinRight / JoinFull need.
+//
+// Memory profile: right-side Frame + its index + one probe batch +
+// one output batch.
+type streamingAsofJoinExec struct {
+	left, right     ExecOperator
+	leftOn, rightOn string
+	by              []string
+	opts            AsofOptions
+	outSchema       *arrow.Schema
+
+	built      bool
+	buildFrame *Frame     // right side, materialized on first Next
+	index      *asofIndex // built once from buildFrame and reused
+	rightBy    []Series   // right's by columns, for output coalescing
+	closed     bool
+}
+
+func (e *streamingAsofJoinExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *streamingAsofJoinExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if err := e.buildIfNeeded(ctx); err != nil {
+		return nil, err
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		probeBatch, err := e.left.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		probeFrame, err := batchToFrame(probeBatch)
+		probeBatch.Release()
+		if err != nil {
+			return nil, err
+		}
+		if probeFrame.NumRows() == 0 {
+			probeFrame.Release()
+			continue
+		}
+		if err := validateAsof(probeFrame, e.buildFrame, e.leftOn, e.rightOn, &e.opts); err != nil {
+			probeFrame.Release()
+			return nil, err
+		}
+		joined, err := probeFrame.joinAsofWithIndex(
+			e.buildFrame, e.leftOn, e.by, e.rightBy, e.index, e.opts)
+		probeFrame.Release()
+		if err != nil {
+			return nil, err
+		}
+		out := frameToBatch(joined)
+		joined.Release()
+		return out, nil
+	}
+}
+
+func (e *streamingAsofJoinExec) buildIfNeeded(ctx context.Context) error {
+	if e.built {
+		return nil
+	}
+	e.built = true
+	rf, err := Execute(ctx, e.right)
+	if err != nil {
+		return err
+	}
+	e.buildFrame = rf
+	e.index, e.rightBy, err = buildAsofIndex(rf, e.rightOn, e.by)
+	return err
+}
+
+func (e *streamingAsofJoinExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	_ = e.left.Close()
+	if !e.built {
+		_ = e.right.Close()
+	}
+	// Drop the materialized build side (and the index pointing into
+	// it) so the arrow columns can be freed — see streamingJoinExec.
+	if e.buildFrame != nil {
+		e.buildFrame.Release()
+		e.buildFrame = nil
+	}
+	e.index = nil
+	e.rightBy = nil
+	return nil
+}
@@ -0,0 +1,253 @@
+package gobi
+
+import (
+	"context"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// graceJoinExec is the hash join under a memory budget — a grace hash
+// join.
+//
+// The right (build) side buffers until it's exhausted or its size
+// passes the budget. If it fits, the join runs exactly as without a
+// budget: the buffered batches become the build side of the usual
+// streaming join (or the materializing one for Right / Full). If it
+// doesn't, both sides are hash-partitioned on the join key into
+// spillPartitions file pairs — right first, then the whole left — and
+// the pairs are joined one at a time. Equal keys hash alike, so every
+// match is within a pair, and each pair's unmatched rows are exactly
+// the unmatched rows of the whole join; that makes the scheme correct
+// for every JoinType, Right and Full included. A pair whose build side
+// is still too big partitions again, one level deeper.
+//
+// Keys hash through joinKeyAppend — the encoding the join itself
+// matches on — so a String key and a categorical key with the same
+// values meet in the same partition. Once spilled, output comes
+// partition by partition rather than in probe order.
+type graceJoinExec struct {
+	left, right         ExecOperator
+	leftKeys, rightKeys []string
+	kind                JoinType
+	outSchema           *arrow.Schema
+	spill               spillConfig
+	depth               int
+
+	started     bool
+	partitioned bool
+	leftParts   []*spillFile
+	rightParts  []*spillFile
+	part        int
+	inner       ExecOperator
+	closed      bool
+}
+
+func (e *graceJoinExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *graceJoinExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if !e.started {
+		e.started = true
+		if err := e.start(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		if e.inner != nil {
+			batch, err := e.inner.Next(ctx)
+			if err != io.EOF {
+				return batch, err
+			}
+			err = e.inner.Close()
+			e.inner = nil
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !e.partitioned {
+			return nil, io.EOF
+		}
+		for e.part < spillPartitions && e.leftParts[e.part] == nil && e.rightParts[e.part] == nil {
+			e.part++
+		}
+		if e.part >= spillPartitions {
+			return nil, io.EOF
+		}
+		if err := e.openPartition(ctx, e.part); err != nil {
+			return nil, err
+		}
+		e.part++
+	}
+}
+
+// start buffers the build side and either hands it to an in-memory
+// join or partitions both sides to disk.
+func (e *graceJoinExec) start(ctx context.Context) error {
+	var (
+		buffered []arrow.RecordBatch
+		size     int64
+	)
+	defer func() { releaseBatches(buffered) }()
+	for size <= e.spill.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := e.right.Next(ctx)
+		if err == io.EOF {
+			e.right.Close()
+			build := &batchSliceExec{schema: e.right.Schema(), batches: buffered}
+			buffered = nil
+			inner, err := inMemoryJoinExec(ctx, e.left, build, e.leftKeys, e.rightKeys, e.kind, e.outSchema)
+			if err != nil {
+				return err
+			}
+			e.inner = inner
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		buffered = append(buffered, batch)
+		size += batchBytes(batch)
+	}
+
+	e.partitioned = true
+	e.leftParts = make([]*spillFile, spillPartitions)
+	e.rightParts = make([]*spillFile, spillPartitions)
+	router := newSpillPartitioner()
+	for _, batch := range buffered {
+		if err := e.partitionBatch(batch, e.rightKeys, e.rightParts, router); err != nil {
+			return err
+		}
+	}
+	releaseBatches(buffered)
+	buffered = nil
+	if err := e.partitionAll(ctx, e.right, e.rightKeys, e.rightParts, router); err != nil {
+		return err
+	}
+	return e.partitionAll(ctx, e.left, e.leftKeys, e.leftParts, router)
+}
+
+// partitionAll drains op into parts, then closes it.
+func (e *graceJoinExec) partitionAll(ctx context.Context, op ExecOperator, keys []string, parts []*spillFile, router spillPartitioner) error {
+	defer op.Close()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := op.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = e.partitionBatch(batch, keys, parts, router)
+		batch.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *graceJoinExec) partitionBatch(batch arrow.RecordBatch, keys []string, parts []*spillFile, router spillPartitioner) error {
+	if batch.NumRows() == 0 {
+		return nil
+	}
+	f, err := batchToFrame(batch)
+	if err != nil {
+		return err
+	}
+	defer f.Release()
+	keyCols := make([]Series, len(keys))
+	for i, k := range keys {
+		if keyCols[i], err = f.Column(k); err != nil {
+			return err
+		}
+	}
+	part := make([]int, f.NumRows())
+	var k []byte
+	for row := range part {
+		if k, err = joinKeyAppend(k[:0], keyCols, row); err != nil {
+			return err
+		}
+		part[row] = router.partition(k)
+	}
+	return writePartitioned(f, part, parts, e.spill.dir)
+}
+
+// openPartition starts the join of partition pair i.
+func (e *graceJoinExec) openPartition(ctx context.Context, i int) error {
+	left, err := openSpillPartition(e.leftParts, i, e.left.Schema())
+	if err != nil {
+		return err
+	}
+	right, err := openSpillPartition(e.rightParts, i, e.right.Schema())
+	if err != nil {
+		left.Close()
+		return err
+	}
+	if child := e.spill.child(e.depth); child.enabled() {
+		e.inner = &graceJoinExec{
+			left:      left,
+			right:     right,
+			leftKeys:  e.leftKeys,
+			rightKeys: e.rightKeys,
+			kind:      e.kind,
+			outSchema: e.outSchema,
+			spill:     child,
+			depth:     e.depth + 1,
+		}
+		return nil
+	}
+	e.inner, err = inMemoryJoinExec(ctx, left, right, e.leftKeys, e.rightKeys, e.kind, e.outSchema)
+	return err
+}
+
+func (e *graceJoinExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	removeSpillFiles(e.leftParts)
+	removeSpillFiles(e.rightParts)
+	e.leftParts, e.rightParts = nil, nil
+	var err error
+	if e.inner != nil {
+		err = e.inner.Close()
+		e.inner = nil
+	}
+	// The inputs may already be closed (drained into the build side
+	// or the partitions); a second Close is a no-op.
+	_ = e.left.Close()
+	_ = e.right.Close()
+	return err
+}
+
+// inMemoryJoinExec is the unbudgeted hash join Compile builds for a
+// joinNode: left-driven kinds stream the probe side, Right and Full
+// materialize both sides and delegate to Frame.JoinOn.
+func inMemoryJoinExec(ctx context.Context, left, right ExecOperator, leftKeys, rightKeys []string, kind JoinType, outSchema *arrow.Schema) (ExecOperator, error) {
+	if canStreamJoin(kind) {
+		return &streamingJoinExec{
+			left:      left,
+			right:     right,
+			leftKeys:  leftKeys,
+			rightKeys: rightKeys,
+			kind:      kind,
+			outSchema: outSchema,
+		}, nil
+	}
+	rightFrame, err := Execute(ctx, right)
+	if err != nil {
+		left.Close()
+		return nil, err
+	}
+	return &materializeExecOp{
+		input:     left,
+		outSchema: outSchema,
+		compute: func(f *Frame) (*Frame, error) {
+			return f.JoinOn(rightFrame, leftKeys, rightKeys, kind)
+		},
+	}, nil
+}
@@ -3,7 +3,6 @@ package gobi
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -35,9 +34,9 @@ import (
 // designed for. Users who care about probe-side streaming stay on
 // the streaming hash path by not asserting alignment claims.
 type sortMergeJoinExec struct {
-	left, right       ExecOperator
-	leftKey, rightKey string
-	outSchema         *arrow.Schema
+	left, right         ExecOperator
+	leftKeys, rightKeys []string
+	outSchema           *arrow.Schema
 
 	emitted    bool
 	closed     bool
@@ -57,7 +56,7 @@ func (e *sortMergeJoinExec) Next(ctx context.Context) (arrow.RecordBatch, error)
 		return nil, err
 	}
 
-	joined, err := mergeJoinInner(e.probeFrame, e.buildFrame, e.leftKey, e.rightKey)
+	joined, err := mergeJoinInner(e.probeFrame, e.buildFrame, e.leftKeys, e.rightKeys)
 	if err != nil {
 		return nil, err
 	}
@@ -114,32 +113,25 @@ func (e *sortMergeJoinExec) Close() error {
 }
 
 // mergeJoinInner implements the two-pointer scan for an Inner join
-// over two sorted+aligned frames. Encoded keys are compared via
-// bytes.Compare — same encoding as the hash join uses (keyOfAppend
-// with big-endian integers), so the byte order matches the numeric
-// / string order that the SortEnforced writer contract promises.
+// over two sorted+aligned frames. Each key column is encoded with
+// keyOfAppend (big-endian integers) and compared via bytes.Compare,
+// so the byte order matches the numeric / string order that the
+// SortEnforced writer contract promises.
 //
 // For each pair of contiguous same-key runs (one on each side), emits
 // the cross-product to leftIdxs / rightIdxs, then delegates to
 // Frame.buildTwoSidedOutput for the actual output materialization —
 // reusing the same output builder as the hash join so schemas +
 // column ordering stay identical.
-func mergeJoinInner(left, right *Frame, leftKey, rightKey string) (*Frame, error) {
-	lKey, err := left.Column(leftKey)
-	if err != nil {
-		return nil, err
-	}
-	rKey, err := right.Column(rightKey)
+//
+// Composite keys compare column-by-column in leftOn order (see
+// compareKeyParts) — which is why canMergeJoin insists SortedBy lists
+// the join keys as its leading columns, in the same order.
+func mergeJoinInner(left, right *Frame, leftOn, rightOn []string) (*Frame, error) {
+	lKeys, rKeys, err := resolveJoinKeys(left, right, leftOn, rightOn)
 	if err != nil {
 		return nil, err
 	}
-	if !isHashable(lKey.DataType()) {
-		return nil, fmt.Errorf("gobi: left key type %s is not hashable", lKey.DataType())
-	}
-	if lKey.DataType().ID() != rKey.DataType().ID() {
-		return nil, fmt.Errorf("%w: %s vs %s", ErrColumnTypeMismatch,
-			lKey.DataType(), rKey.DataType())
-	}
 
 	nLeft, nRight := left.NumRows(), right.NumRows()
 
@@ -150,11 +142,11 @@ func mergeJoinInner(left, right *Frame, leftKey, rightKey string) (*Frame, error
 	// Storage: 2 × N × avg-key-len bytes. For an int64 key that's
 	// ~9 bytes per row (tag + 8-byte value). 100k rows = ~1.8MB —
 	// negligible next to the input frames.
-	leftKeys, err := encodeAllKeys(lKey, nLeft)
+	leftKeys, err := encodeAllKeys(lKeys, nLeft)
 	if err != nil {
 		return nil, err
 	}
-	rightKeys, err := encodeAllKeys(rKey, nRight)
+	rightKeys, err := encodeAllKeys(rKeys, nRight)
 	if err != nil {
 		return nil, err
 	}
@@ -162,17 +154,17 @@ func mergeJoinInner(left, right *Frame, leftKey, rightKey string) (*Frame, error
 	var leftIdxs, rightIdxs []int
 	i, j := 0, 0
 	for i < nLeft && j < nRight {
-		// Skip null keys on either side (encoded as single 0x00 byte);
-		// null never matches null in Inner join semantics.
-		if isNullKey(leftKeys[i]) {
+		// Skip null keys on either side (encoded as nil); null never
+		// matches null in Inner join semantics.
+		if leftKeys[i] == nil {
 			i++
 			continue
 		}
-		if isNullKey(rightKeys[j]) {
+		if rightKeys[j] == nil {
 			j++
 			continue
 		}
-		cmp := bytes.Compare(leftKeys[i], rightKeys[j])
+		cmp := compareKeyParts(leftKeys[i], rightKeys[j])
 		switch {
 		case cmp < 0:
 			i++
@@ -181,11 +173,11 @@ func mergeJoinInner(left, right *Frame, leftKey, rightKey string) (*Frame, error
 		default:
 			// Match. Find the extent of equal-key runs on both sides.
 			iEnd := i + 1
-			for iEnd < nLeft && bytes.Equal(leftKeys[iEnd], leftKeys[i]) {
+			for iEnd < nLeft && leftKeys[iEnd] != nil && compareKeyParts(leftKeys[iEnd], leftKeys[i]) == 0 {
 				iEnd++
 			}
 			jEnd := j + 1
-			for jEnd < nRight && bytes.Equal(rightKeys[jEnd], rightKeys[j]) {
+			for jEnd < nRight && rightKeys[jEnd] != nil && compareKeyParts(rightKeys[jEnd], rightKeys[j]) == 0 {
 				jEnd++
 			}
 			// Cross-product for the run.
@@ -199,27 +191,49 @@ func mergeJoinInner(left, right *Frame, leftKey, rightKey string) (*Frame, error
 		}
 	}
 
-	return left.buildTwoSidedOutput(right, leftKey, rightKey, rKey, leftIdxs, rightIdxs)
+	return left.buildTwoSidedOutput(right, leftOn, rightOn, rKeys, leftIdxs, rightIdxs)
 }
 
-// encodeAllKeys returns per-row encoded keys for s. Same encoding
-// keyOf uses in the hash-join path — sharing the encoding keeps byte
-// comparisons consistent with the hash-lookup path (though sort-
-// merge only cares about byte order, not hash lookup).
-func encodeAllKeys(s Series, n int) ([][]byte, error) {
-	out := make([][]byte, n)
+// encodeAllKeys returns, per row, the keyOfAppend encoding of each
+// key column — the per-column form of joinKeyAppend's key, kept apart
+// so compareKeyParts can order rows column by column. A row with a
+// null in any key column is nil, mirroring joinKeyAppend's null
+// sentinel.
+func encodeAllKeys(keyCols []Series, n int) ([][][]byte, error) {
+	out := make([][][]byte, n)
 	for row := range n {
-		k, err := keyOfAppend(nil, s, row)
-		if err != nil {
-			return nil, err
+		parts := make([][]byte, len(keyCols))
+		for c, s := range keyCols {
+			k, err := keyOfAppend(nil, s, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNullKey(k) {
+				parts = nil
+				break
+			}
+			parts[c] = k
 		}
-		out[row] = k
+		out[row] = parts
 	}
 	return out, nil
 }
 
+// compareKeyParts orders two encodeAllKeys rows lexicographically by
+// column.
+func compareKeyParts(a, b [][]byte) int {
+	for c := range a {
+		if cmp := bytes.Compare(a[c], b[c]); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
 // isNullKey reports whether k is the null sentinel produced by
-// keyOfAppend for a null cell (a single 0x00 byte).
+// keyOfAppend for a null cell (a single 0x00 byte). joinKeyAppend
+// collapses any composite key with a null column to the same
+// sentinel.
 func isNullKey(k []byte) bool {
 	return len(k) == 1 && k[0] == 0x00
 }
@@ -231,8 +245,8 @@ func isNullKey(k []byte) bool {
 //   - AlignedWith holds — same HashFn + same ordered Columns on
 //     both sides (they must use the same partitioning scheme so
 //     same-key rows are colocated in the same bucket order).
-//   - Both sides claim SortedBy starting with the join key column
-//     with SortEnforced=true — hint-only sortedness could silently
+//   - Both sides claim SortedBy starting with the join key columns
+//     (in join-key order) with SortEnforced=true — hint-only sortedness could silently
 //     produce wrong results if the actual data isn't ordered.
 //
 // Any failure falls through to streamingJoinExec (the general hash
@@ -250,28 +264,33 @@ func canMergeJoin(n *joinNode) bool {
 	if !AlignedWith(lm, rm) {
 		return false
 	}
-	// Both sides must be sorted on the join key with writer-enforced
-	// order. Sort keys are single-column here — the join keys are
-	// single columns too, so require the first SortedBy element to
-	// match the respective join key.
-	if !sortedByStartsWith(lm, n.leftKey) {
+	// Both sides must be sorted on the join keys with writer-enforced
+	// order: the leading SortedBy elements must name the join keys in
+	// join-key order, so the composite byte encoding's order matches
+	// the physical row order.
+	if !sortedByStartsWith(lm, n.leftKeys) {
 		return false
 	}
-	if !sortedByStartsWith(rm, n.rightKey) {
+	if !sortedByStartsWith(rm, n.rightKeys) {
 		return false
 	}
 	return true
 }
 
 // sortedByStartsWith reports whether meta claims a writer-enforced
-// sort whose leading key matches col. Direction (ascending vs
+// sort whose leading keys match cols, in order. Direction (ascending vs
 // descending) is ignored — sort-merge works either way as long as
 // both sides use the same direction, which is enforced by the
 // AlignedWith HashFn equality check (same source == same sort
 // direction in practice).
-func sortedByStartsWith(meta *PartitionMetadata, col string) bool {
-	if meta == nil || !meta.SortEnforced || len(meta.SortedBy) == 0 {
+func sortedByStartsWith(meta *PartitionMetadata, cols []string) bool {
+	if meta == nil || !meta.SortEnforced || len(cols) == 0 || len(meta.SortedBy) < len(cols) {
 		return false
 	}
-	return meta.SortedBy[0].Column == col
+	for i, c := range cols {
+		if meta.SortedBy[i].Column != c {
+			return false
+		}
+	}
+	return true
 }
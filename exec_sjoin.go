@@ -0,0 +1,103 @@
+package gobi
+
+import (
+	"context"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// streamingSJoinExec is the streaming form of Frame.SJoin.
+//
+// Same shape as streamingJoinExec: the right side materializes once
+// on first Next() and its geometries are decoded into an R-tree; the
+// left side streams a batch at a time, each probed against the cached
+// index (sharded across workers within the batch, as in SJoin).
+// Handles the left-driven kinds only — Inner, Left, Semi, Anti. Right
+// and Full need every left row before unmatched right rows are known,
+// so Compile routes them through the materializing fallback.
+//
+// Memory profile: right-side Frame + decoded geometries + R-tree +
+// one probe batch + one output batch.
+type streamingSJoinExec struct {
+	left, right         ExecOperator
+	leftGeom, rightGeom string
+	matcher             spatialMatcher
+	kind                JoinType
+	workers             int
+	outSchema           *arrow.Schema
+
+	built      bool
+	buildFrame *Frame      // right side, materialized on first Next
+	index      *sjoinIndex // built once from buildFrame and reused
+	closed     bool
+}
+
+func (e *streamingSJoinExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *streamingSJoinExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if err := e.buildIfNeeded(ctx); err != nil {
+		return nil, err
+	}
+	// Skip probe batches that join to nothing (no matches on an inner
+	// or semi join, all matched on an anti join) — see streamingJoinExec.
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		probeBatch, err := e.left.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		probeFrame, err := batchToFrame(probeBatch)
+		probeBatch.Release()
+		if err != nil {
+			return nil, err
+		}
+		joined, err := probeFrame.sjoinWithIndex(
+			e.buildFrame, e.leftGeom, e.rightGeom, e.index, e.matcher, e.kind, e.workers)
+		probeFrame.Release()
+		if err != nil {
+			return nil, err
+		}
+		if joined.NumRows() == 0 {
+			joined.Release()
+			continue
+		}
+		out := frameToBatch(joined)
+		joined.Release()
+		return out, nil
+	}
+}
+
+func (e *streamingSJoinExec) buildIfNeeded(ctx context.Context) error {
+	if e.built {
+		return nil
+	}
+	e.built = true
+	rf, err := Execute(ctx, e.right)
+	if err != nil {
+		return err
+	}
+	e.buildFrame = rf
+	e.index, err = buildSJoinIndex(rf, e.rightGeom)
+	return err
+}
+
+func (e *streamingSJoinExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	_ = e.left.Close()
+	if !e.built {
+		_ = e.right.Close()
+	}
+	// Drop the materialized build side and the decoded geometries so
+	// they can be freed — see streamingJoinExec.
+	if e.buildFrame != nil {
+		e.buildFrame.Release()
+		e.buildFrame = nil
+	}
+	e.index = nil
+	return nil
+}
@@ -0,0 +1,490 @@
+package gobi
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// externalSortExec is Sort under a memory budget: an external merge
+// sort.
+//
+// Input batches buffer until their size passes the budget; the buffer
+// is then sorted with Frame.SortBy and written to disk as one sorted
+// run. At EOF, if nothing was spilled, the buffer is sorted and
+// emitted exactly like the materializing Sort. Otherwise the last
+// buffer becomes the final run and the runs are k-way merged through
+// a heap, one batch of each run in memory at a time.
+//
+// The merge breaks ties by run number, and runs are cut in input
+// order from stable sorts, so the result is the same stable order
+// Frame.SortBy produces. Key types are those canMergeSortKeys accepts;
+// Compile keeps the materializing Sort for the rest.
+type externalSortExec struct {
+	input     ExecOperator
+	keys      []SortKey
+	outSchema *arrow.Schema
+	spill     spillConfig
+
+	started bool
+	runs    []*spillFile
+	// In-memory result when nothing spilled.
+	sorted *Frame
+	offset int
+	// Merge state when something did.
+	merge  *runMerger
+	closed bool
+}
+
+func (e *externalSortExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *externalSortExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if !e.started {
+		e.started = true
+		if err := e.build(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if e.merge != nil {
+		return e.merge.next(ctx)
+	}
+	if e.sorted == nil || e.offset >= e.sorted.NumRows() {
+		return nil, io.EOF
+	}
+	end := min(e.offset+defaultBatchRows, e.sorted.NumRows())
+	slice := e.sorted.slice(int64(e.offset), int64(end))
+	e.offset = end
+	batch := frameToBatch(slice)
+	slice.Release()
+	return batch, nil
+}
+
+// build drains the input, cutting runs whenever the buffer passes the
+// budget, and sets up either the in-memory result or the merge.
+func (e *externalSortExec) build(ctx context.Context) error {
+	var (
+		buffered []arrow.RecordBatch
+		size     int64
+	)
+	defer func() { releaseBatches(buffered) }()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := e.input.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if batch == nil || batch.NumRows() == 0 {
+			if batch != nil {
+				batch.Release()
+			}
+			continue
+		}
+		buffered = append(buffered, batch)
+		size += batchBytes(batch)
+		if size > e.spill.limit {
+			if err := e.spillRun(buffered); err != nil {
+				return err
+			}
+			releaseBatches(buffered)
+			buffered, size = nil, 0
+		}
+	}
+	if len(e.runs) == 0 {
+		sorted, err := e.sortBatches(buffered)
+		if err != nil {
+			return err
+		}
+		e.sorted = sorted
+		return nil
+	}
+	if len(buffered) > 0 {
+		if err := e.spillRun(buffered); err != nil {
+			return err
+		}
+	}
+	runs := e.runs
+	e.runs = nil
+	m, err := newRunMerger(ctx, e.outSchema, e.keys, runs)
+	if err != nil {
+		return err
+	}
+	e.merge = m
+	return nil
+}
+
+func (e *externalSortExec) sortBatches(batches []arrow.RecordBatch) (*Frame, error) {
+	f, err := concatBatchesToFrame(e.input.Schema(), batches)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Release()
+	return f.SortBy(e.keys...)
+}
+
+// spillRun sorts batches and writes them to a new run file.
+func (e *externalSortExec) spillRun(batches []arrow.RecordBatch) error {
+	sorted, err := e.sortBatches(batches)
+	if err != nil {
+		return err
+	}
+	defer sorted.Release()
+	run, err := createSpillFile(e.spill.dir, e.input.Schema())
+	if err != nil {
+		return err
+	}
+	e.runs = append(e.runs, run)
+	return run.writeFrame(sorted)
+}
+
+func (e *externalSortExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	removeSpillFiles(e.runs)
+	e.runs = nil
+	if e.sorted != nil {
+		e.sorted.Release()
+		e.sorted = nil
+	}
+	var err error
+	if e.merge != nil {
+		err = e.merge.close()
+	}
+	if cerr := e.input.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// canMergeSortKeys reports whether rows from different batches can be
+// ordered by keys — what externalSortExec's merge and topKExec's heaps
+// need: every key must be a type mergeCompare handles — SortBy's key
+// types, except Ordered categoricals, whose rank depends on each
+// batch's own dictionary.
+func canMergeSortKeys(schema *arrow.Schema, keys []SortKey) bool {
+	for _, k := range keys {
+		idx := schema.FieldIndices(k.Column)
+		if len(idx) == 0 {
+			return false
+		}
+		switch dt := schema.Field(idx[0]).Type.(type) {
+		case *arrow.Int64Type, *arrow.Int32Type, *arrow.Uint64Type, *arrow.Uint32Type,
+			*arrow.Float64Type, *arrow.Float32Type, *arrow.BooleanType,
+			*arrow.StringType, *arrow.TimestampType:
+		case *arrow.DictionaryType:
+			if !isCategoricalType(dt) || dt.Ordered {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mergeCompare compares row i of a with row j of b — the same column
+// from two different runs — with SortBy's null-last semantics.
+func mergeCompare(a arrow.Array, i int, b arrow.Array, j int, descending bool) int {
+	ni, nj := a.IsNull(i), b.IsNull(j)
+	if ni || nj {
+		return nullAwareCompare(ni, nj, 0, descending)
+	}
+	var c int
+	switch x := a.(type) {
+	case *array.Int64:
+		c = cmpOrd(x.Value(i), b.(*array.Int64).Value(j))
+	case *array.Int32:
+		c = cmpOrd(x.Value(i), b.(*array.Int32).Value(j))
+	case *array.Uint64:
+		c = cmpOrd(x.Value(i), b.(*array.Uint64).Value(j))
+	case *array.Uint32:
+		c = cmpOrd(x.Value(i), b.(*array.Uint32).Value(j))
+	case *array.Float64:
+		c = cmpFloat(x.Value(i), b.(*array.Float64).Value(j))
+	case *array.Float32:
+		c = cmpFloat(float64(x.Value(i)), float64(b.(*array.Float32).Value(j)))
+	case *array.Boolean:
+		c = cmpBool(x.Value(i), b.(*array.Boolean).Value(j))
+	case *array.String:
+		c = cmpString(x.Value(i), b.(*array.String).Value(j))
+	case *array.Timestamp:
+		c = cmpOrd(int64(x.Value(i)), int64(b.(*array.Timestamp).Value(j)))
+	case *array.Dictionary:
+		ca, _ := asCatChunk(x)
+		cb, _ := asCatChunk(b)
+		c = cmpString(ca.value(i), cb.value(j))
+	}
+	return nullAwareCompare(false, false, c, descending)
+}
+
+// runCursor is a merge input: one run and its current batch.
+type runCursor struct {
+	run   int
+	src   ExecOperator
+	frame *Frame
+	keys  []arrow.Array
+	ranks []string // when the merger ranks rows instead of comparing keys
+	row   int
+	// slot is the cursor's frame's index in the batch being built,
+	// or -1 when the frame hasn't contributed to it yet.
+	slot int
+}
+
+// runMerger k-way merges sorted runs into batches of defaultBatchRows
+// rows.
+type runMerger struct {
+	schema *arrow.Schema
+	keys   []SortKey
+	// rank, when set, replaces keys: it maps a batch to one string
+	// per row whose byte order is the runs' order.
+	rank    func(*Frame) ([]string, error)
+	heap    cursorHeap
+	retired []*Frame // frames fully consumed while building the batch
+}
+
+func newRunMerger(ctx context.Context, schema *arrow.Schema, keys []SortKey, runs []*spillFile) (*runMerger, error) {
+	m := &runMerger{schema: schema, keys: keys}
+	m.heap.keys = keys
+	if err := m.start(ctx, runs); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// newRankedRunMerger merges runs sorted by rank rather than by sort
+// keys — for orders mergeCompare doesn't express, like the
+// aggregate's encoded-key order.
+func newRankedRunMerger(ctx context.Context, schema *arrow.Schema, rank func(*Frame) ([]string, error), runs []*spillFile) (*runMerger, error) {
+	m := &runMerger{schema: schema, rank: rank}
+	if err := m.start(ctx, runs); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// start opens every run and loads its first batch. On error the
+// merger is closed and the runs not yet opened removed.
+func (m *runMerger) start(ctx context.Context, runs []*spillFile) error {
+	for i, run := range runs {
+		src, err := run.open()
+		if err != nil {
+			removeSpillFiles(runs[i+1:])
+			m.close()
+			return err
+		}
+		c := &runCursor{run: i, src: src, slot: -1}
+		ok, err := m.load(ctx, c)
+		if err != nil {
+			removeSpillFiles(runs[i+1:])
+			src.Close()
+			m.close()
+			return err
+		}
+		if !ok {
+			src.Close()
+			continue
+		}
+		m.heap.cursors = append(m.heap.cursors, c)
+	}
+	heap.Init(&m.heap)
+	return nil
+}
+
+// load moves c to its run's next non-empty batch; false at the end
+// of the run.
+func (m *runMerger) load(ctx context.Context, c *runCursor) (bool, error) {
+	for {
+		batch, err := c.src.Next(ctx)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if batch.NumRows() == 0 {
+			batch.Release()
+			continue
+		}
+		f, err := batchToFrame(batch)
+		batch.Release()
+		if err != nil {
+			return false, err
+		}
+		if m.rank != nil {
+			ranks, err := m.rank(f)
+			if err != nil {
+				f.Release()
+				return false, err
+			}
+			c.frame, c.ranks, c.row, c.slot = f, ranks, 0, -1
+			return true, nil
+		}
+		keys := make([]arrow.Array, len(m.keys))
+		for i, k := range m.keys {
+			s, err := f.Column(k.Column)
+			if err != nil {
+				f.Release()
+				return false, err
+			}
+			keys[i] = s.col.Data().Chunk(0)
+		}
+		c.frame, c.keys, c.row, c.slot = f, keys, 0, -1
+		return true, nil
+	}
+}
+
+func (m *runMerger) next(ctx context.Context) (arrow.RecordBatch, error) {
+	if len(m.heap.cursors) == 0 {
+		return nil, io.EOF
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var (
+		frames []*Frame
+		rows   [][]int
+		// picks[i] is output row i's (frame slot, position in that
+		// slot's rows).
+		picks [][2]int
+	)
+	for len(picks) < defaultBatchRows && len(m.heap.cursors) > 0 {
+		c := m.heap.cursors[0]
+		if c.slot < 0 {
+			c.slot = len(frames)
+			frames = append(frames, c.frame)
+			rows = append(rows, nil)
+		}
+		picks = append(picks, [2]int{c.slot, len(rows[c.slot])})
+		rows[c.slot] = append(rows[c.slot], c.row)
+		c.row++
+		if c.row < c.frame.NumRows() {
+			heap.Fix(&m.heap, 0)
+			continue
+		}
+		m.retired = append(m.retired, c.frame)
+		c.frame = nil
+		ok, err := m.load(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			heap.Fix(&m.heap, 0)
+			continue
+		}
+		heap.Pop(&m.heap)
+		c.src.Close()
+	}
+	for _, c := range m.heap.cursors {
+		c.slot = -1
+	}
+	out, err := gatherFrameRows(m.schema, frames, rows, picks)
+	for _, f := range m.retired {
+		f.Release()
+	}
+	m.retired = nil
+	return out, err
+}
+
+// gatherFrameRows builds one batch from rows scattered over several frames:
+// each contributing frame's rows are taken in output order, the
+// pieces concatenated, and — when more than one frame contributed —
+// permuted into the order picks gives. picks[i] is output row i's
+// (frame index, position in that frame's rows).
+func gatherFrameRows(schema *arrow.Schema, frames []*Frame, rows [][]int, picks [][2]int) (arrow.RecordBatch, error) {
+	parts := make([]arrow.RecordBatch, 0, len(frames))
+	defer func() { releaseBatches(parts) }()
+	offsets := make([]int, len(frames))
+	total := 0
+	for i, f := range frames {
+		sub, err := f.take(rows[i])
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, frameToBatch(sub))
+		sub.Release()
+		offsets[i] = total
+		total += len(rows[i])
+	}
+	if len(parts) == 1 {
+		out := parts[0]
+		out.Retain()
+		return out, nil
+	}
+	combined, err := concatBatchesToFrame(schema, parts)
+	if err != nil {
+		return nil, err
+	}
+	defer combined.Release()
+	perm := make([]int, len(picks))
+	for i, p := range picks {
+		perm[i] = offsets[p[0]] + p[1]
+	}
+	out, err := combined.take(perm)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Release()
+	return frameToBatch(out), nil
+}
+
+func (m *runMerger) close() error {
+	var err error
+	for _, c := range m.heap.cursors {
+		if c.frame != nil {
+			c.frame.Release()
+		}
+		if cerr := c.src.Close(); err == nil {
+			err = cerr
+		}
+	}
+	m.heap.cursors = nil
+	for _, f := range m.retired {
+		f.Release()
+	}
+	m.retired = nil
+	return err
+}
+
+// cursorHeap orders cursors by their current row; ties go to the
+// earlier run, which keeps the merge stable.
+type cursorHeap struct {
+	keys    []SortKey
+	cursors []*runCursor
+}
+
+func (h *cursorHeap) Len() int      { return len(h.cursors) }
+func (h *cursorHeap) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *cursorHeap) Push(x any)    { h.cursors = append(h.cursors, x.(*runCursor)) }
+
+func (h *cursorHeap) Pop() any {
+	n := len(h.cursors)
+	c := h.cursors[n-1]
+	h.cursors = h.cursors[:n-1]
+	return c
+}
+
+func (h *cursorHeap) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	if a.ranks != nil {
+		if c := strings.Compare(a.ranks[a.row], b.ranks[b.row]); c != 0 {
+			return c < 0
+		}
+		return a.run < b.run
+	}
+	for k, key := range h.keys {
+		if c := mergeCompare(a.keys[k], a.row, b.keys[k], b.row, key.Descending); c != 0 {
+			return c < 0
+		}
+	}
+	return a.run < b.run
+}
@@ -441,8 +441,8 @@ func TestExec_StreamingJoin_ProbeSideStreams(t *testing.T) {
 	join := &streamingJoinExec{
 		left:      leftScan,
 		right:     rightScan,
-		leftKey:   "region",
-		rightKey:  "region",
+		leftKeys:  []string{"region"},
+		rightKeys: []string{"region"},
 		kind:      JoinInner,
 		outSchema: newJoinNode(&scanFrameNode{frame: left}, &scanFrameNode{frame: right}, "region", "region", JoinInner).outSchema,
 	}
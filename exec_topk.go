@@ -0,0 +1,384 @@
+package gobi
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// topKExec is TopK: the first n rows of a sort, found without sorting
+// the input.
+//
+// Every worker keeps a bounded heap of at most n candidate rows, the
+// worst of them at the root. Once the heap is full, a row that
+// doesn't beat the root is dropped after a single comparison — the
+// common case for most of a large input — and one that does replaces
+// it. At EOF the workers' candidates (at most workers·n rows) are
+// sorted and the first n emitted.
+//
+// Rows compare key by key through mergeCompare (SortBy's null-last
+// semantics) and then by their position in the input, so the result
+// is exactly the stable Sort + Limit it replaces, however batches were
+// spread over workers. A heap row points into the batch it came from;
+// once more than topKMaxSources batches are pinned that way the heap
+// compacts its rows into one frame, so memory stays proportional to n
+// rather than to the number of batches seen.
+type topKExec struct {
+	input     ExecOperator
+	keys      []SortKey
+	n         int
+	outSchema *arrow.Schema
+	workers   int
+
+	started bool
+	result  *Frame
+	offset  int
+	closed  bool
+}
+
+// topKMaxSources is how many input batches one heap may pin before it
+// compacts.
+const topKMaxSources = 8
+
+func (e *topKExec) Schema() *arrow.Schema { return e.outSchema }
+
+func (e *topKExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if !e.started {
+		e.started = true
+		if err := e.build(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if e.result == nil || e.offset >= e.result.NumRows() {
+		return nil, io.EOF
+	}
+	end := min(e.offset+defaultBatchRows, e.result.NumRows())
+	slice := e.result.slice(int64(e.offset), int64(end))
+	e.offset = end
+	batch := frameToBatch(slice)
+	slice.Release()
+	return batch, nil
+}
+
+// build drains the input through the heaps and merges them into the
+// result.
+func (e *topKExec) build(ctx context.Context) error {
+	heaps := make([]*topKHeap, max(e.workers, 1))
+	for i := range heaps {
+		heaps[i] = &topKHeap{schema: e.input.Schema(), keys: e.keys, n: e.n}
+	}
+	defer func() {
+		for _, h := range heaps {
+			h.release()
+		}
+	}()
+	var err error
+	if len(heaps) == 1 {
+		err = e.consumeSerial(ctx, heaps[0])
+	} else {
+		err = e.consumeParallel(ctx, heaps)
+	}
+	if err != nil {
+		return err
+	}
+	e.input.Close()
+
+	var all []topKRow
+	for _, h := range heaps {
+		all = append(all, h.rows...)
+	}
+	if len(all) == 0 {
+		e.result, err = emptyFrame(e.outSchema)
+		return err
+	}
+	// Input positions are unique, so the order is total and needn't
+	// be a stable sort.
+	sort.Slice(all, func(i, j int) bool { return topKBefore(e.keys, all[i], all[j]) })
+	batch, err := gatherTopKRows(e.input.Schema(), all[:min(e.n, len(all))])
+	if err != nil {
+		return err
+	}
+	defer batch.Release()
+	e.result, err = batchToFrame(batch)
+	return err
+}
+
+func (e *topKExec) consumeSerial(ctx context.Context, h *topKHeap) error {
+	var seq int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := e.input.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = h.add(batch, seq)
+		seq += batch.NumRows()
+		batch.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// topKMsg is one input batch and the input position of its first row.
+type topKMsg struct {
+	batch arrow.RecordBatch
+	seq   int64
+}
+
+// consumeParallel fans input batches out to one goroutine per heap.
+// Any worker may take any batch — the heaps are merged by key and
+// input position at the end, so which heap saw a row doesn't matter.
+//
+// Errors + cancellation follow buildParallel: the first error cancels
+// the derived context so the reader stops pulling upstream, and
+// workers drain the inbox on cancellation to unblock the reader.
+func (e *topKExec) consumeParallel(ctx context.Context, heaps []*topKHeap) error {
+	const chanBuf = 4
+	inbox := make(chan topKMsg, chanBuf*len(heaps))
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var errOnce sync.Once
+	setErr := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(heaps))
+	for _, h := range heaps {
+		go func() {
+			defer wg.Done()
+			for msg := range inbox {
+				if wctx.Err() == nil {
+					if err := h.add(msg.batch, msg.seq); err != nil {
+						setErr(err)
+					}
+				}
+				msg.batch.Release()
+			}
+		}()
+	}
+
+	readErr := func() error {
+		defer close(inbox)
+		var seq int64
+		for {
+			if err := wctx.Err(); err != nil {
+				return err
+			}
+			batch, err := e.input.Next(wctx)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			rows := batch.NumRows()
+			if rows == 0 {
+				batch.Release()
+				continue
+			}
+			select {
+			case inbox <- topKMsg{batch: batch, seq: seq}:
+			case <-wctx.Done():
+				batch.Release()
+				return wctx.Err()
+			}
+			seq += rows
+		}
+	}()
+	if readErr != nil {
+		setErr(readErr)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+func (e *topKExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.result != nil {
+		e.result.Release()
+		e.result = nil
+	}
+	return e.input.Close()
+}
+
+// topKSource is an input batch (or a compacted set of rows) that heap
+// rows point into, with its key columns resolved once.
+type topKSource struct {
+	frame *Frame
+	keys  []arrow.Array
+	refs  int // heap rows pointing here
+}
+
+func newTopKSource(batch arrow.RecordBatch, keys []SortKey) (*topKSource, error) {
+	f, err := batchToFrame(batch)
+	if err != nil {
+		return nil, err
+	}
+	src := &topKSource{frame: f, keys: make([]arrow.Array, len(keys))}
+	for i, k := range keys {
+		s, err := f.Column(k.Column)
+		if err != nil {
+			f.Release()
+			return nil, err
+		}
+		src.keys[i] = s.col.Data().Chunk(0)
+	}
+	return src, nil
+}
+
+// topKRow is a candidate row: where it lives and its input position.
+type topKRow struct {
+	src *topKSource
+	row int
+	seq int64
+}
+
+// topKBefore reports whether a sorts before b: by keys, then by input
+// position.
+func topKBefore(keys []SortKey, a, b topKRow) bool {
+	for k, key := range keys {
+		if c := mergeCompare(a.src.keys[k], a.row, b.src.keys[k], b.row, key.Descending); c != 0 {
+			return c < 0
+		}
+	}
+	return a.seq < b.seq
+}
+
+// topKHeap is one worker's bounded heap. It is a max-heap in sort
+// order: rows[0] is the candidate that sorts last, the one a better
+// row evicts.
+type topKHeap struct {
+	schema  *arrow.Schema
+	keys    []SortKey
+	n       int
+	rows    []topKRow
+	sources []*topKSource
+}
+
+func (h *topKHeap) Len() int           { return len(h.rows) }
+func (h *topKHeap) Less(i, j int) bool { return topKBefore(h.keys, h.rows[j], h.rows[i]) }
+func (h *topKHeap) Swap(i, j int)      { h.rows[i], h.rows[j] = h.rows[j], h.rows[i] }
+func (h *topKHeap) Push(x any)         { h.rows = append(h.rows, x.(topKRow)) }
+
+func (h *topKHeap) Pop() any {
+	n := len(h.rows)
+	r := h.rows[n-1]
+	h.rows = h.rows[:n-1]
+	return r
+}
+
+// add offers every row of batch, whose first row is at input position
+// seq. The caller keeps its reference to batch.
+func (h *topKHeap) add(batch arrow.RecordBatch, seq int64) error {
+	if batch.NumRows() == 0 {
+		return nil
+	}
+	src, err := newTopKSource(batch, h.keys)
+	if err != nil {
+		return err
+	}
+	for row := range int(batch.NumRows()) {
+		cand := topKRow{src: src, row: row, seq: seq + int64(row)}
+		if len(h.rows) < h.n {
+			heap.Push(h, cand)
+			src.refs++
+			continue
+		}
+		if !topKBefore(h.keys, cand, h.rows[0]) {
+			continue
+		}
+		h.rows[0].src.refs--
+		h.rows[0] = cand
+		src.refs++
+		heap.Fix(h, 0)
+	}
+	h.sources = append(h.sources, src)
+	live := h.sources[:0]
+	for _, s := range h.sources {
+		if s.refs == 0 {
+			s.frame.Release()
+			continue
+		}
+		live = append(live, s)
+	}
+	clear(h.sources[len(live):])
+	h.sources = live
+	if len(h.sources) > topKMaxSources {
+		return h.compact()
+	}
+	return nil
+}
+
+// compact copies the heap's rows into one frame and releases the
+// batches they pointed into. Row i of the new frame is rows[i], so the
+// heap order is untouched.
+func (h *topKHeap) compact() error {
+	batch, err := gatherTopKRows(h.schema, h.rows)
+	if err != nil {
+		return err
+	}
+	src, err := newTopKSource(batch, h.keys)
+	batch.Release()
+	if err != nil {
+		return err
+	}
+	for i := range h.rows {
+		h.rows[i].src, h.rows[i].row = src, i
+	}
+	src.refs = len(h.rows)
+	for _, s := range h.sources {
+		s.frame.Release()
+	}
+	h.sources = []*topKSource{src}
+	return nil
+}
+
+func (h *topKHeap) release() {
+	for _, s := range h.sources {
+		s.frame.Release()
+	}
+	h.sources, h.rows = nil, nil
+}
+
+// gatherTopKRows builds one batch holding rows, in order.
+func gatherTopKRows(schema *arrow.Schema, rows []topKRow) (arrow.RecordBatch, error) {
+	var (
+		frames []*Frame
+		idx    [][]int
+		slots  = make(map[*topKSource]int)
+	)
+	picks := make([][2]int, len(rows))
+	for i, r := range rows {
+		slot, ok := slots[r.src]
+		if !ok {
+			slot = len(frames)
+			slots[r.src] = slot
+			frames = append(frames, r.src.frame)
+			idx = append(idx, nil)
+		}
+		picks[i] = [2]int{slot, len(idx[slot])}
+		idx[slot] = append(idx[slot], r.row)
+	}
+	return gatherFrameRows(schema, frames, idx, picks)
+}
@@ -0,0 +1,121 @@
+package gobi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestFuseTopK_Rewrites(t *testing.T) {
+	lf := spillFrame(t, 100).Lazy()
+	keys := []SortKey{{Column: "rank", Descending: true}, {Column: "key"}}
+
+	topK, ok := Optimize(lf.SortBy(keys...).Limit(10).plan).(*topKNode)
+	if !ok || topK.n != 10 || len(topK.keys) != 2 {
+		t.Fatalf("Limit(Sort) optimized to %v, want TopK(10; ...)", topK)
+	}
+	if got := topK.String(); got != "TopK(10; rank DESC, key)" {
+		t.Errorf("String() = %q", got)
+	}
+	// A second Limit folds into the TopK.
+	if n := Optimize(lf.SortBy(keys...).Limit(10).Limit(3).plan).(*topKNode).n; n != 3 {
+		t.Errorf("Limit(3) over TopK(10) = TopK(%d), want 3", n)
+	}
+	if n := Optimize(lf.SortBy(keys...).Limit(3).Limit(10).plan).(*topKNode).n; n != 3 {
+		t.Errorf("Limit(10) over TopK(3) = TopK(%d), want 3", n)
+	}
+
+	// Limit(0) and Ordered categorical keys keep Sort + Limit.
+	if _, ok := Optimize(lf.SortBy(keys...).Limit(0).plan).(*limitNode); !ok {
+		t.Error("Limit(0) over Sort was fused")
+	}
+	ordered := &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int16, ValueType: arrow.BinaryTypes.String, Ordered: true}
+	byCode := lf.WithColumn("cat", Col("name").Cast(ordered)).SortBy(SortKey{Column: "cat"}).Limit(5)
+	if _, ok := Optimize(byCode.plan).(*limitNode); !ok {
+		t.Error("Limit over an Ordered categorical Sort was fused")
+	}
+}
+
+// runTopK compiles lf, pins the TopK root to workers, and collects it.
+func runTopK(t *testing.T, lf *LazyFrame, workers int) *Frame {
+	t.Helper()
+	op, err := Compile(Optimize(lf.plan))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := op.(*topKExec)
+	if !ok {
+		t.Fatalf("root = %T, want *topKExec", op)
+	}
+	e.workers = workers
+	f, err := Execute(context.Background(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestTopK_MatchesSortLimit(t *testing.T) {
+	f := spillFrame(t, spillRows)
+	keySets := map[string][]SortKey{
+		"int desc ties":  {{Column: "rank", Descending: true}},
+		"nullable multi": {{Column: "key", Descending: true}, {Column: "value"}},
+		"string float":   {{Column: "name"}, {Column: "value", Descending: true}},
+	}
+	for name, keys := range keySets {
+		sorted, err := f.SortBy(keys...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 100_000 is more than one batch; 200_000 is more than the input.
+		for _, n := range []int{1, 100, 100_000, 200_000} {
+			for _, workers := range []int{1, 4} {
+				t.Run(fmt.Sprintf("%s/n=%d/workers=%d", name, n, workers), func(t *testing.T) {
+					got := runTopK(t, f.Lazy().SortBy(keys...).Limit(n), workers)
+					// Stable, so even tied rows (same id) match.
+					assertSameFrame(t, got, sorted.Head(n))
+				})
+			}
+		}
+	}
+}
+
+func TestTopK_NullsLast(t *testing.T) {
+	// 500 rows, 6 of them with a null key: the top 497 ascending are
+	// every non-null key, then the first three null rows.
+	f := spillFrame(t, 500)
+	for _, desc := range []bool{false, true} {
+		lf := f.Lazy().SortBy(SortKey{Column: "key", Descending: desc}).Limit(497)
+		got := runTopK(t, lf, 4)
+		want, err := lf.CollectRaw()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSameFrame(t, got, want)
+		keys := valuesOrNull(t, got, "key")
+		if keys[493] == "null" || keys[494] != "null" || keys[496] != "null" {
+			t.Errorf("desc=%v: keys[493:] = %v, want nulls from 494", desc, keys[493:])
+		}
+		if ids := valuesOrNull(t, got, "id")[494:]; ids[0] != "0" || ids[1] != "97" || ids[2] != "194" {
+			t.Errorf("desc=%v: null rows = %v, want the first three in input order", desc, ids)
+		}
+	}
+}
+
+func TestTopK_ExplainPhysical(t *testing.T) {
+	prevMax := MaxParallelism()
+	SetMaxParallelism(4)
+	t.Cleanup(func() { SetMaxParallelism(prevMax) })
+
+	lf := spillFrame(t, 100).Lazy().SortBy(SortKey{Column: "rank", Descending: true}).Limit(10)
+	got := lf.ExplainPhysical()
+	if !strings.Contains(got, "StreamingTopK(10; rank DESC) [workers=4]") {
+		t.Fatalf("ExplainPhysical missing the TopK label:\n%s", got)
+	}
+	if strings.Contains(got, "Sort(") {
+		t.Errorf("ExplainPhysical still sorts:\n%s", got)
+	}
+}
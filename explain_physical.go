@@ -25,27 +25,44 @@ import (
 //	      ScanFile[stream, parquet]("events.parquet", cols=[user_id value region])
 //	    ScanFrame(100 rows × 2 cols)
 func (lf *LazyFrame) ExplainPhysical() string {
+	return lf.ExplainPhysicalWith(nil)
+}
+
+// ExplainPhysicalWith is ExplainPhysical for a plan run through
+// CollectWith(opts). With a MemoryLimit, every operator that can
+// spill to disk is labeled "Spilling*" with the budget, e.g.
+//
+//	SpillingSort([col("ts") asc]) [spill>512.0MiB]
+//
+// Sort on a key type the run merge can't compare keeps its
+// Materialize label — it still holds the whole input in memory.
+func (lf *LazyFrame) ExplainPhysicalWith(opts *CollectOptions) string {
 	var sb strings.Builder
-	explainPhysical(Optimize(lf.plan), &sb, 0)
+	explainPhysical(Optimize(lf.plan), newSpillConfig(opts), &sb, 0)
 	return sb.String()
 }
 
-func explainPhysical(p LogicalPlan, sb *strings.Builder, depth int) {
+func explainPhysical(p LogicalPlan, spill spillConfig, sb *strings.Builder, depth int) {
 	for range depth {
 		sb.WriteString("  ")
 	}
-	sb.WriteString(physicalLabel(p))
+	sb.WriteString(physicalLabel(p, spill))
 	sb.WriteByte('\n')
 	for _, c := range p.Children() {
-		explainPhysical(c, sb, depth+1)
+		explainPhysical(c, spill, sb, depth+1)
 	}
 }
 
+// spillLabel is the label of an operator that spills past the budget.
+func spillLabel(n LogicalPlan, spill spillConfig) string {
+	return fmt.Sprintf("Spilling%s [spill>%s]", n.String(), formatBytes(spill.limit))
+}
+
 // physicalLabel returns the one-line label ExplainPhysical uses for
 // a plan node. The label reflects the strategy Compile would pick
 // for that node — matches the type-switch in Compile so the two
 // stay in sync. If the compile logic changes, update this alongside.
-func physicalLabel(p LogicalPlan) string {
+func physicalLabel(p LogicalPlan, spill spillConfig) string {
 	switch n := p.(type) {
 
 	case *scanFrameNode:
@@ -93,9 +110,21 @@ func physicalLabel(p LogicalPlan) string {
 		return "Materialize" + n.String()
 
 	case *sortNode:
-		// Sort must see all rows — always materializes.
+		// Sort must see all rows — materializes, or merges spilled
+		// runs under a budget.
+		if spill.enabled() && canMergeSortKeys(n.input.Schema(), n.keys) {
+			return spillLabel(n, spill)
+		}
 		return "Materialize" + n.String()
 
+	case *topKNode:
+		// Bounded heaps, one per worker — never more than n rows per
+		// heap, so a memory budget doesn't change it.
+		if w := resolveWorkers(); w > 1 {
+			return fmt.Sprintf("Streaming%s [workers=%d]", n.String(), w)
+		}
+		return "Streaming" + n.String()
+
 	case *aggregateNode:
 		// Compile picks streaming when every Aggregation uses a
 		// built-in Kind (no custom Fn). Match that here, and echo
@@ -103,6 +132,9 @@ func physicalLabel(p LogicalPlan) string {
 		// partitioned build kicks in (workers>1) versus the serial
 		// fast path (workers==1). Uses resolveWorkers() with no
 		// per-op overrides, matching Compile.
+		if spill.enabled() && canSpillAggregate(n) {
+			return spillLabel(n, spill)
+		}
 		prefix := "Materialize"
 		if allBuiltInAggs(n.aggs) {
 			prefix = "Streaming"
@@ -112,10 +144,29 @@ func physicalLabel(p LogicalPlan) string {
 		}
 		return prefix + n.String()
 
+	case *asofJoinNode:
+		// Always streams the left side against a materialized,
+		// time-sorted right side — see streamingAsofJoinExec.
+		return "Streaming" + n.String()
+
+	case *sjoinNode:
+		// Same split as joinNode: left-driven kinds stream the probe
+		// side against the R-tree; Right / Full materialize.
+		prefix := "Materialize"
+		if canStreamJoin(n.kind) {
+			prefix = "Streaming"
+		}
+		return prefix + n.String()
+
 	case *joinNode:
 		// Compile picks streaming for left-driven kinds (Inner,
 		// Left, Semi, Anti). Right/Full route through the
 		// materializing fallback until we do second-phase state.
+		// Under a budget every kind but the sort-merge Inner join
+		// runs as the grace join.
+		if spill.enabled() && !(n.kind == JoinInner && canMergeJoin(n)) {
+			return spillLabel(n, spill)
+		}
 		prefix := "Materialize"
 		if canStreamJoin(n.kind) {
 			prefix = "Streaming"
@@ -21,6 +21,14 @@ import (
 //   - Uint64  ← {Int64, Uint32}
 //   - Uint32  ← {Uint64, Int64}
 //
+// Categorical conversions are the one non-numeric family:
+//
+//   - Categorical ← {String, LargeString, another categorical}
+//   - String / LargeString ← Categorical
+//
+// where Categorical is gobi.Categorical or any arrow.DictionaryType
+// with String values (see categorical.go).
+//
 // Same-type is a no-op (returns the input). Unsupported combinations
 // error at Eval with a clear message.
 //
@@ -80,6 +88,16 @@ func castSeries(s Series, target arrow.DataType) (Series, error) {
 	if arrow.TypeEqual(s.DataType(), target) {
 		return s, nil
 	}
+	if isCategoricalType(target) {
+		return encodeCategorical(s, target.(*arrow.DictionaryType))
+	}
+	if s.IsCategorical() {
+		if id := target.ID(); id == arrow.STRING || id == arrow.LARGE_STRING {
+			return decodeCategorical(s, target)
+		}
+		return Series{}, fmt.Errorf("%w: Cast: categorical to %s, want String or LargeString",
+			ErrExprTypeMismatch, target)
+	}
 	switch target.ID() {
 	case arrow.FLOAT64:
 		return castToFloat64(s)
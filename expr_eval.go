@@ -181,6 +181,9 @@ type binOpNode struct {
 }
 
 func (n *binOpNode) Eval(input *Frame) (Series, error) {
+	if s, ok, err := n.evalCategoricalEq(input); ok || err != nil {
+		return s, err
+	}
 	// Fast path: (col op literal). Applies to arithmetic and numeric
 	// comparisons; left must be non-literal, right must be a numeric
 	// literal that maps cleanly to Series' *Scalar methods.
@@ -233,6 +236,46 @@ func (n *binOpNode) Eval(input *Frame) (Series, error) {
 	return applyBinaryOp(n.op, left, right)
 }
 
+// evalCategoricalEq is the (col ==/!= "literal") path for a
+// categorical col, either operand order: the literal is looked up in
+// each chunk's dictionary once and rows compare codes. Returns ok=false
+// without evaluating anything when the shape doesn't apply, and falls
+// through to applyBinaryOp when col turns out not to be categorical.
+func (n *binOpNode) evalCategoricalEq(input *Frame) (Series, bool, error) {
+	if n.op != bopEq && n.op != bopNe {
+		return Series{}, false, nil
+	}
+	col, other := n.left, n.right
+	lit, ok := other.(*literalNode)
+	if !ok {
+		col, other = n.right, n.left
+		if lit, ok = other.(*literalNode); !ok {
+			return Series{}, false, nil
+		}
+	}
+	str, ok := lit.value.(string)
+	if !ok || lit.err != nil {
+		return Series{}, false, nil
+	}
+	if _, isLit := col.(*literalNode); isLit {
+		return Series{}, false, nil
+	}
+	s, err := col.Eval(input)
+	if err != nil {
+		return Series{}, true, err
+	}
+	if s.IsCategorical() {
+		out, err := categoricalEqLiteral(s, str, n.op == bopNe)
+		return out, true, err
+	}
+	litSeries, err := other.Eval(input)
+	if err != nil {
+		return Series{}, true, err
+	}
+	out, err := applyBinaryOp(n.op, s, litSeries)
+	return out, true, err
+}
+
 // tryScalarIntFastPath emits (col op int-literal) with Int64 output
 // preserved — only when the input is Int64 single-chunk. Returns
 // (_, false, nil) otherwise so the caller can fall back to the
@@ -397,8 +440,18 @@ func applyBinaryOp(op binOpKind, left, right Series) (Series, error) {
 	// String Eq/Ne: Series.Eq is numeric-only, so route string operand
 	// pairs through a dedicated comparator here.
 	if op == bopEq || op == bopNe {
-		if left.DataType() != nil && left.DataType().ID() == arrow.STRING &&
-			right.DataType() != nil && right.DataType().ID() == arrow.STRING {
+		if left.DataType() != nil && isStringOrCategorical(left.DataType()) &&
+			right.DataType() != nil && isStringOrCategorical(right.DataType()) {
+			// Categorical against a column compares values, so
+			// decode; against a literal evalCategoricalEq got there
+			// first.
+			var err error
+			if left, err = decodedForString(left); err != nil {
+				return Series{}, err
+			}
+			if right, err = decodedForString(right); err != nil {
+				return Series{}, err
+			}
 			return stringCompare(left, right, op == bopNe)
 		}
 	}
@@ -434,15 +487,24 @@ func applyBinaryOp(op binOpKind, left, right Series) (Series, error) {
 }
 
 // stringCompare returns a Boolean Series of left ==/!= right, element-
-// wise. Both inputs must be single-chunk String columns of the same
-// length; the caller is expected to have already verified that.
+// wise. Both inputs must be String columns of the same length; the
+// caller is expected to have already verified the type. Multi-chunk
+// inputs are flattened first.
 func stringCompare(left, right Series, negate bool) (Series, error) {
 	if left.Len() != right.Len() {
 		return Series{}, fmt.Errorf("%w: %d vs %d",
 			ErrColumnLenMismatch, left.Len(), right.Len())
 	}
-	la := left.col.Data().Chunks()[0].(*array.String)
-	ra := right.col.Data().Chunks()[0].(*array.String)
+	la, err := stringArrayOf(left)
+	if err != nil {
+		return Series{}, err
+	}
+	defer la.Release()
+	ra, err := stringArrayOf(right)
+	if err != nil {
+		return Series{}, err
+	}
+	defer ra.Release()
 	n := la.Len()
 
 	pool := memory.DefaultAllocator
@@ -462,6 +524,21 @@ func stringCompare(left, right Series, negate bool) (Series, error) {
 	return arrayToSeries(pool, "", arrow.FixedWidthTypes.Boolean, out.NewArray())
 }
 
+// stringArrayOf returns s's values as one *array.String. The caller
+// releases it.
+func stringArrayOf(s Series) (*array.String, error) {
+	chunks := s.col.Data().Chunks()
+	strs := make([]*array.String, len(chunks))
+	for i, c := range chunks {
+		a, ok := c.(*array.String)
+		if !ok {
+			return nil, fmt.Errorf("%w: string compare on %T", ErrExprTypeMismatch, c)
+		}
+		strs[i] = a
+	}
+	return flattenStringChunks(strs)
+}
+
 // -----------------------------------------------------------------------------
 // notNode: `Not(inner)`
 // -----------------------------------------------------------------------------
@@ -685,7 +762,7 @@ func promoteForComparison(lt, rt arrow.DataType) (arrow.DataType, error) {
 	if isNumericType(lt) && isNumericType(rt) {
 		return promoteNumeric(lt, rt)
 	}
-	if lt.ID() == arrow.STRING && rt.ID() == arrow.STRING {
+	if isStringOrCategorical(lt) && isStringOrCategorical(rt) {
 		return arrow.BinaryTypes.String, nil
 	}
 	if lt.ID() == arrow.BOOL && rt.ID() == arrow.BOOL {
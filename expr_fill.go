@@ -0,0 +1,315 @@
+package gobi
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// FillNull returns an expression that replaces every null in e with
+// the value of fill at the same row. fill is usually a literal but may
+// be any expression — another column, say, which makes FillNull a
+// two-operand Coalesce that tolerates numeric type differences: a
+// numeric fill is Cast to e's type first, so Lit(0) fills a Float64
+// column. Other types must match exactly.
+//
+//	lf.WithColumn("speed", Col("speed").FillNull(Lit(0.0)))
+//
+// Row-local, so it streams batch by batch in lazy plans. The output
+// keeps e's name.
+func (e Expr) FillNull(fill Expr) Expr {
+	return Expr{node: &fillNullNode{inner: e.node, fill: fill.node}}
+}
+
+// FillNullWith returns an expression that replaces nulls in e by
+// strategy — FillForward, FillBackward or FillMean. See
+// Series.FillNullWith for the per-strategy semantics.
+//
+// In a lazy plan, FillForward outside Over streams: each batch's
+// leading nulls take the last non-null value of the batches before
+// it. FillBackward and FillMean read rows that haven't arrived yet,
+// so a plan holding one materializes its input first, as it does for
+// CumSum and Rank. Under Over / OverOrdered the fill runs per
+// partition and materializes too:
+//
+//	// last known position per vehicle, in time order
+//	lf.WithColumn("lat", Col("lat").FillNullWith(gobi.FillForward).
+//	    OverOrdered([]string{"vehicle"}, gobi.SortKey{Column: "ts"}))
+func (e Expr) FillNullWith(strategy FillStrategy) Expr {
+	return Expr{node: &fillStrategyNode{inner: e.node, strategy: strategy}}
+}
+
+// Interpolate returns an expression that fills interior null runs of
+// the numeric e by linear interpolation over row position (see
+// Series.Interpolate). The result is Float64. A gap can only be
+// filled once the value after it is known, so like FillBackward it
+// materializes a lazy plan's input; it runs per partition under Over.
+func (e Expr) Interpolate() Expr {
+	return Expr{node: &interpolateNode{inner: e.node}}
+}
+
+// InterpolateBy is Interpolate weighted by by — a Timestamp, Date or
+// numeric expression — instead of row position (see
+// Series.InterpolateBy). Rows must already be in by order; under
+// OverOrdered, order each partition by the same column:
+//
+//	// time-weighted repair of gappy sensor tracks
+//	lf.WithColumn("temp", Col("temp").InterpolateBy(Col("ts")).
+//	    OverOrdered([]string{"sensor"}, gobi.SortKey{Column: "ts"}))
+func (e Expr) InterpolateBy(by Expr) Expr {
+	return Expr{node: &interpolateNode{inner: e.node, by: by.node}}
+}
+
+// fillNullNode is the literal (or per-row) fill. Evaluating fill
+// against the same input keeps it shape-aligned with inner — a Lit
+// broadcasts to every row.
+type fillNullNode struct {
+	inner ExprNode
+	fill  ExprNode
+}
+
+func (n *fillNullNode) Eval(input *Frame) (Series, error) {
+	if n.inner == nil || n.fill == nil {
+		return Series{}, fmt.Errorf("gobi: FillNull on nil expression")
+	}
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	if s.col.Data().NullN() == 0 {
+		return s, nil
+	}
+	v, err := n.fill.Eval(input)
+	if err != nil {
+		return Series{}, fmt.Errorf("FillNull fill: %w", err)
+	}
+	if v.Len() != s.Len() {
+		return Series{}, fmt.Errorf("%w: FillNull fill has %d rows, input %d",
+			ErrColumnLenMismatch, v.Len(), s.Len())
+	}
+	if !arrow.TypeEqual(v.DataType(), s.DataType()) && isNumericType(v.DataType()) && isNumericType(s.DataType()) {
+		if v, err = castSeries(v, s.DataType()); err != nil {
+			return Series{}, fmt.Errorf("FillNull fill: %w", err)
+		}
+	}
+	if !arrow.TypeEqual(v.DataType(), s.DataType()) {
+		return Series{}, fmt.Errorf("%w: FillNull of %s with %s",
+			ErrExprTypeMismatch, s.DataType(), v.DataType())
+	}
+	b, err := builderForType(memory.DefaultAllocator, s.DataType())
+	if err != nil {
+		return Series{}, fmt.Errorf("FillNull: %w", err)
+	}
+	defer b.Release()
+	nulls := s.Nulls()
+	for row, null := range nulls {
+		src := s
+		if null {
+			src = v
+		}
+		if err := copyRowValue(b, src, row); err != nil {
+			return Series{}, fmt.Errorf("FillNull row %d: %w", row, err)
+		}
+	}
+	f := s.field
+	f.Nullable = true
+	return seriesFromBuilder(f, b), nil
+}
+
+func (n *fillNullNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.inner == nil || n.fill == nil {
+		return nil, fmt.Errorf("gobi: FillNull on nil expression")
+	}
+	t, err := n.inner.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	ft, err := n.fill.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil && ft != nil && !arrow.TypeEqual(t, ft) && !(isNumericType(t) && isNumericType(ft)) {
+		return nil, fmt.Errorf("%w: FillNull of %s with %s", ErrExprTypeMismatch, t, ft)
+	}
+	return t, nil
+}
+
+func (n *fillNullNode) Children() []Expr { return []Expr{{node: n.inner}, {node: n.fill}} }
+func (n *fillNullNode) String() string {
+	return fmt.Sprintf("%s.fill_null(%s)", n.inner, n.fill)
+}
+
+// OutputName passes the inner's name through, so Select of a filled
+// column keeps its name.
+func (n *fillNullNode) OutputName() string { return innerOutputName(n.inner) }
+
+// fillStrategyNode applies Series.FillNullWith to its inner.
+type fillStrategyNode struct {
+	inner    ExprNode
+	strategy FillStrategy
+}
+
+func (n *fillStrategyNode) Eval(input *Frame) (Series, error) {
+	if n.inner == nil {
+		return Series{}, fmt.Errorf("gobi: FillNullWith on nil inner expression")
+	}
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	return s.FillNullWith(n.strategy)
+}
+
+func (n *fillStrategyNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.inner == nil {
+		return nil, fmt.Errorf("gobi: FillNullWith on nil inner expression")
+	}
+	t, err := n.inner.Type(schema)
+	if err != nil || n.strategy != FillMean {
+		return t, err
+	}
+	if t != nil && !isNumericType(t) {
+		return nil, fmt.Errorf("%w: FillNullWith(mean) on %s", ErrExprTypeMismatch, t)
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+func (n *fillStrategyNode) Children() []Expr { return []Expr{{node: n.inner}} }
+func (n *fillStrategyNode) String() string {
+	return fmt.Sprintf("%s.fill_null(strategy=%s)", n.inner, n.strategy)
+}
+func (n *fillStrategyNode) OutputName() string { return innerOutputName(n.inner) }
+
+// streamingFillForwardNode is the forward fill a streaming operator
+// runs instead of a fillStrategyNode: each Eval is the next batch, and
+// carry, the last non-null value seen so far, fills the batch's
+// leading nulls. Stateful, so the compiler builds a fresh one per
+// exec operator (see streamingFills) and never shares it between
+// plans.
+type streamingFillForwardNode struct {
+	inner ExprNode
+	carry Series
+}
+
+func (n *streamingFillForwardNode) Eval(input *Frame) (Series, error) {
+	if n.inner == nil {
+		return Series{}, fmt.Errorf("gobi: FillNullWith on nil inner expression")
+	}
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	nulls := s.Nulls()
+	var out Series
+	if n.carry.col != nil && len(nulls) > 0 && nulls[0] {
+		joined, err := n.carry.Concat(s)
+		if err != nil {
+			return Series{}, fmt.Errorf("gobi: FillNullWith(%s): %w", FillForward, err)
+		}
+		if out, err = joined.FillNullWith(FillForward); err != nil {
+			return Series{}, err
+		}
+		out = out.slice(1, int64(out.Len()))
+	} else if out, err = s.FillNullWith(FillForward); err != nil {
+		return Series{}, err
+	}
+	for i := len(nulls) - 1; i >= 0; i-- {
+		if !nulls[i] {
+			// A copy, so the carry doesn't pin the batch.
+			if n.carry, err = gatherRows(s, []int{i}); err != nil {
+				return Series{}, fmt.Errorf("gobi: FillNullWith(%s): %w", FillForward, err)
+			}
+			break
+		}
+	}
+	return out, nil
+}
+
+func (n *streamingFillForwardNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.inner == nil {
+		return nil, fmt.Errorf("gobi: FillNullWith on nil inner expression")
+	}
+	return n.inner.Type(schema)
+}
+
+func (n *streamingFillForwardNode) Children() []Expr { return []Expr{{node: n.inner}} }
+func (n *streamingFillForwardNode) String() string {
+	return fmt.Sprintf("%s.fill_null(strategy=%s)", n.inner, FillForward)
+}
+func (n *streamingFillForwardNode) OutputName() string { return innerOutputName(n.inner) }
+
+// interpolateNode applies Series.Interpolate, or InterpolateBy when by
+// is set.
+type interpolateNode struct {
+	inner ExprNode
+	by    ExprNode
+}
+
+func (n *interpolateNode) Eval(input *Frame) (Series, error) {
+	if n.inner == nil {
+		return Series{}, fmt.Errorf("gobi: Interpolate on nil inner expression")
+	}
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	if n.by == nil {
+		return s.Interpolate()
+	}
+	by, err := n.by.Eval(input)
+	if err != nil {
+		return Series{}, fmt.Errorf("InterpolateBy by: %w", err)
+	}
+	return s.InterpolateBy(by)
+}
+
+func (n *interpolateNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.inner == nil {
+		return nil, fmt.Errorf("gobi: Interpolate on nil inner expression")
+	}
+	t, err := n.inner.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil && !isNumericType(t) {
+		return nil, fmt.Errorf("%w: Interpolate on %s", ErrExprTypeMismatch, t)
+	}
+	if n.by != nil {
+		bt, err := n.by.Type(schema)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case bt == nil, isNumericType(bt):
+		case bt.ID() == arrow.TIMESTAMP, bt.ID() == arrow.DATE32, bt.ID() == arrow.DATE64:
+		default:
+			return nil, fmt.Errorf("%w: InterpolateBy over %s, want a Timestamp, Date or numeric column",
+				ErrExprTypeMismatch, bt)
+		}
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+func (n *interpolateNode) Children() []Expr {
+	if n.by == nil {
+		return []Expr{{node: n.inner}}
+	}
+	return []Expr{{node: n.inner}, {node: n.by}}
+}
+
+func (n *interpolateNode) String() string {
+	if n.by == nil {
+		return fmt.Sprintf("%s.interpolate()", n.inner)
+	}
+	return fmt.Sprintf("%s.interpolate_by(%s)", n.inner, n.by)
+}
+func (n *interpolateNode) OutputName() string { return innerOutputName(n.inner) }
+
+// innerOutputName is the inner's OutputName when it has one, else "".
+func innerOutputName(inner ExprNode) string {
+	if nm, ok := inner.(Namer); ok {
+		return nm.OutputName()
+	}
+	return ""
+}
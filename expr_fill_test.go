@@ -0,0 +1,154 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// TestExprFill_PerVehicle — the track-repair shape: forward fill and
+// time-weighted interpolation per vehicle in time order, scattered
+// back to the input's row order.
+func TestExprFill_PerVehicle(t *testing.T) {
+	f := trackFrame(t)
+	byTime := SortKey{Column: "ts"}
+	out, err := f.WithColumnExpr("ffill",
+		Col("lat").FillNullWith(FillForward).OverOrdered([]string{"vehicle"}, byTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = out.WithColumnExpr("interp",
+		Col("lat").InterpolateBy(Col("ts")).OverOrdered([]string{"vehicle"}, byTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a: ts 0,10,30,40,50 lat 10,_,_,40,_ · b: ts 0,10,20,60 lat _,50,_,10
+	if got := valuesOrNull(t, out, "ffill"); !slices.Equal(got, []string{"10", "null", "10", "50", "40", "10", "50", "10", "40"}) {
+		t.Fatalf("per-vehicle ffill = %v", got)
+	}
+	if got := valuesOrNull(t, out, "interp"); !slices.Equal(got, []string{"10", "null", "17.5", "50", "40", "32.5", "42", "10", "null"}) {
+		t.Fatalf("per-vehicle InterpolateBy = %v", got)
+	}
+}
+
+func TestExprFill_LiteralAndColumn(t *testing.T) {
+	f := trackFrame(t)
+	// Int64 literal on a Float64 column casts; the name passes through.
+	out, err := f.Lazy().Select(Col("lat").FillNull(Lit(int64(0))), Col("fix").FillNull(Col("vehicle"))).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "lat"); !slices.Equal(got, []string{"10", "0", "0", "50", "40", "0", "0", "10", "0"}) {
+		t.Fatalf("FillNull(Lit(0)) = %v", got)
+	}
+	if got := strValues(t, out, "fix"); !slices.Equal(got, []string{"gps", "b", "a", "gps", "dr", "a", "b", "gps", "a"}) {
+		t.Fatalf("FillNull(Col(vehicle)) = %v", got)
+	}
+	if _, err := f.WithColumnExpr("x", Col("fix").FillNull(Lit(1.0))); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("String filled with Float64: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := f.WithColumnExpr("x", Col("fix").Interpolate()); err == nil {
+		t.Fatal("Interpolate on String: expected error")
+	}
+}
+
+// TestExprFill_Lazy — a literal fill and DropNulls stay on the
+// streaming path; backward and mean fills and interpolation
+// materialize so a batch boundary can't break a run of nulls. Both
+// agree with eager.
+func TestExprFill_Lazy(t *testing.T) {
+	f := trackFrame(t)
+	streaming := f.Lazy().DropNulls("fix").WithColumn("lat", Col("lat").FillNull(Lit(0.0)))
+	op, err := Compile(Optimize(streaming.Plan()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := op.(*materializeExecOp); ok {
+		t.Fatal("FillNull(Lit) + DropNulls should stream, got materializeExecOp")
+	}
+	got, err := streaming.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := valuesOrNull(t, got, "lat"); !slices.Equal(v, []string{"10", "50", "40", "10"}) {
+		t.Fatalf("lazy DropNulls(fix) lat = %v", v)
+	}
+
+	for _, e := range []Expr{Col("lat").FillNullWith(FillBackward), Col("lat").FillNullWith(FillMean), Col("lat").Interpolate()} {
+		lf := f.Lazy().WithColumn("lat", e)
+		op, err := Compile(Optimize(lf.Plan()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := op.(*materializeExecOp); !ok {
+			t.Fatalf("%s should materialize, got %T", e, op)
+		}
+		lazy, err := lf.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		eager, err := f.WithColumnExpr("lat", e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, b := valuesOrNull(t, lazy, "lat"), valuesOrNull(t, eager, "lat"); !slices.Equal(a, b) {
+			t.Fatalf("%s: lazy %v, eager %v", e, a, b)
+		}
+	}
+
+	all, err := f.Lazy().DropNulls().Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.NumRows() != 4 {
+		t.Fatalf("lazy DropNulls() kept %d rows, want 4", all.NumRows())
+	}
+	if got := Col("lat").FillNullWith(FillMean).String(); got != `col("lat").fill_null(strategy=mean)` {
+		t.Fatalf("String = %s", got)
+	}
+}
+
+// TestExprFill_ForwardStreamsAcrossBatches — a forward fill stays on
+// the streaming path and carries the last value over batch
+// boundaries: the lat column is split into chunks, so each becomes
+// its own batch, and two of them start with nulls.
+func TestExprFill_ForwardStreamsAcrossBatches(t *testing.T) {
+	lat := mustColumn(t, trackFrame(t), "lat")
+	chunked, err := lat.slice(0, 2).Concat(lat.slice(2, 4), lat.slice(4, 6), lat.slice(6, 9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewFrame(arrow.NewSchema([]arrow.Field{chunked.field}, nil), []arrow.Column{*chunked.col})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := f.Lazy().WithColumn("ffill", Col("lat").FillNullWith(FillForward))
+	op, err := Compile(Optimize(lf.Plan()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := op.(*materializeExecOp); ok {
+		t.Fatal("FillNullWith(FillForward) should stream, got materializeExecOp")
+	}
+	op.Close()
+	eager, err := f.WithColumnExpr("ffill", Col("lat").FillNullWith(FillForward))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := valuesOrNull(t, eager, "ffill")
+	// Twice: each Collect compiles fresh fill state.
+	for range 2 {
+		lazy, err := lf.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := valuesOrNull(t, lazy, "ffill"); !slices.Equal(got, want) {
+			t.Fatalf("streamed ffill = %v, eager %v", got, want)
+		}
+	}
+	if want[1] != "10" || want[6] != "40" {
+		t.Fatalf("eager ffill = %v", want)
+	}
+}
@@ -0,0 +1,476 @@
+package gobi
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// -----------------------------------------------------------------------------
+// Fluent constructors
+// -----------------------------------------------------------------------------
+
+// Geom returns the geometry namespace for e. e must produce a geometry
+// column (WKB Binary tagged via GeometryField). The namespace mirrors
+// the eager Series.Geom* methods as expression nodes, so geometry work
+// can sit inside FilterExpr, WithColumnExpr, LazyFrame.Filter /
+// WithColumn / Select, GroupBy filters and Over windows — and, on a
+// lazy plan, stream: the nodes are row-wise, so Compile fuses them
+// into the surrounding per-batch chain like any other expression.
+//
+//	// Buildings larger than 500 m² within 1 km of the depot, computed
+//	// per batch straight off the scan.
+//	depot := gobi.LitGeom(geometry.Point{X: 500000, Y: 4649776, CRSValue: utm})
+//	out, err := parquetio.ScanFile("buildings.parquet", nil).
+//	    Filter(gobi.Col("geometry").Geom().Area(geometry.UnitMeters).Gt(gobi.Lit(500.0)).
+//	        And(gobi.Col("geometry").Geom().DWithin(depot, 1, geometry.UnitKilometers))).
+//	    Collect()
+//
+// Semantics match the Series methods: null geometries yield null
+// results, and the column's CRS (from its field metadata) is attached
+// to every decoded geometry. Geometry-valued results (Centroid, Buffer,
+// Simplify, ToCRS) come back as GeometryField columns, so they chain
+// into further Geom() calls.
+func (e Expr) Geom() GeomNamespace { return GeomNamespace{e: e} }
+
+// GeomNamespace groups the geometry expression constructors returned by
+// Expr.Geom. Like StrNamespace it only wraps the expression; every
+// method returns a plain Expr.
+type GeomNamespace struct {
+	e Expr
+}
+
+// Area returns each geometry's planar area in u² (Float64). See
+// Series.GeomArea.
+func (g GeomNamespace) Area(u geometry.Unit) Expr {
+	return g.op(&geomUnaryNode{kind: gkArea, unit: u})
+}
+
+// Length returns each geometry's planar length in u (Float64). See
+// Series.GeomLength.
+func (g GeomNamespace) Length(u geometry.Unit) Expr {
+	return g.op(&geomUnaryNode{kind: gkLength, unit: u})
+}
+
+// Centroid returns each geometry's centroid as a Point geometry. See
+// Series.GeomCentroid.
+func (g GeomNamespace) Centroid() Expr { return g.op(&geomUnaryNode{kind: gkCentroid}) }
+
+// Buffer returns each geometry buffered by distance, in the CRS's
+// linear unit. See Series.GeomBuffer.
+func (g GeomNamespace) Buffer(distance float64, opts geometry.BufferOptions) Expr {
+	return g.op(&geomUnaryNode{kind: gkBuffer, amount: distance, bufOpts: opts})
+}
+
+// Simplify returns each geometry simplified with Douglas-Peucker at
+// tolerance, in the CRS's linear unit. See Series.GeomSimplify.
+func (g GeomNamespace) Simplify(tolerance float64) Expr {
+	return g.op(&geomUnaryNode{kind: gkSimplify, amount: tolerance})
+}
+
+// ToCRS reprojects each geometry into target; the result column is
+// tagged with target's EPSG. See Series.GeomToCRS.
+func (g GeomNamespace) ToCRS(target geometry.CRS) Expr {
+	return g.op(&geomUnaryNode{kind: gkToCRS, target: target})
+}
+
+// MinX returns the minimum X of each geometry's bounding box (Float64).
+// Together with MinY / MaxX / MaxY this is Series.GeomBounds one
+// column at a time.
+func (g GeomNamespace) MinX() Expr { return g.op(&geomUnaryNode{kind: gkMinX}) }
+
+// MinY returns the minimum Y of each geometry's bounding box.
+func (g GeomNamespace) MinY() Expr { return g.op(&geomUnaryNode{kind: gkMinY}) }
+
+// MaxX returns the maximum X of each geometry's bounding box.
+func (g GeomNamespace) MaxX() Expr { return g.op(&geomUnaryNode{kind: gkMaxX}) }
+
+// MaxY returns the maximum Y of each geometry's bounding box.
+func (g GeomNamespace) MaxY() Expr { return g.op(&geomUnaryNode{kind: gkMaxY}) }
+
+// Distance returns the minimum planar distance, in u, from each
+// geometry to other — another geometry column, evaluated row by row,
+// or a LitGeom, decoded once. Null on either side yields null.
+func (g GeomNamespace) Distance(other Expr, u geometry.Unit) Expr {
+	return Expr{node: &geomBinaryNode{left: g.e.node, right: other.node, distance: true, unit: u}}
+}
+
+// Predicate tests pred between each geometry and other (a geometry
+// column or a LitGeom), producing a Boolean column. It accepts every
+// SpatialPredicate SJoin does, SPDWithin included, with the geometry
+// on the left of the predicate: Col("a").Geom().Predicate(SPWithin, b)
+// is "a within b". The named methods below are shorthands.
+func (g GeomNamespace) Predicate(pred SpatialPredicate, other Expr) Expr {
+	return Expr{node: &geomBinaryNode{left: g.e.node, right: other.node, pred: orIntersects(pred)}}
+}
+
+// Intersects is Predicate(SPIntersects, other).
+func (g GeomNamespace) Intersects(other Expr) Expr { return g.Predicate(SPIntersects, other) }
+
+// Contains is Predicate(SPContains, other).
+func (g GeomNamespace) Contains(other Expr) Expr { return g.Predicate(SPContains, other) }
+
+// Within is Predicate(SPWithin, other).
+func (g GeomNamespace) Within(other Expr) Expr { return g.Predicate(SPWithin, other) }
+
+// Touches is Predicate(SPTouches, other).
+func (g GeomNamespace) Touches(other Expr) Expr { return g.Predicate(SPTouches, other) }
+
+// Overlaps is Predicate(SPOverlaps, other).
+func (g GeomNamespace) Overlaps(other Expr) Expr { return g.Predicate(SPOverlaps, other) }
+
+// Crosses is Predicate(SPCrosses, other).
+func (g GeomNamespace) Crosses(other Expr) Expr { return g.Predicate(SPCrosses, other) }
+
+// Covers is Predicate(SPCovers, other).
+func (g GeomNamespace) Covers(other Expr) Expr { return g.Predicate(SPCovers, other) }
+
+// CoveredBy is Predicate(SPCoveredBy, other).
+func (g GeomNamespace) CoveredBy(other Expr) Expr { return g.Predicate(SPCoveredBy, other) }
+
+// DWithin is Predicate(SPDWithin(distance, u), other).
+func (g GeomNamespace) DWithin(other Expr, distance float64, u geometry.Unit) Expr {
+	return g.Predicate(SPDWithin(distance, u), other)
+}
+
+// Disjoint is the negation of Intersects; null stays null.
+func (g GeomNamespace) Disjoint(other Expr) Expr { return g.Intersects(other).Not() }
+
+func (g GeomNamespace) op(n *geomUnaryNode) Expr {
+	n.inner = g.e.node
+	return Expr{node: n}
+}
+
+// LitGeom returns an expression that broadcasts geom, WKB-encoded and
+// tagged with geom's CRS, to every input row. Use it as the `other`
+// operand of the Geom() predicates and Distance: those recognize a
+// LitGeom and decode it once instead of once per row.
+//
+// A LitGeom with no CRS takes on the CRS of the column it is compared
+// against, matching the Series.Geom* methods' handling of `other`.
+func LitGeom(geom geometry.Geometry) Expr {
+	return Expr{node: &geomLiteralNode{geom: geom}}
+}
+
+// -----------------------------------------------------------------------------
+// geomUnaryNode — single-column geometry ops
+// -----------------------------------------------------------------------------
+
+type geomKind int
+
+const (
+	gkArea geomKind = iota
+	gkLength
+	gkCentroid
+	gkBuffer
+	gkSimplify
+	gkToCRS
+	gkMinX
+	gkMinY
+	gkMaxX
+	gkMaxY
+)
+
+var geomKindNames = [...]string{
+	gkArea:     "geom_area",
+	gkLength:   "geom_length",
+	gkCentroid: "geom_centroid",
+	gkBuffer:   "geom_buffer",
+	gkSimplify: "geom_simplify",
+	gkToCRS:    "geom_to_crs",
+	gkMinX:     "geom_minx",
+	gkMinY:     "geom_miny",
+	gkMaxX:     "geom_maxx",
+	gkMaxY:     "geom_maxy",
+}
+
+func (k geomKind) String() string { return geomKindNames[k] }
+
+// geomUnaryNode delegates to the Series.Geom* method for its kind, so
+// the expression and eager forms can't drift apart. The bounds
+// components have no single-column Series method and use
+// geomFloat64Op directly.
+type geomUnaryNode struct {
+	inner   ExprNode
+	kind    geomKind
+	unit    geometry.Unit
+	amount  float64 // buffer distance or simplify tolerance
+	bufOpts geometry.BufferOptions
+	target  geometry.CRS
+}
+
+func (n *geomUnaryNode) Eval(input *Frame) (Series, error) {
+	if n.inner == nil {
+		return Series{}, fmt.Errorf("gobi: %s on nil inner expression", n.kind)
+	}
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	if !s.IsGeometry() {
+		return Series{}, fmt.Errorf("%w: %s got %s column %q",
+			ErrNotGeometry, n.kind, s.DataType(), s.Name())
+	}
+	switch n.kind {
+	case gkArea:
+		return s.GeomArea(n.unit)
+	case gkLength:
+		return s.GeomLength(n.unit)
+	case gkCentroid:
+		return s.GeomCentroid()
+	case gkBuffer:
+		return s.GeomBuffer(n.amount, n.bufOpts)
+	case gkSimplify:
+		return s.GeomSimplify(n.amount)
+	case gkToCRS:
+		return s.GeomToCRS(n.target)
+	}
+	return geomFloat64Op(s, s.name+"_"+n.kind.String(), func(g geometry.Geometry) (float64, bool, error) {
+		b := g.Bounds()
+		switch n.kind {
+		case gkMinX:
+			return b.MinX, true, nil
+		case gkMinY:
+			return b.MinY, true, nil
+		case gkMaxX:
+			return b.MaxX, true, nil
+		}
+		return b.MaxY, true, nil
+	})
+}
+
+func (n *geomUnaryNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if err := checkGeomOperand(n.inner, schema, n.kind.String()); err != nil {
+		return nil, err
+	}
+	switch n.kind {
+	case gkCentroid, gkBuffer, gkSimplify, gkToCRS:
+		return arrow.BinaryTypes.Binary, nil
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+func (n *geomUnaryNode) Children() []Expr { return []Expr{{node: n.inner}} }
+
+func (n *geomUnaryNode) String() string {
+	switch n.kind {
+	case gkArea, gkLength:
+		return fmt.Sprintf("%s(%s, %s)", n.kind, n.inner, n.unit)
+	case gkBuffer, gkSimplify:
+		return fmt.Sprintf("%s(%s, %g)", n.kind, n.inner, n.amount)
+	case gkToCRS:
+		return fmt.Sprintf("%s(%s, %s)", n.kind, n.inner, n.target)
+	}
+	return fmt.Sprintf("%s(%s)", n.kind, n.inner)
+}
+
+// -----------------------------------------------------------------------------
+// geomBinaryNode — predicates and distance between two geometries
+// -----------------------------------------------------------------------------
+
+// geomBinaryNode evaluates either a SpatialPredicate (Boolean) or,
+// when distance is set, GeomDistance in unit (Float64) between left
+// and right row by row. A LitGeom right operand is decoded once
+// rather than broadcast and re-parsed per row.
+type geomBinaryNode struct {
+	left, right ExprNode
+	pred        SpatialPredicate
+	distance    bool
+	unit        geometry.Unit
+}
+
+func (n *geomBinaryNode) label() string {
+	if n.distance {
+		return "geom_distance"
+	}
+	return "geom_" + n.pred.String()
+}
+
+func (n *geomBinaryNode) Eval(input *Frame) (Series, error) {
+	if n.left == nil || n.right == nil {
+		return Series{}, fmt.Errorf("gobi: %s on nil operand", n.label())
+	}
+	var test func(l, r geometry.Geometry) bool
+	if !n.distance {
+		m, err := n.pred.matcher()
+		if err != nil {
+			return Series{}, err
+		}
+		test = m.test
+	} else if _, err := geometry.MetersPerUnit(n.unit); err != nil {
+		return Series{}, err
+	}
+
+	ls, err := n.left.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	lGeoms, lCRS, err := n.decodeOperand(ls)
+	if err != nil {
+		return Series{}, err
+	}
+	rGeomAt, rCRS, err := n.rightOperand(input, len(lGeoms))
+	if err != nil {
+		return Series{}, err
+	}
+	switch {
+	case rCRS.Zero():
+		rCRS = lCRS
+	case lCRS.Zero():
+		lCRS = rCRS
+	case !lCRS.Equal(rCRS):
+		return Series{}, fmt.Errorf("gobi: %s: CRS mismatch (EPSG %d vs %d); reproject one side with Geom().ToCRS",
+			n.label(), lCRS.EPSG, rCRS.EPSG)
+	}
+
+	pool := memory.DefaultAllocator
+	if n.distance {
+		b := array.NewFloat64Builder(pool)
+		defer b.Release()
+		for i, l := range lGeoms {
+			r := rGeomAt(i)
+			if l == nil || r == nil {
+				b.AppendNull()
+				continue
+			}
+			d, err := geometry.GeomDistance(attachCRS(l, lCRS), attachCRS(r, rCRS), n.unit)
+			if err != nil {
+				return Series{}, err
+			}
+			b.Append(d)
+		}
+		return buildSeries(n.label(), arrow.PrimitiveTypes.Float64, b.NewArray()), nil
+	}
+	b := array.NewBooleanBuilder(pool)
+	defer b.Release()
+	for i, l := range lGeoms {
+		r := rGeomAt(i)
+		if l == nil || r == nil {
+			b.AppendNull()
+			continue
+		}
+		b.Append(test(attachCRS(l, lCRS), attachCRS(r, rCRS)))
+	}
+	return buildSeries(n.label(), arrow.FixedWidthTypes.Boolean, b.NewArray()), nil
+}
+
+// decodeOperand checks s is a geometry column and decodes it, returning
+// the column's CRS alongside.
+func (n *geomBinaryNode) decodeOperand(s Series) ([]geometry.Geometry, geometry.CRS, error) {
+	if !s.IsGeometry() {
+		return nil, geometry.CRS{}, fmt.Errorf("%w: %s got %s column %q",
+			ErrNotGeometry, n.label(), s.DataType(), s.Name())
+	}
+	geoms, err := decodeGeometryColumn(s)
+	if err != nil {
+		return nil, geometry.CRS{}, err
+	}
+	crs, _ := geometry.LookupCRS(geometryCRSFromField(s.field))
+	return geoms, crs, nil
+}
+
+// rightOperand returns a row accessor for the right operand: the
+// literal geometry for every row on the LitGeom fast path, the decoded
+// column otherwise.
+func (n *geomBinaryNode) rightOperand(input *Frame, rows int) (func(int) geometry.Geometry, geometry.CRS, error) {
+	if lit, ok := n.right.(*geomLiteralNode); ok {
+		if lit.geom == nil {
+			return nil, geometry.CRS{}, fmt.Errorf("gobi: %s: nil LitGeom", n.label())
+		}
+		return func(int) geometry.Geometry { return lit.geom }, lit.geom.CRS(), nil
+	}
+	rs, err := n.right.Eval(input)
+	if err != nil {
+		return nil, geometry.CRS{}, err
+	}
+	geoms, crs, err := n.decodeOperand(rs)
+	if err != nil {
+		return nil, geometry.CRS{}, err
+	}
+	if len(geoms) != rows {
+		return nil, geometry.CRS{}, fmt.Errorf("%w: %s operands have %d and %d rows",
+			ErrColumnLenMismatch, n.label(), rows, len(geoms))
+	}
+	return func(i int) geometry.Geometry { return geoms[i] }, crs, nil
+}
+
+func (n *geomBinaryNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if err := checkGeomOperand(n.left, schema, n.label()); err != nil {
+		return nil, err
+	}
+	if err := checkGeomOperand(n.right, schema, n.label()); err != nil {
+		return nil, err
+	}
+	if n.distance {
+		return arrow.PrimitiveTypes.Float64, nil
+	}
+	return arrow.FixedWidthTypes.Boolean, nil
+}
+
+func (n *geomBinaryNode) Children() []Expr { return []Expr{{node: n.left}, {node: n.right}} }
+
+func (n *geomBinaryNode) String() string {
+	if n.distance {
+		return fmt.Sprintf("geom_distance(%s, %s, %s)", n.left, n.right, n.unit)
+	}
+	return fmt.Sprintf("geom_%s(%s, %s)", n.pred, n.left, n.right)
+}
+
+// -----------------------------------------------------------------------------
+// geomLiteralNode: `LitGeom(g)`
+// -----------------------------------------------------------------------------
+
+type geomLiteralNode struct {
+	geom geometry.Geometry
+}
+
+func (n *geomLiteralNode) Eval(input *Frame) (Series, error) {
+	if n.geom == nil {
+		return Series{}, fmt.Errorf("gobi: nil LitGeom")
+	}
+	wkb := geometry.WKB(n.geom)
+	b := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+	defer b.Release()
+	for range input.NumRows() {
+		b.Append(wkb)
+	}
+	return SeriesFromArray(GeometryField("lit_geom", n.geom.CRS().EPSG), b.NewArray()), nil
+}
+
+func (n *geomLiteralNode) Type(*arrow.Schema) (arrow.DataType, error) {
+	if n.geom == nil {
+		return nil, fmt.Errorf("gobi: nil LitGeom")
+	}
+	return arrow.BinaryTypes.Binary, nil
+}
+
+func (n *geomLiteralNode) Children() []Expr { return nil }
+
+func (n *geomLiteralNode) String() string {
+	if n.geom == nil {
+		return "lit_geom(<nil>)"
+	}
+	return fmt.Sprintf("lit_geom(%s)", n.geom.WKT())
+}
+
+// checkGeomOperand type-checks n against schema. The plan schema only
+// carries the arrow type, so Binary is as far as inference can confirm;
+// the geometry tag itself is checked at Eval.
+func checkGeomOperand(n ExprNode, schema *arrow.Schema, op string) error {
+	if n == nil {
+		return fmt.Errorf("gobi: %s on nil inner expression", op)
+	}
+	t, err := n.Type(schema)
+	if err != nil {
+		return err
+	}
+	if t.ID() != arrow.BINARY {
+		return fmt.Errorf("%w: %s requires a geometry column, got %s",
+			ErrNotGeometry, op, t)
+	}
+	return nil
+}
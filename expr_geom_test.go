@@ -0,0 +1,224 @@
+package gobi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// parcelsFrame builds (id string, zone, pt) in EPSG 3857: zone is a
+// square of side 2r centred on (cx, 0); pt is one probe point per row.
+// The last row has null geometries on both sides.
+func parcelsFrame(t *testing.T) *Frame {
+	t.Helper()
+	sq := func(cx, r float64) geometry.Geometry {
+		return geometry.SimplePolygon([]geometry.Point{
+			{X: cx - r, Y: -r}, {X: cx + r, Y: -r}, {X: cx + r, Y: r}, {X: cx - r, Y: r}, {X: cx - r, Y: -r},
+		}, geometry.PseudoMercator)
+	}
+	pt := func(x, y float64) geometry.Geometry { return geometry.Point{X: x, Y: y} }
+	type idRow struct {
+		ID string `gobi:"id"`
+	}
+	ids, err := FromStructs([]idRow{{"a"}, {"b"}, {"c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zone := geomSeries(t, "zone", 3857, []geometry.Geometry{sq(0, 1), sq(10, 2), nil})
+	probe := geomSeries(t, "pt", 3857, []geometry.Geometry{pt(0.5, 0.5), pt(20, 0), nil})
+	f, err := ids.WithColumn("zone", zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, err = f.WithColumn("pt", probe); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// floatsOrNaN returns a Float64 column with NaN marking nulls.
+func floatsOrNaN(t *testing.T, f *Frame, name string) []float64 {
+	t.Helper()
+	s := mustColumn(t, f, name)
+	vals, err := s.Float64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, null := range s.Nulls() {
+		if null {
+			vals[i] = math.NaN()
+		}
+	}
+	return vals
+}
+
+// boolsOrNull renders a Boolean column as "true"/"false"/"null".
+func boolsOrNull(t *testing.T, f *Frame, name string) []string {
+	t.Helper()
+	s := mustColumn(t, f, name)
+	vals, err := s.Bools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]string, len(vals))
+	for i, null := range s.Nulls() {
+		switch {
+		case null:
+			out[i] = "null"
+		case vals[i]:
+			out[i] = "true"
+		default:
+			out[i] = "false"
+		}
+	}
+	return out
+}
+
+func floatsClose(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) {
+			return false
+		}
+		if !math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestExprGeom_Measures(t *testing.T) {
+	f := parcelsFrame(t)
+	zone := Col("zone").Geom()
+	nan := math.NaN()
+	cases := []struct {
+		name string
+		expr Expr
+		want []float64
+	}{
+		{"area", zone.Area(geometry.UnitMeters), []float64{4, 16, nan}},
+		// Non-linear geometries have length 0, as in Series.GeomLength.
+		{"length", zone.Length(geometry.UnitMeters), []float64{0, 0, nan}},
+		{"minx", zone.MinX(), []float64{-1, 8, nan}},
+		{"maxy", zone.MaxY(), []float64{1, 2, nan}},
+		// Geometry results chain into further Geom() calls.
+		{"centroid_x", zone.Centroid().Geom().MinX(), []float64{0, 10, nan}},
+		{"buffer_bounds", zone.Buffer(1, geometry.BufferOptions{Style: geometry.BufferSquare}).Geom().MaxX(), []float64{2, 13, nan}},
+		{"distance_col", zone.Distance(Col("pt"), geometry.UnitMeters), []float64{0, 8, nan}},
+		{"distance_lit", zone.Distance(LitGeom(geometry.Point{X: 0, Y: 5}), geometry.UnitMeters), []float64{4, math.Hypot(8, 3), nan}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := floatsOrNaN(t, out, "out"); !floatsClose(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprGeom_Predicates(t *testing.T) {
+	f := parcelsFrame(t)
+	zone := Col("zone").Geom()
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"contains_col", zone.Contains(Col("pt")), []string{"true", "false", "null"}},
+		{"within_col", Col("pt").Geom().Within(Col("zone")), []string{"true", "false", "null"}},
+		{"intersects_lit", zone.Intersects(LitGeom(geometry.Point{X: 9, Y: 0})), []string{"false", "true", "null"}},
+		{"disjoint_lit", zone.Disjoint(LitGeom(geometry.Point{X: 9, Y: 0})), []string{"true", "false", "null"}},
+		{"dwithin_col", zone.DWithin(Col("pt"), 8, geometry.UnitMeters), []string{"true", "true", "null"}},
+		{"dwithin_km", zone.DWithin(Col("pt"), 0.007, geometry.UnitKilometers), []string{"true", "false", "null"}},
+		{"covered_by", Col("pt").Geom().Predicate(SPCoveredBy, Col("zone")), []string{"true", "false", "null"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boolsOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprGeom_ToCRSKeepsGeometryTag(t *testing.T) {
+	f := parcelsFrame(t)
+	out, err := f.WithColumnExpr("wgs", Col("zone").Geom().Centroid().Geom().ToCRS(geometry.WGS84))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := mustColumn(t, out, "wgs")
+	if !s.IsGeometry() || geometryCRSFromField(s.field) != 4326 {
+		t.Fatalf("field = %v, want geometry tagged EPSG 4326", s.field)
+	}
+	// x = 10 m east of the origin is a hair under 1e-4 degrees.
+	if out, err = out.WithColumnExpr("lon", Col("wgs").Geom().MinX()); err != nil {
+		t.Fatal(err)
+	}
+	lon := floatsOrNaN(t, out, "lon")
+	if lon[0] != 0 || math.Abs(lon[1]-10/111319.49079327357) > 1e-9 {
+		t.Fatalf("lon = %v", lon)
+	}
+}
+
+func TestExprGeom_LazyFilterStreams(t *testing.T) {
+	f := parcelsFrame(t)
+	pred := Col("zone").Geom().Area(geometry.UnitMeters).Gt(Lit(10.0)).
+		Or(Col("zone").Geom().Contains(Col("pt")))
+	lf := f.Lazy().
+		WithColumn("cx", Col("zone").Geom().Centroid().Geom().MinX()).
+		Filter(pred)
+	if plan := lf.ExplainPhysical(); !strings.Contains(plan, "StreamingFilter(((geom_area(") ||
+		!strings.Contains(plan, `StreamingWithColumn("cx" = geom_minx(geom_centroid(col("zone"))))`) {
+		t.Fatalf("geometry expressions should stream:\n%s", plan)
+	}
+	out, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strValues(t, out, "id"); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("ids = %v, want [a b]", got)
+	}
+	if got := floatsOrNaN(t, out, "cx"); !floatsClose(got, []float64{0, 10}) {
+		t.Fatalf("cx = %v", got)
+	}
+	if got := lf.Schema().Field(3); got.Name != "cx" || !arrow.TypeEqual(got.Type, arrow.PrimitiveTypes.Float64) {
+		t.Fatalf("plan field = %v, want cx Float64", got)
+	}
+}
+
+func TestExprGeom_Errors(t *testing.T) {
+	f := parcelsFrame(t)
+	if _, err := f.WithColumnExpr("out", Col("id").Geom().Area(geometry.UnitMeters)); !errors.Is(err, ErrNotGeometry) {
+		t.Fatalf("non-geometry input: err = %v, want ErrNotGeometry", err)
+	}
+	if _, err := Col("id").Geom().Intersects(Col("zone")).node.Type(f.Schema()); !errors.Is(err, ErrNotGeometry) {
+		t.Fatalf("type inference: err = %v, want ErrNotGeometry", err)
+	}
+	wgs := LitGeom(geometry.Point{X: 0, Y: 0, CRSValue: geometry.WGS84})
+	if _, err := f.WithColumnExpr("out", Col("zone").Geom().Intersects(wgs)); err == nil || !strings.Contains(err.Error(), "CRS mismatch") {
+		t.Fatalf("err = %v, want CRS mismatch", err)
+	}
+	if _, err := f.WithColumnExpr("out", Col("zone").Geom().Distance(Col("pt"), "furlongs")); !errors.Is(err, geometry.ErrInvalidUnit) {
+		t.Fatalf("err = %v, want ErrInvalidUnit", err)
+	}
+	if _, err := f.WithColumnExpr("out", Col("zone").Geom().DWithin(Col("pt"), -1, geometry.UnitMeters)); err == nil {
+		t.Fatal("expected error for negative DWithin distance")
+	}
+}
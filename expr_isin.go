@@ -0,0 +1,449 @@
+package gobi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// IsIn returns a Boolean expression that is true where e's value
+// equals one of values. values is either a slice of Go scalars —
+// []int64, []string, []time.Time, []any, and so on — or a Series,
+// whose non-null values are used. A nil entry never matches. Null
+// inputs yield null, as they do for Eq.
+//
+//	vips := gobi.Col("user_id").IsIn([]int64{1001, 1002, 1003})
+//	ids, _ := yesterday.Column("user_id")
+//	seen := gobi.Col("user_id").IsIn(ids)
+//
+// The values are loaded into a hash set once per Eval, so a 10k-entry
+// list costs one map probe per row rather than a 10k-deep Or(Eq(...))
+// chain. The input may be any column kind group-by can key on:
+// String, LargeString, Int32/64, Uint32/64, Float64, Boolean and
+// Timestamp. Comparison follows Eq's numeric promotion: an int value
+// matches a Float64 row of the same magnitude, and a fractional value
+// never matches an integer column. Timestamp columns take time.Time
+// values, compared as instants. Mixing kinds (a string against a
+// numeric column) is ErrExprTypeMismatch.
+//
+// Parquet scans use IsIn for row-group skipping: a row group is read
+// only if some value lies within its min/max and, when the column has
+// a bloom filter, the filter admits it. See CanPossiblyMatch.
+func (e Expr) IsIn(values any) Expr {
+	vals, err := membershipValues(values)
+	return Expr{node: &isInNode{input: e.node, values: vals, err: err}}
+}
+
+// NotIn is the negation of IsIn: true where e's value equals none of
+// values. Null inputs still yield null — a missing value is neither
+// in nor out of the list — which matches SQL's NOT IN over a list
+// without NULLs, so Filter(NotIn(...)) drops null rows.
+func (e Expr) NotIn(values any) Expr {
+	vals, err := membershipValues(values)
+	return Expr{node: &isInNode{input: e.node, values: vals, err: err, negate: true}}
+}
+
+// membershipValues flattens a slice of scalars or a Series into
+// canonical Go types — signed ints as int64, unsigned as uint64,
+// floats as float64, plus string, bool and time.Time — so every
+// consumer (Eval, String, CanPossiblyMatch, ExprToSQL) sees one
+// representation.
+func membershipValues(values any) ([]any, error) {
+	if s, ok := values.(Series); ok {
+		return seriesMembershipValues(s)
+	}
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: IsIn wants a slice of values or a Series, got %T",
+			ErrExprTypeMismatch, values)
+	}
+	out := make([]any, 0, rv.Len())
+	for i := range rv.Len() {
+		v := rv.Index(i).Interface()
+		if v == nil {
+			continue
+		}
+		cv, ok := membershipValue(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: IsIn value of type %T", ErrUnsupportedLiteral, v)
+		}
+		out = append(out, cv)
+	}
+	return out, nil
+}
+
+// seriesMembershipValues reads the non-null values of s. Timestamps
+// become time.Time in s's unit, so a Series built at millisecond
+// precision still matches a nanosecond column at the same instant.
+func seriesMembershipValues(s Series) ([]any, error) {
+	if s.col == nil {
+		return nil, fmt.Errorf("%w: IsIn on an empty Series", ErrExprTypeMismatch)
+	}
+	if !isHashable(s.DataType()) {
+		return nil, fmt.Errorf("%w: IsIn values of type %s", ErrExprTypeMismatch, s.DataType())
+	}
+	out := make([]any, 0, s.Len()-s.NullCount())
+	for row := range s.Len() {
+		v, err := readScalarAt(s, row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if ts, ok := v.(arrow.Timestamp); ok {
+			v = ts.ToTime(s.DataType().(*arrow.TimestampType).Unit)
+		}
+		cv, _ := membershipValue(v)
+		out = append(out, cv)
+	}
+	return out, nil
+}
+
+// membershipValue maps one Go scalar to its canonical form; ok=false
+// for types IsIn can't compare.
+func membershipValue(v any) (any, bool) {
+	switch x := v.(type) {
+	case bool, string, int64, uint64, float64:
+		return x, true
+	case time.Time:
+		return x, true
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case uint:
+		return uint64(x), true
+	case uint8:
+		return uint64(x), true
+	case uint16:
+		return uint64(x), true
+	case uint32:
+		return uint64(x), true
+	case float32:
+		return float64(x), true
+	}
+	return nil, false
+}
+
+// Between returns a Boolean expression that is true where
+// lo <= e <= hi, both ends inclusive, as SQL's BETWEEN. It evaluates
+// exactly as e.Ge(lo).And(e.Le(hi)) — same numeric promotion, null
+// wherever any operand is null — but stays one node, so Explain reads
+// as written, ExprToSQL emits BETWEEN and parquet scans prune row
+// groups whose min/max range misses [lo, hi].
+//
+//	mid := gobi.Col("price").Between(gobi.Lit(10.0), gobi.Lit(20.0))
+func (e Expr) Between(lo, hi Expr) Expr {
+	return Expr{node: &betweenNode{
+		input:   e.node,
+		lo:      lo.node,
+		hi:      hi.node,
+		lowered: e.Ge(lo).And(e.Le(hi)).node,
+	}}
+}
+
+// -----------------------------------------------------------------------------
+// isInNode
+// -----------------------------------------------------------------------------
+
+// isInNode tests each row of input against a fixed list of values;
+// negate flips non-null results for NotIn. err parks a bad values
+// argument so the fluent constructor stays error-free; it surfaces at
+// Type and Eval.
+type isInNode struct {
+	input  ExprNode
+	values []any
+	err    error
+	negate bool
+}
+
+func (n *isInNode) Eval(input *Frame) (Series, error) {
+	if n.err != nil {
+		return Series{}, n.err
+	}
+	s, err := n.input.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	set, err := newValueSet(s.DataType(), n.values)
+	if err != nil {
+		return Series{}, err
+	}
+	b := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer b.Release()
+	b.Reserve(s.Len())
+	for _, chunk := range s.col.Data().Chunks() {
+		if err := set.appendMatches(b, chunk, n.negate); err != nil {
+			return Series{}, err
+		}
+	}
+	return buildSeries("", arrow.FixedWidthTypes.Boolean, b.NewArray()), nil
+}
+
+func (n *isInNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	dt, err := n.input.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := newValueSet(dt, n.values); err != nil {
+		return nil, err
+	}
+	return arrow.FixedWidthTypes.Boolean, nil
+}
+
+func (n *isInNode) Children() []Expr { return []Expr{{node: n.input}} }
+
+// isInShownValues caps how many values String spells out; longer
+// lists print a count and a digest of the full list instead, so a
+// 10k-ID filter keeps Explain readable while two different lists
+// still render differently.
+const isInShownValues = 8
+
+func (n *isInNode) String() string {
+	var b strings.Builder
+	op := "IN"
+	if n.negate {
+		op = "NOT IN"
+	}
+	fmt.Fprintf(&b, "%s %s [", n.input, op)
+	for i, v := range n.values {
+		if i == isInShownValues {
+			h := fnv.New32a()
+			for _, v := range n.values {
+				fmt.Fprintf(h, "%#v\x00", v)
+			}
+			fmt.Fprintf(&b, ", … %d values #%08x", len(n.values), h.Sum32())
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(formatLiteral(v))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// formatLiteral renders a membership value: strings quoted, times in
+// RFC 3339, other scalars bare.
+func formatLiteral(v any) string {
+	switch x := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// -----------------------------------------------------------------------------
+// betweenNode
+// -----------------------------------------------------------------------------
+
+// betweenNode is `lo <= input <= hi`. Evaluation and typing delegate
+// to lowered, the equivalent Ge/And/Le tree, so Between can never
+// drift from the comparison kernels; the separate node exists for
+// String, ExprToSQL and CanPossiblyMatch, which want the bounds as a
+// pair.
+type betweenNode struct {
+	input, lo, hi ExprNode
+	lowered       ExprNode
+}
+
+func (n *betweenNode) Eval(input *Frame) (Series, error) { return n.lowered.Eval(input) }
+
+func (n *betweenNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	return n.lowered.Type(schema)
+}
+
+func (n *betweenNode) Children() []Expr {
+	return []Expr{{node: n.input}, {node: n.lo}, {node: n.hi}}
+}
+
+func (n *betweenNode) String() string {
+	return fmt.Sprintf("(%s BETWEEN %s AND %s)", n.input, n.lo, n.hi)
+}
+
+// -----------------------------------------------------------------------------
+// valueSet
+// -----------------------------------------------------------------------------
+
+// valueSet holds the IsIn values converted to the key type of one
+// column. Only the map for that column's kind is populated; Timestamp
+// columns share ints, keyed by ticks in the column's unit.
+type valueSet struct {
+	ints   map[int64]struct{}
+	uints  map[uint64]struct{}
+	floats map[float64]struct{}
+	strs   map[string]struct{}
+	bools  [2]bool
+}
+
+// newValueSet converts values to dt's key type. Values that can't
+// equal any row of dt (1.5 or -1 against an integer column, NaN
+// anywhere, an instant finer than a Timestamp's unit) are dropped;
+// values of another kind are ErrExprTypeMismatch.
+func newValueSet(dt arrow.DataType, values []any) (*valueSet, error) {
+	set := &valueSet{}
+	mismatch := func(v any) error {
+		return fmt.Errorf("%w: IsIn value %v (%T) against %s column",
+			ErrExprTypeMismatch, v, v, dt)
+	}
+	switch dt.ID() {
+	case arrow.INT64, arrow.INT32:
+		set.ints = make(map[int64]struct{}, len(values))
+		for _, v := range values {
+			switch x := v.(type) {
+			case int64:
+				set.ints[x] = struct{}{}
+			case uint64:
+				if x <= math.MaxInt64 {
+					set.ints[int64(x)] = struct{}{}
+				}
+			case float64:
+				if x == math.Trunc(x) && x >= math.MinInt64 && x < math.MaxInt64 {
+					set.ints[int64(x)] = struct{}{}
+				}
+			default:
+				return nil, mismatch(v)
+			}
+		}
+	case arrow.UINT64, arrow.UINT32:
+		set.uints = make(map[uint64]struct{}, len(values))
+		for _, v := range values {
+			switch x := v.(type) {
+			case uint64:
+				set.uints[x] = struct{}{}
+			case int64:
+				if x >= 0 {
+					set.uints[uint64(x)] = struct{}{}
+				}
+			case float64:
+				if x == math.Trunc(x) && x >= 0 && x < math.MaxUint64 {
+					set.uints[uint64(x)] = struct{}{}
+				}
+			default:
+				return nil, mismatch(v)
+			}
+		}
+	case arrow.FLOAT64:
+		set.floats = make(map[float64]struct{}, len(values))
+		for _, v := range values {
+			f, ok := toFloat64(v)
+			if !ok {
+				return nil, mismatch(v)
+			}
+			if !math.IsNaN(f) {
+				set.floats[f] = struct{}{}
+			}
+		}
+	case arrow.TIMESTAMP:
+		unit := dt.(*arrow.TimestampType).Unit
+		set.ints = make(map[int64]struct{}, len(values))
+		for _, v := range values {
+			t, ok := v.(time.Time)
+			if !ok {
+				return nil, mismatch(v)
+			}
+			ts, err := arrow.TimestampFromTime(t, unit)
+			if err != nil || !ts.ToTime(unit).Equal(t) {
+				continue // out of range, or finer than the unit
+			}
+			set.ints[int64(ts)] = struct{}{}
+		}
+	case arrow.STRING, arrow.LARGE_STRING, arrow.DICTIONARY:
+		if dt.ID() == arrow.DICTIONARY && !isCategoricalType(dt) {
+			return nil, fmt.Errorf("%w: IsIn on %s column", ErrExprTypeMismatch, dt)
+		}
+		set.strs = make(map[string]struct{}, len(values))
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return nil, mismatch(v)
+			}
+			set.strs[s] = struct{}{}
+		}
+	case arrow.BOOL:
+		for _, v := range values {
+			bv, ok := v.(bool)
+			if !ok {
+				return nil, mismatch(v)
+			}
+			if bv {
+				set.bools[1] = true
+			} else {
+				set.bools[0] = true
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: IsIn on %s column", ErrExprTypeMismatch, dt)
+	}
+	return set, nil
+}
+
+// appendMatches probes every row of chunk and appends the result,
+// inverted when negate is set, and null for a null row.
+func (set *valueSet) appendMatches(b *array.BooleanBuilder, chunk arrow.Array, negate bool) error {
+	var probe func(i int) bool
+	switch a := chunk.(type) {
+	case *array.Int64:
+		probe = func(i int) bool { _, ok := set.ints[a.Value(i)]; return ok }
+	case *array.Int32:
+		probe = func(i int) bool { _, ok := set.ints[int64(a.Value(i))]; return ok }
+	case *array.Uint64:
+		probe = func(i int) bool { _, ok := set.uints[a.Value(i)]; return ok }
+	case *array.Uint32:
+		probe = func(i int) bool { _, ok := set.uints[uint64(a.Value(i))]; return ok }
+	case *array.Timestamp:
+		probe = func(i int) bool { _, ok := set.ints[int64(a.Value(i))]; return ok }
+	case *array.Float64:
+		probe = func(i int) bool { _, ok := set.floats[a.Value(i)]; return ok }
+	case *array.String:
+		probe = func(i int) bool { _, ok := set.strs[a.Value(i)]; return ok }
+	case *array.LargeString:
+		probe = func(i int) bool { _, ok := set.strs[a.Value(i)]; return ok }
+	case *array.Dictionary:
+		// Categorical: probe each category once, then index by code.
+		c, ok := asCatChunk(a)
+		if !ok {
+			return fmt.Errorf("%w: IsIn on %s", ErrColumnTypeMismatch, a.DataType())
+		}
+		in := make([]bool, c.dict.Len())
+		for code := range in {
+			_, in[code] = set.strs[c.dict.Value(code)]
+		}
+		probe = func(i int) bool { return in[c.GetValueIndex(i)] }
+	case *array.Boolean:
+		probe = func(i int) bool {
+			if a.Value(i) {
+				return set.bools[1]
+			}
+			return set.bools[0]
+		}
+	default:
+		return fmt.Errorf("%w: IsIn on %T", ErrColumnTypeMismatch, chunk)
+	}
+	for i := range chunk.Len() {
+		if chunk.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(probe(i) != negate)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestExprIsIn_Eval(t *testing.T) {
+	f := strFrame(t) // addr *string (null in row 2), city string, zip int64
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"strings", Col("city").IsIn([]string{"Paris", "Nowhere", "Rome"}), []string{"false", "true", "true"}},
+		{"ints", Col("zip").IsIn([]int64{12345, 1}), []string{"true", "false", "false"}},
+		// int and float values follow Eq's numeric promotion; 0.5
+		// can never equal an Int64 row and simply doesn't match.
+		{"mixed_numeric", Col("zip").IsIn([]any{75008.0, 0.5, int32(0)}), []string{"false", "true", "true"}},
+		{"null_propagates", Col("addr").IsIn([]string{"Élysée 7"}), []string{"false", "true", "null"}},
+		{"nil_entry_ignored", Col("city").IsIn([]any{nil, "Paris"}), []string{"false", "true", "false"}},
+		{"empty_list", Col("city").IsIn([]string{}), []string{"false", "false", "false"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boolsOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprIsIn_NotInAndBetween(t *testing.T) {
+	f := strFrame(t) // zip: 12345, 75008, 0
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"not_in", Col("zip").NotIn([]int64{12345}), []string{"false", "true", "true"}},
+		// A null row is neither in nor out of the list.
+		{"not_in_null", Col("addr").NotIn([]string{"Élysée 7"}), []string{"true", "false", "null"}},
+		{"not_in_empty", Col("city").NotIn([]string{}), []string{"true", "true", "true"}},
+		{"between", Col("zip").Between(Lit(int64(0)), Lit(int64(12345))), []string{"true", "false", "true"}},
+		{"between_float_bounds", Col("zip").Between(Lit(1.5), Lit(80000.0)), []string{"true", "true", "false"}},
+		{"between_col_bound", Col("zip").Between(Lit(int64(1)), Col("zip")), []string{"true", "true", "false"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boolsOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+	if got := Col("zip").Between(Lit(int64(1)), Lit(int64(9))).String(); got != `(col("zip") BETWEEN lit(1) AND lit(9))` {
+		t.Fatalf("String = %s", got)
+	}
+	if got := Col("zip").NotIn([]int64{1}).String(); got != `col("zip") NOT IN [1]` {
+		t.Fatalf("String = %s", got)
+	}
+}
+
+// TestExprIsIn_HashableKinds covers the key types beyond the strFrame
+// columns — unsigned and Timestamp — and a Series as the value list.
+func TestExprIsIn_HashableKinds(t *testing.T) {
+	type row struct {
+		U  uint64     `gobi:"u"`
+		TS *time.Time `gobi:"ts"`
+	}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	later := day.Add(time.Hour)
+	f, err := FromStructs([]row{{1, &day}, {1 << 63, &later}, {7, nil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := mustColumn(t, f, "u")
+
+	// A millisecond Series still matches the nanosecond column by
+	// instant.
+	mb := array.NewTimestampBuilder(memory.DefaultAllocator, &arrow.TimestampType{Unit: arrow.Millisecond})
+	defer mb.Release()
+	mb.Append(arrow.Timestamp(later.UnixMilli()))
+	mb.AppendNull()
+	ms := SeriesFromArray(arrow.Field{Name: "ms", Type: mb.Type(), Nullable: true}, mb.NewArray())
+
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"uint_values", Col("u").IsIn([]uint64{1 << 63, 2}), []string{"false", "true", "false"}},
+		// Negative ints can't equal an unsigned row and are dropped.
+		{"uint_signed_values", Col("u").IsIn([]int{-1, 7}), []string{"false", "false", "true"}},
+		{"series_values", Col("u").IsIn(u), []string{"true", "true", "true"}},
+		{"times", Col("ts").IsIn([]time.Time{day}), []string{"true", "false", "null"}},
+		{"time_series", Col("ts").NotIn(ms), []string{"true", "false", "null"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := boolsOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+	if _, err := f.WithColumnExpr("out", Col("ts").IsIn([]int64{1})); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("int against Timestamp: err = %v, want ErrExprTypeMismatch", err)
+	}
+}
+
+func TestExprIsIn_Errors(t *testing.T) {
+	f := strFrame(t)
+	bad := []Expr{
+		Col("zip").IsIn([]string{"12345"}),
+		Col("city").IsIn(42),
+		Col("city").IsIn([]any{struct{}{}}),
+	}
+	for _, e := range bad {
+		if _, err := f.WithColumnExpr("out", e); err == nil {
+			t.Fatalf("%s: expected error", e)
+		}
+		if _, err := e.node.Type(f.Schema()); err == nil {
+			t.Fatalf("%s: expected type-inference error", e)
+		}
+	}
+	if _, err := f.WithColumnExpr("out", Col("zip").IsIn([]string{"x"})); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("err = %v, want ErrExprTypeMismatch", err)
+	}
+}
+
+func TestExprIsIn_StringDigestsLongLists(t *testing.T) {
+	short := Col("id").IsIn([]any{1, "a"}).String()
+	if short != `col("id") IN [1, "a"]` {
+		t.Fatalf("String = %s", short)
+	}
+	ids := make([]int64, 100)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	long := Col("id").IsIn(ids).String()
+	if !strings.Contains(long, "… 100 values #") || len(long) > 80 {
+		t.Fatalf("String = %s", long)
+	}
+	ids[99] = 1000
+	if Col("id").IsIn(ids).String() == long {
+		t.Fatal("lists differing past the shown prefix must render differently")
+	}
+}
@@ -0,0 +1,456 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// -----------------------------------------------------------------------------
+// Numeric functions
+//
+// Every function propagates nulls (a null in any operand gives a null
+// row) and follows promoteNumeric for its output type: the ops that
+// stay integral on integers — Abs, Neg, Sign, Floor, Ceil, Round, Mod,
+// Clip — return Int64 when every operand is Int64 and Float64
+// otherwise; the transcendental ones — Sqrt, Log, Log10, Exp, Sin,
+// Cos, Pow, Atan2 — always return Float64. Float results follow
+// IEEE-754 rather than erroring: Sqrt(-1) and Log(-1) are NaN, Log(0)
+// is -Inf. Int64 overflow wraps, as in Go (Abs and Neg of MinInt64
+// are MinInt64).
+//
+// Calls whose operands are all literals fold to a single literal in
+// the optimizer's FoldConstants pass.
+// -----------------------------------------------------------------------------
+
+// Abs returns |e|.
+func (e Expr) Abs() Expr { return mathExpr(mkAbs, e) }
+
+// Neg returns -e.
+func (e Expr) Neg() Expr { return mathExpr(mkNeg, e) }
+
+// Sign returns -1, 0 or +1 by the sign of e. A NaN row stays NaN.
+func (e Expr) Sign() Expr { return mathExpr(mkSign, e) }
+
+// Floor rounds e toward -Inf. Int64 input passes through unchanged.
+func (e Expr) Floor() Expr { return mathExpr(mkFloor, e) }
+
+// Ceil rounds e toward +Inf. Int64 input passes through unchanged.
+func (e Expr) Ceil() Expr { return mathExpr(mkCeil, e) }
+
+// Round rounds e to digits decimal places, halves away from zero
+// (2.5 → 3, -2.5 → -3). Negative digits round to tens, hundreds and
+// so on, which is the only case that changes an Int64 column:
+//
+//	gobi.Col("price").Round(2)      // currency
+//	gobi.Col("population").Round(-3) // nearest thousand, stays Int64
+//
+// Float rounding scales by 10^digits first, so it inherits binary
+// floating point's representation error: 1.005 is stored as
+// 1.00499…, and Round(2) gives 1.0.
+func (e Expr) Round(digits int) Expr {
+	return Expr{node: &mathNode{kind: mkRound, args: []ExprNode{e.node}, digits: digits}}
+}
+
+// Sqrt returns the square root of e.
+func (e Expr) Sqrt() Expr { return mathExpr(mkSqrt, e) }
+
+// Log returns the natural logarithm of e.
+func (e Expr) Log() Expr { return mathExpr(mkLog, e) }
+
+// Log10 returns the base-10 logarithm of e.
+func (e Expr) Log10() Expr { return mathExpr(mkLog10, e) }
+
+// Exp returns e raised to the power of Euler's number.
+func (e Expr) Exp() Expr { return mathExpr(mkExp, e) }
+
+// Sin returns the sine of e, in radians.
+func (e Expr) Sin() Expr { return mathExpr(mkSin, e) }
+
+// Cos returns the cosine of e, in radians.
+func (e Expr) Cos() Expr { return mathExpr(mkCos, e) }
+
+// Mod returns the remainder of e / o, truncated toward zero as in Go
+// and SQL: the result takes the sign of e, so -7 mod 3 is -1. An
+// Int64 row divided by zero is null; a Float64 one is NaN.
+//
+//	bucket := gobi.Col("user_id").Mod(gobi.Lit(16))
+func (e Expr) Mod(o Expr) Expr { return mathExpr(mkMod, e, o) }
+
+// Pow returns e raised to the power o. Always Float64, since a
+// negative exponent makes an integer base fractional.
+func (e Expr) Pow(o Expr) Expr { return mathExpr(mkPow, e, o) }
+
+// Atan2 returns the arc tangent of e / x in radians, using both signs
+// to pick the quadrant — e is the y coordinate. The usual bearing
+// formula reads naturally:
+//
+//	y := dLon.Sin().Mul(lat2.Cos())
+//	x := lat1.Cos().Mul(lat2.Sin()).Sub(lat1.Sin().Mul(lat2.Cos()).Mul(dLon.Cos()))
+//	bearing := y.Atan2(x)
+func (e Expr) Atan2(x Expr) Expr { return mathExpr(mkAtan2, e, x) }
+
+// Clip limits e to [lo, hi]: rows below lo become lo, rows above hi
+// become hi. A null bound makes the row null rather than unbounded;
+// wrap it in Coalesce for a one-sided clip. NaN rows stay NaN.
+func (e Expr) Clip(lo, hi Expr) Expr { return mathExpr(mkClip, e, lo, hi) }
+
+func mathExpr(kind mathKind, operands ...Expr) Expr {
+	args := make([]ExprNode, len(operands))
+	for i, o := range operands {
+		args[i] = o.node
+	}
+	return Expr{node: &mathNode{kind: kind, args: args}}
+}
+
+// -----------------------------------------------------------------------------
+// mathNode
+// -----------------------------------------------------------------------------
+
+type mathKind int
+
+const (
+	mkAbs mathKind = iota
+	mkNeg
+	mkSign
+	mkFloor
+	mkCeil
+	mkRound
+	mkSqrt
+	mkLog
+	mkLog10
+	mkExp
+	mkSin
+	mkCos
+	mkMod
+	mkPow
+	mkAtan2
+	mkClip
+)
+
+var mathKindNames = [...]string{
+	mkAbs:   "abs",
+	mkNeg:   "neg",
+	mkSign:  "sign",
+	mkFloor: "floor",
+	mkCeil:  "ceil",
+	mkRound: "round",
+	mkSqrt:  "sqrt",
+	mkLog:   "log",
+	mkLog10: "log10",
+	mkExp:   "exp",
+	mkSin:   "sin",
+	mkCos:   "cos",
+	mkMod:   "mod",
+	mkPow:   "pow",
+	mkAtan2: "atan2",
+	mkClip:  "clip",
+}
+
+func (k mathKind) String() string { return mathKindNames[k] }
+
+// keepsInt reports whether k maps Int64 operands to an Int64 result.
+func (k mathKind) keepsInt() bool {
+	switch k {
+	case mkAbs, mkNeg, mkSign, mkFloor, mkCeil, mkRound, mkMod, mkClip:
+		return true
+	}
+	return false
+}
+
+// mathNode is one numeric function over its operands. Like strNode,
+// the kinds share a node so the operand evaluation, promotion and
+// null handling live in one Eval; only the per-row function differs.
+type mathNode struct {
+	kind   mathKind
+	args   []ExprNode
+	digits int // Round only
+}
+
+// outType checks every operand is numeric and picks the result type.
+func (n *mathNode) outType(operands []arrow.DataType) (arrow.DataType, error) {
+	allInt := true
+	for _, dt := range operands {
+		if !isNumericType(dt) {
+			return nil, fmt.Errorf("%w: %s on %s", ErrExprTypeMismatch, n.kind, dt)
+		}
+		allInt = allInt && dt.ID() == arrow.INT64
+	}
+	if allInt && n.kind.keepsInt() {
+		return arrow.PrimitiveTypes.Int64, nil
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+func (n *mathNode) Eval(input *Frame) (Series, error) {
+	operands := make([]Series, len(n.args))
+	dts := make([]arrow.DataType, len(n.args))
+	for i, a := range n.args {
+		s, err := a.Eval(input)
+		if err != nil {
+			return Series{}, err
+		}
+		operands[i], dts[i] = s, s.DataType()
+	}
+	dt, err := n.outType(dts)
+	if err != nil {
+		return Series{}, err
+	}
+	rows := input.NumRows()
+	valid := make([]bool, rows)
+	for i := range valid {
+		valid[i] = true
+	}
+	for _, s := range operands {
+		for i, null := range s.Nulls() {
+			if null {
+				valid[i] = false
+			}
+		}
+	}
+
+	if dt.ID() == arrow.INT64 {
+		cols := make([][]int64, len(operands))
+		for i, s := range operands {
+			if cols[i], err = s.Int64s(); err != nil {
+				return Series{}, err
+			}
+		}
+		out := make([]int64, rows)
+		row := make([]int64, len(cols))
+		for i := range out {
+			if !valid[i] {
+				continue
+			}
+			for j, c := range cols {
+				row[j] = c[i]
+			}
+			out[i], valid[i] = n.evalInt(row)
+		}
+		return buildInt64Series("", out, valid), nil
+	}
+
+	cols := make([][]float64, len(operands))
+	for i, s := range operands {
+		if s.DataType().ID() != arrow.FLOAT64 {
+			if s, err = castToFloat64(s); err != nil {
+				return Series{}, err
+			}
+		}
+		if cols[i], err = s.Float64s(); err != nil {
+			return Series{}, err
+		}
+	}
+	out := make([]float64, rows)
+	row := make([]float64, len(cols))
+	for i := range out {
+		if !valid[i] {
+			continue
+		}
+		for j, c := range cols {
+			row[j] = c[i]
+		}
+		out[i] = n.evalFloat(row)
+	}
+	return buildFloat64Series("", out, valid), nil
+}
+
+// evalInt applies the function to one row of Int64 operands; ok=false
+// means a null result (Mod by zero).
+func (n *mathNode) evalInt(a []int64) (int64, bool) {
+	x := a[0]
+	switch n.kind {
+	case mkAbs:
+		if x < 0 {
+			return -x, true
+		}
+		return x, true
+	case mkNeg:
+		return -x, true
+	case mkSign:
+		switch {
+		case x > 0:
+			return 1, true
+		case x < 0:
+			return -1, true
+		}
+		return 0, true
+	case mkRound:
+		return roundInt64(x, n.digits), true
+	case mkMod:
+		if a[1] == 0 {
+			return 0, false
+		}
+		return x % a[1], true
+	case mkClip:
+		return min(max(x, a[1]), a[2]), true
+	}
+	return x, true // Floor, Ceil
+}
+
+// evalFloat applies the function to one row of Float64 operands.
+func (n *mathNode) evalFloat(a []float64) float64 {
+	x := a[0]
+	switch n.kind {
+	case mkAbs:
+		return math.Abs(x)
+	case mkNeg:
+		return -x
+	case mkSign:
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		}
+		return x // ±0 and NaN
+	case mkFloor:
+		return math.Floor(x)
+	case mkCeil:
+		return math.Ceil(x)
+	case mkRound:
+		return roundFloat64(x, n.digits)
+	case mkSqrt:
+		return math.Sqrt(x)
+	case mkLog:
+		return math.Log(x)
+	case mkLog10:
+		return math.Log10(x)
+	case mkExp:
+		return math.Exp(x)
+	case mkSin:
+		return math.Sin(x)
+	case mkCos:
+		return math.Cos(x)
+	case mkMod:
+		return math.Mod(x, a[1])
+	case mkPow:
+		return math.Pow(x, a[1])
+	case mkAtan2:
+		return math.Atan2(x, a[1])
+	case mkClip:
+		// Same order as the Int64 path, so lo > hi gives hi in both.
+		if x < a[1] {
+			x = a[1]
+		}
+		if x > a[2] {
+			x = a[2]
+		}
+		return x
+	}
+	return math.NaN()
+}
+
+// roundFloat64 rounds x to digits decimal places, halves away from
+// zero. Past 10^308 the scale overflows; x is already exact to that
+// many digits, so it comes back unchanged.
+func roundFloat64(x float64, digits int) float64 {
+	if digits == 0 {
+		return math.Round(x)
+	}
+	if digits < 0 {
+		p := math.Pow10(-digits)
+		return math.Round(x/p) * p
+	}
+	p := math.Pow10(digits)
+	if math.IsInf(x*p, 0) {
+		return x
+	}
+	return math.Round(x*p) / p
+}
+
+// roundInt64 rounds x to a multiple of 10^-digits, halves away from
+// zero. Non-negative digits leave an integer alone; past 18 negative
+// digits every Int64 rounds to 0.
+func roundInt64(x int64, digits int) int64 {
+	if digits >= 0 {
+		return x
+	}
+	if digits < -18 {
+		return 0
+	}
+	p := int64(1)
+	for range -digits {
+		p *= 10
+	}
+	q, r := x/p, x%p
+	if r < 0 {
+		r = -r
+	}
+	if r >= p-r { // r*2 >= p without overflowing
+		if x < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q * p
+}
+
+func (n *mathNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	dts := make([]arrow.DataType, len(n.args))
+	for i, a := range n.args {
+		dt, err := a.Type(schema)
+		if err != nil {
+			return nil, err
+		}
+		dts[i] = dt
+	}
+	return n.outType(dts)
+}
+
+func (n *mathNode) Children() []Expr {
+	out := make([]Expr, len(n.args))
+	for i, a := range n.args {
+		out[i] = Expr{node: a}
+	}
+	return out
+}
+
+func (n *mathNode) String() string {
+	parts := make([]string, 0, len(n.args)+1)
+	for _, a := range n.args {
+		parts = append(parts, a.String())
+	}
+	if n.kind == mkRound {
+		parts = append(parts, fmt.Sprint(n.digits))
+	}
+	return fmt.Sprintf("%s(%s)", n.kind, strings.Join(parts, ", "))
+}
+
+// foldMath evaluates n once its operands are all numeric literals;
+// (_, false) otherwise, and for the one null-producing case (Int64
+// Mod by zero), which stays in the plan to null out at runtime.
+func foldMath(n *mathNode) (Expr, bool) {
+	dts := make([]arrow.DataType, len(n.args))
+	ints := make([]int64, len(n.args))
+	floats := make([]float64, len(n.args))
+	for i, a := range n.args {
+		lit, ok := a.(*literalNode)
+		if !ok || lit.err != nil {
+			return Expr{}, false
+		}
+		f, ok := lit.asFloat64()
+		if !ok {
+			return Expr{}, false
+		}
+		floats[i], dts[i] = f, lit.dtype
+		if v, isInt := lit.value.(int64); isInt {
+			ints[i] = v
+		}
+	}
+	dt, err := n.outType(dts)
+	if err != nil {
+		return Expr{}, false
+	}
+	if dt.ID() == arrow.INT64 {
+		v, ok := n.evalInt(ints)
+		if !ok {
+			return Expr{}, false
+		}
+		return Lit(v), true
+	}
+	return Lit(n.evalFloat(floats)), true
+}
@@ -0,0 +1,185 @@
+package gobi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// mathFrame builds (i *int64, f *float64, n int32); row 3 is null in i
+// and f.
+func mathFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		I *int64   `gobi:"i"`
+		F *float64 `gobi:"f"`
+		N int32    `gobi:"n"`
+	}
+	i := func(v int64) *int64 { return &v }
+	f := func(v float64) *float64 { return &v }
+	df, err := FromStructs([]row{
+		{i(-7), f(-2.5), 4},
+		{i(0), f(0.125), 9},
+		{i(1250), f(100), 2},
+		{nil, nil, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+func TestExprMath_IntPreserving(t *testing.T) {
+	f := mathFrame(t)
+	cases := []struct {
+		name string
+		expr Expr
+		want []int64 // -1 marks null in the last row only
+	}{
+		{"abs", Col("i").Abs(), []int64{7, 0, 1250}},
+		{"neg", Col("i").Neg(), []int64{7, 0, -1250}},
+		{"sign", Col("i").Sign(), []int64{-1, 0, 1}},
+		{"floor", Col("i").Floor(), []int64{-7, 0, 1250}},
+		{"round_tens", Col("i").Round(-1), []int64{-10, 0, 1250}},
+		{"round_hundreds", Col("i").Round(-2), []int64{0, 0, 1300}},
+		// Truncated: the remainder takes the dividend's sign.
+		{"mod", Col("i").Mod(Lit(3)), []int64{-1, 0, 2}},
+		{"clip", Col("i").Clip(Lit(-1), Lit(100)), []int64{-1, 0, 100}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := mustColumn(t, out, "out")
+			if !arrow.TypeEqual(s.DataType(), arrow.PrimitiveTypes.Int64) {
+				t.Fatalf("%s: type = %s, want Int64", tc.expr, s.DataType())
+			}
+			got, _ := s.Int64s()
+			if !slices.Equal(got[:3], tc.want) || !s.Nulls()[3] {
+				t.Fatalf("%s = %v (nulls %v), want %v then null", tc.expr, got, s.Nulls(), tc.want)
+			}
+		})
+	}
+}
+
+func TestExprMath_Float(t *testing.T) {
+	f := mathFrame(t)
+	nan := math.NaN()
+	cases := []struct {
+		name string
+		expr Expr
+		want []float64
+	}{
+		{"abs", Col("f").Abs(), []float64{2.5, 0.125, 100, nan}},
+		{"sign", Col("f").Sign(), []float64{-1, 1, 1, nan}},
+		{"floor", Col("f").Floor(), []float64{-3, 0, 100, nan}},
+		{"ceil", Col("f").Ceil(), []float64{-2, 1, 100, nan}},
+		// Halves away from zero.
+		{"round", Col("f").Round(0), []float64{-3, 0, 100, nan}},
+		{"round_digits", Col("f").Round(2), []float64{-2.5, 0.13, 100, nan}},
+		{"round_negative", Col("f").Round(-2), []float64{-0, 0, 100, nan}},
+		{"sqrt", Col("f").Sqrt(), []float64{nan, math.Sqrt(0.125), 10, nan}},
+		{"log10", Col("f").Log10(), []float64{nan, math.Log10(0.125), 2, nan}},
+		{"log_exp", Col("f").Exp().Log(), []float64{-2.5, 0.125, 100, nan}},
+		{"sin_cos", Col("f").Sin().Pow(Lit(2)).Add(Col("f").Cos().Pow(Lit(2))), []float64{1, 1, 1, nan}},
+		{"mod", Col("f").Mod(Lit(2.0)), []float64{-0.5, 0.125, 0, nan}},
+		{"pow_int", Col("i").Pow(Lit(2)), []float64{49, 0, 1562500, nan}},
+		{"atan2", Col("f").Atan2(Lit(1.0)), []float64{math.Atan2(-2.5, 1), math.Atan2(0.125, 1), math.Atan2(100, 1), nan}},
+		// Int32 and mixed operands promote to Float64.
+		{"int32_abs", Col("n").Neg().Abs(), []float64{4, 9, 2, 1}},
+		{"clip_mixed", Col("f").Clip(Lit(0), Col("n")), []float64{0, 0.125, 2, nan}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := mustColumn(t, out, "out")
+			if !arrow.TypeEqual(s.DataType(), arrow.PrimitiveTypes.Float64) {
+				t.Fatalf("%s: type = %s, want Float64", tc.expr, s.DataType())
+			}
+			// NaN stands for both a NaN and a null row here; row 3 is
+			// null wherever an operand is.
+			if got := floatsOrNaN(t, out, "out"); !floatsClose(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprMath_ModByZeroAndNullBounds(t *testing.T) {
+	f := mathFrame(t)
+	out, err := f.WithColumnExpr("out", Col("i").Mod(Col("i")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustColumn(t, out, "out").Nulls(); !slices.Equal(got, []bool{false, true, false, true}) {
+		t.Fatalf("i mod i nulls = %v, want the zero and null rows null", got)
+	}
+	out, err = f.WithColumnExpr("out", Col("n").Clip(Col("i"), Lit(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustColumn(t, out, "out").Nulls(); !slices.Equal(got, []bool{false, false, false, true}) {
+		t.Fatalf("clip with null bound nulls = %v", got)
+	}
+}
+
+func TestExprMath_TypeInferenceAndErrors(t *testing.T) {
+	f := mathFrame(t)
+	schema := f.Schema()
+	cases := []struct {
+		expr Expr
+		want arrow.DataType
+	}{
+		{Col("i").Abs(), arrow.PrimitiveTypes.Int64},
+		{Col("i").Mod(Lit(2)), arrow.PrimitiveTypes.Int64},
+		{Col("i").Mod(Lit(2.0)), arrow.PrimitiveTypes.Float64},
+		{Col("i").Sqrt(), arrow.PrimitiveTypes.Float64},
+		{Col("n").Round(0), arrow.PrimitiveTypes.Float64},
+		{Col("i").Clip(Lit(0), Col("f")), arrow.PrimitiveTypes.Float64},
+	}
+	for _, tc := range cases {
+		got, err := tc.expr.node.Type(schema)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if !arrow.TypeEqual(got, tc.want) {
+			t.Fatalf("%s: type = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+	if got := Col("f").Round(2).Clip(Lit(0), Lit(1)).String(); got != `clip(round(col("f"), 2), lit(0), lit(1))` {
+		t.Fatalf("String = %s", got)
+	}
+	bad := Col("x").Abs()
+	sf := strFrame(t)
+	if _, err := sf.WithColumnExpr("out", Col("city").Abs()); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("Abs on String: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := Col("zip").Pow(Col("city")).node.Type(sf.Schema()); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("Pow with String exponent: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := bad.node.Type(schema); err == nil {
+		t.Fatal("unknown column: expected error")
+	}
+}
+
+func TestExprMath_LazyStreams(t *testing.T) {
+	lf := mathFrame(t).Lazy().
+		WithColumn("bucket", Col("i").Abs().Mod(Lit(4))).
+		Filter(Col("f").Abs().Gt(Lit(1.0)))
+	out, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := mustColumn(t, out, "bucket").Int64s()
+	if !slices.Equal(got, []int64{3, 2}) {
+		t.Fatalf("bucket = %v, want [3 2]", got)
+	}
+}
@@ -41,6 +41,17 @@ func (e Expr) MaxAgg() Expr { return Expr{node: &scalarAggNode{inner: e.node, ki
 // non-null values, broadcast to every input row. Output is Int64.
 func (e Expr) Count() Expr { return Expr{node: &scalarAggNode{inner: e.node, kind: AggCount}} }
 
+// Std returns an expression that evaluates to the sample standard
+// deviation (n-1 denominator) of e's non-null numeric values,
+// broadcast to every input row. Output is Float64; fewer than two
+// values give null.
+func (e Expr) Std() Expr { return Expr{node: &scalarAggNode{inner: e.node, kind: AggStd}} }
+
+// Var returns an expression that evaluates to the sample variance of
+// e's non-null numeric values, broadcast to every input row. Same
+// null rule as Std.
+func (e Expr) Var() Expr { return Expr{node: &scalarAggNode{inner: e.node, kind: AggVar}} }
+
 // Median returns an expression that evaluates to the sample median of
 // e's non-null numeric values, broadcast to every input row. Output
 // is Float64. Even-sized groups interpolate between the two middle
@@ -52,6 +63,39 @@ func (e Expr) Median() Expr { return Expr{node: &scalarAggNode{inner: e.node, ki
 // by first-seen order. Output type matches the source column.
 func (e Expr) Mode() Expr { return Expr{node: &scalarAggNode{inner: e.node, kind: AggMode}} }
 
+// Quantile returns an expression that evaluates to the q-th quantile
+// (0 <= q <= 1) of e's non-null numeric values with linear
+// interpolation, broadcast to every input row. Output is Float64.
+// QuantileWith picks another interpolation; see AggQuantile.
+func (e Expr) Quantile(q float64) Expr { return e.QuantileWith(q, QuantileLinear) }
+
+// QuantileWith is Quantile with an explicit interpolation mode.
+func (e Expr) QuantileWith(q float64, interp QuantileInterpolation) Expr {
+	return Expr{node: &scalarAggNode{inner: e.node, fn: AggQuantile(q, interp)}}
+}
+
+// ApproxQuantile returns an expression that evaluates to a t-digest
+// estimate of the q-th quantile of e's non-null numeric values,
+// broadcast to every input row. Output is Float64. See
+// AggApproxQuantile for the accuracy and memory trade-off.
+func (e Expr) ApproxQuantile(q float64) Expr {
+	return Expr{node: &scalarAggNode{inner: e.node, fn: AggApproxQuantile(q)}}
+}
+
+// CountDistinct returns an expression that evaluates to the exact
+// number of distinct non-null values of e, broadcast to every input
+// row. Output is Int64.
+func (e Expr) CountDistinct() Expr {
+	return Expr{node: &scalarAggNode{inner: e.node, fn: AggCountDistinct()}}
+}
+
+// ApproxNUnique returns an expression that evaluates to a
+// HyperLogLog estimate of the number of distinct non-null values of
+// e, broadcast to every input row. Output is Int64.
+func (e Expr) ApproxNUnique() Expr {
+	return Expr{node: &scalarAggNode{inner: e.node, fn: AggApproxNUnique()}}
+}
+
 // Over wraps an expression with partition keys.
 //
 // Two shapes are supported depending on the inner:
@@ -61,16 +105,20 @@ func (e Expr) Mode() Expr { return Expr{node: &scalarAggNode{inner: e.node, kind
 //     and broadcast to every row in that group. This is the reduce-
 //     and-scatter shape gobi has had since v0.2.0.
 //
-//  2. **Shape-preserving inner** (Shift, arithmetic chains like
-//     `Col("v").Add(Lit(1.0))`, and other row-order-preserving
-//     ExprNodes): the inner is evaluated separately on each
-//     partition's rows and the per-row output is scattered back to
-//     the original row positions. Input row order is preserved
+//  2. **Shape-preserving inner** (Shift, the window functions in
+//     expr_window.go — CumSum, RowNumber, Rank, Diff, … — arithmetic
+//     chains like `Col("v").Add(Lit(1.0))`, and other row-order-
+//     preserving ExprNodes): the inner is evaluated separately on
+//     each partition's rows and the per-row output is scattered back
+//     to the original row positions. Input row order is preserved
 //     within each partition (polars-parity default).
 //
 // If the shape-preserving transform is row-order sensitive (e.g.
 // Shift) and you need a specific within-partition order, use
 // OverOrdered — this variant does not sort within partitions.
+//
+// A scalar aggregate can also be bounded to a moving frame around
+// each row with Rows or Range (expr_window_frame.go).
 func (e Expr) Over(partitionCols ...string) Expr {
 	return Expr{node: &overNode{inner: e.node, partitionCols: partitionCols}}
 }
@@ -83,7 +131,8 @@ func (e Expr) Over(partitionCols ...string) Expr {
 // each partition.
 //
 // For scalar aggregate inners the orderBy is ignored (Sum, Mean,
-// Min, Max, Count are order-invariant).
+// Min, Max, Count are order-invariant) unless a Rows or Range frame
+// is chained on, in which case it defines which rows are "preceding".
 //
 // Example: previous v within each K, ordered by t.
 //
@@ -99,9 +148,30 @@ func (e Expr) OverOrdered(partitionCols []string, orderBy ...SortKey) Expr {
 // scalarAggNode reduces its inner column to a single value and
 // broadcasts it to input length. Sum/Mean/Min/Max output types match
 // the eager aggregate path (see accumulator OutputType methods).
+//
+// fn, when set, takes precedence over kind exactly as Aggregation.Fn
+// does: newAccumulator clones it per group, so the quantile and
+// distinct-count aggregators (agg_quantile.go, agg_distinct.go) run
+// through the same Over paths as the built-in kinds.
 type scalarAggNode struct {
 	inner ExprNode
 	kind  AggKind
+	fn    IncrementalAggregator
+}
+
+// aggregation is the Aggregation an accumulator for n is built from.
+func (n *scalarAggNode) aggregation(column string) Aggregation {
+	if n.fn != nil {
+		return Aggregation{Column: column, Fn: n.fn}
+	}
+	return Aggregation{Kind: n.kind, Column: column}
+}
+
+func (n *scalarAggNode) name() string {
+	if n.fn != nil {
+		return n.fn.Name()
+	}
+	return n.kind.String()
 }
 
 func (n *scalarAggNode) Eval(input *Frame) (Series, error) {
@@ -114,9 +184,9 @@ func (n *scalarAggNode) Eval(input *Frame) (Series, error) {
 	for i := range rows {
 		rows[i] = i
 	}
-	acc, err := newAccumulator(Aggregation{Kind: n.kind, Column: col.Name()})
+	acc, err := newAccumulator(n.aggregation(col.Name()))
 	if err != nil {
-		return Series{}, fmt.Errorf("%s: %w", n.kind, err)
+		return Series{}, fmt.Errorf("%s: %w", n.name(), err)
 	}
 	if err := acc.Update(col, rows); err != nil {
 		return Series{}, err
@@ -126,16 +196,16 @@ func (n *scalarAggNode) Eval(input *Frame) (Series, error) {
 	// accumulator's Float64 fallback OutputType. (Same pattern as
 	// First/Last, but those don't have Expr surface today.)
 	outType := acc.OutputType()
-	if n.kind == AggMode {
+	if n.fn == nil && n.kind == AggMode {
 		outType = col.DataType()
 	}
-	return broadcastScalar(v, outType, input.NumRows(), n.kind.String())
+	return broadcastScalar(v, outType, input.NumRows(), n.name())
 }
 
 func (n *scalarAggNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
 	// Type inference: build a temporary accumulator to ask its
 	// OutputType. Cheap — the accumulator has no state until Update.
-	acc, err := newAccumulator(Aggregation{Kind: n.kind})
+	acc, err := newAccumulator(n.aggregation(""))
 	if err != nil {
 		return nil, err
 	}
@@ -147,14 +217,14 @@ func (n *scalarAggNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
 	if err != nil {
 		return nil, err
 	}
-	if n.kind == AggMode {
+	if n.fn == nil && n.kind == AggMode {
 		return innerType, nil
 	}
 	return acc.OutputType(), nil
 }
 
 func (n *scalarAggNode) Children() []Expr { return []Expr{{node: n.inner}} }
-func (n *scalarAggNode) String() string   { return fmt.Sprintf("%s(%s)", n.kind, n.inner) }
+func (n *scalarAggNode) String() string   { return fmt.Sprintf("%s(%s)", n.name(), n.inner) }
 
 // overNode is a partition-aware wrapper. Two eval modes selected
 // automatically by inner type:
@@ -163,13 +233,24 @@ func (n *scalarAggNode) String() string   { return fmt.Sprintf("%s(%s)", n.kind,
 //   - anything else        → shape-preserving per-partition eval.
 //
 // orderBy is set only via OverOrdered; plain .Over(...) leaves it nil.
+// frame is set only via Rows / Range (expr_window_frame.go) and takes
+// precedence over both modes; err parks a bad frame spec until Eval /
+// Type, the same way isInNode defers a bad values argument.
 type overNode struct {
 	inner         ExprNode
 	partitionCols []string
 	orderBy       []SortKey
+	frame         *frameSpec
+	err           error
 }
 
 func (n *overNode) Eval(input *Frame) (Series, error) {
+	if n.err != nil {
+		return Series{}, n.err
+	}
+	if n.frame != nil {
+		return n.evalFramed(input)
+	}
 	if agg, ok := n.inner.(*scalarAggNode); ok {
 		return n.evalScalarAgg(input, agg)
 	}
@@ -177,11 +258,25 @@ func (n *overNode) Eval(input *Frame) (Series, error) {
 }
 
 func (n *overNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	if n.frame != nil {
+		return n.framedType(schema)
+	}
 	return n.inner.Type(schema)
 }
 
 func (n *overNode) Children() []Expr { return []Expr{{node: n.inner}} }
 func (n *overNode) String() string {
+	s := n.overString()
+	if n.frame != nil {
+		s += "." + n.frame.String()
+	}
+	return s
+}
+
+func (n *overNode) overString() string {
 	if len(n.orderBy) == 0 {
 		return fmt.Sprintf("%s.over(%v)", n.inner, n.partitionCols)
 	}
@@ -260,7 +355,7 @@ func (n *overNode) evalScalarAgg(input *Frame, agg *scalarAggNode) (Series, erro
 	groupVals := make([]any, len(groupRows))
 	var outType arrow.DataType
 	for gid, rows := range groupRows {
-		acc, err := newAccumulator(Aggregation{Kind: agg.kind, Column: col.Name()})
+		acc, err := newAccumulator(agg.aggregation(col.Name()))
 		if err != nil {
 			return Series{}, fmt.Errorf("Over: %w", err)
 		}
@@ -270,19 +365,19 @@ func (n *overNode) evalScalarAgg(input *Frame, agg *scalarAggNode) (Series, erro
 		groupVals[gid] = acc.Finalize()
 		if outType == nil {
 			outType = acc.OutputType()
-			if agg.kind == AggMode {
+			if agg.fn == nil && agg.kind == AggMode {
 				outType = col.DataType()
 			}
 		}
 	}
 	if outType == nil {
 		// Zero-row input — fall back to the accumulator's declared type.
-		acc, err := newAccumulator(Aggregation{Kind: agg.kind, Column: col.Name()})
+		acc, err := newAccumulator(agg.aggregation(col.Name()))
 		if err != nil {
 			return Series{}, err
 		}
 		outType = acc.OutputType()
-		if agg.kind == AggMode {
+		if agg.fn == nil && agg.kind == AggMode {
 			outType = col.DataType()
 		}
 	}
@@ -304,7 +399,7 @@ func (n *overNode) evalScalarAgg(input *Frame, agg *scalarAggNode) (Series, erro
 			return Series{}, fmt.Errorf("Over: emit row %d: %w", row, err)
 		}
 	}
-	return buildSeries(agg.kind.String()+"_over", outType, b.NewArray()), nil
+	return buildSeries(agg.name()+"_over", outType, b.NewArray()), nil
 }
 
 // -----------------------------------------------------------------------------
@@ -667,7 +762,7 @@ func (n *overNode) evalContiguous(input *Frame, agg *scalarAggNode, col Series,
 
 	// Discover the output type via a throwaway accumulator (cheap;
 	// same call the general path makes per group).
-	protoAcc, err := newAccumulator(Aggregation{Kind: agg.kind, Column: col.Name()})
+	protoAcc, err := newAccumulator(agg.aggregation(col.Name()))
 	if err != nil {
 		return Series{}, fmt.Errorf("Over: %w", err)
 	}
@@ -681,7 +776,7 @@ func (n *overNode) evalContiguous(input *Frame, agg *scalarAggNode, col Series,
 	defer b.Release()
 
 	if nRows == 0 {
-		return buildSeries(agg.kind.String()+"_over", outType, b.NewArray()), nil
+		return buildSeries(agg.name()+"_over", outType, b.NewArray()), nil
 	}
 
 	// Reusable row-index buffer for feeding acc.Update. Grown to
@@ -707,7 +802,7 @@ func (n *overNode) evalContiguous(input *Frame, agg *scalarAggNode, col Series,
 		for k := groupStart; k < groupEnd; k++ {
 			rowsBuf = append(rowsBuf, k)
 		}
-		acc, err := newAccumulator(Aggregation{Kind: agg.kind, Column: col.Name()})
+		acc, err := newAccumulator(agg.aggregation(col.Name()))
 		if err != nil {
 			return fmt.Errorf("Over: %w", err)
 		}
@@ -745,7 +840,7 @@ func (n *overNode) evalContiguous(input *Frame, agg *scalarAggNode, col Series,
 	if err := emit(groupStart, nRows); err != nil {
 		return Series{}, err
 	}
-	return buildSeries(agg.kind.String()+"_over", outType, b.NewArray()), nil
+	return buildSeries(agg.name()+"_over", outType, b.NewArray()), nil
 }
 
 // bytesEqual is a small inline byte-slice equality check. Kept local
@@ -0,0 +1,306 @@
+package gobi
+
+// -----------------------------------------------------------------------------
+// Expression-tree rewriting for the optimizer.
+//
+// ExprNode exposes its children read-only (Children), which is all
+// the evaluator and the column-reference walks need. Rules that change
+// an expression's inside — rename a column it reads, swap a repeated
+// subtree for a temporary column — also need to put new children
+// back. withChildren does that for every built-in node type; a Custom
+// node can't be rebuilt, so a rewrite that would have to change one
+// reports failure and the rule leaves that expression alone.
+// -----------------------------------------------------------------------------
+
+// withChildren returns a copy of n with its children replaced by kids,
+// in Children order. false for node types it doesn't know (Custom).
+func withChildren(n ExprNode, kids []ExprNode) (ExprNode, bool) {
+	switch n := n.(type) {
+	case *colRefNode, *literalNode, *literalNullNode, *literalEmptyListNode, *geomLiteralNode:
+		return n, true
+	case *castNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *binOpNode:
+		c := *n
+		c.left, c.right = kids[0], kids[1]
+		return &c, true
+	case *notNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *aliasNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *fillNullNode:
+		c := *n
+		c.inner, c.fill = kids[0], kids[1]
+		return &c, true
+	case *fillStrategyNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *interpolateNode:
+		c := *n
+		c.inner = kids[0]
+		if len(kids) > 1 {
+			c.by = kids[1]
+		}
+		return &c, true
+	case *geomUnaryNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *geomBinaryNode:
+		c := *n
+		c.left, c.right = kids[0], kids[1]
+		return &c, true
+	case *haversineNode:
+		c := *n
+		c.lat1, c.lon1, c.lat2, c.lon2 = kids[0], kids[1], kids[2], kids[3]
+		return &c, true
+	case *ifNode:
+		c := *n
+		c.cond, c.then, c.otherwise = kids[0], kids[1], kids[2]
+		return &c, true
+	case *isInNode:
+		c := *n
+		c.input = kids[0]
+		return &c, true
+	case *betweenNode:
+		// lowered is derived from the other three; rebuild it so
+		// evaluation sees the new children.
+		return Expr{node: kids[0]}.Between(Expr{node: kids[1]}, Expr{node: kids[2]}).node, true
+	case *listLenNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *listGetNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *listSliceNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *listContainsNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *listAggNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *listUnionNode:
+		c := *n
+		c.left, c.right = kids[0], kids[1]
+		return &c, true
+	case *mathNode:
+		c := *n
+		c.args = append([]ExprNode(nil), kids...)
+		return &c, true
+	case *nullCheckNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *coalesceNode:
+		c := *n
+		c.operands = append([]ExprNode(nil), kids...)
+		return &c, true
+	case *scalarAggNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *overNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *shiftNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *strNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *strConcatNode:
+		c := *n
+		c.operands = append([]ExprNode(nil), kids...)
+		return &c, true
+	case *strFormatNode:
+		c := *n
+		c.operands = append([]ExprNode(nil), kids...)
+		return &c, true
+	case *unixNanoNode:
+		c := *n
+		c.inner = kids[0]
+		return &c, true
+	case *windowNode:
+		c := *n
+		if len(kids) > 0 {
+			c.inner = kids[0]
+		}
+		return &c, true
+	}
+	return nil, false
+}
+
+// isBuiltinExpr reports whether withChildren can rebuild n.
+func isBuiltinExpr(n ExprNode) bool {
+	_, ok := withChildren(n, childNodes(n))
+	return ok
+}
+
+func childNodes(n ExprNode) []ExprNode {
+	children := n.Children()
+	out := make([]ExprNode, len(children))
+	for i, c := range children {
+		out[i] = c.node
+	}
+	return out
+}
+
+// transformExpr rewrites e top-down. fn sees each node first; when it
+// returns a replacement the walk doesn't descend into it. Parents of
+// changed nodes are rebuilt through withChildren, and unchanged
+// subtrees keep their identity. ok is false when a changed node sits
+// under a node that can't be rebuilt.
+func transformExpr(e Expr, fn func(ExprNode) (ExprNode, bool)) (out Expr, ok bool) {
+	if e.node == nil {
+		return e, true
+	}
+	if repl, did := fn(e.node); did {
+		return Expr{node: repl}, true
+	}
+	children := e.node.Children()
+	kids := make([]ExprNode, len(children))
+	changed := false
+	for i, c := range children {
+		nc, ok := transformExpr(c, fn)
+		if !ok {
+			return e, false
+		}
+		kids[i] = nc.node
+		changed = changed || nc.node != c.node
+	}
+	if !changed {
+		return e, true
+	}
+	rebuilt, ok := withChildren(e.node, kids)
+	if !ok {
+		return e, false
+	}
+	return Expr{node: rebuilt}, true
+}
+
+// renameColumnRefs rewrites every column e reads through names
+// (current → replacement): Col references and the partition and
+// order-by columns of Over. false if e holds a Custom node, whose
+// column references can't be seen.
+func renameColumnRefs(e Expr, names map[string]string) (Expr, bool) {
+	if !allBuiltinExpr(e) {
+		return e, false
+	}
+	rename := func(c string) string {
+		if r, ok := names[c]; ok {
+			return r
+		}
+		return c
+	}
+	var fn func(ExprNode) (ExprNode, bool)
+	fn = func(n ExprNode) (ExprNode, bool) {
+		switch n := n.(type) {
+		case *colRefNode:
+			if r, ok := names[n.name]; ok {
+				return Col(r).node, true
+			}
+		case *overNode:
+			inner, _ := transformExpr(Expr{node: n.inner}, fn)
+			c := *n
+			c.inner = inner.node
+			c.partitionCols = make([]string, len(n.partitionCols))
+			for i, p := range n.partitionCols {
+				c.partitionCols[i] = rename(p)
+			}
+			c.orderBy = make([]SortKey, len(n.orderBy))
+			for i, k := range n.orderBy {
+				c.orderBy[i] = SortKey{Column: rename(k.Column), Descending: k.Descending}
+			}
+			return &c, true
+		}
+		return nil, false
+	}
+	return transformExpr(e, fn)
+}
+
+// allBuiltinExpr reports whether every node of e is a built-in one.
+func allBuiltinExpr(e Expr) bool {
+	if e.node == nil {
+		return true
+	}
+	if !isBuiltinExpr(e.node) {
+		return false
+	}
+	for _, c := range e.node.Children() {
+		if !allBuiltinExpr(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRowLocal reports whether e computes each output row from the same
+// input row alone, so evaluating it before or after a change in the
+// row set (a filter, a join) gives the same values for the rows that
+// remain. Windows, Over, whole-column aggregates, shifts and
+// strategy fills look at neighbouring rows; Custom nodes are unknown
+// and count as not row-local.
+func isRowLocal(e Expr) bool {
+	if e.node == nil {
+		return true
+	}
+	switch e.node.(type) {
+	case *overNode, *windowNode, *scalarAggNode, *shiftNode, *fillStrategyNode, *interpolateNode:
+		return false
+	}
+	if !isBuiltinExpr(e.node) {
+		return false
+	}
+	for _, c := range e.node.Children() {
+		if !isRowLocal(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitConjuncts flattens a tree of ANDs into its operands.
+func splitConjuncts(e Expr) []Expr {
+	if b, ok := e.node.(*binOpNode); ok && b.op == bopAnd {
+		return append(splitConjuncts(Expr{node: b.left}), splitConjuncts(Expr{node: b.right})...)
+	}
+	return []Expr{e}
+}
+
+// andAll is the inverse of splitConjuncts; exprs must be non-empty.
+func andAll(exprs []Expr) Expr {
+	out := exprs[0]
+	for _, e := range exprs[1:] {
+		out = out.And(e)
+	}
+	return out
+}
+
+// exprSize counts e's nodes.
+func exprSize(e Expr) int {
+	if e.node == nil {
+		return 0
+	}
+	n := 1
+	for _, c := range e.node.Children() {
+		n += exprSize(c)
+	}
+	return n
+}
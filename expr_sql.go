@@ -2,7 +2,10 @@ package gobi
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // ExprToSQL translates a gobi.Expr into a SQL fragment + a slice of
@@ -20,6 +23,23 @@ import (
 // expect `$1`/`$2` (pgx) or `:name` (Oracle) can rewrite the
 // placeholders trivially since the args slice is 1:1 with `?` order.
 //
+// IsIn / NotIn render as `x IN (?, ?)` / `x NOT IN (?, ?)`, one
+// placeholder per value, and Between as `x BETWEEN ? AND ?`. An empty
+// list, a value sqlBindable rejects (NaN, time, uint64 past
+// MaxInt64), or a Between bound that isn't a non-null literal
+// declines the whole expression, since the SQL spelling would
+// disagree with the executor (PostgreSQL rejects `IN ()`; SQLite binds
+// NaN as NULL).
+//
+// String expressions translate where SQLite and PostgreSQL agree
+// exactly: Str().Len (LENGTH), Replace (REPLACE), Concat (`||`),
+// StartsWith / EndsWith / non-negative Slice (SUBSTR), and Contains
+// (spelled via REPLACE + LENGTH, since INSTR / STRPOS aren't
+// portable). Lower / Upper / Trim are deliberately not translated —
+// SQLite's LOWER / UPPER only fold ASCII and its TRIM only strips
+// spaces, so a pushed `lower(x) = 'école'` would drop rows the
+// executor keeps — and neither are the regex ops, Split or Format.
+//
 // Column names are double-quoted per SQL:2016 identifier rules; any
 // embedded double-quote in a name is escaped by doubling. NULL
 // comparisons (`x = NULL`) are rewritten to `IS NULL` / `IS NOT NULL`
@@ -81,6 +101,51 @@ func appendExprSQL(b *strings.Builder, args *[]any, n ExprNode) bool {
 		b.WriteByte(')')
 		return true
 
+	case *strNode:
+		return appendStrSQL(b, args, node)
+
+	case *isInNode:
+		return appendIsInSQL(b, args, node)
+
+	case *betweenNode:
+		// With literal bounds the only null source is x, which nulls
+		// both halves alike. A column bound could null one half while
+		// the other is false, where SQL's AND says false and the
+		// executor's says null — and NOT (...) would then keep the row.
+		if !isNonNullLiteral(node.lo) || !isNonNullLiteral(node.hi) {
+			return false
+		}
+		if !sqlBindable(node.lo.(*literalNode).value) || !sqlBindable(node.hi.(*literalNode).value) {
+			return false
+		}
+		b.WriteByte('(')
+		if !appendExprSQL(b, args, node.input) {
+			return false
+		}
+		b.WriteString(" BETWEEN ")
+		if !appendExprSQL(b, args, node.lo) {
+			return false
+		}
+		b.WriteString(" AND ")
+		if !appendExprSQL(b, args, node.hi) {
+			return false
+		}
+		b.WriteByte(')')
+		return true
+
+	case *strConcatNode:
+		b.WriteByte('(')
+		for i, op := range node.operands {
+			if i > 0 {
+				b.WriteString(" || ")
+			}
+			if !appendExprSQL(b, args, op) {
+				return false
+			}
+		}
+		b.WriteByte(')')
+		return true
+
 	case *aliasNode:
 		// An alias inside a WHERE clause doesn't change the value;
 		// unwrap and translate the inner expression. Aliases matter
@@ -91,6 +156,69 @@ func appendExprSQL(b *strings.Builder, args *[]any, n ExprNode) bool {
 	return false
 }
 
+// appendIsInSQL renders a membership test; every value must pass
+// sqlBindable.
+func appendIsInSQL(b *strings.Builder, args *[]any, node *isInNode) bool {
+	if node.err != nil || len(node.values) == 0 {
+		return false
+	}
+	for _, v := range node.values {
+		if !sqlBindable(v) {
+			return false
+		}
+	}
+	b.WriteByte('(')
+	if !appendExprSQL(b, args, node.input) {
+		return false
+	}
+	if node.negate {
+		b.WriteString(" NOT IN (")
+	} else {
+		b.WriteString(" IN (")
+	}
+	for i, v := range node.values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('?')
+		*args = append(*args, v)
+	}
+	b.WriteString("))")
+	return true
+}
+
+// sqlBindable reports whether v, bound to a placeholder, means in
+// SQL what it means to the executor. Three kinds of value don't:
+//
+//   - NaN: SQLite binds it as NULL, so the comparison turns null
+//     instead of false.
+//   - uint64 past MaxInt64: database/sql refuses it outright.
+//   - time.Time: a driver binds it as formatted text, but what a
+//     timestamp column holds depends on the writer — gpkgio stores the
+//     raw int64 in the column's unit, other GeoPackage writers ISO
+//     8601 text in their own format — and SQLite compares an INTEGER
+//     against TEXT by storage class, not by instant.
+func sqlBindable(v any) bool {
+	switch x := v.(type) {
+	case float64:
+		return !math.IsNaN(x)
+	case float32:
+		return !math.IsNaN(float64(x))
+	case uint64:
+		return x <= math.MaxInt64
+	case time.Time:
+		return false
+	}
+	return true
+}
+
+// isNonNullLiteral reports whether n is a well-formed Lit other than
+// Lit(nil).
+func isNonNullLiteral(n ExprNode) bool {
+	lit, ok := n.(*literalNode)
+	return ok && lit.err == nil && lit.value != nil
+}
+
 // appendBinOpSQL handles the binary-operator arm of the translator.
 // Extracted because it carries the null-safety rewrite (=/!= vs
 // IS NULL/IS NOT NULL) and the operator-symbol switch, both of which
@@ -144,6 +272,102 @@ func appendBinOpSQL(b *strings.Builder, args *[]any, node *binOpNode) bool {
 	return true
 }
 
+// appendStrSQL handles the Str() namespace. Every emitted form must
+// match the executor row-for-row, not merely select a superset: the
+// fragment can sit under a NOT, where a superset turns into a subset
+// and silently drops rows. Lengths and offsets are bound as int64 in
+// code points, which is what both SQLite's and PostgreSQL's LENGTH /
+// SUBSTR count on text.
+func appendStrSQL(b *strings.Builder, args *[]any, node *strNode) bool {
+	if node.err != nil {
+		return false
+	}
+	inner := func() bool { return appendExprSQL(b, args, node.inner) }
+	switch node.kind {
+	case skLen:
+		b.WriteString("LENGTH(")
+		if !inner() {
+			return false
+		}
+		b.WriteByte(')')
+		return true
+
+	case skReplace:
+		// SQL's REPLACE leaves the string alone for an empty pattern;
+		// strings.ReplaceAll inserts repl between every code point.
+		if node.pattern == "" {
+			return false
+		}
+		b.WriteString("REPLACE(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(", ?, ?)")
+		*args = append(*args, node.pattern, node.repl)
+		return true
+
+	case skContains:
+		// x contains p iff removing every p shortens x. Empty p would
+		// need a NULL-aware TRUE, which has no tidy portable spelling.
+		if node.pattern == "" {
+			return false
+		}
+		b.WriteString("(LENGTH(REPLACE(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(", ?, '')) < LENGTH(")
+		*args = append(*args, node.pattern)
+		if !inner() {
+			return false
+		}
+		b.WriteString("))")
+		return true
+
+	case skStartsWith:
+		b.WriteString("(SUBSTR(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(", 1, ?) = ?)")
+		*args = append(*args, int64(utf8.RuneCountInString(node.pattern)), node.pattern)
+		return true
+
+	case skEndsWith:
+		// When the suffix is longer than x the start position drops
+		// to <= 0; SQLite then counts from the right, PostgreSQL
+		// clamps, but either way the result is shorter than the
+		// suffix and the comparison is false, as it should be.
+		b.WriteString("(SUBSTR(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(", LENGTH(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(") - ? + 1) = ?)")
+		*args = append(*args, int64(utf8.RuneCountInString(node.pattern)), node.pattern)
+		return true
+
+	case skSlice:
+		// Negative indices depend on the row's length; SQLite and
+		// PostgreSQL disagree on negative SUBSTR starts, so only the
+		// non-negative form is portable.
+		if node.start < 0 || node.stop < 0 {
+			return false
+		}
+		b.WriteString("SUBSTR(")
+		if !inner() {
+			return false
+		}
+		b.WriteString(", ?, ?)")
+		*args = append(*args, int64(node.start+1), int64(max(node.stop-node.start, 0)))
+		return true
+	}
+	return false
+}
+
 // sqlBinOpSymbol maps a gobi binOpKind to its SQL text. Returns
 // ok=false for ops that don't have a portable SQL spelling — today
 // every built-in op has one, but keeping the guard means future
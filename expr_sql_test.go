@@ -1,7 +1,10 @@
 package gobi
 
 import (
+	"math"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 )
@@ -147,3 +150,104 @@ func (fakeCustomNode) Eval(*Frame) (Series, error)                  { return Ser
 func (fakeCustomNode) Type(*arrow.Schema) (arrow.DataType, error)   { return arrow.FixedWidthTypes.Boolean, nil }
 func (fakeCustomNode) Children() []Expr                             { return nil }
 func (fakeCustomNode) String() string                               { return "custom" }
+
+// TestExprToSQL_StringOps pins the Str() translations and checks the
+// ops without an exact portable SQL spelling decline.
+func TestExprToSQL_StringOps(t *testing.T) {
+	name := Col("name").Str()
+	cases := []struct {
+		name     string
+		expr     Expr
+		wantSQL  string
+		wantArgs []any
+	}{
+		{"len", name.Len().Gt(Lit(int64(3))), `(LENGTH("name") > ?)`, []any{int64(3)}},
+		{"replace", name.Replace("-", " ").Eq(Lit("a b")), `(REPLACE("name", ?, ?) = ?)`, []any{"-", " ", "a b"}},
+		{"contains", name.Contains("ab"), `(LENGTH(REPLACE("name", ?, '')) < LENGTH("name"))`, []any{"ab"}},
+		{"starts_with", name.StartsWith("Él"), `(SUBSTR("name", 1, ?) = ?)`, []any{int64(2), "Él"}},
+		{"ends_with", name.EndsWith("xyz"), `(SUBSTR("name", LENGTH("name") - ? + 1) = ?)`, []any{int64(3), "xyz"}},
+		{"slice", name.Slice(1, 3).Eq(Lit("bc")), `(SUBSTR("name", ?, ?) = ?)`, []any{int64(2), int64(2), "bc"}},
+		{"concat", name.Concat(Lit("-"), Col("code")).Eq(Lit("a-1")), `(("name" || ? || "code") = ?)`, []any{"-", "a-1"}},
+		{"not_contains", name.Contains("x").Not(), `NOT ((LENGTH(REPLACE("name", ?, '')) < LENGTH("name")))`, []any{"x"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, ok := ExprToSQL(tc.expr)
+			if !ok {
+				t.Fatal("ExprToSQL returned ok=false")
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tc.wantSQL)
+			}
+			if !slices.Equal(args, tc.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tc.wantArgs)
+			}
+		})
+	}
+
+	declined := []Expr{
+		name.Lower().Eq(Lit("x")),
+		name.Upper().Eq(Lit("X")),
+		name.Trim().Eq(Lit("x")),
+		name.Match(`^a`),
+		name.Extract(`(a)`, 1).Eq(Lit("a")),
+		name.ReplaceAll(`a`, "b").Eq(Lit("b")),
+		name.Contains(""),
+		name.Replace("", "x").Eq(Lit("x")),
+		name.Slice(-2, 10).Eq(Lit("x")),
+		name.Format("{}!").Eq(Lit("x!")),
+	}
+	for _, e := range declined {
+		if _, _, ok := ExprToSQL(e); ok {
+			t.Errorf("%s: ExprToSQL should decline", e)
+		}
+	}
+}
+
+// TestExprToSQL_Membership pins the IN / NOT IN / BETWEEN spellings
+// and the shapes that must decline.
+func TestExprToSQL_Membership(t *testing.T) {
+	cases := []struct {
+		name     string
+		expr     Expr
+		wantSQL  string
+		wantArgs []any
+	}{
+		{"in", Col("id").IsIn([]int{1, 2, 3}), `("id" IN (?, ?, ?))`, []any{int64(1), int64(2), int64(3)}},
+		{"not_in", Col("code").NotIn([]string{"a", "b"}), `("code" NOT IN (?, ?))`, []any{"a", "b"}},
+		{"between", Col("price").Between(Lit(1.5), Lit(int64(9))), `("price" BETWEEN ? AND ?)`, []any{1.5, int64(9)}},
+		{"and_between", Col("id").IsIn([]int64{7}).And(Col("price").Between(Lit(0.0), Lit(1.0))),
+			`(("id" IN (?)) AND ("price" BETWEEN ? AND ?))`, []any{int64(7), 0.0, 1.0}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, ok := ExprToSQL(tc.expr)
+			if !ok {
+				t.Fatal("ExprToSQL returned ok=false")
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tc.wantSQL)
+			}
+			if !slices.Equal(args, tc.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tc.wantArgs)
+			}
+		})
+	}
+
+	declined := []Expr{
+		Col("id").IsIn([]int64{}),
+		Col("x").IsIn([]float64{1, math.NaN()}),
+		Col("x").NotIn([]uint64{1 << 63}),
+		Col("t").IsIn([]time.Time{time.Unix(0, 0)}),
+		Col("x").IsIn(42),
+		Col("x").Between(Lit(int64(1)), Col("y")),
+		Col("x").Between(Lit(nil), Lit(int64(1))),
+		Col("x").Between(Lit(math.NaN()), Lit(1.0)),
+		Col("x").Between(Lit(0.0), Lit(float32(math.NaN()))),
+	}
+	for _, e := range declined {
+		if _, _, ok := ExprToSQL(e); ok {
+			t.Errorf("%s: ExprToSQL should decline", e)
+		}
+	}
+}
@@ -0,0 +1,625 @@
+package gobi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// -----------------------------------------------------------------------------
+// Fluent constructors
+// -----------------------------------------------------------------------------
+
+// Str returns the string namespace for e. e must produce a String
+// column; every method on the namespace evaluates vectorized over the
+// underlying Arrow String chunks and propagates nulls (a null input
+// row yields a null output row, for predicates as well as
+// transforms).
+//
+// Cleaning an address column without leaving the expression IR:
+//
+//	f.WithColumnExpr("street",
+//	    gobi.Col("address").Str().Trim().Str().Lower().
+//	        Str().ReplaceAll(`\s+`, " "))
+//
+// Lengths, slices and offsets count Unicode code points, not bytes,
+// so results agree with SQL's LENGTH / SUBSTR on UTF-8 text.
+func (e Expr) Str() StrNamespace { return StrNamespace{e: e} }
+
+// StrNamespace groups the string expression constructors returned by
+// Expr.Str. It is a value type with no state beyond the wrapped
+// expression; every method returns a plain Expr.
+type StrNamespace struct {
+	e Expr
+}
+
+// Contains reports whether each row contains substr as a literal
+// substring. Use Match for regular expressions.
+func (s StrNamespace) Contains(substr string) Expr {
+	return s.op(&strNode{kind: skContains, pattern: substr})
+}
+
+// StartsWith reports whether each row begins with prefix.
+func (s StrNamespace) StartsWith(prefix string) Expr {
+	return s.op(&strNode{kind: skStartsWith, pattern: prefix})
+}
+
+// EndsWith reports whether each row ends with suffix.
+func (s StrNamespace) EndsWith(suffix string) Expr {
+	return s.op(&strNode{kind: skEndsWith, pattern: suffix})
+}
+
+// Lower converts each row to lower case using Unicode case mapping.
+func (s StrNamespace) Lower() Expr { return s.op(&strNode{kind: skLower}) }
+
+// Upper converts each row to upper case using Unicode case mapping.
+func (s StrNamespace) Upper() Expr { return s.op(&strNode{kind: skUpper}) }
+
+// Trim strips leading and trailing Unicode white space from each row.
+func (s StrNamespace) Trim() Expr { return s.op(&strNode{kind: skTrim}) }
+
+// Replace replaces every non-overlapping occurrence of the literal old
+// with repl. Use ReplaceAll for regular expressions.
+func (s StrNamespace) Replace(old, repl string) Expr {
+	return s.op(&strNode{kind: skReplace, pattern: old, repl: repl})
+}
+
+// Match reports whether each row matches the regular expression
+// pattern (Go RE2 syntax) anywhere in the string; anchor with ^ / $
+// for a full match. An invalid pattern surfaces at Eval / type
+// inference, not at construction.
+func (s StrNamespace) Match(pattern string) Expr {
+	re, err := regexp.Compile(pattern)
+	return s.op(&strNode{kind: skMatch, pattern: pattern, re: re, err: err})
+}
+
+// Extract returns capture group group of the first match of pattern
+// in each row (0 = the whole match). Rows with no match, or where the
+// group did not participate in the match, evaluate to null. A group
+// index beyond the pattern's capture count is an error.
+func (s StrNamespace) Extract(pattern string, group int) Expr {
+	re, err := regexp.Compile(pattern)
+	if err == nil && (group < 0 || group > re.NumSubexp()) {
+		err = fmt.Errorf("gobi: str_extract group %d out of range for %q (%d groups)",
+			group, pattern, re.NumSubexp())
+	}
+	return s.op(&strNode{kind: skExtract, pattern: pattern, re: re, group: group, err: err})
+}
+
+// ReplaceAll replaces every match of the regular expression pattern
+// with repl. Inside repl, $1 / ${name} expand to the corresponding
+// capture group, exactly as regexp.Regexp.ReplaceAllString.
+func (s StrNamespace) ReplaceAll(pattern, repl string) Expr {
+	re, err := regexp.Compile(pattern)
+	return s.op(&strNode{kind: skReplaceAll, pattern: pattern, repl: repl, re: re, err: err})
+}
+
+// Split splits each row around every occurrence of sep, producing a
+// List<String> column — so it composes directly with Explode and the
+// List* expressions:
+//
+//	tags, _ := f.WithColumnExpr("tags", gobi.Col("tag_csv").Str().Split(","))
+//	rows, _ := tags.Explode("tags")
+//
+// Semantics follow strings.Split: an empty sep splits into code
+// points, and an empty row yields a one-element list [""].
+func (s StrNamespace) Split(sep string) Expr {
+	return s.op(&strNode{kind: skSplit, pattern: sep})
+}
+
+// Len returns the number of Unicode code points in each row as Int64.
+func (s StrNamespace) Len() Expr { return s.op(&strNode{kind: skLen}) }
+
+// Slice returns the code points [start, stop) of each row. Same
+// Python-style semantics as ListSlice: negative indices count from the
+// end and out-of-range endpoints clamp to the string bounds.
+func (s StrNamespace) Slice(start, stop int) Expr {
+	return s.op(&strNode{kind: skSlice, start: start, stop: stop})
+}
+
+// Concat appends others to e, row by row. Every operand must produce a
+// String column (Lit("sep") broadcasts); a null in any operand makes
+// the output row null, matching SQL's `||`.
+func (s StrNamespace) Concat(others ...Expr) Expr {
+	nodes := make([]ExprNode, 0, len(others)+1)
+	nodes = append(nodes, s.e.node)
+	for _, o := range others {
+		nodes = append(nodes, o.node)
+	}
+	return Expr{node: &strConcatNode{operands: nodes}}
+}
+
+// Format renders template once per row, substituting each `{}`
+// placeholder in order: the first with e, the rest with others.
+// Operands may be of any scalar type — non-string values render with
+// fmt's %v — and a null in any operand makes the output row null.
+// Write `{{` / `}}` for literal braces. The placeholder count must
+// equal 1 + len(others).
+//
+//	gobi.Col("city").Str().Format("{}, {} ({})", gobi.Col("state"), gobi.Col("zip"))
+func (s StrNamespace) Format(template string, others ...Expr) Expr {
+	nodes := make([]ExprNode, 0, len(others)+1)
+	nodes = append(nodes, s.e.node)
+	for _, o := range others {
+		nodes = append(nodes, o.node)
+	}
+	parts, err := parseStrFormat(template)
+	if err == nil && len(parts)-1 != len(nodes) {
+		err = fmt.Errorf("gobi: str_format template %q has %d placeholders, got %d operands",
+			template, len(parts)-1, len(nodes))
+	}
+	return Expr{node: &strFormatNode{template: template, parts: parts, operands: nodes, err: err}}
+}
+
+func (s StrNamespace) op(n *strNode) Expr {
+	n.inner = s.e.node
+	return Expr{node: n}
+}
+
+// -----------------------------------------------------------------------------
+// strNode — the single-input string ops
+// -----------------------------------------------------------------------------
+
+type strKind int
+
+const (
+	skContains strKind = iota
+	skStartsWith
+	skEndsWith
+	skLower
+	skUpper
+	skTrim
+	skReplace
+	skMatch
+	skExtract
+	skReplaceAll
+	skSplit
+	skLen
+	skSlice
+)
+
+var strKindNames = [...]string{
+	skContains:   "str_contains",
+	skStartsWith: "str_starts_with",
+	skEndsWith:   "str_ends_with",
+	skLower:      "str_lower",
+	skUpper:      "str_upper",
+	skTrim:       "str_trim",
+	skReplace:    "str_replace",
+	skMatch:      "str_match",
+	skExtract:    "str_extract",
+	skReplaceAll: "str_replace_all",
+	skSplit:      "str_split",
+	skLen:        "str_len",
+	skSlice:      "str_slice",
+}
+
+func (k strKind) String() string { return strKindNames[k] }
+
+// strNode covers every single-input op in the namespace. The kinds
+// differ only in the per-row function and the output type, so one
+// node with a kind switch keeps Eval's chunk walk and null handling
+// in one place. Regex patterns compile at construction; a compile
+// error is parked in err and reported from Eval / Type, the same way
+// literalNode defers unsupported-type errors.
+type strNode struct {
+	inner       ExprNode
+	kind        strKind
+	pattern     string // substring, prefix, suffix, old, regex, or sep
+	repl        string
+	re          *regexp.Regexp
+	group       int
+	start, stop int
+	err         error
+}
+
+func (n *strNode) outType() arrow.DataType {
+	switch n.kind {
+	case skContains, skStartsWith, skEndsWith, skMatch:
+		return arrow.FixedWidthTypes.Boolean
+	case skLen:
+		return arrow.PrimitiveTypes.Int64
+	case skSplit:
+		return arrow.ListOf(arrow.BinaryTypes.String)
+	}
+	return arrow.BinaryTypes.String
+}
+
+func (n *strNode) Eval(input *Frame) (Series, error) {
+	if n.err != nil {
+		return Series{}, n.err
+	}
+	chunks, err := evalStringChunks(n.inner, input, n.kind.String())
+	if err != nil {
+		return Series{}, err
+	}
+	pool := memory.DefaultAllocator
+	var b array.Builder
+	switch n.kind {
+	case skContains, skStartsWith, skEndsWith, skMatch:
+		bb := array.NewBooleanBuilder(pool)
+		b = bb
+		n.evalEach(chunks, bb, func(v string) { bb.Append(n.predicate(v)) })
+	case skLen:
+		ib := array.NewInt64Builder(pool)
+		b = ib
+		n.evalEach(chunks, ib, func(v string) { ib.Append(int64(utf8.RuneCountInString(v))) })
+	case skSplit:
+		lb := array.NewListBuilder(pool, arrow.BinaryTypes.String)
+		b = lb
+		vb := lb.ValueBuilder().(*array.StringBuilder)
+		n.evalEach(chunks, lb, func(v string) {
+			lb.Append(true)
+			vb.AppendValues(strings.Split(v, n.pattern), nil)
+		})
+	case skExtract:
+		sb := array.NewStringBuilder(pool)
+		b = sb
+		n.evalEach(chunks, sb, func(v string) {
+			loc := n.re.FindStringSubmatchIndex(v)
+			if loc == nil || loc[2*n.group] < 0 {
+				sb.AppendNull()
+				return
+			}
+			sb.Append(v[loc[2*n.group]:loc[2*n.group+1]])
+		})
+	default:
+		sb := array.NewStringBuilder(pool)
+		b = sb
+		n.evalEach(chunks, sb, func(v string) { sb.Append(n.transform(v)) })
+	}
+	defer b.Release()
+	return buildSeries(n.kind.String(), n.outType(), b.NewArray()), nil
+}
+
+// evalEach walks every row of chunks, appending a null to b for null
+// inputs and calling fn for the rest.
+func (n *strNode) evalEach(chunks []*array.String, b array.Builder, fn func(string)) {
+	for _, c := range chunks {
+		for i := range c.Len() {
+			if c.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			fn(c.Value(i))
+		}
+	}
+}
+
+func (n *strNode) predicate(v string) bool {
+	switch n.kind {
+	case skContains:
+		return strings.Contains(v, n.pattern)
+	case skStartsWith:
+		return strings.HasPrefix(v, n.pattern)
+	case skEndsWith:
+		return strings.HasSuffix(v, n.pattern)
+	}
+	return n.re.MatchString(v)
+}
+
+func (n *strNode) transform(v string) string {
+	switch n.kind {
+	case skLower:
+		return strings.ToLower(v)
+	case skUpper:
+		return strings.ToUpper(v)
+	case skTrim:
+		return strings.TrimSpace(v)
+	case skReplace:
+		return strings.ReplaceAll(v, n.pattern, n.repl)
+	case skReplaceAll:
+		return n.re.ReplaceAllString(v, n.repl)
+	case skSlice:
+		return sliceRunes(v, n.start, n.stop)
+	}
+	return v
+}
+
+func (n *strNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	if err := checkStringOperand(n.inner, schema, n.kind.String()); err != nil {
+		return nil, err
+	}
+	return n.outType(), nil
+}
+
+func (n *strNode) Children() []Expr { return []Expr{{node: n.inner}} }
+
+func (n *strNode) String() string {
+	switch n.kind {
+	case skLower, skUpper, skTrim, skLen:
+		return fmt.Sprintf("%s(%s)", n.kind, n.inner)
+	case skReplace, skReplaceAll:
+		return fmt.Sprintf("%s(%s, %q, %q)", n.kind, n.inner, n.pattern, n.repl)
+	case skExtract:
+		return fmt.Sprintf("%s(%s, %q, %d)", n.kind, n.inner, n.pattern, n.group)
+	case skSlice:
+		return fmt.Sprintf("%s(%s, %d, %d)", n.kind, n.inner, n.start, n.stop)
+	}
+	return fmt.Sprintf("%s(%s, %q)", n.kind, n.inner, n.pattern)
+}
+
+// sliceRunes returns the code points [start, stop) of v with
+// clampSlice's Python-style semantics. ASCII input slices bytes
+// directly; anything else goes through a []rune copy. clampSlice
+// leaves a start past the end unclamped (an empty range for lists),
+// so cap both bounds at the length before slicing.
+func sliceRunes(v string, start, stop int) string {
+	n := utf8.RuneCountInString(v)
+	lo, hi := clampSlice(start, stop, n)
+	lo, hi = min(lo, n), min(hi, n)
+	if n != len(v) {
+		return string([]rune(v)[lo:hi])
+	}
+	return v[lo:hi]
+}
+
+// -----------------------------------------------------------------------------
+// strConcatNode
+// -----------------------------------------------------------------------------
+
+type strConcatNode struct {
+	operands []ExprNode
+}
+
+func (n *strConcatNode) Eval(input *Frame) (Series, error) {
+	cols := make([]*array.String, len(n.operands))
+	for i, op := range n.operands {
+		chunks, err := evalStringChunks(op, input, "str_concat")
+		if err != nil {
+			return Series{}, err
+		}
+		cols[i], err = flattenStringChunks(chunks)
+		if err != nil {
+			return Series{}, err
+		}
+		defer cols[i].Release()
+	}
+	b := array.NewStringBuilder(memory.DefaultAllocator)
+	defer b.Release()
+	var sb strings.Builder
+	for row := range input.NumRows() {
+		sb.Reset()
+		null := false
+		for _, c := range cols {
+			if c.IsNull(row) {
+				null = true
+				break
+			}
+			sb.WriteString(c.Value(row))
+		}
+		if null {
+			b.AppendNull()
+			continue
+		}
+		b.Append(sb.String())
+	}
+	return buildSeries("str_concat", arrow.BinaryTypes.String, b.NewArray()), nil
+}
+
+func (n *strConcatNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	for _, op := range n.operands {
+		if err := checkStringOperand(op, schema, "str_concat"); err != nil {
+			return nil, err
+		}
+	}
+	return arrow.BinaryTypes.String, nil
+}
+
+func (n *strConcatNode) Children() []Expr {
+	out := make([]Expr, len(n.operands))
+	for i, op := range n.operands {
+		out[i] = Expr{node: op}
+	}
+	return out
+}
+
+func (n *strConcatNode) String() string {
+	parts := make([]string, len(n.operands))
+	for i, op := range n.operands {
+		parts[i] = op.String()
+	}
+	return "str_concat(" + strings.Join(parts, ", ") + ")"
+}
+
+// -----------------------------------------------------------------------------
+// strFormatNode
+// -----------------------------------------------------------------------------
+
+type strFormatNode struct {
+	template string
+	parts    []string // literal text around the placeholders; len = placeholders+1
+	operands []ExprNode
+	err      error
+}
+
+func (n *strFormatNode) Eval(input *Frame) (Series, error) {
+	if n.err != nil {
+		return Series{}, n.err
+	}
+	series := make([]Series, len(n.operands))
+	for i, op := range n.operands {
+		s, err := op.Eval(input)
+		if err != nil {
+			return Series{}, fmt.Errorf("str_format operand %d: %w", i, err)
+		}
+		if s.Len() != input.NumRows() {
+			return Series{}, fmt.Errorf("str_format operand %d length %d != input rows %d",
+				i, s.Len(), input.NumRows())
+		}
+		series[i] = s
+	}
+	b := array.NewStringBuilder(memory.DefaultAllocator)
+	defer b.Release()
+	var sb strings.Builder
+	for row := range input.NumRows() {
+		sb.Reset()
+		sb.WriteString(n.parts[0])
+		null := false
+		for i, s := range series {
+			v, err := readScalarAt(s, row)
+			if err != nil {
+				return Series{}, fmt.Errorf("str_format row %d operand %d: %w", row, i, err)
+			}
+			if v == nil {
+				null = true
+				break
+			}
+			if str, ok := v.(string); ok {
+				sb.WriteString(str)
+			} else {
+				fmt.Fprintf(&sb, "%v", v)
+			}
+			sb.WriteString(n.parts[i+1])
+		}
+		if null {
+			b.AppendNull()
+			continue
+		}
+		b.Append(sb.String())
+	}
+	return buildSeries("str_format", arrow.BinaryTypes.String, b.NewArray()), nil
+}
+
+func (n *strFormatNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	for _, op := range n.operands {
+		if _, err := op.Type(schema); err != nil {
+			return nil, err
+		}
+	}
+	return arrow.BinaryTypes.String, nil
+}
+
+func (n *strFormatNode) Children() []Expr {
+	out := make([]Expr, len(n.operands))
+	for i, op := range n.operands {
+		out[i] = Expr{node: op}
+	}
+	return out
+}
+
+func (n *strFormatNode) String() string {
+	parts := make([]string, len(n.operands))
+	for i, op := range n.operands {
+		parts[i] = op.String()
+	}
+	return fmt.Sprintf("str_format(%q, %s)", n.template, strings.Join(parts, ", "))
+}
+
+// parseStrFormat splits template at its `{}` placeholders, unescaping
+// `{{` and `}}`. Any other brace is an error so a typo like `{0}`
+// fails loudly instead of rendering verbatim.
+func parseStrFormat(template string) ([]string, error) {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		next := byte(0)
+		if i+1 < len(template) {
+			next = template[i+1]
+		}
+		switch {
+		case c == '{' && next == '}':
+			parts = append(parts, cur.String())
+			cur.Reset()
+			i++
+		case c == '{' && next == '{', c == '}' && next == '}':
+			cur.WriteByte(c)
+			i++
+		case c == '{' || c == '}':
+			return nil, fmt.Errorf("gobi: str_format template %q: unmatched %q at offset %d",
+				template, c, i)
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(parts, cur.String()), nil
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// evalStringChunks evaluates n and returns its chunks as String
+// arrays, or ErrExprTypeMismatch naming op when n isn't a String
+// column.
+func evalStringChunks(n ExprNode, input *Frame, op string) ([]*array.String, error) {
+	if n == nil {
+		return nil, fmt.Errorf("gobi: %s on nil inner expression", op)
+	}
+	s, err := n.Eval(input)
+	if err != nil {
+		return nil, err
+	}
+	// String functions read values, so a categorical decodes here.
+	if s, err = decodedForString(s); err != nil {
+		return nil, err
+	}
+	if s.DataType().ID() != arrow.STRING {
+		return nil, fmt.Errorf("%w: %s requires a String column, got %s",
+			ErrExprTypeMismatch, op, s.DataType())
+	}
+	chunks := s.col.Data().Chunks()
+	out := make([]*array.String, len(chunks))
+	for i, c := range chunks {
+		sa, ok := c.(*array.String)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: chunk type %T isn't *array.String",
+				ErrExprTypeMismatch, op, c)
+		}
+		out[i] = sa
+	}
+	return out, nil
+}
+
+// flattenStringChunks returns a single String array spanning chunks so
+// multi-operand ops can index every operand by row. The caller owns
+// the result and must Release it.
+func flattenStringChunks(chunks []*array.String) (*array.String, error) {
+	if len(chunks) == 1 {
+		chunks[0].Retain()
+		return chunks[0], nil
+	}
+	arrs := make([]arrow.Array, len(chunks))
+	for i, c := range chunks {
+		arrs[i] = c
+	}
+	if len(arrs) == 0 {
+		b := array.NewStringBuilder(memory.DefaultAllocator)
+		defer b.Release()
+		return b.NewStringArray(), nil
+	}
+	out, err := array.Concatenate(arrs, memory.DefaultAllocator)
+	if err != nil {
+		return nil, fmt.Errorf("gobi: str: concatenate chunks: %w", err)
+	}
+	return out.(*array.String), nil
+}
+
+// checkStringOperand type-checks n against schema, requiring a String
+// result.
+func checkStringOperand(n ExprNode, schema *arrow.Schema, op string) error {
+	if n == nil {
+		return fmt.Errorf("gobi: %s on nil inner expression", op)
+	}
+	t, err := n.Type(schema)
+	if err != nil {
+		return err
+	}
+	if !isStringOrCategorical(t) {
+		return fmt.Errorf("%w: %s requires a String column, got %s",
+			ErrExprTypeMismatch, op, t)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+type strRow struct {
+	Addr *string `gobi:"addr"`
+	City string  `gobi:"city"`
+	Zip  int64   `gobi:"zip"`
+}
+
+// strFrame builds (addr *string, city string, zip int64) with a
+// multi-byte row and a null address.
+func strFrame(t *testing.T) *Frame {
+	t.Helper()
+	s := func(v string) *string { return &v }
+	f, err := FromStructs([]strRow{
+		{Addr: s("  12 Main St  "), City: "Springfield", Zip: 12345},
+		{Addr: s("Élysée 7"), City: "Paris", Zip: 75008},
+		{Addr: nil, City: "Nowhere", Zip: 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// strValues renders a String column with "<null>" for nulls.
+func strValues(t *testing.T, f *Frame, name string) []string {
+	t.Helper()
+	arr := getStringColumn(t, f, name)
+	out := make([]string, arr.Len())
+	for i := range out {
+		out[i] = "<null>"
+		if !arr.IsNull(i) {
+			out[i] = arr.Value(i)
+		}
+	}
+	return out
+}
+
+func TestExprStr_Transforms(t *testing.T) {
+	f := strFrame(t)
+	addr := Col("addr").Str()
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"lower", addr.Lower(), []string{"  12 main st  ", "élysée 7", "<null>"}},
+		{"upper", addr.Upper(), []string{"  12 MAIN ST  ", "ÉLYSÉE 7", "<null>"}},
+		{"trim", addr.Trim(), []string{"12 Main St", "Élysée 7", "<null>"}},
+		{"replace", addr.Replace(" ", "_"), []string{"__12_Main_St__", "Élysée_7", "<null>"}},
+		{"replace_all", addr.ReplaceAll(`(\d+)`, "#$1"), []string{"  #12 Main St  ", "Élysée #7", "<null>"}},
+		{"extract", addr.Extract(`(\d+) (\w+)`, 2), []string{"Main", "<null>", "<null>"}},
+		// Code points, not bytes: "Élysée" is 6 runes, 8 bytes.
+		{"slice", addr.Slice(0, 6), []string{"  12 M", "Élysée", "<null>"}},
+		{"slice_negative", addr.Slice(-1, 100), []string{" ", "7", "<null>"}},
+		{"chained", addr.Trim().Str().Lower().Str().Concat(Lit(", "), Col("city")),
+			[]string{"12 main st, Springfield", "élysée 7, Paris", "<null>"}},
+		{"format", Col("city").Str().Format("{} {{{}}}", Col("zip")),
+			[]string{"Springfield {12345}", "Paris {75008}", "Nowhere {0}"}},
+		{"format_null", addr.Format("{}/{}", Col("city")),
+			[]string{"  12 Main St  /Springfield", "Élysée 7/Paris", "<null>"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strValues(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %q, want %q", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprStr_PredicatesAndLen(t *testing.T) {
+	f := strFrame(t)
+	addr := Col("addr").Str()
+	cases := []struct {
+		name string
+		expr Expr
+		want []bool
+	}{
+		{"contains", addr.Contains("Main"), []bool{true, false}},
+		{"starts_with", addr.StartsWith("Él"), []bool{false, true}},
+		{"ends_with", addr.EndsWith("St  "), []bool{true, false}},
+		{"match", addr.Match(`^\s*\d+`), []bool{true, false}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, _ := out.Column("out")
+			arr := s.col.Data().Chunks()[0].(*array.Boolean)
+			if arr.Value(0) != tc.want[0] || arr.Value(1) != tc.want[1] || !arr.IsNull(2) {
+				t.Fatalf("%s = %v, want %v then null", tc.expr, arr, tc.want)
+			}
+		})
+	}
+
+	out, err := f.WithColumnExpr("n", addr.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := out.Column("n")
+	arr := s.col.Data().Chunks()[0].(*array.Int64)
+	if arr.Value(0) != 14 || arr.Value(1) != 8 || !arr.IsNull(2) {
+		t.Fatalf("len = %v, want [14 8 null]", arr)
+	}
+}
+
+func TestExprStr_SplitComposesWithExplode(t *testing.T) {
+	f := strFrame(t)
+	out, err := f.Lazy().
+		Select(Col("city"), Col("addr").Str().Trim().Str().Split(" ").Alias("word")).
+		Explode("word").
+		Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"12", "Main", "St", "Élysée", "7", "<null>"}
+	if got := strValues(t, out, "word"); !slices.Equal(got, want) {
+		t.Fatalf("words = %q, want %q", got, want)
+	}
+	if got := strValues(t, out, "city"); got[0] != "Springfield" || got[3] != "Paris" {
+		t.Fatalf("city = %q", got)
+	}
+}
+
+func TestExprStr_TypeInference(t *testing.T) {
+	schema := strFrame(t).Schema()
+	cases := []struct {
+		expr Expr
+		want arrow.DataType
+	}{
+		{Col("addr").Str().Contains("x"), arrow.FixedWidthTypes.Boolean},
+		{Col("addr").Str().Len(), arrow.PrimitiveTypes.Int64},
+		{Col("addr").Str().Split(","), arrow.ListOf(arrow.BinaryTypes.String)},
+		{Col("addr").Str().Upper(), arrow.BinaryTypes.String},
+		{Col("city").Str().Format("{}-{}", Col("zip")), arrow.BinaryTypes.String},
+	}
+	for _, tc := range cases {
+		got, err := tc.expr.node.Type(schema)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expr, err)
+		}
+		if !arrow.TypeEqual(got, tc.want) {
+			t.Fatalf("%s: type = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestExprStr_Errors(t *testing.T) {
+	f := strFrame(t)
+	if _, err := f.WithColumnExpr("out", Col("zip").Str().Lower()); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("non-string input: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := f.WithColumnExpr("out", Col("city").Str().Concat(Col("zip"))); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("non-string concat operand: err = %v, want ErrExprTypeMismatch", err)
+	}
+	bad := []Expr{
+		Col("city").Str().Match(`(`),
+		Col("city").Str().Extract(`(\w+)`, 2),
+		Col("city").Str().Format("{}-{}"),
+		Col("city").Str().Format("{0}"),
+	}
+	for _, e := range bad {
+		if _, err := f.WithColumnExpr("out", e); err == nil {
+			t.Fatalf("%s: expected error", e)
+		}
+		if _, err := e.node.Type(f.Schema()); err == nil {
+			t.Fatalf("%s: expected type-inference error", e)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package gobi
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// -----------------------------------------------------------------------------
+// Cumulative and ranking window functions
+//
+// Every function here is shape-preserving and reads its input in
+// row order, so it is meant as the inner of OverOrdered: the
+// partition's rows arrive sorted by orderBy, and "running", "first"
+// and "rank" mean what SQL's window functions mean.
+//
+//	// running spend per user, in time order
+//	Col("amount").CumSum().OverOrdered([]string{"user"}, gobi.SortKey{Column: "ts"})
+//
+//	// top 3 per region
+//	Col("sales").Rank().OverOrdered([]string{"region"},
+//	    gobi.SortKey{Column: "sales", Descending: true}).Le(Lit(3))
+//
+// Without Over they run over the whole Frame in its current order.
+// Either way they need every row of the partition at once, so a lazy
+// plan that uses one materializes that step rather than streaming it
+// (see exprContainsOver).
+// -----------------------------------------------------------------------------
+
+// CumSum returns the running sum of e's non-null values. A null row
+// stays null and doesn't reset the sum. Int64 input keeps Int64
+// (wrapping on overflow, as Go does); other numeric types sum as
+// Float64.
+func (e Expr) CumSum() Expr { return windowExpr(wkCumSum, e.node, 0) }
+
+// CumProd returns the running product of e's non-null values, with
+// CumSum's null and type rules.
+func (e Expr) CumProd() Expr { return windowExpr(wkCumProd, e.node, 0) }
+
+// CumMin returns the running minimum of e's non-null values, with
+// CumSum's null and type rules.
+func (e Expr) CumMin() Expr { return windowExpr(wkCumMin, e.node, 0) }
+
+// CumMax returns the running maximum of e's non-null values, with
+// CumSum's null and type rules.
+func (e Expr) CumMax() Expr { return windowExpr(wkCumMax, e.node, 0) }
+
+// CumCount returns how many of e's values up to and including this
+// row are non-null. Int64, never null; e may be any type.
+func (e Expr) CumCount() Expr { return windowExpr(wkCumCount, e.node, 0) }
+
+// RowNumber numbers rows 1, 2, 3, … in order — per partition under
+// Over. Int64.
+func RowNumber() Expr { return windowExpr(wkRowNumber, nil, 0) }
+
+// NTile splits the rows, in order, into n buckets numbered 1..n whose
+// sizes differ by at most one, the larger buckets first — SQL's
+// NTILE. With fewer rows than buckets each row gets its own. n must
+// be positive. Int64.
+func NTile(n int) Expr { return windowExpr(wkNTile, nil, n) }
+
+// Rank returns SQL's RANK: the 1-based position of the first row of
+// this row's peer group, so ties share a rank and leave a gap after
+// them (1, 2, 2, 4). Peers are adjacent rows with equal values of e —
+// order by e (OverOrdered with e's column as the last sort key) so
+// that equal values are adjacent. Nulls are peers of each other.
+// Int64.
+func (e Expr) Rank() Expr { return windowExpr(wkRank, e.node, 0) }
+
+// DenseRank is Rank without gaps: 1, 2, 2, 3. Int64.
+func (e Expr) DenseRank() Expr { return windowExpr(wkDenseRank, e.node, 0) }
+
+// PercentRank returns (rank - 1) / (rows - 1), in [0, 1]; 0 for a
+// single row. Float64.
+func (e Expr) PercentRank() Expr { return windowExpr(wkPercentRank, e.node, 0) }
+
+// FirstValue broadcasts e's value at the first row — per partition
+// under Over — including a null there. The type is e's.
+func (e Expr) FirstValue() Expr { return windowExpr(wkFirstValue, e.node, 0) }
+
+// LastValue broadcasts e's value at the last row. Unlike SQL's
+// LAST_VALUE under its default frame, which stops at the current
+// row's peers, this is the partition's last row.
+func (e Expr) LastValue() Expr { return windowExpr(wkLastValue, e.node, 0) }
+
+// Diff returns e minus e n rows earlier (n < 0: later), null where
+// either side is null or out of range. Int64 input keeps Int64;
+// other numeric types give Float64.
+//
+//	// time since the previous event per session
+//	Col("ts_ns").Diff(1).OverOrdered([]string{"session"}, gobi.SortKey{Column: "ts_ns"})
+func (e Expr) Diff(n int) Expr { return windowExpr(wkDiff, e.node, n) }
+
+func windowExpr(kind windowKind, inner ExprNode, n int) Expr {
+	return Expr{node: &windowNode{kind: kind, inner: inner, n: n}}
+}
+
+// -----------------------------------------------------------------------------
+// windowNode
+// -----------------------------------------------------------------------------
+
+type windowKind int
+
+const (
+	wkCumSum windowKind = iota
+	wkCumProd
+	wkCumMin
+	wkCumMax
+	wkCumCount
+	wkRowNumber
+	wkNTile
+	wkRank
+	wkDenseRank
+	wkPercentRank
+	wkFirstValue
+	wkLastValue
+	wkDiff
+)
+
+var windowKindNames = [...]string{
+	wkCumSum:      "cum_sum",
+	wkCumProd:     "cum_prod",
+	wkCumMin:      "cum_min",
+	wkCumMax:      "cum_max",
+	wkCumCount:    "cum_count",
+	wkRowNumber:   "row_number",
+	wkNTile:       "ntile",
+	wkRank:        "rank",
+	wkDenseRank:   "dense_rank",
+	wkPercentRank: "percent_rank",
+	wkFirstValue:  "first_value",
+	wkLastValue:   "last_value",
+	wkDiff:        "diff",
+}
+
+func (k windowKind) String() string { return windowKindNames[k] }
+
+// windowNode is one order-dependent function over the rows it is
+// given. inner is nil for RowNumber and NTile, which only count rows;
+// n is NTile's bucket count or Diff's offset.
+type windowNode struct {
+	kind  windowKind
+	inner ExprNode
+	n     int
+}
+
+func (n *windowNode) outType(in arrow.DataType) (arrow.DataType, error) {
+	switch n.kind {
+	case wkRowNumber, wkNTile, wkRank, wkDenseRank, wkCumCount:
+		return arrow.PrimitiveTypes.Int64, nil
+	case wkPercentRank:
+		return arrow.PrimitiveTypes.Float64, nil
+	case wkFirstValue, wkLastValue:
+		return in, nil
+	}
+	if !isNumericType(in) {
+		return nil, fmt.Errorf("%w: %s on %s", ErrExprTypeMismatch, n.kind, in)
+	}
+	if in.ID() == arrow.INT64 {
+		return arrow.PrimitiveTypes.Int64, nil
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+func (n *windowNode) Type(schema *arrow.Schema) (arrow.DataType, error) {
+	if n.kind == wkNTile && n.n <= 0 {
+		return nil, fmt.Errorf("gobi: NTile: bucket count %d must be positive", n.n)
+	}
+	if n.inner == nil {
+		return n.outType(nil)
+	}
+	in, err := n.inner.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	return n.outType(in)
+}
+
+func (n *windowNode) Eval(input *Frame) (Series, error) {
+	rows := input.NumRows()
+	switch n.kind {
+	case wkRowNumber:
+		out := make([]int64, rows)
+		for i := range out {
+			out[i] = int64(i + 1)
+		}
+		return buildInt64Series("", out, nil), nil
+	case wkNTile:
+		if n.n <= 0 {
+			return Series{}, fmt.Errorf("gobi: NTile: bucket count %d must be positive", n.n)
+		}
+		return buildInt64Series("", ntile(rows, n.n), nil), nil
+	}
+
+	s, err := n.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	dt, err := n.outType(s.DataType())
+	if err != nil {
+		return Series{}, err
+	}
+	switch n.kind {
+	case wkCumCount:
+		out := make([]int64, rows)
+		var count int64
+		for i, null := range s.Nulls() {
+			if !null {
+				count++
+			}
+			out[i] = count
+		}
+		return buildInt64Series("", out, nil), nil
+	case wkRank, wkDenseRank, wkPercentRank:
+		return n.evalRank(s)
+	case wkFirstValue, wkLastValue:
+		if rows == 0 {
+			return broadcastScalar(nil, dt, 0, n.kind.String())
+		}
+		row := 0
+		if n.kind == wkLastValue {
+			row = rows - 1
+		}
+		v, err := readScalarAt(s, row)
+		if err != nil {
+			return Series{}, fmt.Errorf("gobi: %s: %w", n.kind, err)
+		}
+		return broadcastScalar(v, dt, rows, n.kind.String())
+	}
+
+	valid := make([]bool, rows)
+	for i, null := range s.Nulls() {
+		valid[i] = !null
+	}
+	if dt.ID() == arrow.INT64 {
+		vals, err := s.Int64s()
+		if err != nil {
+			return Series{}, err
+		}
+		if n.kind == wkDiff {
+			return buildInt64Series("", diffOf(vals, valid, n.n, func(a, b int64) int64 { return a - b }), valid), nil
+		}
+		return buildInt64Series("", runningOf(vals, valid, n.kind), valid), nil
+	}
+	if s.DataType().ID() != arrow.FLOAT64 {
+		if s, err = castToFloat64(s); err != nil {
+			return Series{}, err
+		}
+	}
+	vals, err := s.Float64s()
+	if err != nil {
+		return Series{}, err
+	}
+	if n.kind == wkDiff {
+		return buildFloat64Series("", diffOf(vals, valid, n.n, func(a, b float64) float64 { return a - b }), valid), nil
+	}
+	return buildFloat64Series("", runningOf(vals, valid, n.kind), valid), nil
+}
+
+// runningOf folds vals in order for the Cum* kinds, skipping rows
+// that aren't valid (their output slot is left zero and stays null).
+// CumMin / CumMax compare with < and >, which are false against NaN:
+// a NaN row is skipped unless it is the first value.
+func runningOf[T int64 | float64](vals []T, valid []bool, kind windowKind) []T {
+	out := make([]T, len(vals))
+	var acc T
+	seen := false
+	for i, v := range vals {
+		if !valid[i] {
+			continue
+		}
+		switch {
+		case !seen:
+			acc = v
+		case kind == wkCumSum:
+			acc += v
+		case kind == wkCumProd:
+			acc *= v
+		case kind == wkCumMin && v < acc:
+			acc = v
+		case kind == wkCumMax && v > acc:
+			acc = v
+		}
+		seen = true
+		out[i] = acc
+	}
+	return out
+}
+
+// diffOf sets out[i] = vals[i] - vals[i-k], clearing valid[i] when
+// the earlier row is out of range or null. valid is updated in place
+// for the caller's builder.
+func diffOf[T int64 | float64](vals []T, valid []bool, k int, sub func(a, b T) T) []T {
+	src := append([]bool(nil), valid...)
+	out := make([]T, len(vals))
+	for i := range vals {
+		j := i - k
+		if j < 0 || j >= len(vals) || !src[i] || !src[j] {
+			valid[i] = false
+			continue
+		}
+		out[i] = sub(vals[i], vals[j])
+	}
+	return out
+}
+
+// ntile assigns rows in order to n buckets, SQL style: every bucket
+// gets rows/n rows and the first rows%n get one more.
+func ntile(rows, n int) []int64 {
+	out := make([]int64, rows)
+	size, extra := rows/n, rows%n
+	row := 0
+	for bucket := 1; row < rows; bucket++ {
+		end := row + size
+		if bucket <= extra {
+			end++
+		}
+		for ; row < end; row++ {
+			out[row] = int64(bucket)
+		}
+	}
+	return out
+}
+
+// evalRank walks s in order, starting a new peer group wherever the
+// value differs from the previous row's.
+func (n *windowNode) evalRank(s Series) (Series, error) {
+	rows := s.Len()
+	ranks := make([]int64, rows)
+	var prev any
+	var rank, dense int64
+	for i := range rows {
+		v, err := readScalarAt(s, i)
+		if err != nil {
+			return Series{}, fmt.Errorf("gobi: %s: %w", n.kind, err)
+		}
+		if i == 0 || v != prev {
+			rank = int64(i + 1)
+			dense++
+		}
+		prev = v
+		if n.kind == wkDenseRank {
+			ranks[i] = dense
+		} else {
+			ranks[i] = rank
+		}
+	}
+	if n.kind != wkPercentRank {
+		return buildInt64Series("", ranks, nil), nil
+	}
+	pct := make([]float64, rows)
+	if rows > 1 {
+		for i, r := range ranks {
+			pct[i] = float64(r-1) / float64(rows-1)
+		}
+	}
+	return buildFloat64Series("", pct, nil), nil
+}
+
+func (n *windowNode) Children() []Expr {
+	if n.inner == nil {
+		return nil
+	}
+	return []Expr{{node: n.inner}}
+}
+
+func (n *windowNode) String() string {
+	switch n.kind {
+	case wkRowNumber:
+		return "row_number()"
+	case wkNTile:
+		return fmt.Sprintf("ntile(%d)", n.n)
+	case wkDiff:
+		return fmt.Sprintf("diff(%s, %d)", n.inner, n.n)
+	}
+	return fmt.Sprintf("%s(%s)", n.kind, n.inner)
+}
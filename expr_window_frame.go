@@ -0,0 +1,533 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// -----------------------------------------------------------------------------
+// Bounded window frames for Over — moving aggregates per partition.
+//
+// A plain Over aggregate reads the whole partition; a framed one reads
+// only the rows around each row, in the partition's OverOrdered
+// order:
+//
+//	// 7-day trailing average speed per vehicle.
+//	Col("speed").Mean().
+//	    OverOrdered([]string{"vehicle"}, gobi.SortKey{Column: "ts"}).
+//	    Range(7 * 24 * time.Hour)
+//
+//	// Centered 5-row moving sum.
+//	Col("v").Sum().OverOrdered([]string{"k"}, gobi.SortKey{Column: "t"}).Rows(2, 2)
+//
+// Each partition is sorted once and then swept with a sliding window
+// whose two edges only move forward, so a partition of n rows costs
+// O(n) after the sort whatever the frame width: Sum/Mean/Count keep a
+// running total, Std/Var a Welford mean and M2 with removal, and
+// Min/Max a monotonic deque. Series.RollingSum and Frame.RollingBy are
+// the whole-Frame, non-expression equivalents.
+// -----------------------------------------------------------------------------
+
+// Rows bounds a Sum/Mean/MinAgg/MaxAgg/Count/Std/Var Over aggregate to
+// a frame of row offsets: each row aggregates the preceding rows
+// before it, itself, and the following rows after it, in the
+// OverOrdered order (input order under plain Over). Rows(6, 0) is a
+// 7-row trailing window; Rows(2, 2) a centered 5-row one. Frames are
+// clipped at the partition edges rather than nulled, so the first rows
+// of a partition see a shorter window. Both offsets must be >= 0.
+//
+// The receiver must be an Over or OverOrdered expression; anything
+// else, or a negative offset, reports an error when the expression is
+// evaluated.
+func (e Expr) Rows(preceding, following int) Expr {
+	var err error
+	if preceding < 0 || following < 0 {
+		err = fmt.Errorf("gobi: Rows(%d, %d): offsets must be >= 0", preceding, following)
+	}
+	return e.withFrame(&frameSpec{preceding: preceding, following: following}, err)
+}
+
+// Range bounds an Over aggregate to a trailing time window: each row
+// aggregates the partition's rows whose order key lies in
+// (t - period, t], where t is the row's own order key — the same
+// right-anchored, exclusive-left convention as Frame.RollingBy. Unlike
+// Rows, the frame is defined by key values, so rows sharing a
+// timestamp all see each other and get the same result.
+//
+// Range needs exactly one ascending OverOrdered key on a Timestamp,
+// Date32 or Date64 column, and period must be > 0. Rows whose order
+// key is null have no position on the time axis and come out null.
+func (e Expr) Range(period time.Duration) Expr {
+	var err error
+	if period <= 0 {
+		err = fmt.Errorf("gobi: Range period must be > 0, got %v", period)
+	}
+	return e.withFrame(&frameSpec{byRange: true, period: period}, err)
+}
+
+// withFrame returns a copy of e's overNode carrying frame. Exprs are
+// values that callers reuse, so the receiver's node is never mutated.
+func (e Expr) withFrame(frame *frameSpec, err error) Expr {
+	over, ok := e.node.(*overNode)
+	if !ok {
+		return Expr{node: &overNode{
+			inner: e.node,
+			frame: frame,
+			err:   fmt.Errorf("gobi: %s needs an Over or OverOrdered receiver, got %s", frame, e.node),
+		}}
+	}
+	cp := *over
+	cp.frame = frame
+	if cp.err == nil {
+		cp.err = err
+	}
+	return Expr{node: &cp}
+}
+
+// frameSpec is the frame spec set by Rows or Range. byRange selects
+// which of the two field groups applies.
+type frameSpec struct {
+	byRange              bool
+	preceding, following int
+	period               time.Duration
+}
+
+func (w *frameSpec) String() string {
+	if w.byRange {
+		return fmt.Sprintf("range(%v)", w.period)
+	}
+	return fmt.Sprintf("rows(%d, %d)", w.preceding, w.following)
+}
+
+// framedAggSupported reports whether kind has an incremental
+// add/remove form. First/Last/Median/Mode/NUnique don't, and would
+// fall back to O(n·w).
+func framedAggSupported(kind AggKind) bool {
+	switch kind {
+	case AggSum, AggMean, AggMin, AggMax, AggCount, AggStd, AggVar:
+		return true
+	}
+	return false
+}
+
+// checkFrame validates everything about a framed overNode that can be
+// checked without data and returns the aggregate it frames.
+func (n *overNode) checkFrame() (*scalarAggNode, error) {
+	agg, ok := n.inner.(*scalarAggNode)
+	if !ok {
+		return nil, fmt.Errorf("gobi: %s frames an aggregate (Sum, Mean, MinAgg, MaxAgg, Count, Std, Var), got %s", n.frame, n.inner)
+	}
+	if agg.fn != nil || !framedAggSupported(agg.kind) {
+		return nil, fmt.Errorf("gobi: %s does not support %s; use sum, mean, min, max, count, std or var", n.frame, agg.name())
+	}
+	if n.frame.byRange {
+		if len(n.orderBy) != 1 || n.orderBy[0].Descending {
+			return nil, fmt.Errorf("gobi: %s needs exactly one ascending OverOrdered key, got %d key(s)", n.frame, len(n.orderBy))
+		}
+	}
+	return agg, nil
+}
+
+// framedType is overNode.Type for a framed aggregate. Count is Int64;
+// everything else is Float64, matching the unframed accumulators.
+func (n *overNode) framedType(schema *arrow.Schema) (arrow.DataType, error) {
+	agg, err := n.checkFrame()
+	if err != nil {
+		return nil, err
+	}
+	inner, err := agg.inner.Type(schema)
+	if err != nil {
+		return nil, err
+	}
+	if n.frame.byRange {
+		key := n.orderBy[0].Column
+		idx := schema.FieldIndices(key)
+		if len(idx) == 0 {
+			return nil, fmt.Errorf("gobi: %s: %w: %q", n.frame, ErrColumnNotFound, key)
+		}
+		switch schema.Field(idx[0]).Type.ID() {
+		case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		default:
+			return nil, fmt.Errorf("%w: %s requires a Timestamp or Date order key, %q is %s", ErrExprTypeMismatch, n.frame, key, schema.Field(idx[0]).Type)
+		}
+	}
+	if agg.kind == AggCount {
+		return arrow.PrimitiveTypes.Int64, nil
+	}
+	if !isNumericType(inner) {
+		return nil, fmt.Errorf("%w: framed %s requires a numeric input, got %s", ErrExprTypeMismatch, agg.kind, inner)
+	}
+	return arrow.PrimitiveTypes.Float64, nil
+}
+
+// evalFramed is overNode.Eval for a framed aggregate. Partitions are
+// found and sorted exactly as on the shape-preserving path (including
+// the aligned fast path, which skips both); a partition-less Over
+// treats the whole Frame as one partition, sorted by orderBy.
+func (n *overNode) evalFramed(input *Frame) (Series, error) {
+	agg, err := n.checkFrame()
+	if err != nil {
+		return Series{}, err
+	}
+	col, err := agg.inner.Eval(input)
+	if err != nil {
+		return Series{}, err
+	}
+	nRows := input.NumRows()
+	var vals []float64
+	var valid []bool
+	if agg.kind == AggCount {
+		valid = make([]bool, nRows)
+		for i, null := range col.Nulls() {
+			valid[i] = !null
+		}
+	} else {
+		if !col.isNumeric() {
+			return Series{}, fmt.Errorf("%w: framed %s requires a numeric input, got %s", ErrExprTypeMismatch, agg.kind, col.DataType())
+		}
+		if vals, valid, err = materializeF64(col); err != nil {
+			return Series{}, err
+		}
+	}
+
+	var keys []int64
+	var keyValid []bool
+	if n.frame.byRange {
+		if keys, keyValid, err = frameRangeKeys(input, n.orderBy[0].Column); err != nil {
+			return Series{}, fmt.Errorf("%s: %w", n.frame, err)
+		}
+	}
+
+	partitions, err := n.framedPartitions(input, nRows)
+	if err != nil {
+		return Series{}, err
+	}
+
+	out := make([]float64, nRows)
+	outValid := make([]bool, nRows)
+	w := frameWindow{kind: agg.kind, vals: vals, valid: valid}
+	for _, rows := range partitions {
+		w.reset()
+		lo, hi := 0, 0
+		for i, row := range rows {
+			var start, end int
+			if n.frame.byRange {
+				if !keyValid[row] {
+					// Nulls sort last, so every later row in the
+					// partition is null-keyed too.
+					break
+				}
+				// (t - period, t]: hi runs past t's peers, lo past
+				// everything at or before t - period. Keys ascend, so
+				// both only move forward.
+				t := keys[row]
+				cutoff := t - int64(n.frame.period)
+				if cutoff > t {
+					cutoff = math.MinInt64 // period underflowed the key
+				}
+				end = max(hi, i)
+				for end < len(rows) && keyValid[rows[end]] && keys[rows[end]] <= t {
+					end++
+				}
+				start = lo
+				for start < end && keys[rows[start]] <= cutoff {
+					start++
+				}
+			} else {
+				start = max(0, i-n.frame.preceding)
+				end = min(len(rows), i+n.frame.following+1)
+			}
+			for ; hi < end; hi++ {
+				w.add(rows[hi])
+			}
+			for ; lo < start; lo++ {
+				w.remove(rows[lo])
+			}
+			out[row], outValid[row] = w.value()
+		}
+	}
+
+	name := agg.kind.String() + "_over"
+	if agg.kind == AggCount {
+		counts := make([]int64, nRows)
+		for i, v := range out {
+			counts[i] = int64(v)
+		}
+		return buildInt64Series(name, counts, outValid), nil
+	}
+	return buildFloat64Series(name, out, outValid), nil
+}
+
+// framedPartitions returns each partition's row indices in frame
+// order. On an aligned, writer-sorted input the runs are already in
+// order and are read off with one linear scan; otherwise rows are
+// hash-bucketed and each bucket sorted by orderBy.
+func (n *overNode) framedPartitions(input *Frame, nRows int) ([][]int, error) {
+	partCols := make([]Series, len(n.partitionCols))
+	for i, name := range n.partitionCols {
+		s, err := input.Column(name)
+		if err != nil {
+			return nil, fmt.Errorf("Over: %w", err)
+		}
+		partCols[i] = s
+	}
+	if len(partCols) > 0 && overShapeFastPathApplicable(input.PartitionMetadata(), n.partitionCols, n.orderBy) {
+		var partitions [][]int
+		var cur, next []byte
+		start := 0
+		for row := range nRows {
+			var err error
+			next, err = composeCompositeKeyInto(next[:0], partCols, row)
+			if err != nil {
+				return nil, fmt.Errorf("Over: partition key row %d: %w", row, err)
+			}
+			if row > 0 && !bytesEqual(cur, next) {
+				partitions = append(partitions, rangeRows(start, row))
+				start = row
+			}
+			cur, next = next, cur
+		}
+		if nRows > 0 {
+			partitions = append(partitions, rangeRows(start, nRows))
+		}
+		return partitions, nil
+	}
+
+	var partitions [][]int
+	if len(partCols) == 0 {
+		partitions = [][]int{rangeRows(0, nRows)}
+	} else {
+		partitions = collectHashedPartitions(nRows, partCols)
+	}
+	if len(n.orderBy) > 0 {
+		cmps, err := buildOrderComparators(input, n.orderBy)
+		if err != nil {
+			return nil, fmt.Errorf("Over.OrderBy: %w", err)
+		}
+		for _, rows := range partitions {
+			sortRowIndicesBy(rows, cmps)
+		}
+	}
+	return partitions, nil
+}
+
+func rangeRows(start, end int) []int {
+	rows := make([]int, end-start)
+	for i := range rows {
+		rows[i] = start + i
+	}
+	return rows
+}
+
+// frameRangeKeys reads a Range order key as Unix nanoseconds.
+func frameRangeKeys(input *Frame, name string) ([]int64, []bool, error) {
+	s, err := input.Column(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.IsDateTime() {
+		return nil, nil, fmt.Errorf("%w: order key %q is %s, not a Timestamp or Date", ErrExprTypeMismatch, name, s.DataType())
+	}
+	return timeKeysNanos(s)
+}
+
+// timeKeysNanos reads a Timestamp or Date series as Unix nanoseconds,
+// one chunk at a time through the tsView the RollingBy path uses.
+// valid is false for null rows.
+func timeKeysNanos(s Series) ([]int64, []bool, error) {
+	keys := make([]int64, s.Len())
+	valid := make([]bool, s.Len())
+	loc := timeLocation(s)
+	off := 0
+	for _, chunk := range s.col.Data().Chunks() {
+		view, ok := viewTimestampChunk(chunk, loc)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %q is %s, not a Timestamp or Date", ErrExprTypeMismatch, s.name, s.DataType())
+		}
+		for i := range chunk.Len() {
+			if t, ok := view.at(i); ok {
+				keys[off+i], valid[off+i] = t.UnixNano(), true
+			}
+		}
+		off += chunk.Len()
+	}
+	return keys, valid, nil
+}
+
+// frameWindow is the sliding-window state for one partition. Rows
+// enter with add and leave with remove in the same order, which is
+// what lets Min/Max keep a deque instead of rescanning.
+//
+// Sum/Mean total only finite values and count ±Inf/NaN separately, so
+// an Inf leaving the frame doesn't poison the total with Inf - Inf.
+// Min/Max skip NaN, as the grouped minMaxAcc does.
+type frameWindow struct {
+	kind  AggKind
+	vals  []float64
+	valid []bool
+
+	count               int // non-null rows in the frame
+	sum                 float64
+	posInf, negInf, nan int
+	mean, m2            float64 // Welford over finite values
+	deque               []int   // Min/Max candidates, oldest first
+	head                int
+}
+
+func (w *frameWindow) reset() {
+	w.count, w.sum = 0, 0
+	w.posInf, w.negInf, w.nan = 0, 0, 0
+	w.mean, w.m2 = 0, 0
+	w.deque, w.head = w.deque[:0], 0
+}
+
+func (w *frameWindow) add(row int) {
+	if !w.valid[row] {
+		return
+	}
+	w.count++
+	if w.kind == AggCount {
+		return
+	}
+	v := w.vals[row]
+	switch {
+	case math.IsNaN(v):
+		w.nan++
+		return
+	case math.IsInf(v, 1):
+		w.posInf++
+		return
+	case math.IsInf(v, -1):
+		w.negInf++
+		return
+	}
+	switch w.kind {
+	case AggMin, AggMax:
+		for len(w.deque) > w.head && w.dominates(v, w.vals[w.deque[len(w.deque)-1]]) {
+			w.deque = w.deque[:len(w.deque)-1]
+		}
+		w.deque = append(w.deque, row)
+	case AggStd, AggVar:
+		k := float64(w.count - w.posInf - w.negInf - w.nan)
+		d := v - w.mean
+		w.mean += d / k
+		w.m2 += d * (v - w.mean)
+	default:
+		w.sum += v
+	}
+}
+
+func (w *frameWindow) remove(row int) {
+	if !w.valid[row] {
+		return
+	}
+	w.count--
+	if w.kind == AggCount {
+		return
+	}
+	v := w.vals[row]
+	switch {
+	case math.IsNaN(v):
+		w.nan--
+		return
+	case math.IsInf(v, 1):
+		w.posInf--
+		return
+	case math.IsInf(v, -1):
+		w.negInf--
+		return
+	}
+	switch w.kind {
+	case AggMin, AggMax:
+		if w.head < len(w.deque) && w.deque[w.head] == row {
+			w.head++
+		}
+	case AggStd, AggVar:
+		k := float64(w.count - w.posInf - w.negInf - w.nan)
+		if k == 0 {
+			w.mean, w.m2 = 0, 0
+			return
+		}
+		d := v - w.mean
+		w.mean -= d / k
+		w.m2 -= d * (v - w.mean)
+	default:
+		w.sum -= v
+		if w.count == w.posInf+w.negInf+w.nan {
+			w.sum = 0 // drop accumulated rounding once no finite values remain
+		}
+	}
+}
+
+// dominates reports whether a new value v makes the deque's back
+// entry old unreachable: a later, no-worse value outlives it.
+func (w *frameWindow) dominates(v, old float64) bool {
+	if w.kind == AggMin {
+		return v <= old
+	}
+	return v >= old
+}
+
+// value finalizes the current frame. Null rules follow the unframed
+// accumulators: Count never nulls, Std/Var need two values, the rest
+// need one.
+func (w *frameWindow) value() (float64, bool) {
+	if w.kind == AggCount {
+		return float64(w.count), true
+	}
+	if w.count == 0 {
+		return 0, false
+	}
+	nonFinite := w.nan > 0 || (w.posInf > 0 && w.negInf > 0)
+	switch w.kind {
+	case AggMin, AggMax:
+		best := math.NaN()
+		if w.head < len(w.deque) {
+			best = w.vals[w.deque[w.head]]
+		}
+		switch {
+		case w.kind == AggMin && w.negInf > 0, w.kind == AggMax && w.posInf > 0:
+			return w.extremeInf(), true
+		case math.IsNaN(best) && w.posInf > 0:
+			return math.Inf(1), true
+		case math.IsNaN(best) && w.negInf > 0:
+			return math.Inf(-1), true
+		}
+		return best, true
+	case AggStd, AggVar:
+		if w.count < 2 {
+			return 0, false
+		}
+		if nonFinite || w.posInf > 0 || w.negInf > 0 {
+			return math.NaN(), true
+		}
+		variance := max(w.m2, 0) / float64(w.count-1)
+		if w.kind == AggStd {
+			return math.Sqrt(variance), true
+		}
+		return variance, true
+	}
+	total := w.sum
+	switch {
+	case nonFinite:
+		total = math.NaN()
+	case w.posInf > 0:
+		total = math.Inf(1)
+	case w.negInf > 0:
+		total = math.Inf(-1)
+	}
+	if w.kind == AggMean {
+		return total / float64(w.count), true
+	}
+	return total, true
+}
+
+func (w *frameWindow) extremeInf() float64 {
+	if w.kind == AggMin {
+		return math.Inf(-1)
+	}
+	return math.Inf(1)
+}
@@ -0,0 +1,309 @@
+package gobi
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestFrame_RowsOverOrdered(t *testing.T) {
+	f := windowFrame(t)
+	byTS := func(e Expr) Expr { return e.OverOrdered([]string{"region"}, SortKey{Column: "ts"}) }
+	sales := Col("sales")
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"trailing_sum", byTS(sales.Sum()).Rows(1, 0), []string{"100", "10", "70", "10", "120", "30", "70"}},
+		{"centered_count", byTS(sales.Count()).Rows(1, 1), []string{"3", "1", "2", "2", "3", "1", "2"}},
+		{"trailing_max", byTS(sales.MaxAgg()).Rows(2, 0), []string{"70", "10", "70", "10", "70", "30", "50"}},
+		// One non-null value in the frame is too few for a variance.
+		{"trailing_var", byTS(Col("ts").Var()).Rows(1, 0), []string{"0.5", "null", "null", "0.5", "0.5", "0.5", "0.5"}},
+		{"leading_min", byTS(sales.MinAgg()).Rows(0, 1), []string{"20", "10", "50", "30", "50", "30", "20"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := valuesOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFrame_RangeTrailingTime(t *testing.T) {
+	type row struct {
+		Vehicle string     `gobi:"vehicle"`
+		TS      *time.Time `gobi:"ts"`
+		Speed   *float64   `gobi:"speed"`
+	}
+	day := func(d int) *time.Time {
+		ts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d)
+		return &ts
+	}
+	v := func(x float64) *float64 { return &x }
+	f, err := FromStructs([]row{
+		{"a", day(3), v(40)},
+		{"b", nil, v(7)},
+		{"a", day(1), v(20)},
+		{"a", day(0), v(10)},
+		{"b", day(0), v(5)},
+		{"a", day(9), v(50)},
+		{"a", day(1), v(30)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (t - 2 days, t]: day 1's two rows see each other and day 0;
+	// day 3 no longer sees day 1, which sits exactly on the open edge.
+	out, err := f.WithColumnExpr("avg",
+		Col("speed").Mean().OverOrdered([]string{"vehicle"}, SortKey{Column: "ts"}).Range(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"40", "null", "20", "10", "5", "50", "20"}
+	if got := valuesOrNull(t, out, "avg"); !slices.Equal(got, want) {
+		t.Fatalf("range mean = %v, want %v", got, want)
+	}
+}
+
+// framedOracleFrame builds n rows over a handful of groups with
+// minute-grained timestamps (so Range frames see ties) and ~10% null
+// values, sorted by (group, ts) so it can also be tagged aligned.
+func framedOracleFrame(t *testing.T, n int) *Frame {
+	t.Helper()
+	type row struct {
+		Group string    `gobi:"group"`
+		TS    time.Time `gobi:"ts"`
+		V     *float64  `gobi:"v"`
+	}
+	r := rand.New(rand.NewPCG(14, 14))
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i].Group = string(rune('a' + r.IntN(4)))
+		rows[i].TS = base.Add(time.Duration(r.IntN(600)) * time.Minute)
+		if r.IntN(10) > 0 {
+			x := math.Round(r.NormFloat64()*1000) / 8
+			rows[i].V = &x
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b row) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), a.TS.Compare(b.TS))
+	})
+	f, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// TestFrame_MatchesAccumulatorOracle checks the sliding window against
+// the unframed accumulators re-run from scratch on every row's frame,
+// on both the hash-partitioned and the aligned fast path.
+func TestFrame_MatchesAccumulatorOracle(t *testing.T) {
+	const n = 400
+	general := framedOracleFrame(t, n)
+	aligned := framedOracleFrame(t, n)
+	aligned.WithPartitionMeta(&PartitionMetadata{
+		Columns:      []string{"group"},
+		SortedBy:     []SortKey{{Column: "group"}, {Column: "ts"}},
+		SortEnforced: true,
+	})
+	groups := strValues(t, general, "group")
+	tsCol := mustColumn(t, general, "ts")
+	vCol := mustColumn(t, general, "v")
+	ts := make([]int64, n)
+	for i := range ts {
+		v, err := readScalarAt(tsCol, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts[i] = int64(v.(arrow.Timestamp)) // FromStructs writes nanoseconds
+	}
+
+	type frameCase struct {
+		apply  func(Expr) Expr
+		window func(i int) []int // rows in row i's frame; input is already in frame order
+	}
+	rowsFrame := func(p, fw int) frameCase {
+		return frameCase{
+			apply: func(e Expr) Expr { return e.Rows(p, fw) },
+			window: func(i int) []int {
+				var out []int
+				for j := i - p; j <= i+fw; j++ {
+					if j >= 0 && j < n && groups[j] == groups[i] {
+						out = append(out, j)
+					}
+				}
+				return out
+			},
+		}
+	}
+	rangeFrame := func(d time.Duration) frameCase {
+		return frameCase{
+			apply: func(e Expr) Expr { return e.Range(d) },
+			window: func(i int) []int {
+				var out []int
+				for j := range n {
+					if groups[j] == groups[i] && ts[j] <= ts[i] && ts[j] > ts[i]-int64(d) {
+						out = append(out, j)
+					}
+				}
+				return out
+			},
+		}
+	}
+	frames := []frameCase{rowsFrame(0, 0), rowsFrame(3, 1), rowsFrame(0, 4), rowsFrame(n, 0), rangeFrame(time.Minute), rangeFrame(45 * time.Minute)}
+	kinds := []struct {
+		kind AggKind
+		expr Expr
+	}{
+		{AggSum, Col("v").Sum()}, {AggMean, Col("v").Mean()},
+		{AggMin, Col("v").MinAgg()}, {AggMax, Col("v").MaxAgg()},
+		{AggCount, Col("v").Count()}, {AggStd, Col("v").Std()}, {AggVar, Col("v").Var()},
+	}
+	for _, fc := range frames {
+		for _, k := range kinds {
+			want := make([]float64, n)
+			wantNull := make([]bool, n)
+			for i := range n {
+				acc, err := newAccumulator(Aggregation{Kind: k.kind, Column: "v"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := acc.Update(vCol, fc.window(i)); err != nil {
+					t.Fatal(err)
+				}
+				switch v := acc.Finalize().(type) {
+				case nil:
+					wantNull[i] = true
+				case float64:
+					want[i] = v
+				case int64:
+					want[i] = float64(v)
+				}
+			}
+			expr := fc.apply(k.expr.OverOrdered([]string{"group"}, SortKey{Column: "ts"}))
+			for _, f := range []*Frame{general, aligned} {
+				out, err := f.WithColumnExpr("out", expr)
+				if err != nil {
+					t.Fatalf("%s: %v", expr, err)
+				}
+				got := mustColumn(t, out, "out")
+				for i := range n {
+					v, err := readScalarAt(got, i)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if (v == nil) != wantNull[i] {
+						t.Fatalf("%s row %d: got %v, want null=%v", expr, i, v, wantNull[i])
+					}
+					if v == nil {
+						continue
+					}
+					x, ok := v.(float64)
+					if !ok {
+						x = float64(v.(int64))
+					}
+					if math.Abs(x-want[i]) > 1e-6*max(1, math.Abs(want[i])) {
+						t.Fatalf("%s row %d: got %v, want %v", expr, i, x, want[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFrame_NonFiniteLeavesWindow(t *testing.T) {
+	type row struct {
+		K string  `gobi:"k"`
+		V float64 `gobi:"v"`
+	}
+	f, err := FromStructs([]row{{"x", 1}, {"x", math.Inf(1)}, {"x", 2}, {"x", 3}, {"x", math.NaN()}, {"x", 4}, {"x", 5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := f.WithColumnExpr("s", Col("v").Sum().Over("k").Rows(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{1, math.Inf(1), math.Inf(1), 5, math.NaN(), math.NaN(), 9}
+	got, err := mustColumn(t, out, "s").Float64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if got[i] != want[i] && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+			t.Fatalf("sum = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFrame_ErrorsStringAndLazy(t *testing.T) {
+	f := windowFrame(t)
+	byTS := Col("sales").Sum().OverOrdered([]string{"region"}, SortKey{Column: "ts"})
+	if got := byTS.Rows(2, 0).String(); got != `sum(col("sales")).over([region]).orderBy([ts]).rows(2, 0)` {
+		t.Fatalf("String = %s", got)
+	}
+	if got := byTS.Range(time.Hour).String(); got != `sum(col("sales")).over([region]).orderBy([ts]).range(1h0m0s)` {
+		t.Fatalf("String = %s", got)
+	}
+
+	errCases := []struct {
+		name string
+		expr Expr
+		is   error
+	}{
+		{"negative_offset", byTS.Rows(-1, 0), nil},
+		{"zero_period", byTS.Range(0), nil},
+		{"not_over", Col("sales").Sum().Rows(1, 0), nil},
+		{"not_aggregate", Col("sales").Shift(1).Over("region").Rows(1, 0), nil},
+		{"median", Col("sales").Median().Over("region").Rows(1, 0), nil},
+		{"range_int_key", byTS.Range(time.Hour), ErrExprTypeMismatch},
+		{"range_two_keys", Col("sales").Sum().OverOrdered([]string{"region"}, SortKey{Column: "ts"}, SortKey{Column: "sales"}).Range(time.Hour), nil},
+		{"string_sum", Col("region").Sum().Over("region").Rows(1, 0), ErrExprTypeMismatch},
+	}
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.WithColumnExpr("out", tc.expr)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tc.is != nil && !errors.Is(err, tc.is) {
+				t.Fatalf("err = %v, want %v", err, tc.is)
+			}
+		})
+	}
+
+	// The frame survives the unframed receiver being reused.
+	if _, err := f.WithColumnExpr("out", byTS); err != nil {
+		t.Fatalf("unframed receiver: %v", err)
+	}
+
+	lf := f.Lazy().WithColumn("s", byTS.Rows(1, 0))
+	op, err := Compile(Optimize(lf.Plan()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := op.(*materializeExecOp); !ok {
+		t.Fatalf("framed Over should materialize, got %T", op)
+	}
+	got, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals := valuesOrNull(t, got, "s"); !slices.Equal(vals, []string{"100", "10", "70", "10", "120", "30", "70"}) {
+		t.Fatalf("lazy rows(1, 0) sum = %v", vals)
+	}
+}
@@ -0,0 +1,217 @@
+package gobi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// windowFrame builds (region, ts, sales *int64) with rows shuffled
+// out of ts order, a tie on sales in "east" and a null in "west".
+func windowFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		Region string `gobi:"region"`
+		TS     int64  `gobi:"ts"`
+		Sales  *int64 `gobi:"sales"`
+	}
+	i := func(v int64) *int64 { return &v }
+	f, err := FromStructs([]row{
+		{"east", 3, i(50)},
+		{"west", 1, i(10)},
+		{"east", 1, i(70)},
+		{"west", 2, nil},
+		{"east", 2, i(50)},
+		{"west", 3, i(30)},
+		{"east", 4, i(20)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// valuesOrNull renders any column through readScalarAt, "null" for
+// nulls.
+func valuesOrNull(t *testing.T, f *Frame, name string) []string {
+	t.Helper()
+	s := mustColumn(t, f, name)
+	out := make([]string, s.Len())
+	for i := range out {
+		v, err := readScalarAt(s, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = "null"
+		if v != nil {
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+func TestWindow_OverOrderedByTime(t *testing.T) {
+	f := windowFrame(t)
+	byTS := func(e Expr) Expr { return e.OverOrdered([]string{"region"}, SortKey{Column: "ts"}) }
+	sales := Col("sales")
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"cum_sum", byTS(sales.CumSum()), []string{"170", "10", "70", "null", "120", "40", "190"}},
+		{"cum_count", byTS(sales.CumCount()), []string{"3", "1", "1", "1", "2", "2", "4"}},
+		{"cum_min", byTS(sales.CumMin()), []string{"50", "10", "70", "null", "50", "10", "20"}},
+		{"cum_max", byTS(sales.CumMax()), []string{"70", "10", "70", "null", "70", "30", "70"}},
+		{"cum_prod", byTS(Col("ts").CumProd()), []string{"6", "1", "1", "2", "2", "6", "24"}},
+		{"row_number", byTS(RowNumber()), []string{"3", "1", "1", "2", "2", "3", "4"}},
+		{"ntile", byTS(NTile(2)), []string{"2", "1", "1", "1", "1", "2", "2"}},
+		// The earlier row being null nulls the difference too.
+		{"diff", byTS(sales.Diff(1)), []string{"0", "null", "null", "null", "-20", "null", "-30"}},
+		{"diff_lead", byTS(Col("ts").Diff(-1)), []string{"-1", "-1", "-1", "-1", "-1", "null", "null"}},
+		{"first_value", byTS(sales.FirstValue()), []string{"70", "10", "70", "10", "70", "10", "70"}},
+		{"last_value", byTS(sales.LastValue()), []string{"20", "30", "20", "30", "20", "30", "20"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := valuesOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWindow_Ranks(t *testing.T) {
+	f := windowFrame(t)
+	// Sales descending: east 70, 50, 50, 20; west 30, 10, null.
+	bySales := func(e Expr) Expr {
+		return e.OverOrdered([]string{"region"}, SortKey{Column: "sales", Descending: true})
+	}
+	cases := []struct {
+		name string
+		expr Expr
+		want []string
+	}{
+		{"rank", bySales(Col("sales").Rank()), []string{"2", "2", "1", "3", "2", "1", "4"}},
+		{"dense_rank", bySales(Col("sales").DenseRank()), []string{"2", "2", "1", "3", "2", "1", "3"}},
+		{"percent_rank", bySales(Col("sales").PercentRank()),
+			[]string{fmt.Sprint(1.0 / 3), "0.5", "0", "1", fmt.Sprint(1.0 / 3), "0", "1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := f.WithColumnExpr("out", tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := valuesOrNull(t, out, "out"); !slices.Equal(got, tc.want) {
+				t.Fatalf("%s = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+
+	// Top 2 per region, the motivating query.
+	top, err := f.Lazy().
+		Filter(bySales(Col("sales").Rank()).Le(Lit(int64(2)))).
+		Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, top, "sales"); !slices.Equal(got, []string{"50", "10", "70", "50", "30"}) {
+		t.Fatalf("top-2 sales = %v", got)
+	}
+}
+
+func TestWindow_WholeFrameWithoutOver(t *testing.T) {
+	f := windowFrame(t)
+	out, err := f.WithColumnExpr("out", Col("ts").CumSum())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "out"); !slices.Equal(got, []string{"3", "4", "5", "7", "9", "12", "16"}) {
+		t.Fatalf("cum_sum = %v", got)
+	}
+	// Non-Int64 numeric input accumulates as Float64.
+	out, err = f.WithColumnExpr("out", Col("ts").Mul(Lit(0.5)).CumMax())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := mustColumn(t, out, "out"); !arrow.TypeEqual(s.DataType(), arrow.PrimitiveTypes.Float64) {
+		t.Fatalf("type = %s, want Float64", s.DataType())
+	}
+	if got := valuesOrNull(t, out, "out"); !slices.Equal(got, []string{"1.5", "1.5", "1.5", "1.5", "1.5", "1.5", "2"}) {
+		t.Fatalf("cum_max = %v", got)
+	}
+}
+
+// TestWindow_AlignedFastPathMatchesGeneral runs the inners through
+// evalShapePreservingAligned and checks it against the hash path.
+func TestWindow_AlignedFastPathMatchesGeneral(t *testing.T) {
+	exprs := []Expr{
+		Col("v").CumSum().Over("group"),
+		RowNumber().Over("group"),
+		Col("v").Diff(2).Over("group"),
+		Col("v").Rank().Over("group"),
+	}
+	for _, e := range exprs {
+		slow, err := sortedOverFrame(t, 4, 5).WithColumnExpr("out", e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := sortedOverFrame(t, 4, 5)
+		f.WithPartitionMeta(alignedSortedMeta())
+		fast, err := f.WithColumnExpr("out", e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, b := valuesOrNull(t, slow, "out"), valuesOrNull(t, fast, "out"); !slices.Equal(a, b) {
+			t.Fatalf("%s: general %v, aligned %v", e, a, b)
+		}
+	}
+	out, err := sortedOverFrame(t, 2, 3).WithColumnExpr("out", Col("v").CumSum().Over("group"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "out"); !slices.Equal(got, []string{"0", "1", "3", "3", "7", "12"}) {
+		t.Fatalf("cum_sum = %v", got)
+	}
+}
+
+func TestWindow_LazyMaterializesAndErrors(t *testing.T) {
+	f := windowFrame(t)
+	lf := f.Lazy().WithColumn("n", RowNumber())
+	op, err := Compile(Optimize(lf.Plan()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := op.(*materializeExecOp); !ok {
+		t.Fatalf("window function should materialize, got %T", op)
+	}
+	if got := RowNumber().String(); got != "row_number()" {
+		t.Fatalf("String = %s", got)
+	}
+	if got := Col("v").Diff(1).String(); got != `diff(col("v"), 1)` {
+		t.Fatalf("String = %s", got)
+	}
+
+	if _, err := f.WithColumnExpr("out", Col("region").CumSum()); !errors.Is(err, ErrExprTypeMismatch) {
+		t.Fatalf("CumSum on String: err = %v, want ErrExprTypeMismatch", err)
+	}
+	if _, err := f.WithColumnExpr("out", NTile(0)); err == nil {
+		t.Fatal("NTile(0): expected error")
+	}
+	// Non-numeric ranks and counts are fine.
+	out, err := f.WithColumnExpr("out", Col("region").DenseRank())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, out, "out"); !slices.Equal(got, []string{"1", "2", "3", "4", "5", "6", "7"}) {
+		t.Fatalf("dense_rank over unsorted region = %v", got)
+	}
+}
@@ -287,6 +287,8 @@ func takeArrayFast(pool memory.Allocator, chunk arrow.Array, indexes []int) (arr
 			}
 		}
 		return lb.NewArray(), nil
+	case *array.Dictionary:
+		return takeDictionaryCodes(pool, a, indexes), nil
 	}
 	return nil, fmt.Errorf("%w: take not implemented for %T", ErrColumnTypeMismatch, chunk)
 }
@@ -379,6 +381,20 @@ func takeArraySlow(pool memory.Allocator, s Series, indexes []int) (arrow.Array,
 			}
 		}
 		return lb.NewArray(), nil
+	case arrow.DICTIONARY:
+		// Chunks carry their own dictionaries; re-encode into one.
+		if !isCategoricalType(dt) {
+			return nil, fmt.Errorf("%w: take not implemented for %s",
+				ErrColumnTypeMismatch, dt)
+		}
+		b := array.NewDictionaryBuilder(pool, dt.(*arrow.DictionaryType))
+		defer b.Release()
+		for _, idx := range indexes {
+			if err := appendPrimitiveAt(s, idx, b); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
 	default:
 		return nil, fmt.Errorf("%w: take not implemented for %s",
 			ErrColumnTypeMismatch, dt)
@@ -412,13 +428,24 @@ func appendPrimitiveAt(s Series, idx int, b array.Builder) error {
 			case *array.Boolean:
 				b.(*array.BooleanBuilder).Append(a.Value(local))
 			case *array.String:
-				b.(*array.StringBuilder).Append(a.Value(local))
+				return appendStringTo(b, a.Value(local))
 			case *array.LargeString:
-				b.(*array.LargeStringBuilder).Append(a.Value(local))
+				return appendStringTo(b, a.Value(local))
 			case *array.Binary:
 				b.(*array.BinaryBuilder).Append(a.Value(local))
 			case *array.Timestamp:
 				b.(*array.TimestampBuilder).Append(a.Value(local))
+			case *array.Date32:
+				b.(*array.Date32Builder).Append(a.Value(local))
+			case *array.Date64:
+				b.(*array.Date64Builder).Append(a.Value(local))
+			case *array.Dictionary:
+				c, ok := asCatChunk(a)
+				if !ok {
+					return fmt.Errorf("%w: unsupported dictionary type %s",
+						ErrColumnTypeMismatch, a.DataType())
+				}
+				return appendStringTo(b, c.value(local))
 			default:
 				return fmt.Errorf("%w: unsupported chunk type %T",
 					ErrColumnTypeMismatch, chunk)
@@ -1,6 +1,7 @@
 package geojsonio_test
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -250,6 +251,35 @@ func TestFrame_WriteAndRead_FeatureCollection(t *testing.T) {
 	}
 }
 
+func TestFrame_WriteAndRead_Categorical(t *testing.T) {
+	df, err := buildFeatureFrame(t).WithColumnExpr("name", gobi.Col("name").Cast(gobi.Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	written := filepath.Join(dir, "written.geojson")
+	if err := geojsonio.WriteFile(df, written, nil); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	sunk := filepath.Join(dir, "sunk.geojson")
+	if err := geojsonio.SinkFile(context.Background(), df.Lazy(), sunk, nil); err != nil {
+		t.Fatalf("SinkFile: %v", err)
+	}
+	for _, path := range []string{written, sunk} {
+		got, err := geojsonio.ReadFile(path, nil)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		nameS, err := got.Column("name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vals, _ := nameS.Strings(); strings.Join(vals, ",") != "a,b,c" {
+			t.Errorf("%s: name = %q, want a,b,c", filepath.Base(path), vals)
+		}
+	}
+}
+
 func TestFrame_WriteAndRead_LineDelimited(t *testing.T) {
 	df := buildFeatureFrame(t)
 	// .geojsonl extension triggers FormatLineDelimited via
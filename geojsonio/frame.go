@@ -633,26 +633,9 @@ func WriteFile(df *gobi.Frame, path string, opts *WriteOptions) error {
 	if opts == nil {
 		opts = &WriteOptions{}
 	}
-	geomIdx := -1
-	if opts.GeomCol != "" {
-		names := df.ColumnNames()
-		for i, n := range names {
-			if n == opts.GeomCol {
-				geomIdx = i
-				break
-			}
-		}
-		if geomIdx < 0 {
-			return fmt.Errorf("geojsonio: WriteFile: GeomCol %q not in frame", opts.GeomCol)
-		}
-	} else {
-		for i := range df.NumCols() {
-			s, _ := df.ColumnAt(i)
-			if s.IsGeometry() {
-				geomIdx = i
-				break
-			}
-		}
+	geomIdx, err := resolveGeomIdx(df, opts.GeomCol)
+	if err != nil {
+		return err
 	}
 
 	f, err := os.Create(path)
@@ -676,52 +659,90 @@ func WriteFile(df *gobi.Frame, path string, opts *WriteOptions) error {
 	}
 }
 
+// resolveGeomIdx returns the index of df's geometry column: geomCol
+// when named (an error when absent), else the first column tagged as
+// gobi.GeometryField, else -1 — features then carry a null geometry.
+func resolveGeomIdx(df *gobi.Frame, geomCol string) (int, error) {
+	if geomCol != "" {
+		for i, n := range df.ColumnNames() {
+			if n == geomCol {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("geojsonio: GeomCol %q not in frame", geomCol)
+	}
+	for i := range df.NumCols() {
+		s, _ := df.ColumnAt(i)
+		if s.IsGeometry() {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
 // writeFeatureCollection emits a `{"type":"FeatureCollection","features":[...]}`
 // document. Indentation controls whitespace: empty for compact,
 // non-empty for pretty-printed.
 func writeFeatureCollection(w io.Writer, df *gobi.Frame, geomIdx int, indent string) error {
-	pretty := indent != ""
-	if pretty {
-		if _, err := fmt.Fprintf(w, "{\n%[1]s\"type\": \"FeatureCollection\",\n%[1]s\"features\": [\n", indent); err != nil {
-			return err
-		}
-	} else {
-		if _, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`); err != nil {
-			return err
-		}
+	fc := &fcWriter{w: w, indent: indent}
+	if err := fc.begin(); err != nil {
+		return err
 	}
-	err := forEachFeature(df, geomIdx, func(row int, featJSON []byte) error {
-		if row > 0 {
+	if err := fc.writeFrame(df, geomIdx); err != nil {
+		return err
+	}
+	return fc.end()
+}
+
+// fcWriter writes a FeatureCollection incrementally: begin, any number
+// of writeFrame calls, end. It tracks how many features it has written
+// so the separators come out right across Frames.
+type fcWriter struct {
+	w      io.Writer
+	indent string
+	n      int
+}
+
+func (fc *fcWriter) begin() error {
+	if fc.indent != "" {
+		_, err := fmt.Fprintf(fc.w, "{\n%[1]s\"type\": \"FeatureCollection\",\n%[1]s\"features\": [\n", fc.indent)
+		return err
+	}
+	_, err := io.WriteString(fc.w, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (fc *fcWriter) writeFrame(df *gobi.Frame, geomIdx int) error {
+	pretty := fc.indent != ""
+	return forEachFeature(df, geomIdx, func(_ int, featJSON []byte) error {
+		if fc.n > 0 {
 			if pretty {
-				if _, err := fmt.Fprintf(w, ",\n%s%s", indent, indent); err != nil {
+				if _, err := fmt.Fprintf(fc.w, ",\n%s%s", fc.indent, fc.indent); err != nil {
 					return err
 				}
 			} else {
-				if _, err := io.WriteString(w, ","); err != nil {
+				if _, err := io.WriteString(fc.w, ","); err != nil {
 					return err
 				}
 			}
 		} else if pretty {
-			if _, err := fmt.Fprintf(w, "%s%s", indent, indent); err != nil {
+			if _, err := fmt.Fprintf(fc.w, "%s%s", fc.indent, fc.indent); err != nil {
 				return err
 			}
 		}
-		_, err := w.Write(featJSON)
+		fc.n++
+		_, err := fc.w.Write(featJSON)
 		return err
 	})
-	if err != nil {
+}
+
+func (fc *fcWriter) end() error {
+	if fc.indent != "" {
+		_, err := fmt.Fprintf(fc.w, "\n%s]\n}\n", fc.indent)
 		return err
 	}
-	if pretty {
-		if _, err := fmt.Fprintf(w, "\n%s]\n}\n", indent); err != nil {
-			return err
-		}
-	} else {
-		if _, err := io.WriteString(w, "]}"); err != nil {
-			return err
-		}
-	}
-	return nil
+	_, err := io.WriteString(fc.w, "]}")
+	return err
 }
 
 // writeLines emits one Feature per line — the `.geojsonl`
@@ -822,6 +843,11 @@ func scalarAt(s gobi.Series, row int) (any, error) {
 				return a.Value(local), nil
 			case *array.Binary:
 				return a.Value(local), nil
+			case *array.Dictionary:
+				// Categorical: the property is the category string.
+				if dict, ok := a.Dictionary().(*array.String); ok {
+					return dict.Value(a.GetValueIndex(local)), nil
+				}
 			case *array.Timestamp:
 				// Emit as RFC 3339 for round-trip-friendly JSON. Unit
 				// comes from the field's arrow type.
@@ -0,0 +1,104 @@
+package geojsonio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/zoobst/gobi"
+)
+
+// SinkFile runs lf and streams its output to a GeoJSON file at path,
+// one batch at a time, instead of Collect-ing it first:
+//
+//	err := geojsonio.SinkFile(ctx,
+//		parquetio.ScanFile("parcels.parquet", nil).
+//			Filter(gobi.Col("zone").Eq(gobi.Lit("R1"))),
+//		"r1.geojsonl", nil)
+//
+// Unlike WriteFile, FormatAuto follows the path the way ReadFile
+// does: `.geojsonl` / `.ndjson` write newline-delimited GeoJSON, one
+// Feature per line — the natural shape for a stream — and anything
+// else a FeatureCollection, whose features are likewise written as
+// each batch arrives. GeomCol, Indent and the property encoding match
+// WriteFile.
+//
+// On any error — including cancellation of ctx — the partial file is
+// removed.
+func SinkFile(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions) error {
+	return SinkFileWith(ctx, lf, path, opts, nil)
+}
+
+// SinkFileWith is SinkFile run under collect (see
+// gobi.LazyFrame.SinkWith), so a plan whose Sort, Aggregate or Join
+// outgrows memory spills to disk on its way into the GeoJSON file.
+// A nil collect is SinkFile.
+func SinkFileWith(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions, collect *gobi.CollectOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	format := opts.Format
+	if format == FormatAuto {
+		format = detectFormatFromPath(path)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = lf.SinkWith(ctx, &fileSink{bw: bufio.NewWriter(out), format: format, opts: opts}, collect)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(path))
+	}
+	return nil
+}
+
+// fileSink is the gobi.BatchSink behind SinkFile.
+type fileSink struct {
+	bw      *bufio.Writer
+	format  Format
+	opts    *WriteOptions
+	geomIdx int
+	// fc is the open FeatureCollection; nil for line-delimited output.
+	fc *fcWriter
+}
+
+// Open resolves the geometry column against the plan's schema and,
+// for a FeatureCollection, writes the document head.
+func (s *fileSink) Open(schema *arrow.Schema) error {
+	empty, err := gobi.EmptyFrame(schema)
+	if err != nil {
+		return err
+	}
+	defer empty.Release()
+	if s.geomIdx, err = resolveGeomIdx(empty, s.opts.GeomCol); err != nil {
+		return err
+	}
+	if s.format == FormatLineDelimited {
+		return nil
+	}
+	s.fc = &fcWriter{w: s.bw, indent: s.opts.Indent}
+	return s.fc.begin()
+}
+
+func (s *fileSink) Write(batch *gobi.Frame) error {
+	if s.fc != nil {
+		return s.fc.writeFrame(batch, s.geomIdx)
+	}
+	return writeLines(s.bw, batch, s.geomIdx)
+}
+
+// Close ends the FeatureCollection, if any, and flushes the buffer.
+func (s *fileSink) Close() error {
+	if s.fc != nil {
+		if err := s.fc.end(); err != nil {
+			return err
+		}
+	}
+	return s.bw.Flush()
+}
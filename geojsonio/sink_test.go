@@ -0,0 +1,98 @@
+package geojsonio_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geojsonio"
+	"github.com/zoobst/gobi/geometry"
+)
+
+func TestSinkFile(t *testing.T) {
+	// Enough rows for the lazy scan to emit two batches.
+	type row struct {
+		ID int64 `gobi:"id"`
+	}
+	rows := make([]row, 70_000)
+	geoms := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+	defer geoms.Release()
+	for i := range rows {
+		rows[i].ID = int64(i)
+		geoms.Append(geometry.WKB(geometry.Point{X: float64(i % 360), Y: float64(i % 90)}))
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if df, err = df.WithColumn("geometry", gobi.SeriesFromArray(gobi.GeometryField("geometry", 4326), geoms.NewArray())); err != nil {
+		t.Fatal(err)
+	}
+	lf := df.Lazy().Filter(gobi.Col("id").Ge(gobi.Lit(int64(10))))
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	cases := []struct {
+		name string
+		opts *geojsonio.WriteOptions
+		// write is the WriteFile equivalent of SinkFile with opts.
+		write *geojsonio.WriteOptions
+	}{
+		{"out.geojsonl", nil, &geojsonio.WriteOptions{Format: geojsonio.FormatLineDelimited}},
+		{"out.geojson", nil, nil},
+		{"pretty.geojson", &geojsonio.WriteOptions{Indent: "  "}, &geojsonio.WriteOptions{Indent: "  "}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sunk, written := filepath.Join(dir, "sink-"+tc.name), filepath.Join(dir, "write-"+tc.name)
+			if err := geojsonio.SinkFile(context.Background(), lf, sunk, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			if err := geojsonio.WriteFile(want, written, tc.write); err != nil {
+				t.Fatal(err)
+			}
+			a, _ := os.ReadFile(sunk)
+			b, _ := os.ReadFile(written)
+			if !bytes.Equal(a, b) {
+				t.Fatalf("SinkFile output (%d bytes) differs from WriteFile (%d bytes)", len(a), len(b))
+			}
+		})
+	}
+
+	// No rows: an empty FeatureCollection, an empty line-delimited file.
+	none := df.Lazy().Filter(gobi.Col("id").Lt(gobi.Lit(int64(0))))
+	for name, body := range map[string]string{"empty.geojson": `{"type":"FeatureCollection","features":[]}`, "empty.ndjson": ""} {
+		path := filepath.Join(dir, name)
+		if err := geojsonio.SinkFile(context.Background(), none, path, nil); err != nil {
+			t.Fatal(err)
+		}
+		if raw, _ := os.ReadFile(path); string(raw) != body {
+			t.Fatalf("%s = %q, want %q", name, raw, body)
+		}
+	}
+
+	// An unknown GeomCol fails before writing; the file is removed.
+	bad := filepath.Join(dir, "bad.geojsonl")
+	if err := geojsonio.SinkFile(context.Background(), lf, bad, &geojsonio.WriteOptions{GeomCol: "nope"}); err == nil {
+		t.Fatal("GeomCol nope: expected error")
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Fatalf("file left behind: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := geojsonio.SinkFile(ctx, lf, bad, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
@@ -32,6 +32,26 @@ func GeomDistance(a, b Geometry, u Unit) (float64, error) {
 	return d / perM, nil
 }
 
+// DWithin reports whether a and b are within distance of each other
+// (inclusive), measured like GeomDistance in the given unit. A nil
+// geometry is never within any distance; a negative distance never
+// matches.
+func DWithin(a, b Geometry, distance float64, u Unit) (bool, error) {
+	// Validate the unit up front: GeomDistance short-circuits on
+	// intersecting inputs before it looks at u.
+	if _, err := metersPerUnit(u); err != nil {
+		return false, err
+	}
+	if a == nil || b == nil || distance < 0 {
+		return false, nil
+	}
+	d, err := GeomDistance(a, b, u)
+	if err != nil {
+		return false, err
+	}
+	return d <= distance, nil
+}
+
 // planarMinDistance returns the min Euclidean distance between a and b
 // in coord units. Assumes non-intersecting inputs.
 func planarMinDistance(a, b Geometry) float64 {
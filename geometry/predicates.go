@@ -72,6 +72,20 @@ func Contains(a, b Geometry) bool {
 // Within is Contains(b, a).
 func Within(a, b Geometry) bool { return Contains(b, a) }
 
+// Covers reports whether no point of b lies outside a. Matches
+// shapely's a.covers(b).
+//
+// OGC Contains additionally requires b to reach a's interior, so a
+// polygon doesn't "contain" a point on its edge but does cover it.
+// gobi's Contains has always accepted boundary points (see
+// polygonContains), which is exactly the Covers rule; Covers is
+// therefore the same test under the name callers porting shapely
+// code look for.
+func Covers(a, b Geometry) bool { return Contains(a, b) }
+
+// CoveredBy is Covers(b, a).
+func CoveredBy(a, b Geometry) bool { return Contains(b, a) }
+
 // ---------- Intersects dispatch ----------
 
 func intersects(a, b Geometry) bool {
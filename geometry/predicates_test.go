@@ -225,3 +225,44 @@ func TestIntersects_NilSafe(t *testing.T) {
 		t.Error("nil contains anything should be false")
 	}
 }
+
+func TestCovers_BoundaryPoint(t *testing.T) {
+	poly := square(0, 0, 10)
+	if !Covers(poly, pt(10, 0)) {
+		t.Error("Covers(poly, edge point) = false, want true")
+	}
+	if !CoveredBy(pt(10, 0), poly) {
+		t.Error("CoveredBy(edge point, poly) = false, want true")
+	}
+	if Covers(poly, pt(11, 0)) || CoveredBy(pt(11, 0), poly) {
+		t.Error("outside point must not be covered")
+	}
+}
+
+func TestDWithin_InclusiveAndUnits(t *testing.T) {
+	line := LineString{Points: []Point{pt(0, 0), pt(0, 10)}}
+	cases := []struct {
+		name string
+		p    Point
+		d    float64
+		u    Unit
+		want bool
+	}{
+		{"exactly at distance", pt(30, 5), 30, UnitMeters, true},
+		{"just beyond", pt(30, 5), 29.9, UnitMeters, false},
+		{"kilometers", pt(30, 5), 0.03, UnitKilometers, true},
+		{"negative distance", pt(0, 0), -1, UnitMeters, false},
+	}
+	for _, c := range cases {
+		got, err := DWithin(c.p, line, c.d, c.u)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: DWithin = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if _, err := DWithin(pt(0, 0), line, 1, Unit("furlongs")); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
@@ -3,9 +3,11 @@ package gobi
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 
 	"github.com/zoobst/gobi/geometry"
 )
@@ -31,6 +33,118 @@ type GeoParquetColumnMeta struct {
 	GeometryTypes []string       `json:"geometry_types"`
 	CRS           map[string]any `json:"crs,omitempty"`
 	Bbox          []float64      `json:"bbox,omitempty"`
+	// Covering points at columns that hold a cheap per-row
+	// approximation of the geometry. Only the GeoParquet 1.1 "bbox"
+	// covering is defined; see AddGeoParquetBboxCovering.
+	Covering *GeoParquetCovering `json:"covering,omitempty"`
+}
+
+// GeoParquetCovering is the "covering" object of a GeoParquet 1.1
+// column entry.
+type GeoParquetCovering struct {
+	Bbox GeoParquetBboxCovering `json:"bbox"`
+}
+
+// GeoParquetBboxCovering names the four leaves of a per-row bounding
+// box struct column. Each entry is a column path — the struct column
+// name followed by the field name, e.g. ["bbox", "xmin"] — as the
+// spec requires, so readers can find the leaves in a nested parquet
+// schema without guessing at a naming convention.
+type GeoParquetBboxCovering struct {
+	Xmin []string `json:"xmin"`
+	Ymin []string `json:"ymin"`
+	Xmax []string `json:"xmax"`
+	Ymax []string `json:"ymax"`
+}
+
+// BboxCoveringType is the arrow type of a bbox covering column: a
+// struct of four float64 fields. The struct (and so each leaf) is
+// null for rows whose geometry is null or empty, which keeps those
+// rows out of the leaves' min/max statistics.
+var BboxCoveringType = arrow.StructOf(
+	arrow.Field{Name: "xmin", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	arrow.Field{Name: "ymin", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	arrow.Field{Name: "xmax", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	arrow.Field{Name: "ymax", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+)
+
+// AddGeoParquetBboxCovering appends a bbox covering column for every
+// geometry column described by meta and records it in that column's
+// Covering entry. The primary column's covering is named "bbox" (the
+// name geopandas and GDAL write); any other geometry column gets
+// "<column>_bbox". A name collision with an existing column is an
+// error rather than a silent overwrite.
+//
+// The covering exists for readers: parquet keeps min/max statistics
+// per leaf column, so the four bbox leaves give every row group a
+// spatial extent that a scan can test a query window against without
+// decoding a single WKB value. The returned Frame shares f's columns;
+// meta is updated in place.
+func AddGeoParquetBboxCovering(f *Frame, meta *GeoParquetMetadata) (*Frame, error) {
+	if meta == nil {
+		return f, nil
+	}
+	out := f
+	for _, s := range f.series {
+		colMeta, ok := meta.Columns[s.name]
+		if !ok {
+			continue
+		}
+		name := s.name + "_bbox"
+		if s.name == meta.PrimaryColumn {
+			name = "bbox"
+		}
+		if _, err := out.Column(name); err == nil {
+			return nil, fmt.Errorf("gobi: bbox covering column %q already exists", name)
+		}
+		bbox, err := bboxCoveringSeries(s)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", s.name, err)
+		}
+		if out, err = out.WithColumn(name, bbox); err != nil {
+			return nil, err
+		}
+		colMeta.Covering = &GeoParquetCovering{Bbox: GeoParquetBboxCovering{
+			Xmin: []string{name, "xmin"},
+			Ymin: []string{name, "ymin"},
+			Xmax: []string{name, "xmax"},
+			Ymax: []string{name, "ymax"},
+		}}
+		meta.Columns[s.name] = colMeta
+	}
+	return out, nil
+}
+
+// bboxCoveringSeries decodes s and builds its BboxCoveringType column.
+func bboxCoveringSeries(s Series) (Series, error) {
+	geoms, err := decodeGeometryColumn(s)
+	if err != nil {
+		return Series{}, err
+	}
+	b := array.NewStructBuilder(memory.DefaultAllocator, BboxCoveringType)
+	defer b.Release()
+	fields := [4]*array.Float64Builder{}
+	for i := range fields {
+		fields[i] = b.FieldBuilder(i).(*array.Float64Builder)
+	}
+	for _, g := range geoms {
+		if g == nil {
+			b.AppendNull()
+			continue
+		}
+		bounds := g.Bounds()
+		if bounds.Empty() {
+			b.AppendNull()
+			continue
+		}
+		b.Append(true)
+		fields[0].Append(bounds.MinX)
+		fields[1].Append(bounds.MinY)
+		fields[2].Append(bounds.MaxX)
+		fields[3].Append(bounds.MaxY)
+	}
+	field := arrow.Field{Name: "bbox", Type: BboxCoveringType, Nullable: true}
+	return SeriesFromArray(field, b.NewArray()), nil
 }
 
 // BuildGeoParquetMetadata scans f and produces a GeoParquet metadata blob
@@ -61,6 +175,50 @@ func BuildGeoParquetMetadata(f *Frame) (*GeoParquetMetadata, error) {
 	return meta, nil
 }
 
+// Merge folds other — the metadata of a later batch of the same
+// schema — into m: each column's geometry types become the sorted
+// union and its bbox the union extent. Encoding, CRS and Covering are
+// m's. Columns only other describes are copied over. Streaming
+// writers build metadata per batch and Merge it, so the footer's
+// "geo" blob describes the whole file without a second pass.
+func (m *GeoParquetMetadata) Merge(other *GeoParquetMetadata) {
+	if other == nil {
+		return
+	}
+	if m.Columns == nil {
+		m.Columns = map[string]GeoParquetColumnMeta{}
+	}
+	if m.PrimaryColumn == "" {
+		m.PrimaryColumn = other.PrimaryColumn
+	}
+	for name, oc := range other.Columns {
+		col, ok := m.Columns[name]
+		if !ok {
+			m.Columns[name] = oc
+			continue
+		}
+		types := map[string]struct{}{}
+		for _, t := range col.GeometryTypes {
+			types[t] = struct{}{}
+		}
+		for _, t := range oc.GeometryTypes {
+			types[t] = struct{}{}
+		}
+		col.GeometryTypes = sortedKeys(types)
+		switch {
+		case len(oc.Bbox) != 4:
+		case len(col.Bbox) != 4:
+			col.Bbox = slices.Clone(oc.Bbox)
+		default:
+			col.Bbox = []float64{
+				min(col.Bbox[0], oc.Bbox[0]), min(col.Bbox[1], oc.Bbox[1]),
+				max(col.Bbox[2], oc.Bbox[2]), max(col.Bbox[3], oc.Bbox[3]),
+			}
+		}
+		m.Columns[name] = col
+	}
+}
+
 func describeGeometryColumn(s Series) (GeoParquetColumnMeta, error) {
 	col := GeoParquetColumnMeta{Encoding: "WKB"}
 	epsg := geometryCRSFromField(s.field)
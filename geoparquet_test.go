@@ -2,6 +2,7 @@ package gobi
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -230,3 +231,61 @@ func TestGeoParquetSchemaWithMetadata_RoundTripJSON(t *testing.T) {
 		t.Errorf("version %v", m["version"])
 	}
 }
+
+func TestAddGeoParquetBboxCovering(t *testing.T) {
+	f := geoFrame(t, 4326)
+	meta, err := BuildGeoParquetMetadata(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := AddGeoParquetBboxCovering(f, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := meta.Columns["geometry"].Covering
+	if cov == nil || !slices.Equal(cov.Bbox.Xmin, []string{"bbox", "xmin"}) || !slices.Equal(cov.Bbox.Ymax, []string{"bbox", "ymax"}) {
+		t.Fatalf("covering = %+v", cov)
+	}
+	bbox, err := out.Column("bbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !arrow.TypeEqual(bbox.DataType(), BboxCoveringType) {
+		t.Fatalf("bbox type = %s", bbox.DataType())
+	}
+	st := bbox.col.Data().Chunks()[0].(*array.Struct)
+	// Points: each box collapses to the point itself.
+	if x := st.Field(2).(*array.Float64).Value(1); x != 5 {
+		t.Fatalf("bbox[1].xmax = %v, want 5", x)
+	}
+	if _, err := AddGeoParquetBboxCovering(out, meta); err == nil {
+		t.Fatal("expected error for an existing bbox column")
+	}
+}
+
+func TestGeoParquetMetadata_Merge(t *testing.T) {
+	meta, err := BuildGeoParquetMetadata(geoFrame(t, 4326))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta.Merge(&GeoParquetMetadata{Columns: map[string]GeoParquetColumnMeta{
+		"geometry": {Encoding: "WKB", GeometryTypes: []string{"Polygon", "Point"}, Bbox: []float64{2, -4, 9, 1}},
+		"other":    {Encoding: "WKB", GeometryTypes: []string{"LineString"}},
+	}})
+	col := meta.Columns["geometry"]
+	if !slices.Equal(col.GeometryTypes, []string{"Point", "Polygon"}) {
+		t.Errorf("geometry_types = %v", col.GeometryTypes)
+	}
+	if !slices.Equal(col.Bbox, []float64{-1, -4, 9, 3}) {
+		t.Errorf("bbox = %v", col.Bbox)
+	}
+	if _, ok := meta.Columns["other"]; !ok || meta.PrimaryColumn != "geometry" {
+		t.Errorf("columns = %v, primary = %q", meta.Columns, meta.PrimaryColumn)
+	}
+
+	// An empty batch's metadata (no bbox) leaves the extent alone.
+	meta.Merge(&GeoParquetMetadata{Columns: map[string]GeoParquetColumnMeta{"geometry": {Encoding: "WKB"}}})
+	if got := meta.Columns["geometry"].Bbox; !slices.Equal(got, []float64{-1, -4, 9, 3}) {
+		t.Errorf("bbox after empty merge = %v", got)
+	}
+}
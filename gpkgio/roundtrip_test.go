@@ -1,6 +1,7 @@
 package gpkgio_test
 
 import (
+	"context"
 	"database/sql"
 	"path/filepath"
 	"strings"
@@ -162,6 +163,35 @@ func TestRoundTrip_ColumnsAndGeometry(t *testing.T) {
 // bounds (extent of the 3 test points), gpkg_geometry_columns names
 // the geom column, and the RTree shadow table + gpkg_extensions row
 // were created.
+// TestRoundTrip_Categorical writes a categorical column through
+// WriteFile and SinkFile; both store each row's category as TEXT.
+func TestRoundTrip_Categorical(t *testing.T) {
+	df, err := buildTestFrame(t).WithColumnExpr("name", gobi.Col("name").Cast(gobi.Categorical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "categorical.gpkg")
+	if err := gpkgio.WriteFile(df, path, &gpkgio.WriteOptions{Layer: "written"}); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := gpkgio.SinkFile(context.Background(), df.Lazy(), path, &gpkgio.WriteOptions{Layer: "sunk"}); err != nil {
+		t.Fatalf("SinkFile: %v", err)
+	}
+	for _, layer := range []string{"written", "sunk"} {
+		out, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{Layer: layer})
+		if err != nil {
+			t.Fatalf("ReadFile %s: %v", layer, err)
+		}
+		nameS, err := out.Column("name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vals, _ := nameS.Strings(); strings.Join(vals, ",") != "a,b,c" {
+			t.Errorf("%s: name = %q, want a,b,c", layer, vals)
+		}
+	}
+}
+
 func TestRoundTrip_MetadataInPlace(t *testing.T) {
 	df := buildTestFrame(t)
 	path := filepath.Join(t.TempDir(), "meta.gpkg")
@@ -402,6 +432,120 @@ func TestScanFile_PredicatePushdown_CompoundAND(t *testing.T) {
 	}
 }
 
+// TestScanFile_StringPredicatesMatchExecutor runs each translated
+// Str() predicate (and its negation) straight through SQLite via
+// ReadOptions.Where and compares the surviving rows with the
+// executor's FilterExpr. The optimizer keeps the Filter above the
+// scan, but that only covers supersets; under a NOT the SQL has to
+// agree row for row.
+func TestScanFile_StringPredicatesMatchExecutor(t *testing.T) {
+	type row struct {
+		Name string `gobi:"name"`
+		Geom string `gobi:"geom" geom:"true"`
+	}
+	names := []string{"Main St", "main st", "Élysée", "ab", "", "a-b-a"}
+	rows := make([]row, len(names))
+	for i, n := range names {
+		rows[i] = row{Name: n, Geom: "POINT (0 0)"}
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "scan_str.gpkg")
+	if err := gpkgio.WriteFile(df, path, &gpkgio.WriteOptions{Layer: "features"}); err != nil {
+		t.Fatal(err)
+	}
+	name := gobi.Col("name").Str()
+	preds := []gobi.Expr{
+		name.Contains("a"),
+		name.StartsWith("Él"),
+		name.StartsWith(""),
+		name.EndsWith("st"),
+		name.EndsWith("xx-a-b-a"),
+		name.Len().Gt(gobi.Lit(int64(5))),
+		name.Slice(1, 3).Eq(gobi.Lit("ly")),
+		name.Replace("-", "").Eq(gobi.Lit("aba")),
+		name.Concat(gobi.Lit("!")).Eq(gobi.Lit("ab!")),
+	}
+	for _, p := range preds {
+		for _, pred := range []gobi.Expr{p, p.Not()} {
+			where, args, ok := gobi.ExprToSQL(pred)
+			if !ok {
+				t.Fatalf("%s: ExprToSQL declined", pred)
+			}
+			got, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{
+				Layer: "features", Where: where, WhereArgs: args,
+			})
+			if err != nil {
+				t.Fatalf("%s: %v", pred, err)
+			}
+			want, err := df.FilterExpr(pred)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.NumRows() != want.NumRows() {
+				t.Errorf("%s: SQLite kept %d rows, executor %d (where %s)",
+					pred, got.NumRows(), want.NumRows(), where)
+			}
+		}
+	}
+}
+
+// TestScanFile_MembershipPredicatesMatchExecutor does the same for
+// IsIn / NotIn / Between over a column with a null, where SQL's
+// three-valued logic has to line up with the executor's.
+func TestScanFile_MembershipPredicatesMatchExecutor(t *testing.T) {
+	type row struct {
+		Code *int64 `gobi:"code"`
+		Name string `gobi:"name"`
+		Geom string `gobi:"geom" geom:"true"`
+	}
+	i := func(v int64) *int64 { return &v }
+	df, err := gobi.FromStructs([]row{
+		{i(1), "a", "POINT (0 0)"},
+		{i(5), "b", "POINT (0 0)"},
+		{nil, "c", "POINT (0 0)"},
+		{i(9), "d", "POINT (0 0)"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "scan_in.gpkg")
+	if err := gpkgio.WriteFile(df, path, &gpkgio.WriteOptions{Layer: "features"}); err != nil {
+		t.Fatal(err)
+	}
+	code := gobi.Col("code")
+	preds := []gobi.Expr{
+		code.IsIn([]int64{1, 9, 42}),
+		code.NotIn([]int64{5}),
+		code.Between(gobi.Lit(int64(2)), gobi.Lit(int64(9))),
+		gobi.Col("name").IsIn([]string{"b", "c"}),
+	}
+	for _, p := range preds {
+		for _, pred := range []gobi.Expr{p, p.Not()} {
+			where, args, ok := gobi.ExprToSQL(pred)
+			if !ok {
+				t.Fatalf("%s: ExprToSQL declined", pred)
+			}
+			got, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{
+				Layer: "features", Where: where, WhereArgs: args,
+			})
+			if err != nil {
+				t.Fatalf("%s: %v", pred, err)
+			}
+			want, err := df.FilterExpr(pred)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.NumRows() != want.NumRows() {
+				t.Errorf("%s: SQLite kept %d rows, executor %d (where %s)",
+					pred, got.NumRows(), want.NumRows(), where)
+			}
+		}
+	}
+}
+
 // TestRoundTrip_MultipleLayers writes two layers into the same file
 // and verifies both are readable independently.
 func TestRoundTrip_MultipleLayers(t *testing.T) {
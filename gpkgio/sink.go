@@ -0,0 +1,141 @@
+package gpkgio
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geometry"
+)
+
+// SinkFile runs lf and streams its output into the GeoPackage layer
+// opts.Layer at path, one batch at a time, instead of Collect-ing it
+// first. The file is created if it doesn't exist; other layers stay
+// untouched, as with WriteFile.
+//
+// The feature table, its gpkg_contents / gpkg_geometry_columns rows
+// and the RTree are created from the plan's schema before the first
+// batch arrives. Each batch is then inserted in transactions of
+// opts.BatchSize rows, with the RTree maintained row by row. The
+// layer extent is accumulated across batches and written to
+// gpkg_contents at the end, and the registered geometry type is
+// narrowed from GEOMETRY to the one type seen when every batch
+// agrees on it.
+//
+// On any error — including cancellation of ctx — the partially
+// written layer is dropped. With opts.Replace, the layer it replaced
+// is already gone by then.
+func SinkFile(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions) error {
+	return SinkFileWith(ctx, lf, path, opts, nil)
+}
+
+// SinkFileWith is SinkFile with collect options for the plan — a
+// memory budget past which Sort, Aggregate and Join spill to disk
+// (see gobi.CollectOptions). A nil collect is SinkFile.
+func SinkFileWith(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions, collect *gobi.CollectOptions) error {
+	db, err := openWriteDB(path)
+	if err != nil {
+		return err
+	}
+	s := &layerSink{db: db, opts: opts}
+	err = lf.SinkWith(ctx, s, collect)
+	if err != nil && s.created {
+		if derr := dropLayer(db, s.layer.opts.Layer); derr != nil {
+			err = errors.Join(err, fmt.Errorf("gpkg: drop partial layer: %w", derr))
+		}
+	}
+	if cerr := db.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// layerSink is the gobi.BatchSink behind SinkFile.
+type layerSink struct {
+	db      *sql.DB
+	opts    *WriteOptions
+	layer   layerTarget
+	created bool
+	extent  geometry.Bounds
+	scratch []byte
+	// geomType is the GeoPackage geometry type name of the first
+	// non-null geometry in each batch so far: "" before any, GEOMETRY
+	// once two batches disagree.
+	geomType string
+}
+
+func (s *layerSink) Open(schema *arrow.Schema) error {
+	empty, err := gobi.EmptyFrame(schema)
+	if err != nil {
+		return err
+	}
+	defer empty.Release()
+	if s.layer, err = createLayer(s.db, empty, s.opts); err != nil {
+		return err
+	}
+	s.created = true
+	s.extent = geometry.EmptyBounds()
+	return nil
+}
+
+func (s *layerSink) Write(batch *gobi.Frame) error {
+	cols := make([]colWriter, batch.NumCols())
+	for i, name := range batch.ColumnNames() {
+		col, err := batch.ColumnAt(i)
+		if err != nil {
+			return err
+		}
+		if cols[i], err = columnWriter(col, name); err != nil {
+			return err
+		}
+	}
+	if s.layer.geomOK {
+		s.noteGeomType(batch)
+	}
+	return insertRows(s.db, batch, s.layer.opts, s.layer.geomIdx, cols, &s.extent)
+}
+
+// noteGeomType folds batch's first non-null geometry into geomType.
+func (s *layerSink) noteGeomType(batch *gobi.Frame) {
+	col, err := batch.ColumnAt(s.layer.geomIdx)
+	if err != nil {
+		return
+	}
+	wkb, ok := firstNonNullBinary(col)
+	if !ok {
+		return
+	}
+	name := "GEOMETRY"
+	if g, err := geometry.ParseWKB(wkb); err == nil {
+		name = geomTypeName(g)
+	}
+	switch s.geomType {
+	case "":
+		s.geomType = name
+	case name:
+	default:
+		s.geomType = "GEOMETRY"
+	}
+}
+
+// Close writes the accumulated extent and geometry type. Rows are
+// already committed batch by batch, so there is nothing to flush.
+func (s *layerSink) Close() error {
+	if err := updateContentsExtent(s.db, s.layer.opts.Layer, s.extent); err != nil {
+		return err
+	}
+	if !s.layer.geomOK || s.geomType == "" || s.geomType == "GEOMETRY" {
+		return nil
+	}
+	if _, err := s.db.Exec(`
+		UPDATE gpkg_geometry_columns SET geometry_type_name = ?
+		WHERE table_name = ? AND column_name = ?`,
+		s.geomType, s.layer.opts.Layer, s.layer.opts.GeomCol); err != nil {
+		return fmt.Errorf("gpkg: update geometry type: %w", err)
+	}
+	return nil
+}
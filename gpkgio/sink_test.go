@@ -0,0 +1,102 @@
+package gpkgio_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geometry"
+	"github.com/zoobst/gobi/gpkgio"
+)
+
+func TestSinkFile(t *testing.T) {
+	// Enough rows for the lazy scan to emit two batches.
+	type row struct {
+		ID int64 `gobi:"id"`
+	}
+	rows := make([]row, 66_000)
+	geoms := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+	defer geoms.Release()
+	for i := range rows {
+		rows[i].ID = int64(i)
+		geoms.Append(geometry.WKB(geometry.Point{X: float64(i % 100), Y: float64(i / 1000)}))
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if df, err = df.WithColumn("geom", gobi.SeriesFromArray(gobi.GeometryField("geom", 3857), geoms.NewArray())); err != nil {
+		t.Fatal(err)
+	}
+	lf := df.Lazy().Filter(gobi.Col("id").Ge(gobi.Lit(int64(1000))))
+
+	path := filepath.Join(t.TempDir(), "sink.gpkg")
+	if err := gpkgio.WriteFile(df.Head(3), path, &gpkgio.WriteOptions{Layer: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gpkgio.SinkFile(context.Background(), lf, path, &gpkgio.WriteOptions{Layer: "points"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{Layer: "points"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 65_000 {
+		t.Fatalf("rows = %d, want 65000", out.NumRows())
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var minX, minY, maxX, maxY float64
+	var srsID int32
+	if err := db.QueryRow(`SELECT min_x, min_y, max_x, max_y, srs_id FROM gpkg_contents WHERE table_name = ?`,
+		"points").Scan(&minX, &minY, &maxX, &maxY, &srsID); err != nil {
+		t.Fatal(err)
+	}
+	// The extent spans both batches: y = 1 comes from the first, y = 65
+	// from the second.
+	if minX != 0 || minY != 1 || maxX != 99 || maxY != 65 || srsID != 3857 {
+		t.Errorf("contents = (%v,%v,%v,%v) srs %d, want (0,1,99,65) srs 3857", minX, minY, maxX, maxY, srsID)
+	}
+	var geomType string
+	var rtreeCount int
+	if err := db.QueryRow(`SELECT geometry_type_name FROM gpkg_geometry_columns WHERE table_name = ?`, "points").Scan(&geomType); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM rtree_points_geom`).Scan(&rtreeCount); err != nil {
+		t.Fatal(err)
+	}
+	if geomType != "POINT" || rtreeCount != 65_000 {
+		t.Errorf("geometry type %q, %d rtree rows; want POINT, 65000", geomType, rtreeCount)
+	}
+
+	// A failed run drops its own layer and leaves the others alone.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gpkgio.SinkFile(ctx, lf, path, &gpkgio.WriteOptions{Layer: "cancelled"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if _, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{Layer: "cancelled"}); err == nil {
+		t.Error("cancelled layer still present")
+	}
+	if other, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{Layer: "other"}); err != nil || other.NumRows() != 3 {
+		t.Errorf("other layer after failed sink: %v", err)
+	}
+	// An existing layer without Replace fails in Open and is kept.
+	if err := gpkgio.SinkFile(context.Background(), lf, path, &gpkgio.WriteOptions{Layer: "other"}); err == nil {
+		t.Error("sink into an existing layer without Replace: expected error")
+	}
+	if _, err := gpkgio.ReadFile(path, &gpkgio.ReadOptions{Layer: "other"}); err != nil {
+		t.Errorf("other layer dropped by a sink that never created it: %v", err)
+	}
+}
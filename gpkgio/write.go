@@ -291,12 +291,43 @@ func openWriteDB(path string) (*sql.DB, error) {
 // via openWriteDB — the PRAGMAs + metadata scaffolding this function
 // depends on live there.
 func writeLayerToDB(db *sql.DB, df *gobi.Frame, opts *WriteOptions) error {
+	layer, err := createLayer(db, df, opts)
+	if err != nil {
+		return err
+	}
+	extent := geometry.EmptyBounds()
+	if err := insertRows(db, df, layer.opts, layer.geomIdx, layer.cols, &extent); err != nil {
+		return err
+	}
+	return updateContentsExtent(db, layer.opts.Layer, extent)
+}
+
+// layerTarget is a feature table createLayer has laid down and
+// registered, ready for insertRows.
+type layerTarget struct {
+	// opts is the resolved options: defaults applied, GeomCol and
+	// SRID filled in from the frame's geometry column.
+	opts    WriteOptions
+	geomIdx int
+	// geomOK reports whether the layer is registered in
+	// gpkg_geometry_columns.
+	geomOK bool
+	// cols reads df's columns, in table order.
+	cols []colWriter
+}
+
+// createLayer validates opts, creates the feature table for df's
+// schema, and registers it in gpkg_contents and, with a geometry
+// column, gpkg_geometry_columns and the RTree. No rows are written;
+// df only has to carry the schema (and, for the registered geometry
+// type, a first non-null geometry).
+func createLayer(db *sql.DB, df *gobi.Frame, opts *WriteOptions) (layerTarget, error) {
 	o := defaultWriteOptions(opts)
 	if o.Layer == "" {
-		return fmt.Errorf("gpkg: Layer is required")
+		return layerTarget{}, fmt.Errorf("gpkg: Layer is required")
 	}
 	if !validSQLIdent(o.Layer) {
-		return fmt.Errorf("gpkg: Layer %q is not a valid SQLite identifier", o.Layer)
+		return layerTarget{}, fmt.Errorf("gpkg: Layer %q is not a valid SQLite identifier", o.Layer)
 	}
 
 	// Detect the geometry column if the caller didn't name one.
@@ -304,14 +335,14 @@ func writeLayerToDB(db *sql.DB, df *gobi.Frame, opts *WriteOptions) error {
 	if o.GeomCol != "" {
 		i, err := columnIndex(df, o.GeomCol)
 		if err != nil {
-			return err
+			return layerTarget{}, err
 		}
 		geomIdx = i
 	} else {
 		for i := 0; i < df.NumCols(); i++ {
 			s, err := df.ColumnAt(i)
 			if err != nil {
-				return err
+				return layerTarget{}, err
 			}
 			if s.IsGeometry() {
 				geomIdx = i
@@ -341,32 +372,32 @@ func writeLayerToDB(db *sql.DB, df *gobi.Frame, opts *WriteOptions) error {
 	}
 
 	if err := registerSRS(db, o.SRID); err != nil {
-		return fmt.Errorf("gpkg: register srs: %w", err)
+		return layerTarget{}, fmt.Errorf("gpkg: register srs: %w", err)
 	}
 	if o.Replace {
 		if err := dropLayer(db, o.Layer); err != nil {
-			return fmt.Errorf("gpkg: drop existing layer: %w", err)
+			return layerTarget{}, fmt.Errorf("gpkg: drop existing layer: %w", err)
 		}
 	}
 	if err := layerExists(db, o.Layer); err != nil {
-		return err
+		return layerTarget{}, err
 	}
 
 	// Build feature table DDL from the frame schema. The geometry
 	// column, if any, gets a BLOB affinity so the raw GPB blob fits.
 	tableDDL, colDDL, err := buildFeatureTableDDL(df, o.Layer, geomIdx)
 	if err != nil {
-		return err
+		return layerTarget{}, err
 	}
 	if _, err := db.Exec(tableDDL); err != nil {
-		return fmt.Errorf("gpkg: create layer table: %w\nSQL: %s", err, tableDDL)
+		return layerTarget{}, fmt.Errorf("gpkg: create layer table: %w\nSQL: %s", err, tableDDL)
 	}
 
 	// Register in gpkg_contents + gpkg_geometry_columns (feature
 	// tables only). gpkg_contents.last_change is set to
 	// datetime('now') so QGIS shows a fresh timestamp on the layer.
 	if err := registerLayerContents(db, o.Layer, o.SRID); err != nil {
-		return fmt.Errorf("gpkg: register layer: %w", err)
+		return layerTarget{}, fmt.Errorf("gpkg: register layer: %w", err)
 	}
 	if geomOK {
 		geomTypeName, hasZ, hasM := geomTypeForColumn(df, geomIdx)
@@ -375,27 +406,26 @@ func writeLayerToDB(db *sql.DB, df *gobi.Frame, opts *WriteOptions) error {
 			  (table_name, column_name, geometry_type_name, srs_id, z, m)
 			VALUES (?, ?, ?, ?, ?, ?)`,
 			o.Layer, o.GeomCol, geomTypeName, o.SRID, boolToInt(hasZ), boolToInt(hasM)); err != nil {
-			return fmt.Errorf("gpkg: register geometry column: %w", err)
+			return layerTarget{}, fmt.Errorf("gpkg: register geometry column: %w", err)
 		}
 	}
 
 	// Optional RTree — see rtree.go. Skipped when there's no geometry.
 	if !o.SkipRTree && geomOK {
 		if err := createRTree(db, o.Layer, o.GeomCol); err != nil {
-			return fmt.Errorf("gpkg: create rtree: %w", err)
+			return layerTarget{}, fmt.Errorf("gpkg: create rtree: %w", err)
 		}
 	}
 
-	// Insert rows in batches.
-	return insertRows(db, df, o, geomIdx, colDDL)
+	return layerTarget{opts: o, geomIdx: geomIdx, geomOK: geomOK, cols: colDDL}, nil
 }
 
 // insertRows runs the actual batched INSERT loop. Wraps every
 // opts.BatchSize rows in a single transaction for throughput; uses
-// one prepared statement across the whole write. Also accumulates
-// the layer's extent (min/max x/y) and, at the end, writes it into
-// gpkg_contents.
-func insertRows(db *sql.DB, df *gobi.Frame, opts WriteOptions, geomIdx int, cols []colWriter) error {
+// one prepared statement per transaction. Also accumulates the
+// layer's extent (min/max x/y) into *extent; the caller writes it
+// into gpkg_contents with updateContentsExtent once every row is in.
+func insertRows(db *sql.DB, df *gobi.Frame, opts WriteOptions, geomIdx int, cols []colWriter, extent *geometry.Bounds) error {
 	if len(cols) == 0 || df.NumRows() == 0 {
 		return nil
 	}
@@ -423,26 +453,29 @@ func insertRows(db *sql.DB, df *gobi.Frame, opts WriteOptions, geomIdx int, cols
 			quoteIdent(rtreeTableName(opts.Layer, opts.GeomCol)))
 	}
 
-	extent := geometry.EmptyBounds()
-
 	nRows := df.NumRows()
 	batchSize := opts.BatchSize
 	scratch := make([]byte, 0, 64) // reused for GPB encoding
 
 	for start := 0; start < nRows; start += batchSize {
 		end := min(start+batchSize, nRows)
-		if err := insertBatch(db, insertSQL, rtreeSQL, cols, geomIdx, opts.SRID, start, end, &extent, &scratch); err != nil {
+		if err := insertBatch(db, insertSQL, rtreeSQL, cols, geomIdx, opts.SRID, start, end, extent, &scratch); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// Final bounds → gpkg_contents.
+// updateContentsExtent writes the layer's final bounds into
+// gpkg_contents. An empty extent (no geometry rows) leaves the
+// columns NULL.
+func updateContentsExtent(db *sql.DB, layer string, extent geometry.Bounds) error {
 	if !extent.Empty() {
 		if _, err := db.Exec(`
 			UPDATE gpkg_contents
 			SET min_x = ?, min_y = ?, max_x = ?, max_y = ?, last_change = datetime('now')
 			WHERE table_name = ?`,
-			extent.MinX, extent.MinY, extent.MaxX, extent.MaxY, opts.Layer); err != nil {
+			extent.MinX, extent.MinY, extent.MaxX, extent.MaxY, layer); err != nil {
 			return fmt.Errorf("gpkg: update contents bounds: %w", err)
 		}
 	}
@@ -703,6 +736,19 @@ func singleChunkColumnWriter(name string, chunk arrow.Array) (colWriter, bool) {
 			}
 			return a.Value(row), nil
 		}}, true
+	case *array.Dictionary:
+		// Categorical columns store each row's category as TEXT;
+		// other dictionaries have no fast path.
+		dict, ok := a.Dictionary().(*array.String)
+		if !ok {
+			return colWriter{}, false
+		}
+		return colWriter{name: name, read: func(row int) (any, error) {
+			if a.IsNull(row) {
+				return nil, nil
+			}
+			return dict.Value(a.GetValueIndex(row)), nil
+		}}, true
 	case *array.Timestamp:
 		return colWriter{name: name, read: func(row int) (any, error) {
 			if a.IsNull(row) {
@@ -187,6 +187,38 @@ type IncrementalAggregator interface {
 	Finalize() any
 }
 
+// SizedAggregator is optionally implemented by an IncrementalAggregator
+// whose per-group state grows with the values it sees — a buffer, a
+// set, a sketch. SizeBytes estimates that state's current size. The
+// memory-budgeted aggregate (CollectOptions.MemoryLimit) charges it
+// against the budget, so a group table of large sketches spills as
+// soon as a table of many small groups would. Aggregators with a few
+// fixed fields need not implement it; the executor already counts a
+// fixed cost per accumulator.
+type SizedAggregator interface {
+	SizeBytes() int64
+}
+
+// fieldAggregator is implemented by aggregators whose output column
+// needs more than a bare arrow type. The geometry aggregators use it
+// to emit a GeometryField carrying the input column's CRS, so a
+// dissolved column is still a geometry column downstream. Every
+// schema site (eager Agg, the aligned fast path, and the logical
+// plan the streaming executor builds from) routes through
+// customAggField.
+type fieldAggregator interface {
+	outputField(name string, input arrow.Field) arrow.Field
+}
+
+// customAggField is the output field for an aggregation with a custom
+// Fn: nullable, typed by Fn.Type(), unless Fn is a fieldAggregator.
+func customAggField(a Aggregation, input arrow.Field) arrow.Field {
+	if fa, ok := a.Fn.(fieldAggregator); ok {
+		return fa.outputField(aggName(a), input)
+	}
+	return arrow.Field{Name: aggName(a), Type: a.Fn.Type(), Nullable: true}
+}
+
 // GroupBy partitions a Frame by the values in one or more key columns. The
 // keys must be of a hashable Arrow type (String, Int64, Int32, Bool, Float64).
 type GroupBy struct {
@@ -274,7 +306,8 @@ func (g *GroupBy) Agg(aggs ...Aggregation) (*Frame, error) {
 	aggFields := make([]arrow.Field, len(aggs))
 	for i, a := range aggs {
 		if a.Fn != nil {
-			if _, err := g.frame.Column(a.Column); err != nil {
+			col, err := g.frame.Column(a.Column)
+			if err != nil {
 				return nil, err
 			}
 			b, err := builderForType(pool, a.Fn.Type())
@@ -283,9 +316,7 @@ func (g *GroupBy) Agg(aggs ...Aggregation) (*Frame, error) {
 					i, aggName(a), err)
 			}
 			aggBuilders[i] = b
-			aggFields[i] = arrow.Field{
-				Name: aggName(a), Type: a.Fn.Type(), Nullable: true,
-			}
+			aggFields[i] = customAggField(a, col.field)
 			continue
 		}
 		if a.Kind == AggCount || a.Kind == AggNUnique {
@@ -438,6 +469,13 @@ func builderForType(pool memory.Allocator, t arrow.DataType) (array.Builder, err
 		// nested-struct aggregator outputs.
 		lt := t.(*arrow.ListType)
 		return array.NewListBuilder(pool, lt.Elem()), nil
+	case arrow.DICTIONARY:
+		// Categorical only: a dictionary builder over String values
+		// dedups appended strings into codes as it goes.
+		if isCategoricalType(t) {
+			return array.NewDictionaryBuilder(pool, t.(*arrow.DictionaryType)), nil
+		}
+		return nil, fmt.Errorf("unsupported Aggregator output type %s", t)
 	case arrow.STRUCT:
 		// StructBuilder needs the concrete *arrow.StructType so it
 		// can stand up per-field builders internally. Users drive
@@ -513,6 +551,12 @@ func appendCustomValue(b array.Builder, v any) error {
 			return fmt.Errorf("value %T does not match declared LargeString", v)
 		}
 		tb.Append(x)
+	case *array.BinaryDictionaryBuilder:
+		x, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("value %T does not match declared categorical", v)
+		}
+		return tb.AppendString(x)
 	case *array.BinaryBuilder:
 		x, ok := v.([]byte)
 		if !ok {
@@ -787,6 +831,15 @@ func keyOfAppend(dst []byte, s Series, row int) ([]byte, error) {
 				// column with equal contents produce equal keys.
 				dst = append(dst, 0x01)
 				return append(dst, a.Value(local)...), nil
+			case *array.Dictionary:
+				// Categorical keys encode their value, like String,
+				// so keys agree across chunks whose dictionaries
+				// number the same category differently.
+				if c, ok := asCatChunk(a); ok {
+					dst = append(dst, 0x01)
+					return append(dst, c.value(local)...), nil
+				}
+				return nil, fmt.Errorf("gobi: key type %s not hashable", a.DataType())
 			case *array.Int64:
 				dst = append(dst, 0x02)
 				return appendI64BE(dst, a.Value(local)), nil
@@ -835,6 +888,8 @@ func isHashable(t arrow.DataType) bool {
 	case arrow.STRING, arrow.LARGE_STRING, arrow.INT64, arrow.INT32, arrow.BOOL,
 		arrow.FLOAT64, arrow.UINT64, arrow.UINT32, arrow.TIMESTAMP:
 		return true
+	case arrow.DICTIONARY:
+		return isCategoricalType(t)
 	default:
 		return false
 	}
@@ -862,6 +917,12 @@ func makeKeyBuilders(pool memory.Allocator, keys []Series) ([]array.Builder, err
 			out[i] = array.NewUint32Builder(pool)
 		case arrow.TIMESTAMP:
 			out[i] = array.NewTimestampBuilder(pool, dt.(*arrow.TimestampType))
+		case arrow.DICTIONARY:
+			b, err := builderForType(pool, dt)
+			if err != nil {
+				return nil, fmt.Errorf("gobi: unsupported key type %s", dt)
+			}
+			out[i] = b
 		default:
 			return nil, fmt.Errorf("gobi: unsupported key type %s", dt)
 		}
@@ -178,7 +178,8 @@ func (g *GroupBy) buildAggBuilders(aggs []Aggregation, pool memory.Allocator) ([
 	aggFields := make([]arrow.Field, len(aggs))
 	for i, a := range aggs {
 		if a.Fn != nil {
-			if _, err := g.frame.Column(a.Column); err != nil {
+			col, err := g.frame.Column(a.Column)
+			if err != nil {
 				return nil, nil, err
 			}
 			b, err := builderForType(pool, a.Fn.Type())
@@ -187,9 +188,7 @@ func (g *GroupBy) buildAggBuilders(aggs []Aggregation, pool memory.Allocator) ([
 					i, aggName(a), err)
 			}
 			aggBuilders[i] = b
-			aggFields[i] = arrow.Field{
-				Name: aggName(a), Type: a.Fn.Type(), Nullable: true,
-			}
+			aggFields[i] = customAggField(a, col.field)
 			continue
 		}
 		if a.Kind == AggCount || a.Kind == AggNUnique {
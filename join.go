@@ -1,6 +1,7 @@
 package gobi
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -52,44 +53,89 @@ const (
 // JoinAnti a null-keyed left row still appears in the output; in
 // JoinRight and JoinFull a null-keyed right row still appears; in
 // JoinSemi and JoinInner it is filtered out.
+//
+// Join is JoinOn with a single key on each side; see JoinOn for
+// composite keys.
 func (f *Frame) Join(right *Frame, leftKey, rightKey string, kind JoinType) (*Frame, error) {
-	lKey, err := f.Column(leftKey)
-	if err != nil {
-		return nil, err
-	}
-	rKey, err := right.Column(rightKey)
+	return f.JoinOn(right, []string{leftKey}, []string{rightKey}, kind)
+}
+
+// JoinOn is the composite-key form of Join: a left row matches a
+// right row when leftOn[i] equals rightOn[i] for every i. Both slices
+// must be non-empty and the same length, and each pair must share an
+// arrow type — no implicit Int32→Int64 widening, same as Join.
+//
+//	// (tenant_id, day) composite key, no synthetic string concat needed
+//	out, err := events.JoinOn(daily,
+//	    []string{"tenant_id", "day"}, []string{"tenant_id", "day"}, gobi.JoinLeft)
+//
+// A row whose key has a null in ANY of its columns never matches —
+// SQL's "NULL = anything is unknown" applied per column. Every other
+// rule carries over from Join unchanged: all right key columns are
+// dropped from the output, and each left key column is coalesced
+// against its right counterpart so Right/Full joins keep the key
+// values of unmatched right rows.
+func (f *Frame) JoinOn(right *Frame, leftOn, rightOn []string, kind JoinType) (*Frame, error) {
+	lKeys, rKeys, err := resolveJoinKeys(f, right, leftOn, rightOn)
 	if err != nil {
 		return nil, err
 	}
-	if !isHashable(lKey.DataType()) {
-		return nil, fmt.Errorf("gobi: left key type %s is not hashable", lKey.DataType())
-	}
-	if lKey.DataType().ID() != rKey.DataType().ID() {
-		return nil, fmt.Errorf("%w: %s vs %s", ErrColumnTypeMismatch,
-			lKey.DataType(), rKey.DataType())
-	}
 
 	switch kind {
 	case JoinInner, JoinLeft, JoinFull, JoinSemi, JoinAnti:
-		return f.joinHashRight(right, leftKey, rightKey, lKey, rKey, kind)
+		return f.joinHashRight(right, leftOn, rightOn, lKeys, rKeys, kind)
 	case JoinRight:
-		return f.joinHashLeft(right, leftKey, rightKey, lKey, rKey)
+		return f.joinHashLeft(right, leftOn, rightOn, lKeys, rKeys)
 	default:
 		return nil, fmt.Errorf("gobi: unknown join kind %d", kind)
 	}
 }
 
-// joinHashRight builds a hash of the right frame's key column and walks
+// resolveJoinKeys looks up and type-checks the key columns on both
+// sides. Shared by the hash join, the sort-merge join, and the
+// streaming exec so every path rejects the same inputs with the same
+// errors.
+func resolveJoinKeys(left, right *Frame, leftOn, rightOn []string) (lKeys, rKeys []Series, err error) {
+	if len(leftOn) == 0 || len(leftOn) != len(rightOn) {
+		return nil, nil, fmt.Errorf("gobi: join needs the same non-zero number of left and right keys, got %d and %d",
+			len(leftOn), len(rightOn))
+	}
+	lKeys = make([]Series, len(leftOn))
+	rKeys = make([]Series, len(rightOn))
+	for i := range leftOn {
+		lKey, err := left.Column(leftOn[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		rKey, err := right.Column(rightOn[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isHashable(lKey.DataType()) {
+			return nil, nil, fmt.Errorf("gobi: left key type %s is not hashable", lKey.DataType())
+		}
+		// Categorical keys hash by value, so they join String keys.
+		lt, rt := lKey.DataType(), rKey.DataType()
+		if lt.ID() != rt.ID() && !(isStringOrCategorical(lt) && isStringOrCategorical(rt)) {
+			return nil, nil, fmt.Errorf("%w: %s vs %s", ErrColumnTypeMismatch,
+				lKey.DataType(), rKey.DataType())
+		}
+		lKeys[i], rKeys[i] = lKey, rKey
+	}
+	return lKeys, rKeys, nil
+}
+
+// joinHashRight builds a hash of the right frame's key columns and walks
 // the left frame. Handles Inner, Left, Full, Semi, and Anti — every join
 // kind that iterates the left frame as the outer loop.
-func (f *Frame) joinHashRight(right *Frame, leftKey, rightKey string,
-	lKey, rKey Series, kind JoinType,
+func (f *Frame) joinHashRight(right *Frame, leftOn, rightOn []string,
+	lKeys, rKeys []Series, kind JoinType,
 ) (*Frame, error) {
-	rightIndex, err := buildKeyIndex(rKey, right.NumRows())
+	rightIndex, err := buildKeyIndex(rKeys, right.NumRows())
 	if err != nil {
 		return nil, err
 	}
-	return f.joinHashRightWithIndex(right, leftKey, rightKey, lKey, rKey, kind, rightIndex)
+	return f.joinHashRightWithIndex(right, leftOn, rightOn, lKeys, rKeys, kind, rightIndex)
 }
 
 // joinHashRightWithIndex is the index-agnostic core of joinHashRight,
@@ -102,8 +148,8 @@ func (f *Frame) joinHashRight(right *Frame, leftKey, rightKey string,
 // Not exported outside the package; the streaming path and Frame.Join
 // share this helper, but external callers stick to Frame.Join, which
 // still builds the index internally on each call.
-func (f *Frame) joinHashRightWithIndex(right *Frame, leftKey, rightKey string,
-	lKey, rKey Series, kind JoinType, rightIndex map[string][]int,
+func (f *Frame) joinHashRightWithIndex(right *Frame, leftOn, rightOn []string,
+	lKeys, rKeys []Series, kind JoinType, rightIndex map[string][]int,
 ) (*Frame, error) {
 	// Track which right rows have been matched so JoinFull can emit
 	// unmatched right rows afterwards.
@@ -113,15 +159,12 @@ func (f *Frame) joinHashRightWithIndex(right *Frame, leftKey, rightKey string,
 	}
 
 	var leftIdxs, rightIdxs []int
+	probe := newJoinProber(lKeys, rightIndex)
 	for lRow := range f.NumRows() {
-		k, err := keyOf(lKey, lRow)
+		matches, err := probe(lRow)
 		if err != nil {
 			return nil, err
 		}
-		var matches []int
-		if k[0] != 0x00 {
-			matches = rightIndex[string(k)]
-		}
 
 		switch kind {
 		case JoinSemi:
@@ -177,31 +220,28 @@ func (f *Frame) joinHashRightWithIndex(right *Frame, leftKey, rightKey string,
 	if kind == JoinSemi || kind == JoinAnti {
 		return f.buildLeftOnlyOutput(leftIdxs)
 	}
-	return f.buildTwoSidedOutput(right, leftKey, rightKey, rKey, leftIdxs, rightIdxs)
+	return f.buildTwoSidedOutput(right, leftOn, rightOn, rKeys, leftIdxs, rightIdxs)
 }
 
 // joinHashLeft is the mirror of joinHashRight for JoinRight: it builds a
-// hash of the LEFT frame's key column and walks the right frame. The
+// hash of the LEFT frame's key columns and walks the right frame. The
 // output column order still puts left columns first — this is a right
 // outer join in the SQL sense, not a "swap sides" alias.
-func (f *Frame) joinHashLeft(right *Frame, leftKey, rightKey string,
-	lKey, rKey Series,
+func (f *Frame) joinHashLeft(right *Frame, leftOn, rightOn []string,
+	lKeys, rKeys []Series,
 ) (*Frame, error) {
-	leftIndex, err := buildKeyIndex(lKey, f.NumRows())
+	leftIndex, err := buildKeyIndex(lKeys, f.NumRows())
 	if err != nil {
 		return nil, err
 	}
 
 	var leftIdxs, rightIdxs []int
+	probe := newJoinProber(rKeys, leftIndex)
 	for rRow := range right.NumRows() {
-		k, err := keyOf(rKey, rRow)
+		matches, err := probe(rRow)
 		if err != nil {
 			return nil, err
 		}
-		var matches []int
-		if k[0] != 0x00 {
-			matches = leftIndex[string(k)]
-		}
 		if len(matches) == 0 {
 			leftIdxs = append(leftIdxs, -1)
 			rightIdxs = append(rightIdxs, rRow)
@@ -213,19 +253,29 @@ func (f *Frame) joinHashLeft(right *Frame, leftKey, rightKey string,
 		}
 	}
 
-	return f.buildTwoSidedOutput(right, leftKey, rightKey, rKey, leftIdxs, rightIdxs)
+	return f.buildTwoSidedOutput(right, leftOn, rightOn, rKeys, leftIdxs, rightIdxs)
 }
 
-// buildKeyIndex returns a map of hashed-key → row indices for keyCol.
-// Null-keyed rows are skipped: they never match anything.
-func buildKeyIndex(keyCol Series, n int) (map[string][]int, error) {
+// buildKeyIndex returns a map of hashed-key → row indices over the
+// composite key formed by keyCols. Null-keyed rows (a null in any key
+// column) are skipped: they never match anything.
+//
+// A single categorical key column is bucketed by dictionary code
+// first, so the map sees one write per category per chunk rather than
+// one per row.
+func buildKeyIndex(keyCols []Series, n int) (map[string][]int, error) {
+	if len(keyCols) == 1 && keyCols[0].IsCategorical() {
+		return buildCategoricalKeyIndex(keyCols[0])
+	}
 	idx := make(map[string][]int, n)
+	var k []byte
 	for row := range n {
-		k, err := keyOf(keyCol, row)
+		var err error
+		k, err = joinKeyAppend(k[:0], keyCols, row)
 		if err != nil {
 			return nil, err
 		}
-		if k[0] == 0x00 {
+		if isNullKey(k) {
 			continue
 		}
 		idx[string(k)] = append(idx[string(k)], row)
@@ -233,6 +283,141 @@ func buildKeyIndex(keyCol Series, n int) (map[string][]int, error) {
 	return idx, nil
 }
 
+// buildCategoricalKeyIndex is buildKeyIndex for one categorical key
+// column. Keys encode as keyOfAppend does — by value — so the index
+// probes the same from a String or categorical column.
+func buildCategoricalKeyIndex(s Series) (map[string][]int, error) {
+	idx := make(map[string][]int)
+	offset := 0
+	var k []byte
+	for _, chunk := range s.col.Data().Chunks() {
+		c, ok := asCatChunk(chunk)
+		if !ok {
+			return nil, fmt.Errorf("gobi: key type %s not hashable", chunk.DataType())
+		}
+		buckets := make([][]int, c.dict.Len())
+		for i := range c.Len() {
+			if c.IsValid(i) {
+				code := c.GetValueIndex(i)
+				buckets[code] = append(buckets[code], offset+i)
+			}
+		}
+		for code, rows := range buckets {
+			if len(rows) == 0 {
+				continue
+			}
+			k = append(append(k[:0], 0x01), c.dict.Value(code)...)
+			idx[string(k)] = append(idx[string(k)], rows...)
+		}
+		offset += c.Len()
+	}
+	return idx, nil
+}
+
+// newJoinProber returns a function giving the index rows whose key
+// matches keyCols at row — nil for a null key. Rows must be probed in
+// ascending order. For a single categorical key column the matches
+// are looked up once per dictionary code per chunk and rows just
+// index that table; otherwise every row hashes its composite key.
+func newJoinProber(keyCols []Series, index map[string][]int) func(row int) ([]int, error) {
+	if len(keyCols) == 1 && keyCols[0].IsCategorical() {
+		chunks := keyCols[0].col.Data().Chunks()
+		var (
+			cur       catChunk
+			byCode    [][]int
+			start     int
+			end       int
+			nextChunk int
+			k         []byte
+		)
+		return func(row int) ([]int, error) {
+			for row >= end {
+				if nextChunk == len(chunks) {
+					return nil, fmt.Errorf("%w: %d", ErrRowOutOfRange, row)
+				}
+				c, ok := asCatChunk(chunks[nextChunk])
+				if !ok {
+					return nil, fmt.Errorf("gobi: key type %s not hashable", chunks[nextChunk].DataType())
+				}
+				nextChunk++
+				cur, start, end = c, end, end+c.Len()
+				byCode = make([][]int, c.dict.Len())
+				for code := range byCode {
+					k = append(append(k[:0], 0x01), c.dict.Value(code)...)
+					byCode[code] = index[string(k)]
+				}
+			}
+			local := row - start
+			if cur.IsNull(local) {
+				return nil, nil
+			}
+			return byCode[cur.GetValueIndex(local)], nil
+		}
+	}
+	var k []byte
+	return func(row int) ([]int, error) {
+		var err error
+		k, err = joinKeyAppend(k[:0], keyCols, row)
+		if err != nil {
+			return nil, err
+		}
+		if isNullKey(k) {
+			return nil, nil
+		}
+		return index[string(k)], nil
+	}
+}
+
+// joinKeyAppend appends the composite join key for row to dst. Per-
+// column encodings come from keyOfAppend and are separated by 0x1F,
+// the same layout GroupBy.rowKey uses, so a single-column key encodes
+// byte-for-byte like keyOf.
+//
+// In a composite key, string parts carry a uvarint length between the
+// tag and the bytes. Without it the separator is ambiguous: a string
+// can contain 0x1F followed by a tag byte, so ("a\x1f\x01b", "c") and
+// ("a", "b\x1f\x01c") would encode identically and match each other.
+// The prefix makes the bytes unordered, which is why the sort-merge
+// path compares columns one by one (compareKeyParts) instead of
+// comparing these keys.
+//
+// A null in any key column collapses the whole key to the single-byte
+// null sentinel (see isNullKey): a partially-null composite key must
+// not match another partially-null key that happens to agree on the
+// non-null columns.
+func joinKeyAppend(dst []byte, keyCols []Series, row int) ([]byte, error) {
+	start := len(dst)
+	for i, s := range keyCols {
+		if i > 0 {
+			dst = append(dst, 0x1F)
+		}
+		colStart := len(dst)
+		var err error
+		dst, err = keyOfAppend(dst, s, row)
+		if err != nil {
+			return nil, err
+		}
+		if isNullKey(dst[colStart:]) {
+			return append(dst[:start], 0x00), nil
+		}
+		if len(keyCols) > 1 && dst[colStart] == 0x01 {
+			dst = insertKeyLength(dst, colStart+1)
+		}
+	}
+	return dst, nil
+}
+
+// insertKeyLength inserts the uvarint length of dst[at:] at position
+// at.
+func insertKeyLength(dst []byte, at int) []byte {
+	var lb [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(lb[:], uint64(len(dst)-at))
+	dst = append(dst, lb[:l]...)
+	copy(dst[at+l:], dst[at:len(dst)-l])
+	copy(dst[at:], lb[:l])
+	return dst
+}
+
 // buildLeftOnlyOutput materializes just the left frame's columns at the
 // given indexes. Used by JoinSemi and JoinAnti, which never emit
 // right-side columns. leftIdxs must not contain -1.
@@ -257,18 +442,27 @@ func (f *Frame) buildLeftOnlyOutput(leftIdxs []int) (*Frame, error) {
 }
 
 // buildTwoSidedOutput materializes left + right (minus the right join
-// key) at the paired indexes. Either side's indices may contain -1 to
+// keys) at the paired indexes. Either side's indices may contain -1 to
 // mean "emit a null in this row" — needed for outer joins.
 //
-// The join key column (leftKey on the left frame) is coalesced: for
+// The join key columns (leftOn on the left frame) are coalesced: for
 // rows where the left index is -1 but the right has a match, we pull
-// the key value from rKey instead of emitting null. Matches pandas
-// merge / SQL COALESCE(left.key, right.key) — a right or full outer
-// join otherwise loses the key value for unmatched-right rows because
-// the right-side key column is filtered out of the output.
-func (f *Frame) buildTwoSidedOutput(right *Frame, leftKey, rightKey string,
-	rKey Series, leftIdxs, rightIdxs []int,
+// each key value from the matching rKeys column instead of emitting
+// null. Matches pandas merge / SQL COALESCE(left.key, right.key) — a
+// right or full outer join otherwise loses the key value for
+// unmatched-right rows because the right-side key columns are
+// filtered out of the output.
+func (f *Frame) buildTwoSidedOutput(right *Frame, leftOn, rightOn []string,
+	rKeys []Series, leftIdxs, rightIdxs []int,
 ) (*Frame, error) {
+	leftKeyPos := make(map[string]int, len(leftOn))
+	for i, n := range leftOn {
+		leftKeyPos[n] = i
+	}
+	rightKeySet := make(map[string]struct{}, len(rightOn))
+	for _, n := range rightOn {
+		rightKeySet[n] = struct{}{}
+	}
 	leftNames := f.ColumnNames()
 	leftNameSet := make(map[string]struct{}, len(leftNames))
 	for _, n := range leftNames {
@@ -284,8 +478,8 @@ func (f *Frame) buildTwoSidedOutput(right *Frame, leftKey, rightKey string,
 			arr arrow.Array
 			err error
 		)
-		if s.name == leftKey {
-			arr, err = takeCoalescedKey(pool, s, rKey, leftIdxs, rightIdxs)
+		if i, ok := leftKeyPos[s.name]; ok {
+			arr, err = takeCoalescedKey(pool, s, rKeys[i], leftIdxs, rightIdxs)
 		} else {
 			arr, err = takeArrayWithNulls(pool, s, leftIdxs)
 		}
@@ -300,7 +494,7 @@ func (f *Frame) buildTwoSidedOutput(right *Frame, leftKey, rightKey string,
 	}
 
 	for _, s := range right.series {
-		if s.name == rightKey {
+		if _, isKey := rightKeySet[s.name]; isKey {
 			continue
 		}
 		arr, err := takeArrayWithNulls(pool, s, rightIdxs)
@@ -322,7 +516,7 @@ func (f *Frame) buildTwoSidedOutput(right *Frame, leftKey, rightKey string,
 	return NewFrame(schema, outColumns)
 }
 
-// takeCoalescedKey materializes the join key column, pulling from
+// takeCoalescedKey materializes one join key column, pulling from
 // primary at primaryIdxs and falling back to fallback at fallbackIdxs
 // whenever the primary index is -1. Only the join key columns need
 // this: for right / full outer joins, unmatched-right rows have -1
@@ -421,6 +615,19 @@ func takeCoalescedKey(pool memory.Allocator, primary, fallback Series,
 			}
 		}
 		return b.NewArray(), nil
+	case arrow.DICTIONARY:
+		if !isCategoricalType(dt) {
+			return nil, fmt.Errorf("%w: coalesced join key not implemented for %s",
+				ErrColumnTypeMismatch, dt)
+		}
+		b := array.NewDictionaryBuilder(pool, dt.(*arrow.DictionaryType))
+		defer b.Release()
+		for i := range primaryIdxs {
+			if err := appendFrom(b, i); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
 	default:
 		return nil, fmt.Errorf("%w: coalesced join key not implemented for %s",
 			ErrColumnTypeMismatch, dt)
@@ -511,6 +718,45 @@ func takeArrayWithNulls(pool memory.Allocator, s Series, indexes []int) (arrow.A
 			}
 		}
 		return b.NewArray(), nil
+	case arrow.TIMESTAMP:
+		b := array.NewTimestampBuilder(pool, dt.(*arrow.TimestampType))
+		defer b.Release()
+		for _, idx := range indexes {
+			if idx < 0 {
+				b.AppendNull()
+				continue
+			}
+			if err := appendPrimitiveAt(s, idx, b); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
+	case arrow.DATE32:
+		b := array.NewDate32Builder(pool)
+		defer b.Release()
+		for _, idx := range indexes {
+			if idx < 0 {
+				b.AppendNull()
+				continue
+			}
+			if err := appendPrimitiveAt(s, idx, b); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
+	case arrow.DATE64:
+		b := array.NewDate64Builder(pool)
+		defer b.Release()
+		for _, idx := range indexes {
+			if idx < 0 {
+				b.AppendNull()
+				continue
+			}
+			if err := appendPrimitiveAt(s, idx, b); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
 	case arrow.BINARY:
 		b := array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
 		defer b.Release()
@@ -549,6 +795,26 @@ func takeArrayWithNulls(pool memory.Allocator, s Series, indexes []int) (arrow.A
 			}
 		}
 		return lb.NewArray(), nil
+	case arrow.DICTIONARY:
+		if !isCategoricalType(dt) {
+			return nil, fmt.Errorf("%w: join not implemented for %s",
+				ErrColumnTypeMismatch, dt)
+		}
+		if chunks := s.col.Data().Chunks(); len(chunks) == 1 {
+			return takeDictionaryCodes(pool, chunks[0].(*array.Dictionary), indexes), nil
+		}
+		b := array.NewDictionaryBuilder(pool, dt.(*arrow.DictionaryType))
+		defer b.Release()
+		for _, idx := range indexes {
+			if idx < 0 {
+				b.AppendNull()
+				continue
+			}
+			if err := appendPrimitiveAt(s, idx, b); err != nil {
+				return nil, err
+			}
+		}
+		return b.NewArray(), nil
 	default:
 		return nil, fmt.Errorf("%w: join not implemented for %s",
 			ErrColumnTypeMismatch, dt)
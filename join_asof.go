@@ -0,0 +1,395 @@
+package gobi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// AsofStrategy selects which right row an as-of join attaches to each
+// left row.
+type AsofStrategy uint8
+
+const (
+	// AsofBackward matches the last right row whose time is at or
+	// before the left row's time — "the most recent known state". The
+	// zero value.
+	AsofBackward AsofStrategy = iota
+	// AsofForward matches the first right row whose time is at or
+	// after the left row's time — "the next scheduled event".
+	AsofForward
+	// AsofNearest matches whichever of the backward and forward
+	// candidates is closer in time. Ties go to the backward candidate.
+	AsofNearest
+)
+
+// AsofOptions tunes Frame.JoinAsof. A nil *AsofOptions means
+// AsofBackward with no tolerance.
+type AsofOptions struct {
+	// Strategy picks backward / forward / nearest matching.
+	Strategy AsofStrategy
+	// Tolerance, when > 0, caps how far apart (in absolute time) a
+	// left row and its matched right row may be. A candidate further
+	// away than Tolerance leaves the left row unmatched (null right
+	// columns) — "last known position, but only if it's under 30s
+	// old". Zero means unbounded.
+	Tolerance time.Duration
+}
+
+// JoinAsof attaches to each row of f (the left frame) the right row
+// whose rightOn time is closest to the row's leftOn time in the
+// direction opts.Strategy picks — by default, the most recent right
+// row at or before the left row's time. The canonical shape is
+// enriching events with slowly-changing reference data:
+//
+//	// Each ping gets the last vehicle state reported at or before it,
+//	// per vehicle, but only if that state is under 5 minutes old.
+//	out, err := pings.JoinAsof(states, "ts", "reported_at",
+//	    []string{"vehicle_id"}, &gobi.AsofOptions{Tolerance: 5 * time.Minute})
+//
+// leftOn and rightOn must be datetime columns (Timestamp at any unit,
+// Date32, Date64); values compare as absolute instants, so mixed
+// units and timezone labels are fine. by, when non-empty, names
+// columns present on both sides: only right rows with equal by values
+// are candidates, with the same hashable-type and null rules as
+// JoinOn (a null in any by column never matches).
+//
+// The result has exactly one row per left row, in left order — a
+// left join. Left rows with a null time or no candidate get null
+// right-side columns. Columns are laid out like JoinOn with by as
+// the key: left columns, then right columns except the by columns,
+// with "_right" appended on name collisions. The rightOn column is
+// kept so callers can see how stale each match is.
+//
+// Neither side has to be pre-sorted. The right side is bucketed by
+// the by key and each bucket is stably sorted by time; equal right
+// times resolve to the last such row in right order for
+// AsofBackward and the first for AsofForward.
+func (f *Frame) JoinAsof(right *Frame, leftOn, rightOn string, by []string, opts *AsofOptions) (*Frame, error) {
+	if opts == nil {
+		opts = &AsofOptions{}
+	}
+	if err := validateAsof(f, right, leftOn, rightOn, opts); err != nil {
+		return nil, err
+	}
+	idx, rBy, err := buildAsofIndex(right, rightOn, by)
+	if err != nil {
+		return nil, err
+	}
+	return f.joinAsofWithIndex(right, leftOn, by, rBy, idx, *opts)
+}
+
+// validateAsof checks the time columns and options shared by the
+// eager and streaming as-of paths.
+func validateAsof(left, right *Frame, leftOn, rightOn string, opts *AsofOptions) error {
+	lt, err := left.Column(leftOn)
+	if err != nil {
+		return err
+	}
+	if !lt.IsDateTime() {
+		return fmt.Errorf("%w: column %q", ErrNotDateTime, leftOn)
+	}
+	rt, err := right.Column(rightOn)
+	if err != nil {
+		return err
+	}
+	if !rt.IsDateTime() {
+		return fmt.Errorf("%w: column %q", ErrNotDateTime, rightOn)
+	}
+	switch opts.Strategy {
+	case AsofBackward, AsofForward, AsofNearest:
+	default:
+		return fmt.Errorf("gobi: unknown as-of strategy %d", opts.Strategy)
+	}
+	if opts.Tolerance < 0 {
+		return fmt.Errorf("gobi: JoinAsof tolerance must be >= 0, got %v", opts.Tolerance)
+	}
+	return nil
+}
+
+// asofIndex is the right side of an as-of join: right rows bucketed
+// by encoded by-key, each bucket sorted ascending by time. Built once
+// and probed per left row, so the streaming exec can reuse it across
+// probe batches the same way streamingJoinExec reuses its hash index.
+type asofIndex struct {
+	groups map[string]*asofGroup
+}
+
+// asofGroup holds one by-key bucket: ts[i] is the time of right row
+// rows[i], ascending.
+type asofGroup struct {
+	ts   []asofInstant
+	rows []int
+}
+
+// asofInstant is a datetime value as whole seconds since the Unix
+// epoch plus a nanosecond remainder in [0, 1e9). Every Arrow datetime
+// type converts into it exactly, so Date32 and Timestamp[s]/[ms]
+// values outside the roughly 1678–2262 window time.UnixNano can
+// represent still order and match correctly against each other and
+// against Timestamp[ns].
+type asofInstant struct {
+	sec  int64
+	nsec int64
+}
+
+func (a asofInstant) compare(b asofInstant) int {
+	if c := cmpOrd(a.sec, b.sec); c != 0 {
+		return c
+	}
+	return cmpOrd(a.nsec, b.nsec)
+}
+
+// asofGap is the non-negative distance between two instants. The
+// seconds are unsigned because two Timestamp[s] values can be further
+// apart than an int64 holds.
+type asofGap struct {
+	sec  uint64
+	nsec int64
+}
+
+// gap returns |a - b|.
+func (a asofInstant) gap(b asofInstant) asofGap {
+	if a.compare(b) < 0 {
+		a, b = b, a
+	}
+	g := asofGap{sec: uint64(a.sec) - uint64(b.sec), nsec: a.nsec - b.nsec}
+	if g.nsec < 0 {
+		g.sec--
+		g.nsec += int64(time.Second)
+	}
+	return g
+}
+
+func (g asofGap) compare(o asofGap) int {
+	if c := cmpOrd(g.sec, o.sec); c != 0 {
+		return c
+	}
+	return cmpOrd(g.nsec, o.nsec)
+}
+
+// buildAsofIndex buckets right's rows by the by columns and sorts each
+// bucket by rightOn. Rows with a null time or a null by value are
+// left out — they can never be matched. Also returns the right-side
+// by Series so the output builder can coalesce against them.
+func buildAsofIndex(right *Frame, rightOn string, by []string) (*asofIndex, []Series, error) {
+	rt, err := right.Column(rightOn)
+	if err != nil {
+		return nil, nil, err
+	}
+	ts, valid, err := seriesInstants(rt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rBy []Series
+	if len(by) > 0 {
+		_, rBy, err = resolveJoinKeys(right, right, by, by)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	idx := &asofIndex{groups: make(map[string]*asofGroup)}
+	var k []byte
+	for row := range right.NumRows() {
+		if !valid[row] {
+			continue
+		}
+		if len(rBy) > 0 {
+			k, err = joinKeyAppend(k[:0], rBy, row)
+			if err != nil {
+				return nil, nil, err
+			}
+			if isNullKey(k) {
+				continue
+			}
+		}
+		g, ok := idx.groups[string(k)]
+		if !ok {
+			g = &asofGroup{}
+			idx.groups[string(k)] = g
+		}
+		g.rows = append(g.rows, row)
+	}
+	// Stable sort keeps right-input order among equal times, which is
+	// what makes the documented tie-breaking deterministic.
+	for _, g := range idx.groups {
+		slices.SortStableFunc(g.rows, func(a, b int) int { return ts[a].compare(ts[b]) })
+		g.ts = make([]asofInstant, len(g.rows))
+		for i, r := range g.rows {
+			g.ts[i] = ts[r]
+		}
+	}
+	return idx, rBy, nil
+}
+
+// match returns the right row matched to time t under opts, or -1.
+func (g *asofGroup) match(t asofInstant, opts AsofOptions) int {
+	n := len(g.ts)
+	// back: last position with ts <= t. fwd: first position with ts >= t.
+	back := sort.Search(n, func(i int) bool { return g.ts[i].compare(t) > 0 }) - 1
+	fwd := sort.Search(n, func(i int) bool { return g.ts[i].compare(t) >= 0 })
+
+	pos := -1
+	switch opts.Strategy {
+	case AsofBackward:
+		pos = back
+	case AsofForward:
+		if fwd < n {
+			pos = fwd
+		}
+	case AsofNearest:
+		switch {
+		case back < 0 && fwd >= n:
+		case back < 0:
+			pos = fwd
+		case fwd >= n:
+			pos = back
+		case g.ts[fwd].gap(t).compare(t.gap(g.ts[back])) < 0:
+			pos = fwd
+		default:
+			pos = back
+		}
+	}
+	if pos < 0 {
+		return -1
+	}
+	if opts.Tolerance > 0 {
+		tol := asofGap{
+			sec:  uint64(opts.Tolerance / time.Second),
+			nsec: int64(opts.Tolerance % time.Second),
+		}
+		if t.gap(g.ts[pos]).compare(tol) > 0 {
+			return -1
+		}
+	}
+	return g.rows[pos]
+}
+
+// joinAsofWithIndex is the index-agnostic core of JoinAsof, shared
+// with streamingAsofJoinExec which builds the index once and probes
+// it with every left batch.
+func (f *Frame) joinAsofWithIndex(right *Frame, leftOn string, by []string,
+	rBy []Series, idx *asofIndex, opts AsofOptions,
+) (*Frame, error) {
+	lt, err := f.Column(leftOn)
+	if err != nil {
+		return nil, err
+	}
+	ts, valid, err := seriesInstants(lt)
+	if err != nil {
+		return nil, err
+	}
+	var lBy []Series
+	if len(by) > 0 {
+		lBy, _, err = resolveJoinKeys(f, right, by, by)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	n := f.NumRows()
+	leftIdxs := make([]int, n)
+	rightIdxs := make([]int, n)
+	var k []byte
+	for row := range n {
+		leftIdxs[row] = row
+		rightIdxs[row] = -1
+		if !valid[row] {
+			continue
+		}
+		if len(lBy) > 0 {
+			k, err = joinKeyAppend(k[:0], lBy, row)
+			if err != nil {
+				return nil, err
+			}
+			if isNullKey(k) {
+				continue
+			}
+		}
+		g, ok := idx.groups[string(k)]
+		if !ok {
+			continue
+		}
+		rightIdxs[row] = g.match(ts[row], opts)
+	}
+	return f.buildTwoSidedOutput(right, by, by, rBy, leftIdxs, rightIdxs)
+}
+
+// seriesInstants materializes a datetime Series as asofInstants plus
+// a validity mask, walking chunks once. It reads the stored integers
+// directly rather than going through time.Time.UnixNano, which
+// overflows for dates outside roughly 1678–2262.
+func seriesInstants(s Series) ([]asofInstant, []bool, error) {
+	if !s.IsDateTime() {
+		return nil, nil, fmt.Errorf("%w: column %q", ErrNotDateTime, s.Name())
+	}
+	n := s.Len()
+	out := make([]asofInstant, n)
+	valid := make([]bool, n)
+	row := 0
+	for _, chunk := range s.col.Data().Chunks() {
+		for i := range chunk.Len() {
+			if !chunk.IsNull(i) {
+				out[row] = arrowInstantAt(chunk, i)
+				valid[row] = true
+			}
+			row++
+		}
+	}
+	return out, valid, nil
+}
+
+// arrowInstantAt is arrowTimeAt for asofInstant: one row of a
+// date/time chunk, split into seconds and nanoseconds with floor
+// division so pre-1970 values keep a non-negative remainder.
+func arrowInstantAt(chunk arrow.Array, local int) asofInstant {
+	switch a := chunk.(type) {
+	case *array.Timestamp:
+		v := int64(a.Value(local))
+		switch a.DataType().(*arrow.TimestampType).Unit {
+		case arrow.Second:
+			return asofInstant{sec: v}
+		case arrow.Millisecond:
+			return splitInstant(v, int64(time.Second/time.Millisecond))
+		case arrow.Microsecond:
+			return splitInstant(v, int64(time.Second/time.Microsecond))
+		case arrow.Nanosecond:
+			return splitInstant(v, int64(time.Second))
+		}
+	case *array.Date32:
+		return asofInstant{sec: int64(a.Value(local)) * 86400}
+	case *array.Date64:
+		return splitInstant(int64(a.Value(local)), int64(time.Second/time.Millisecond))
+	}
+	return asofInstant{}
+}
+
+// splitInstant converts v ticks of 1/perSec seconds into an
+// asofInstant.
+func splitInstant(v, perSec int64) asofInstant {
+	sec, rem := v/perSec, v%perSec
+	if rem < 0 {
+		sec--
+		rem += perSec
+	}
+	return asofInstant{sec: sec, nsec: rem * (int64(time.Second) / perSec)}
+}
+
+// asofStrategyLabel returns a short label for plan String() output,
+// mirroring joinKindLabel.
+func asofStrategyLabel(s AsofStrategy) string {
+	switch s {
+	case AsofBackward:
+		return "backward"
+	case AsofForward:
+		return "forward"
+	case AsofNearest:
+		return "nearest"
+	}
+	return fmt.Sprintf("strategy(%d)", s)
+}
@@ -0,0 +1,272 @@
+package gobi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+type asofPing struct {
+	Vehicle string    `gobi:"vehicle"`
+	TS      time.Time `gobi:"ts"`
+	Seq     int64     `gobi:"seq"`
+}
+
+type asofState struct {
+	Vehicle    string    `gobi:"vehicle"`
+	ReportedAt time.Time `gobi:"reported_at"`
+	Speed      float64   `gobi:"speed"`
+}
+
+var asofT0 = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func asofAt(min int) time.Time { return asofT0.Add(time.Duration(min) * time.Minute) }
+
+// asofFrames returns pings (deliberately not time-sorted) and vehicle
+// states (also unsorted) for two vehicles.
+func asofFrames(t *testing.T) (*Frame, *Frame) {
+	t.Helper()
+	pings, err := FromStructs([]asofPing{
+		{"a", asofAt(5), 0},  // a: state@3 behind, state@10 ahead
+		{"b", asofAt(1), 1},  // b: nothing behind, state@4 ahead
+		{"a", asofAt(10), 2}, // a: exact hit on state@10
+		{"a", asofAt(30), 3}, // a: state@10 is 20m stale
+		{"c", asofAt(5), 4},  // c: no states at all
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	states, err := FromStructs([]asofState{
+		{"a", asofAt(10), 10},
+		{"b", asofAt(4), 40},
+		{"a", asofAt(3), 3},
+		{"b", asofAt(2), 20}, // b-only; must never leak into "a" matches
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pings, states
+}
+
+// asofSpeeds returns the speed column with nulls rendered as -1.
+func asofSpeeds(t *testing.T, f *Frame) []float64 {
+	t.Helper()
+	s := mustColumn(t, f, "speed")
+	vals, err := s.Float64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, null := range s.Nulls() {
+		if null {
+			vals[i] = -1
+		}
+	}
+	return vals
+}
+
+func TestJoinAsof_BackwardBy(t *testing.T) {
+	pings, states := asofFrames(t)
+	out, err := pings.JoinAsof(states, "ts", "reported_at", []string{"vehicle"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"vehicle", "ts", "seq", "reported_at", "speed"}
+	if got := out.ColumnNames(); !slices.Equal(got, want) {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+	// Left order preserved; b@1 has no earlier b state (b@2 is later).
+	if got := mustInt64s(t, out, "seq"); !slices.Equal(got, []int64{0, 1, 2, 3, 4}) {
+		t.Fatalf("seq = %v, want input order", got)
+	}
+	if got, want := asofSpeeds(t, out), []float64{3, -1, 10, 10, -1}; !slices.Equal(got, want) {
+		t.Fatalf("speed = %v, want %v", got, want)
+	}
+}
+
+func TestJoinAsof_Strategies(t *testing.T) {
+	pings, states := asofFrames(t)
+	cases := []struct {
+		name string
+		opts AsofOptions
+		want []float64
+	}{
+		{"forward", AsofOptions{Strategy: AsofForward}, []float64{10, 20, 10, -1, -1}},
+		// a@5: state@3 is 2m back, state@10 is 5m ahead → backward.
+		// b@1: only forward candidates → b@2.
+		{"nearest", AsofOptions{Strategy: AsofNearest}, []float64{3, 20, 10, 10, -1}},
+		// a@30's only candidate is 20m old → dropped.
+		{"backward_tolerance", AsofOptions{Tolerance: 5 * time.Minute}, []float64{3, -1, 10, -1, -1}},
+		// Tolerance is symmetric for forward matches too.
+		{"forward_tolerance", AsofOptions{Strategy: AsofForward, Tolerance: 2 * time.Minute}, []float64{-1, 20, 10, -1, -1}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := pings.JoinAsof(states, "ts", "reported_at", []string{"vehicle"}, &tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := asofSpeeds(t, out); !slices.Equal(got, tc.want) {
+				t.Fatalf("speed = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoinAsof_NoBy(t *testing.T) {
+	pings, states := asofFrames(t)
+	out, err := pings.JoinAsof(states, "ts", "reported_at", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without by every state is a candidate: a@5 → b@4, b@1 → none.
+	if got, want := asofSpeeds(t, out), []float64{40, -1, 10, 10, 40}; !slices.Equal(got, want) {
+		t.Fatalf("speed = %v, want %v", got, want)
+	}
+	// Right vehicle collides with the left one → suffixed, not dropped.
+	if !slices.Contains(out.ColumnNames(), "vehicle_right") {
+		t.Fatalf("columns = %v, want vehicle_right", out.ColumnNames())
+	}
+}
+
+func TestJoinAsof_RejectsNonDateTime(t *testing.T) {
+	pings, states := asofFrames(t)
+	_, err := pings.JoinAsof(states, "seq", "reported_at", nil, nil)
+	if !errors.Is(err, ErrNotDateTime) {
+		t.Fatalf("err = %v, want ErrNotDateTime", err)
+	}
+	_, err = pings.JoinAsof(states, "ts", "reported_at", nil, &AsofOptions{Tolerance: -time.Second})
+	if err == nil {
+		t.Fatal("expected error for negative tolerance")
+	}
+}
+
+// asofWideFrames returns a Timestamp[s] left frame and a Date32 right
+// frame whose times lie outside the range time.UnixNano can represent.
+func asofWideFrames(t *testing.T) (*Frame, *Frame) {
+	t.Helper()
+	pool := memory.DefaultAllocator
+	unix := func(y int, m time.Month) int64 { return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Unix() }
+
+	tsType := &arrow.TimestampType{Unit: arrow.Second}
+	tb := array.NewTimestampBuilder(pool, tsType)
+	defer tb.Release()
+	for _, y := range []int{1600, 2400, 2600, 1400} {
+		tb.Append(arrow.Timestamp(unix(y, time.June)))
+	}
+	tsArr := tb.NewArray()
+	defer tsArr.Release()
+	tsField := arrow.Field{Name: "ts", Type: tsType}
+	left, err := NewFrame(arrow.NewSchema([]arrow.Field{tsField}, nil),
+		[]arrow.Column{*arrow.NewColumn(tsField, arrow.NewChunked(tsType, []arrow.Array{tsArr}))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deliberately unsorted: 2500, 1500, 2000.
+	db := array.NewDate32Builder(pool)
+	defer db.Release()
+	sb := array.NewFloat64Builder(pool)
+	defer sb.Release()
+	for i, y := range []int{2500, 1500, 2000} {
+		db.Append(arrow.Date32(unix(y, time.January) / 86400))
+		sb.Append(float64([]int{3, 1, 2}[i]))
+	}
+	dayArr, speedArr := db.NewArray(), sb.NewArray()
+	defer dayArr.Release()
+	defer speedArr.Release()
+	dayField := arrow.Field{Name: "day", Type: arrow.FixedWidthTypes.Date32}
+	speedField := arrow.Field{Name: "speed", Type: arrow.PrimitiveTypes.Float64}
+	right, err := NewFrame(arrow.NewSchema([]arrow.Field{dayField, speedField}, nil), []arrow.Column{
+		*arrow.NewColumn(dayField, arrow.NewChunked(dayField.Type, []arrow.Array{dayArr})),
+		*arrow.NewColumn(speedField, arrow.NewChunked(speedField.Type, []arrow.Array{speedArr})),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return left, right
+}
+
+func TestJoinAsof_OutsideUnixNanoRange(t *testing.T) {
+	left, right := asofWideFrames(t)
+	cases := []struct {
+		name string
+		opts AsofOptions
+		want []float64
+	}{
+		// 1600 → 1500, 2400 → 2000, 2600 → 2500, 1400 → nothing before.
+		{"backward", AsofOptions{}, []float64{1, 2, 3, -1}},
+		{"forward", AsofOptions{Strategy: AsofForward}, []float64{2, 3, -1, 1}},
+		// 2400 is closer to 2500 than to 2000.
+		{"nearest", AsofOptions{Strategy: AsofNearest}, []float64{1, 3, 3, 1}},
+		// The widest Duration (~292 years) keeps the century-old
+		// matches but drops 2400 → 2000.
+		{"tolerance", AsofOptions{Tolerance: time.Duration(math.MaxInt64)}, []float64{1, -1, 3, -1}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := left.JoinAsof(right, "ts", "day", nil, &tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := asofSpeeds(t, out); !slices.Equal(got, tc.want) {
+				t.Fatalf("speed = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLazyJoinAsof_MatchesEager(t *testing.T) {
+	pings, states := asofFrames(t)
+	opts := &AsofOptions{Strategy: AsofNearest}
+	eager, err := pings.JoinAsof(states, "ts", "reported_at", []string{"vehicle"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := pings.Lazy().JoinAsof(states.Lazy(), "ts", "reported_at", []string{"vehicle"}, opts)
+	if got := lf.ExplainPhysical(); !strings.Contains(got, "StreamingJoinAsof(nearest, left.ts ~ right.reported_at, by=[vehicle])") {
+		t.Fatalf("ExplainPhysical:\n%s", got)
+	}
+	lazy, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(asofSpeeds(t, lazy), asofSpeeds(t, eager)) {
+		t.Fatalf("lazy %v != eager %v", asofSpeeds(t, lazy), asofSpeeds(t, eager))
+	}
+	raw, err := lf.CollectRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(asofSpeeds(t, raw), asofSpeeds(t, eager)) {
+		t.Fatalf("CollectRaw %v != eager %v", asofSpeeds(t, raw), asofSpeeds(t, eager))
+	}
+}
+
+func TestStreamingAsofJoin_MultiBatchProbe(t *testing.T) {
+	pings, states := asofFrames(t)
+	node := newAsofJoinNode(&scanFrameNode{frame: pings}, &scanFrameNode{frame: states},
+		"ts", "reported_at", []string{"vehicle"}, AsofOptions{})
+	exec := &streamingAsofJoinExec{
+		left:      newScanFrameExec(pings, 2), // 3 probe batches
+		right:     newScanFrameExec(states, 100),
+		leftOn:    "ts",
+		rightOn:   "reported_at",
+		by:        []string{"vehicle"},
+		outSchema: node.outSchema,
+	}
+	got, err := Execute(context.Background(), exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := asofSpeeds(t, got), []float64{3, -1, 10, 10, -1}; !slices.Equal(got, want) {
+		t.Fatalf("speed = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,288 @@
+package gobi
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type joinOnLeftRow struct {
+	Tenant string `gobi:"tenant"`
+	Day    int64  `gobi:"day"`
+	Clicks int64  `gobi:"clicks"`
+}
+
+type joinOnRightRow struct {
+	Tenant string  `gobi:"tenant"`
+	Day    int64   `gobi:"day"`
+	Budget float64 `gobi:"budget"`
+}
+
+// joinOnFrames returns a (tenant, day) keyed pair where only the
+// full composite key discriminates matches: ("a", 1) and ("b", 2)
+// match, while ("a", 2) / ("b", 1) agree with some right row on
+// each column individually but on no right row as a pair.
+func joinOnFrames(t *testing.T) (*Frame, *Frame) {
+	t.Helper()
+	left, err := FromStructs([]joinOnLeftRow{
+		{"a", 1, 10},
+		{"a", 2, 20},
+		{"b", 1, 30},
+		{"b", 2, 40},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := FromStructs([]joinOnRightRow{
+		{"a", 1, 1.5},
+		{"b", 2, 2.5},
+		{"c", 3, 3.5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return left, right
+}
+
+var joinOnKeys = []string{"tenant", "day"}
+
+func TestJoinOn_InnerCompositeKey(t *testing.T) {
+	left, right := joinOnFrames(t)
+	out, err := left.JoinOn(right, joinOnKeys, joinOnKeys, JoinInner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.ColumnNames(), []string{"tenant", "day", "clicks", "budget"}; !slices.Equal(got, want) {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+	clicks := mustInt64s(t, out, "clicks")
+	if !slices.Equal(clicks, []int64{10, 40}) {
+		t.Fatalf("clicks = %v, want [10 40]", clicks)
+	}
+}
+
+func TestJoinOn_FullCoalescesEveryKey(t *testing.T) {
+	left, right := joinOnFrames(t)
+	out, err := left.JoinOn(right, joinOnKeys, joinOnKeys, JoinFull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 4 left rows + the unmatched ("c", 3) right row.
+	if out.NumRows() != 5 {
+		t.Fatalf("rows = %d, want 5", out.NumRows())
+	}
+	tenant, err := mustColumn(t, out, "tenant").Strings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := mustInt64s(t, out, "day")
+	if tenant[4] != "c" || day[4] != 3 {
+		t.Fatalf("unmatched right row keys = (%q, %d), want (\"c\", 3)", tenant[4], day[4])
+	}
+	if n := mustColumn(t, out, "tenant").NullCount(); n != 0 {
+		t.Fatalf("tenant null count = %d, want 0 (coalesced)", n)
+	}
+}
+
+func TestJoinOn_RightMatchesEagerSemantics(t *testing.T) {
+	left, right := joinOnFrames(t)
+	out, err := left.JoinOn(right, joinOnKeys, joinOnKeys, JoinRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 3 {
+		t.Fatalf("rows = %d, want 3", out.NumRows())
+	}
+	day := mustInt64s(t, out, "day")
+	if !slices.Equal(day, []int64{1, 2, 3}) {
+		t.Fatalf("day = %v, want [1 2 3]", day)
+	}
+}
+
+func TestJoinOn_NullInAnyKeyColumnNeverMatches(t *testing.T) {
+	type row struct {
+		Tenant *string `gobi:"tenant"`
+		Day    int64   `gobi:"day"`
+	}
+	a := "a"
+	left, err := FromStructs([]row{{&a, 1}, {nil, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := FromStructs([]row{{&a, 1}, {nil, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, err := left.JoinOn(right, joinOnKeys, joinOnKeys, JoinInner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.NumRows() != 1 {
+		t.Fatalf("inner rows = %d, want 1 (null tenant must not match null tenant)", inner.NumRows())
+	}
+	anti, err := left.JoinOn(right, joinOnKeys, joinOnKeys, JoinAnti)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anti.NumRows() != 1 {
+		t.Fatalf("anti rows = %d, want 1", anti.NumRows())
+	}
+}
+
+func TestJoinOn_CompositeStringKeysDontCollide(t *testing.T) {
+	type row struct {
+		K1 string `gobi:"k1"`
+		K2 string `gobi:"k2"`
+		ID int64  `gobi:"id"`
+	}
+	keys := []string{"k1", "k2"}
+	// Concatenated with the 0x1F separator and 0x01 string tag, the
+	// first rows of each side spell the same bytes.
+	left, err := FromStructs([]row{{"a\x1f\x01b", "c", 1}, {"x", "y", 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := FromStructs([]row{{"a", "b\x1f\x01c", 10}, {"x", "y", 20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager, err := left.JoinOn(right, keys, keys, JoinInner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err := left.Lazy().JoinOn(right.Lazy(), keys, keys, JoinInner).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, out := range map[string]*Frame{"eager": eager, "lazy": lazy} {
+		if ids := mustInt64s(t, out, "id"); !slices.Equal(ids, []int64{2}) {
+			t.Fatalf("%s ids = %v, want [2]", name, ids)
+		}
+	}
+}
+
+func TestJoinOn_KeyCountMismatch(t *testing.T) {
+	left, right := joinOnFrames(t)
+	if _, err := left.JoinOn(right, joinOnKeys, []string{"tenant"}, JoinInner); err == nil {
+		t.Fatal("expected error for mismatched key counts")
+	}
+	if _, err := left.JoinOn(right, nil, nil, JoinInner); err == nil {
+		t.Fatal("expected error for empty key lists")
+	}
+}
+
+func TestLazyJoinOn_StreamingMatchesEager(t *testing.T) {
+	left, right := joinOnFrames(t)
+	for _, kind := range []JoinType{JoinInner, JoinLeft, JoinRight, JoinFull, JoinSemi, JoinAnti} {
+		eager, err := left.JoinOn(right, joinOnKeys, joinOnKeys, kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lazy, err := left.Lazy().JoinOn(right.Lazy(), joinOnKeys, joinOnKeys, kind).Collect()
+		if err != nil {
+			t.Fatalf("kind=%s: %v", joinKindLabel(kind), err)
+		}
+		if lazy.NumRows() != eager.NumRows() || lazy.NumCols() != eager.NumCols() {
+			t.Errorf("kind=%s: lazy %dx%d, eager %dx%d", joinKindLabel(kind),
+				lazy.NumRows(), lazy.NumCols(), eager.NumRows(), eager.NumCols())
+		}
+	}
+
+	plan := left.Lazy().JoinOn(right.Lazy(), joinOnKeys, joinOnKeys, JoinInner).Explain()
+	if !strings.Contains(plan, "Join(inner, left.tenant = right.tenant AND left.day = right.day)") {
+		t.Fatalf("explain missing composite join condition:\n%s", plan)
+	}
+}
+
+func TestLazyJoinOn_SortMergeCompositeKey(t *testing.T) {
+	left, right := joinOnFrames(t)
+	meta := func(sorted ...string) *PartitionMetadata {
+		m := &PartitionMetadata{
+			Columns:      []string{"tenant"},
+			HashFn:       "test/hash/v1",
+			SortEnforced: true,
+		}
+		for _, c := range sorted {
+			m.SortedBy = append(m.SortedBy, SortKey{Column: c})
+		}
+		return m
+	}
+	assert := func(f *Frame, m *PartitionMetadata) *LazyFrame {
+		lf, err := f.Lazy().WithPartitionAssertion(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lf
+	}
+
+	// Sorted on both keys in join order → sort-merge fires.
+	full := assert(left, meta("tenant", "day")).
+		JoinOn(assert(right, meta("tenant", "day")), joinOnKeys, joinOnKeys, JoinInner)
+	if !canMergeJoin(full.plan.(*joinNode)) {
+		t.Fatal("canMergeJoin = false for inputs sorted on every join key")
+	}
+	out, err := full.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clicks := mustInt64s(t, out, "clicks"); !slices.Equal(clicks, []int64{10, 40}) {
+		t.Fatalf("merge clicks = %v, want [10 40]", clicks)
+	}
+
+	// String keys of different lengths: the merge still sees ("a", 2)
+	// before ("ab", 1), which the length-prefixed hash key doesn't
+	// order.
+	type row struct {
+		Tenant string `gobi:"tenant"`
+		Day    int64  `gobi:"day"`
+		Clicks int64  `gobi:"clicks"`
+	}
+	rows, err := FromStructs([]row{{"a", 2, 1}, {"ab", 1, 2}, {"b", 0, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keysOnly, err := rows.SelectCols("tenant", "day")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mergeLF := assert(rows, meta("tenant", "day")).
+		JoinOn(assert(keysOnly, meta("tenant", "day")), joinOnKeys, joinOnKeys, JoinInner)
+	if !canMergeJoin(mergeLF.plan.(*joinNode)) {
+		t.Fatal("canMergeJoin = false for string keys sorted on every join key")
+	}
+	merged, err := mergeLF.
+		Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clicks := mustInt64s(t, merged, "clicks"); !slices.Equal(clicks, []int64{1, 2, 3}) {
+		t.Fatalf("merge clicks = %v, want [1 2 3]", clicks)
+	}
+
+	// Sorted only on the leading key → rows sharing a tenant aren't
+	// ordered by day, so the merge scan would miss pairs. Must fall
+	// back to the hash join.
+	partial := assert(left, meta("tenant")).
+		JoinOn(assert(right, meta("tenant")), joinOnKeys, joinOnKeys, JoinInner)
+	if canMergeJoin(partial.plan.(*joinNode)) {
+		t.Fatal("canMergeJoin = true with SortedBy covering only part of the key")
+	}
+}
+
+func mustColumn(t *testing.T, f *Frame, name string) Series {
+	t.Helper()
+	s, err := f.Column(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func mustInt64s(t *testing.T, f *Frame, name string) []int64 {
+	t.Helper()
+	v, err := mustColumn(t, f, name).Int64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -3,6 +3,7 @@ package gobi
 import (
 	"context"
 	"fmt"
+	"iter"
 	"strings"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -57,6 +58,22 @@ func (lf *LazyFrame) Filter(cond Expr) *LazyFrame {
 	return &LazyFrame{plan: &filterNode{input: lf.plan, cond: cond}}
 }
 
+// DropNulls appends a Filter on the IsNotNull conjunction of subset —
+// every column of the current schema when subset is empty — so it
+// streams and pushes down like any other filter. Matches
+// Frame.DropNulls.
+func (lf *LazyFrame) DropNulls(subset ...string) *LazyFrame {
+	if len(subset) == 0 {
+		for _, fld := range lf.Schema().Fields() {
+			subset = append(subset, fld.Name)
+		}
+	}
+	if len(subset) == 0 {
+		return lf
+	}
+	return lf.Filter(notNullPredicate(subset))
+}
+
 // Select appends a Project node — the resulting LazyFrame contains
 // only the columns produced by exprs, in that order. Each expression's
 // output column name comes from Namer.OutputName if the expression
@@ -133,6 +150,49 @@ func (lf *LazyFrame) Join(right *LazyFrame, leftKey, rightKey string, kind JoinT
 	return &LazyFrame{plan: newJoinNode(lf.plan, right.plan, leftKey, rightKey, kind)}
 }
 
+// JoinOn appends a composite-key Join node: rows match when
+// leftOn[i] equals rightOn[i] for every i. Semantics match
+// Frame.JoinOn; a length mismatch between leftOn and rightOn, like a
+// missing key column, surfaces at Collect.
+func (lf *LazyFrame) JoinOn(right *LazyFrame, leftOn, rightOn []string, kind JoinType) *LazyFrame {
+	return &LazyFrame{plan: newJoinNodeOn(lf.plan, right.plan, leftOn, rightOn, kind)}
+}
+
+// JoinAsof appends an as-of Join node: each left row picks up the
+// right row nearest in time per opts (nil means backward, no
+// tolerance), optionally within by partitions. Semantics match
+// Frame.JoinAsof. The node streams the left side — one output batch
+// per probe batch, left order preserved — so it composes with
+// ScanFile sources without materializing the probe side.
+func (lf *LazyFrame) JoinAsof(right *LazyFrame, leftOn, rightOn string, by []string, opts *AsofOptions) *LazyFrame {
+	var o AsofOptions
+	if opts != nil {
+		o = *opts
+	}
+	return &LazyFrame{plan: newAsofJoinNode(lf.plan, right.plan, leftOn, rightOn, by, o)}
+}
+
+// SJoin appends a spatial Join node matching rows where pred holds
+// between leftGeomCol and rightGeomCol. Semantics, output layout and
+// options (WithJoinType, Workers) match Frame.SJoin; an unknown join
+// type, like a missing or non-geometry column, surfaces at Collect.
+//
+// The compiled operator builds the R-tree once over the right side —
+// keep the smaller frame (zones, polygons) there — and streams the
+// left side a batch at a time, so a ScanFile of points joins without
+// materializing. Right / Full joins must see every left row before
+// emitting unmatched right rows and fall back to materializing.
+// Projection pushdown reaches both inputs: only the columns the rest
+// of the plan reads, plus the two geometry columns, are scanned.
+func (lf *LazyFrame) SJoin(right *LazyFrame, leftGeomCol, rightGeomCol string, pred SpatialPredicate, opts ...Option) *LazyFrame {
+	cfg := resolveOptions(opts...)
+	kind := JoinInner
+	if cfg.joinTypeSet {
+		kind = cfg.joinType
+	}
+	return &LazyFrame{plan: newSJoinNode(lf.plan, right.plan, leftGeomCol, rightGeomCol, pred, kind, opts)}
+}
+
 // DropColumn appends a Drop node. If the named column is missing at
 // Collect time, the underlying Frame.DropColumn surfaces
 // ErrColumnNotFound.
@@ -287,40 +347,52 @@ func explainPlan(p LogicalPlan, sb *strings.Builder, depth int) {
 // pull one batch at a time; blocking operators (Sort, Aggregate,
 // Join, Tail) buffer their input to a Frame and delegate to the
 // eager engine. Peak memory is bounded to one batch per streaming
-// node plus the accumulated Frame at each blocking node.
+// node plus the accumulated Frame at each blocking node. CollectWith
+// caps the blocking nodes with a memory budget, spilling to disk
+// past it.
 //
 // This is where errors surface: bad expressions, type mismatches,
 // unknown columns, scan failures.
 func (lf *LazyFrame) Collect() (*Frame, error) {
-	plan := Optimize(lf.plan)
-	op, err := Compile(plan)
-	if err != nil {
-		return nil, err
-	}
-	f, err := Execute(context.Background(), op)
-	if err != nil {
-		return nil, err
-	}
-	// Propagate the plan-proved PartitionMetadata claim onto the
-	// returned Frame so downstream `frame.Lazy()` chains inherit the
-	// alignment context. Without this, Over / GroupBy / Join on the
-	// re-lifted LazyFrame fall through to the general (unaligned)
-	// path even though the plan already proved contiguity —
-	// silently pessimizing hot paths across a Collect boundary.
-	// CollectRaw's collectPlan already does the same (see
-	// PartitionMetadata's docstring); this brings Collect into
-	// agreement.
-	//
-	// Callers who want to strip the claim can call
-	// f.WithPartitionMeta(nil) — cheap opt-out for the rare case
-	// where the caller mutates the frame in ways that invalidate
-	// the claim before re-lifting.
-	if f != nil {
-		if meta := plan.PartitionMetadata(); meta != nil {
-			f.WithPartitionMeta(meta)
-		}
-	}
-	return f, nil
+	return lf.CollectWith(nil)
+}
+
+// Stream runs lf like Collect — default optimizer, compiled
+// operator tree — but yields the result batch by batch as operators
+// produce it, instead of concatenating everything into one Frame:
+//
+//	for batch, err := range lf.Stream(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		if err := sink(batch); err != nil {
+//			return err
+//		}
+//	}
+//
+// Streaming operators (scan, Filter, Project, WithColumn, Explode,
+// the left side of an Inner / Left / Semi / Anti Join) pass batches
+// straight through, so a
+// filtered, projected or joined pipeline over a ScanFile source feeds
+// an unbounded consumer in bounded memory; a FillForward fill in
+// their expressions carries its last value across batches. Blocking
+// operators (Sort, Aggregate, Tail, window functions, FillBackward,
+// FillMean, Interpolate) still buffer their input and emit once their
+// input is exhausted.
+//
+// Each yielded Frame holds its own references and stays valid after
+// the loop advances; Release it when done to return memory promptly.
+// Batches of zero rows are skipped, so an empty result yields
+// nothing. Compile and execution errors are yielded once as
+// (nil, err), after which the sequence ends. Cancelling ctx stops the
+// pipeline at the next batch boundary and yields ctx.Err(); breaking
+// out of the loop stops it too. Either way the operator tree — open
+// files, scan goroutines — is closed before the loop returns.
+//
+// The sequence is re-runnable: every range over it compiles and
+// executes the plan afresh.
+func (lf *LazyFrame) Stream(ctx context.Context) iter.Seq2[*Frame, error] {
+	return lf.StreamWith(ctx, nil)
 }
 
 // CollectRaw skips both the optimizer AND the streaming executor,
@@ -405,6 +477,19 @@ func collectPlanRaw(p LogicalPlan) (*Frame, error) {
 			return nil, err
 		}
 		return f.SortBy(n.keys...)
+	case *topKNode:
+		f, err := collectPlan(n.input)
+		if err != nil {
+			return nil, err
+		}
+		sorted, err := f.SortBy(n.keys...)
+		if err != nil {
+			return nil, err
+		}
+		if n.n <= 0 {
+			return sorted.take(nil)
+		}
+		return sorted.Head(n.n), nil
 	case *aggregateNode:
 		f, err := collectPlan(n.input)
 		if err != nil {
@@ -424,7 +509,28 @@ func collectPlanRaw(p LogicalPlan) (*Frame, error) {
 		if err != nil {
 			return nil, err
 		}
-		return left.Join(right, n.leftKey, n.rightKey, n.kind)
+		return left.JoinOn(right, n.leftKeys, n.rightKeys, n.kind)
+	case *asofJoinNode:
+		left, err := collectPlan(n.input)
+		if err != nil {
+			return nil, err
+		}
+		right, err := collectPlan(n.right)
+		if err != nil {
+			return nil, err
+		}
+		opts := n.opts
+		return left.JoinAsof(right, n.leftOn, n.rightOn, n.by, &opts)
+	case *sjoinNode:
+		left, err := collectPlan(n.input)
+		if err != nil {
+			return nil, err
+		}
+		right, err := collectPlan(n.right)
+		if err != nil {
+			return nil, err
+		}
+		return left.SJoin(right, n.leftGeom, n.rightGeom, n.pred, n.opts...)
 	case *dropNode:
 		f, err := collectPlan(n.input)
 		if err != nil {
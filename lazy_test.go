@@ -1,9 +1,11 @@
 package gobi
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -586,6 +588,120 @@ func TestLazy_MixedPipeline(t *testing.T) {
 	}
 }
 
+// -- Stream ----------------------------------------------------------------
+
+// TestLazy_Stream_MatchesCollect streams a filtered, projected plan
+// over a multi-batch frame and checks the batches add up to Collect.
+func TestLazy_Stream_MatchesCollect(t *testing.T) {
+	type row struct {
+		ID int64 `gobi:"id"`
+		V  int64 `gobi:"v"`
+	}
+	rows := make([]row, 3*defaultBatchRows+10)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), V: int64(i % 10)}
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := df.Lazy().Filter(Col("v").Lt(Lit(int64(3)))).SelectCols("id")
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 { // re-runnable: each range executes afresh
+		batches, total := 0, 0
+		for batch, err := range lf.Stream(context.Background()) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if batch.NumCols() != 1 || batch.Schema().Field(0).Name != "id" {
+				t.Fatalf("batch schema = %v", batch.Schema())
+			}
+			batches++
+			total += batch.NumRows()
+			batch.Release()
+		}
+		if batches < 2 || total != want.NumRows() {
+			t.Fatalf("Stream: %d batches, %d rows; Collect has %d rows", batches, total, want.NumRows())
+		}
+	}
+}
+
+// TestLazy_Stream_StopsSource feeds an endless streaming scan: the
+// consumer decides when to stop, and breaking out of the loop or
+// cancelling ctx must shut the source down.
+func TestLazy_Stream_StopsSource(t *testing.T) {
+	frame := lazyFrame(t)
+	endless := func(done chan struct{}) *LazyFrame {
+		return NewLazyFrame(NewScanNode("Scan[test](endless)", frame.Schema(),
+			func() (*Frame, error) { return nil, errors.New("read called") },
+			WithStreamRead(func(cb func(*Frame) error) error {
+				defer close(done)
+				for {
+					if err := cb(frame); err != nil {
+						return err
+					}
+				}
+			}),
+		)).Filter(Col("active"))
+	}
+	waitClosed := func(done chan struct{}) {
+		t.Helper()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("source still running after the stream ended")
+		}
+	}
+
+	done := make(chan struct{})
+	n := 0
+	for batch, err := range endless(done).Stream(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n += batch.NumRows()
+		if n >= 30 {
+			break
+		}
+	}
+	waitClosed(done)
+
+	done = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var last error
+	for _, err := range endless(done).Stream(ctx) {
+		if err != nil {
+			last = err
+			continue
+		}
+		cancel()
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Fatalf("after cancel: err = %v, want context.Canceled", last)
+	}
+	waitClosed(done)
+}
+
+func TestLazy_Stream_Error(t *testing.T) {
+	calls := 0
+	lf := NewLazyFrame(NewScanNode("Scan[test](bad)", lazyFrame(t).Schema(),
+		func() (*Frame, error) { return nil, errors.New("boom") }))
+	for f, err := range lf.Stream(context.Background()) {
+		calls++
+		if f != nil || err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("want one (nil, boom), got (%v, %v)", f, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("error yielded %d times, want 1", calls)
+	}
+}
+
 // -- Test helpers ---------------------------------------------------------
 
 // lazyRegions builds a small right-side lookup for join tests:
@@ -1,6 +1,12 @@
 package gobi
 
-import "sort"
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
 
 // Rule is a single plan-tree rewrite. A rule is a pure function from
 // a plan to an equivalent plan, potentially cheaper to execute. The
@@ -28,6 +34,14 @@ func DefaultRules() []Rule {
 		&combineFiltersRule{},
 		&pushFilterBelowProjectRule{},
 		&pushFilterBelowSortRule{},
+		&pushFilterBelowRenameRule{},
+		&pushFilterBelowCSERule{},
+		&pushFilterBelowDropRule{},
+		&pushFilterBelowJoinRule{},
+		&pushFilterBelowAggregateRule{},
+		&fuseTopKRule{},
+		&eliminateDeadColumnsRule{},
+		&commonSubexpressionRule{},
 		&projectionPushdownRule{},
 		&pushPredicateToScanRule{},
 		&cascadeEmptyRule{},
@@ -122,6 +136,12 @@ func mapExprs(p LogicalPlan, fn func(Expr) Expr) LogicalPlan {
 			return p
 		}
 		return &sortNode{input: newInput, keys: n.keys}
+	case *topKNode:
+		newInput = mapExprs(n.input, fn)
+		if newInput == n.input {
+			return p
+		}
+		return &topKNode{input: newInput, keys: n.keys, n: n.n}
 	case *aggregateNode:
 		newInput = mapExprs(n.input, fn)
 		if newInput == n.input {
@@ -134,7 +154,21 @@ func mapExprs(p LogicalPlan, fn func(Expr) Expr) LogicalPlan {
 		if newInput == n.input && newRight == n.right {
 			return p
 		}
-		return newJoinNode(newInput, newRight, n.leftKey, n.rightKey, n.kind)
+		return newJoinNodeOn(newInput, newRight, n.leftKeys, n.rightKeys, n.kind)
+	case *asofJoinNode:
+		newInput = mapExprs(n.input, fn)
+		newRight = mapExprs(n.right, fn)
+		if newInput == n.input && newRight == n.right {
+			return p
+		}
+		return newAsofJoinNode(newInput, newRight, n.leftOn, n.rightOn, n.by, n.opts)
+	case *sjoinNode:
+		newInput = mapExprs(n.input, fn)
+		newRight = mapExprs(n.right, fn)
+		if newInput == n.input && newRight == n.right {
+			return p
+		}
+		return newSJoinNode(newInput, newRight, n.leftGeom, n.rightGeom, n.pred, n.kind, n.opts)
 	case *limitNode:
 		newInput = mapExprs(n.input, fn)
 		if newInput == n.input {
@@ -195,8 +229,17 @@ func collectRefs(e Expr, out map[string]struct{}) {
 	if e.node == nil {
 		return
 	}
-	if cr, ok := e.node.(*colRefNode); ok {
-		out[cr.name] = struct{}{}
+	switch n := e.node.(type) {
+	case *colRefNode:
+		out[n.name] = struct{}{}
+	case *overNode:
+		// Over reads its partition and order-by columns by name.
+		for _, c := range n.partitionCols {
+			out[c] = struct{}{}
+		}
+		for _, k := range n.orderBy {
+			out[k.Column] = struct{}{}
+		}
 	}
 	for _, c := range e.node.Children() {
 		collectRefs(c, out)
@@ -264,6 +307,22 @@ func foldExpr(e Expr) (Expr, bool) {
 			return inner.Alias(n.name), true
 		}
 		return e, false
+	case *mathNode:
+		folded := &mathNode{kind: n.kind, args: make([]ExprNode, len(n.args)), digits: n.digits}
+		changed := false
+		for i, a := range n.args {
+			arg, ac := foldExpr(Expr{node: a})
+			folded.args[i] = arg.node
+			changed = changed || ac
+		}
+		// abs(lit(-2)) → lit(2), round(lit(3.14159), 2) → lit(3.14)
+		if lit, did := foldMath(folded); did {
+			return lit, true
+		}
+		if changed {
+			return Expr{node: folded}, true
+		}
+		return e, false
 	}
 	return e, false
 }
@@ -542,14 +601,340 @@ func (r *pushFilterBelowSortRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
 	})
 }
 
+// -----------------------------------------------------------------------------
+// Rule: PushFilterBelowRename
+//
+//   Filter(Rename(x, old → new), pred)  →  Rename(Filter(x, pred'), old → new)
+//
+// pred' is pred with every reference to new read as old — Col refs
+// and Over's partition / order-by columns alike. Rename doesn't touch
+// rows, so any predicate can go under; it's skipped only when the
+// rename is ambiguous (old missing, new already in x), when pred names
+// old (an error above the Rename that would silently resolve below
+// it), or when pred holds a Custom node whose references can't be
+// rewritten.
+// -----------------------------------------------------------------------------
+
+type pushFilterBelowRenameRule struct{}
+
+func (pushFilterBelowRenameRule) Name() string { return "PushFilterBelowRename" }
+func (r *pushFilterBelowRenameRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		f, ok := node.(*filterNode)
+		if !ok {
+			return node, false
+		}
+		rn, ok := f.input.(*renameNode)
+		if !ok || rn.old == rn.new {
+			return node, false
+		}
+		inSchema := rn.input.Schema()
+		if !inSchema.HasField(rn.old) || inSchema.HasField(rn.new) {
+			return node, false
+		}
+		if _, ok := referencedColumns(f.cond)[rn.old]; ok {
+			return node, false
+		}
+		cond, ok := renameColumnRefs(f.cond, map[string]string{rn.new: rn.old})
+		if !ok {
+			return node, false
+		}
+		return newRenameNode(&filterNode{input: rn.input, cond: cond}, rn.old, rn.new), true
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Rule: PushFilterBelowCSE
+//
+//   Filter(WithColumn(x, "__cse_N", e), pred)  →  WithColumn(Filter(x, pred), "__cse_N", e)
+//
+// Moves a Filter under the temporary columns CommonSubexpressions
+// inserts, so a predicate that used to reach the scan still does.
+// Safe when pred doesn't read the temporary and e is row-local
+// (isRowLocal) — a window or Over computed after the filter would see
+// different neighbours. The user's own WithColumns keep their place
+// relative to filters; the compiler fuses those into one per-batch
+// pass anyway.
+// -----------------------------------------------------------------------------
+
+type pushFilterBelowCSERule struct{}
+
+func (pushFilterBelowCSERule) Name() string { return "PushFilterBelowCSE" }
+func (r *pushFilterBelowCSERule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		f, ok := node.(*filterNode)
+		if !ok {
+			return node, false
+		}
+		wc, ok := f.input.(*withColumnNode)
+		if !ok || !strings.HasPrefix(wc.name, cseTempPrefix) || !isRowLocal(wc.expr) {
+			return node, false
+		}
+		if _, ok := referencedColumns(f.cond)[wc.name]; ok {
+			return node, false
+		}
+		return newWithColumnNode(&filterNode{input: wc.input, cond: f.cond}, wc.name, wc.expr), true
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Rule: PushFilterBelowDrop
+//
+//   Filter(Drop(x, name), pred)  →  Drop(Filter(x, pred), name)
+//
+// Always safe when pred doesn't read name (if it does, the Filter is
+// an error above the Drop and stays put so it still is one).
+// -----------------------------------------------------------------------------
+
+type pushFilterBelowDropRule struct{}
+
+func (pushFilterBelowDropRule) Name() string { return "PushFilterBelowDrop" }
+func (r *pushFilterBelowDropRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		f, ok := node.(*filterNode)
+		if !ok {
+			return node, false
+		}
+		d, ok := f.input.(*dropNode)
+		if !ok {
+			return node, false
+		}
+		if _, ok := referencedColumns(f.cond)[d.name]; ok {
+			return node, false
+		}
+		return newDropNode(&filterNode{input: d.input, cond: f.cond}, d.name), true
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Rule: PushFilterBelowJoin
+//
+//   Filter(Join(l, r), a AND b AND c)  →  Filter(Join(Filter(l, a), Filter(r, b')), c)
+//
+// The predicate is split into its AND-ed conjuncts and each one that
+// reads only one side's columns moves to that side — where the join
+// kind allows it:
+//
+//   - a left-only conjunct goes under the left input for Inner, Left,
+//     Semi and Anti: each output row carries its left row's values
+//     unchanged, so filtering before or after keeps the same rows.
+//   - a right-only conjunct goes under the right input for Inner and
+//     Right. For Left (and Full) the unmatched left rows carry null
+//     right columns a pushed filter would never see, so it stays.
+//
+// Right columns renamed with "_right" on a name clash are mapped back
+// to their input names. Key columns count as left columns — for
+// Right and Full joins they're coalesced from both sides, so Full
+// pushes nothing at all. Conjuncts that read no column, aren't
+// row-local, or span both sides stay above the join.
+// -----------------------------------------------------------------------------
+
+type pushFilterBelowJoinRule struct{}
+
+func (pushFilterBelowJoinRule) Name() string { return "PushFilterBelowJoin" }
+func (r *pushFilterBelowJoinRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		f, ok := node.(*filterNode)
+		if !ok {
+			return node, false
+		}
+		j, ok := f.input.(*joinNode)
+		if !ok {
+			return node, false
+		}
+		toLeft := j.kind == JoinInner || j.kind == JoinLeft || j.kind == JoinSemi || j.kind == JoinAnti
+		toRight := j.kind == JoinInner || j.kind == JoinRight
+		if !toLeft && !toRight {
+			return node, false
+		}
+		leftCols, rightCols, ok := joinSideColumns(j)
+		if !ok {
+			return node, false
+		}
+		var above, left, right []Expr
+		for _, c := range splitConjuncts(f.cond) {
+			refs := referencedColumns(c)
+			if len(refs) == 0 || !isRowLocal(c) {
+				above = append(above, c)
+				continue
+			}
+			if toLeft && colSetWithin(refs, leftCols) {
+				left = append(left, c)
+				continue
+			}
+			if toRight && colSetWithin(refs, rightCols) {
+				if rc, ok := renameColumnRefs(c, rightCols); ok {
+					right = append(right, rc)
+					continue
+				}
+			}
+			above = append(above, c)
+		}
+		if len(left) == 0 && len(right) == 0 {
+			return node, false
+		}
+		newLeft, newRight := j.input, j.right
+		if len(left) > 0 {
+			newLeft = &filterNode{input: newLeft, cond: andAll(left)}
+		}
+		if len(right) > 0 {
+			newRight = &filterNode{input: newRight, cond: andAll(right)}
+		}
+		var out LogicalPlan = newJoinNodeOn(newLeft, newRight, j.leftKeys, j.rightKeys, j.kind)
+		if len(above) > 0 {
+			out = &filterNode{input: out, cond: andAll(above)}
+		}
+		return out, true
+	})
+}
+
+// joinSideColumns attributes a join's output columns to its inputs:
+// left is the set of left column names, right maps each right output
+// name (possibly "_right"-suffixed) to its input name. false when the
+// output has duplicate names and attribution would be ambiguous.
+func joinSideColumns(j *joinNode) (left map[string]struct{}, right map[string]string, ok bool) {
+	seen := make(map[string]struct{}, len(j.outSchema.Fields()))
+	for _, f := range j.outSchema.Fields() {
+		if _, dup := seen[f.Name]; dup {
+			return nil, nil, false
+		}
+		seen[f.Name] = struct{}{}
+	}
+	left = make(map[string]struct{})
+	for _, f := range j.input.Schema().Fields() {
+		left[f.Name] = struct{}{}
+	}
+	right = make(map[string]string)
+	if j.kind == JoinSemi || j.kind == JoinAnti {
+		return left, right, true
+	}
+	for _, f := range j.right.Schema().Fields() {
+		if slices.Contains(j.rightKeys, f.Name) {
+			continue
+		}
+		out := f.Name
+		if _, clash := left[f.Name]; clash {
+			out += "_right"
+		}
+		right[out] = f.Name
+	}
+	return left, right, true
+}
+
+// colSetWithin reports whether every name in refs is a key of set.
+func colSetWithin[V any](refs map[string]struct{}, set map[string]V) bool {
+	for c := range refs {
+		if _, ok := set[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
+// Rule: PushFilterBelowAggregate
+//
+//   Filter(Aggregate(x, keys, aggs), a AND b)  →  Filter(Aggregate(Filter(x, a), keys, aggs), b)
+//
+// A conjunct that reads only group-key columns decides the fate of
+// whole groups, and every row of a group has the group's key values —
+// so it can filter the rows before they're aggregated instead of the
+// groups after, and the aggregate does less work. Conjuncts that read
+// an aggregate output (HAVING-style), read nothing, or aren't
+// row-local stay above. A keyless aggregate has nothing to push.
+// -----------------------------------------------------------------------------
+
+type pushFilterBelowAggregateRule struct{}
+
+func (pushFilterBelowAggregateRule) Name() string { return "PushFilterBelowAggregate" }
+func (r *pushFilterBelowAggregateRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		f, ok := node.(*filterNode)
+		if !ok {
+			return node, false
+		}
+		agg, ok := f.input.(*aggregateNode)
+		if !ok || len(agg.keys) == 0 {
+			return node, false
+		}
+		keys := make(map[string]struct{}, len(agg.keys))
+		for _, k := range agg.keys {
+			keys[k] = struct{}{}
+		}
+		// An aggregate aliased to a key name would make the key
+		// reference ambiguous.
+		for _, fld := range agg.outSchema.Fields()[len(agg.keys):] {
+			if _, ok := keys[fld.Name]; ok {
+				return node, false
+			}
+		}
+		var above, below []Expr
+		for _, c := range splitConjuncts(f.cond) {
+			refs := referencedColumns(c)
+			if len(refs) > 0 && isRowLocal(c) && colSetWithin(refs, keys) {
+				below = append(below, c)
+				continue
+			}
+			above = append(above, c)
+		}
+		if len(below) == 0 {
+			return node, false
+		}
+		var out LogicalPlan = newAggregateNode(&filterNode{input: agg.input, cond: andAll(below)}, agg.keys, agg.aggs)
+		if len(above) > 0 {
+			out = &filterNode{input: out, cond: andAll(above)}
+		}
+		return out, true
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Rule: FuseTopK
+//
+//   Limit(Sort(x, keys), n)  →  TopK(x, keys, n)
+//   Limit(TopK(x, keys, m), n)  →  TopK(x, keys, min(n, m))
+//
+// Sort + Limit sorts every row of x to keep n of them. TopK streams x
+// through bounded heaps instead: O(rows · log n) time and n rows of
+// memory per worker rather than all of x. The output is identical —
+// topKExec breaks ties by input position, as the stable sort does.
+//
+// Only fires for n > 0 (Limit(0) is cheaper as it is) and when every
+// key is a type rows can be compared on across batches
+// (canMergeSortKeys); anything else keeps the plain Sort + Limit.
+// -----------------------------------------------------------------------------
+
+type fuseTopKRule struct{}
+
+func (fuseTopKRule) Name() string { return "FuseTopK" }
+func (r *fuseTopKRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		l, ok := node.(*limitNode)
+		if !ok || l.n <= 0 {
+			return node, false
+		}
+		switch in := l.input.(type) {
+		case *sortNode:
+			if !canMergeSortKeys(in.input.Schema(), in.keys) {
+				return node, false
+			}
+			return &topKNode{input: in.input, keys: in.keys, n: l.n}, true
+		case *topKNode:
+			return &topKNode{input: in.input, keys: in.keys, n: min(l.n, in.n)}, true
+		}
+		return node, false
+	})
+}
+
 // -----------------------------------------------------------------------------
 // Rule: ProjectionPushdown
 //
 // Top-down walk that computes "columns needed" at each level and
 // pushes the tightest set down to any ProjectableScan leaf. Handles
 // the schema-shaping operators (Filter, Project, WithColumn, Sort,
-// Aggregate, Drop, Limit, Tail); joins are deliberately skipped for
-// now — left/right column attribution requires a separate walker.
+// Aggregate, Drop, Limit, Tail) and through SJoin to both inputs;
+// key joins are deliberately skipped for now — their coalesced key
+// columns make left/right attribution more involved.
 //
 // Scans that don't implement ProjectableScan are left untouched.
 // The projectFn provided by the source package decides whether to
@@ -648,6 +1033,17 @@ func pushProjection(p LogicalPlan, neededOut map[string]struct{}) (LogicalPlan,
 		}
 		return &sortNode{input: newIn, keys: n.keys}, true
 
+	case *topKNode:
+		child := copyColSet(neededOut)
+		for _, k := range n.keys {
+			child[k.Column] = struct{}{}
+		}
+		newIn, changed := pushProjection(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return &topKNode{input: newIn, keys: n.keys, n: n.n}, true
+
 	case *aggregateNode:
 		// Aggregate reshapes: input needs group keys + agg source cols.
 		child := make(map[string]struct{})
@@ -658,6 +1054,9 @@ func pushProjection(p LogicalPlan, neededOut map[string]struct{}) (LogicalPlan,
 			if a.Column != "" {
 				child[a.Column] = struct{}{}
 			}
+			for c := range referencedColumns(a.Filter) {
+				child[c] = struct{}{}
+			}
 		}
 		newIn, changed := pushProjection(n.input, child)
 		if !changed {
@@ -716,7 +1115,20 @@ func pushProjection(p LogicalPlan, neededOut map[string]struct{}) (LogicalPlan,
 		}
 		return &tailNode{input: newIn, n: n.n}, true
 
-	case *joinNode:
+	case *sjoinNode:
+		// The spatial join's output is attributable column by column
+		// (no key coalescing), so each side gets exactly what
+		// downstream reads from it — the payoff being that a wide
+		// points scan only decodes the columns the plan needs.
+		lNeed, rNeed := sjoinChildColumns(n, neededOut)
+		newIn, lChanged := pushProjection(n.input, lNeed)
+		newRt, rChanged := pushProjection(n.right, rNeed)
+		if !lChanged && !rChanged {
+			return p, false
+		}
+		return newSJoinNode(newIn, newRt, n.leftGeom, n.rightGeom, n.pred, n.kind, n.opts), true
+
+	case *joinNode, *asofJoinNode:
 		// Deliberately don't push through joins — left/right column
 		// attribution is more involved. See the Layer 4 followup.
 		return p, false
@@ -729,6 +1141,46 @@ func pushProjection(p LogicalPlan, neededOut map[string]struct{}) (LogicalPlan,
 	return p, false
 }
 
+// sjoinChildColumns splits the columns needed above a spatial join
+// into what each input must supply. Both geometry columns are always
+// needed. A left column is also kept whenever a needed right column
+// shares its name: dropping it would remove the collision and rename
+// "x_right" back to "x" under the parent's feet.
+func sjoinChildColumns(n *sjoinNode, neededOut map[string]struct{}) (left, right map[string]struct{}) {
+	leftNames := make(map[string]struct{}, len(n.input.Schema().Fields()))
+	for _, f := range n.input.Schema().Fields() {
+		leftNames[f.Name] = struct{}{}
+	}
+	left = map[string]struct{}{n.leftGeom: {}}
+	right = map[string]struct{}{n.rightGeom: {}}
+	for c := range neededOut {
+		if _, ok := leftNames[c]; ok {
+			left[c] = struct{}{}
+		}
+	}
+	if n.kind != JoinSemi && n.kind != JoinAnti {
+		keepsRightGeom := n.kind == JoinRight || n.kind == JoinFull
+		for _, f := range n.right.Schema().Fields() {
+			if f.Name == n.rightGeom && !keepsRightGeom {
+				continue
+			}
+			out := f.Name
+			_, clash := leftNames[f.Name]
+			if clash {
+				out += "_right"
+			}
+			if _, ok := neededOut[out]; !ok {
+				continue
+			}
+			right[f.Name] = struct{}{}
+			if clash {
+				left[f.Name] = struct{}{}
+			}
+		}
+	}
+	return left, right
+}
+
 // samePlanIdentity reports whether a and b are the same LogicalPlan
 // pointer. Used by rules that treat "returned self" as "no change."
 func samePlanIdentity(a, b LogicalPlan) bool { return a == b }
@@ -758,6 +1210,199 @@ func setToSortedSlice(s map[string]struct{}) []string {
 	return out
 }
 
+// -----------------------------------------------------------------------------
+// Rule: EliminateDeadColumns
+//
+//   Select(WithColumn(x, "tmp", e), a)  →  Select(x, a)
+//   Drop(WithColumn(x, "tmp", e), "tmp")  →  x
+//
+// A top-down "columns needed" walk like ProjectionPushdown's, but
+// aimed at the plan's own computations: a WithColumn whose column
+// nothing above reads — a later Select leaves it out, a Drop removes
+// it — is deleted along with the work of evaluating e. A Drop whose
+// column no longer exists once that happens goes too. When the
+// WithColumn overwrote an existing column, the Drop stays and removes
+// the original instead.
+//
+// Joins are walked, not pruned through: each input keeps its whole
+// schema as needed, so dead columns are only found within a side.
+// -----------------------------------------------------------------------------
+
+type eliminateDeadColumnsRule struct{}
+
+func (eliminateDeadColumnsRule) Name() string { return "EliminateDeadColumns" }
+func (r *eliminateDeadColumnsRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return pruneDeadColumns(p, schemaColSet(p.Schema()))
+}
+
+// pruneDeadColumns drops the WithColumns under p whose output isn't in
+// neededOut or read on the way up to it.
+func pruneDeadColumns(p LogicalPlan, neededOut map[string]struct{}) (LogicalPlan, bool) {
+	switch n := p.(type) {
+	case *withColumnNode:
+		if _, ok := neededOut[n.name]; !ok {
+			newIn, _ := pruneDeadColumns(n.input, neededOut)
+			return newIn, true
+		}
+		child := copyColSet(neededOut)
+		delete(child, n.name)
+		for c := range referencedColumns(n.expr) {
+			child[c] = struct{}{}
+		}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return newWithColumnNode(newIn, n.name, n.expr), true
+
+	case *dropNode:
+		newIn, changed := pruneDeadColumns(n.input, neededOut)
+		if !changed {
+			return p, false
+		}
+		if !newIn.Schema().HasField(n.name) {
+			return newIn, true
+		}
+		return newDropNode(newIn, n.name), true
+
+	case *filterNode:
+		newIn, changed := pruneDeadColumns(n.input, unionColSet(neededOut, referencedColumns(n.cond)))
+		if !changed {
+			return p, false
+		}
+		return &filterNode{input: newIn, cond: n.cond}, true
+
+	case *projectNode:
+		child := make(map[string]struct{})
+		for _, e := range n.exprs {
+			for c := range referencedColumns(e) {
+				child[c] = struct{}{}
+			}
+		}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return newProjectNode(newIn, n.exprs), true
+
+	case *sortNode:
+		child := copyColSet(neededOut)
+		for _, k := range n.keys {
+			child[k.Column] = struct{}{}
+		}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return &sortNode{input: newIn, keys: n.keys}, true
+
+	case *topKNode:
+		child := copyColSet(neededOut)
+		for _, k := range n.keys {
+			child[k.Column] = struct{}{}
+		}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return &topKNode{input: newIn, keys: n.keys, n: n.n}, true
+
+	case *aggregateNode:
+		child := make(map[string]struct{})
+		for _, k := range n.keys {
+			child[k] = struct{}{}
+		}
+		for _, a := range n.aggs {
+			if a.Column != "" {
+				child[a.Column] = struct{}{}
+			}
+			for c := range referencedColumns(a.Filter) {
+				child[c] = struct{}{}
+			}
+		}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return newAggregateNode(newIn, n.keys, n.aggs), true
+
+	case *renameNode:
+		// The input must still have old for the Rename to apply, so
+		// it's needed whether or not new is.
+		child := copyColSet(neededOut)
+		delete(child, n.new)
+		child[n.old] = struct{}{}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return newRenameNode(newIn, n.old, n.new), true
+
+	case *explodeNode:
+		child := copyColSet(neededOut)
+		child[n.name] = struct{}{}
+		newIn, changed := pruneDeadColumns(n.input, child)
+		if !changed {
+			return p, false
+		}
+		return newExplodeNode(newIn, n.name), true
+
+	case *limitNode:
+		newIn, changed := pruneDeadColumns(n.input, neededOut)
+		if !changed {
+			return p, false
+		}
+		return &limitNode{input: newIn, n: n.n}, true
+
+	case *tailNode:
+		newIn, changed := pruneDeadColumns(n.input, neededOut)
+		if !changed {
+			return p, false
+		}
+		return &tailNode{input: newIn, n: n.n}, true
+
+	case *partitionAssertionNode:
+		newIn, changed := pruneDeadColumns(n.input, schemaColSet(n.input.Schema()))
+		if !changed {
+			return p, false
+		}
+		return &partitionAssertionNode{input: newIn, assertion: n.assertion}, true
+
+	case *joinNode:
+		newIn, lc := pruneDeadColumns(n.input, schemaColSet(n.input.Schema()))
+		newRt, rc := pruneDeadColumns(n.right, schemaColSet(n.right.Schema()))
+		if !lc && !rc {
+			return p, false
+		}
+		return newJoinNodeOn(newIn, newRt, n.leftKeys, n.rightKeys, n.kind), true
+
+	case *asofJoinNode:
+		newIn, lc := pruneDeadColumns(n.input, schemaColSet(n.input.Schema()))
+		newRt, rc := pruneDeadColumns(n.right, schemaColSet(n.right.Schema()))
+		if !lc && !rc {
+			return p, false
+		}
+		return newAsofJoinNode(newIn, newRt, n.leftOn, n.rightOn, n.by, n.opts), true
+
+	case *sjoinNode:
+		newIn, lc := pruneDeadColumns(n.input, schemaColSet(n.input.Schema()))
+		newRt, rc := pruneDeadColumns(n.right, schemaColSet(n.right.Schema()))
+		if !lc && !rc {
+			return p, false
+		}
+		return newSJoinNode(newIn, newRt, n.leftGeom, n.rightGeom, n.pred, n.kind, n.opts), true
+	}
+	return p, false
+}
+
+func schemaColSet(s *arrow.Schema) map[string]struct{} {
+	out := make(map[string]struct{}, len(s.Fields()))
+	for _, f := range s.Fields() {
+		out[f.Name] = struct{}{}
+	}
+	return out
+}
+
 // -----------------------------------------------------------------------------
 // Rule: PushPredicateToScan
 //
@@ -842,6 +1487,10 @@ func (r *cascadeEmptyRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
 			if _, ok := n.input.(*emptyNode); ok {
 				return &emptyNode{schema: n.Schema()}, true
 			}
+		case *topKNode:
+			if _, ok := n.input.(*emptyNode); ok {
+				return &emptyNode{schema: n.Schema()}, true
+			}
 		case *tailNode:
 			if _, ok := n.input.(*emptyNode); ok {
 				return &emptyNode{schema: n.Schema()}, true
@@ -870,6 +1519,32 @@ func (r *cascadeEmptyRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
 			if _, ok := n.input.(*emptyNode); ok {
 				return &emptyNode{schema: n.Schema()}, true
 			}
+		case *asofJoinNode:
+			// One output row per left row: empty left → empty. An
+			// empty right side still yields every left row (with
+			// null right columns), so it doesn't cascade.
+			if _, ok := n.input.(*emptyNode); ok {
+				return &emptyNode{schema: n.Schema()}, true
+			}
+		case *sjoinNode:
+			// Same truth table as joinNode, with the geometry
+			// predicate in place of key equality.
+			_, leftEmpty := n.input.(*emptyNode)
+			_, rightEmpty := n.right.(*emptyNode)
+			empty := false
+			switch n.kind {
+			case JoinInner, JoinSemi:
+				empty = leftEmpty || rightEmpty
+			case JoinLeft, JoinAnti:
+				empty = leftEmpty
+			case JoinRight:
+				empty = rightEmpty
+			case JoinFull:
+				empty = leftEmpty && rightEmpty
+			}
+			if empty {
+				return &emptyNode{schema: n.Schema()}, true
+			}
 		case *joinNode:
 			_, leftEmpty := n.input.(*emptyNode)
 			_, rightEmpty := n.right.(*emptyNode)
@@ -940,6 +1615,11 @@ func walkRewrite(p LogicalPlan, visit func(LogicalPlan) (LogicalPlan, bool)) (Lo
 		if newIn != n.input {
 			rebuilt = &sortNode{input: newIn, keys: n.keys}
 		}
+	case *topKNode:
+		newIn := rewriteChild(n.input)
+		if newIn != n.input {
+			rebuilt = &topKNode{input: newIn, keys: n.keys, n: n.n}
+		}
 	case *aggregateNode:
 		newIn := rewriteChild(n.input)
 		if newIn != n.input {
@@ -949,7 +1629,19 @@ func walkRewrite(p LogicalPlan, visit func(LogicalPlan) (LogicalPlan, bool)) (Lo
 		newIn := rewriteChild(n.input)
 		newRt := rewriteChild(n.right)
 		if newIn != n.input || newRt != n.right {
-			rebuilt = newJoinNode(newIn, newRt, n.leftKey, n.rightKey, n.kind)
+			rebuilt = newJoinNodeOn(newIn, newRt, n.leftKeys, n.rightKeys, n.kind)
+		}
+	case *asofJoinNode:
+		newIn := rewriteChild(n.input)
+		newRt := rewriteChild(n.right)
+		if newIn != n.input || newRt != n.right {
+			rebuilt = newAsofJoinNode(newIn, newRt, n.leftOn, n.rightOn, n.by, n.opts)
+		}
+	case *sjoinNode:
+		newIn := rewriteChild(n.input)
+		newRt := rewriteChild(n.right)
+		if newIn != n.input || newRt != n.right {
+			rebuilt = newSJoinNode(newIn, newRt, n.leftGeom, n.rightGeom, n.pred, n.kind, n.opts)
 		}
 	case *limitNode:
 		newIn := rewriteChild(n.input)
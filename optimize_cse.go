@@ -0,0 +1,258 @@
+package gobi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// -----------------------------------------------------------------------------
+// Rule: CommonSubexpressions
+//
+//   WithColumn(WithColumn(x, "a", f(S)), "b", g(S))
+//     →  Drop(WithColumn(WithColumn(WithColumn(x, "__cse_0", S), "a", f(col("__cse_0"))), "b", g(col("__cse_0"))), "__cse_0")
+//
+// An expression subtree S that appears more than once — within one
+// expression or across a Project's list or a run of WithColumns — is
+// evaluated once into a hidden temporary column below them, and every
+// occurrence reads that column instead. The typical win is a window
+// fed to several computations, e.g. HaversineExpr over
+// Col("lat").Shift(1).Over("eid") and its lon twin, where each
+// repeat would otherwise re-partition and re-evaluate the window.
+// A Project picks temporaries up from its input and simply doesn't
+// select them; a WithColumn run Drops them again on top, so the
+// output schema is unchanged either way. Outputs that took their
+// name from the replaced subtree get an Alias back to it.
+//
+// Subtrees are the same when they print the same and are
+// reflect.DeepEqual — every parameter, literal type and all. What is
+// never hoisted:
+//
+//   - leaves and bare Aliases (nothing to save);
+//   - subtrees that read no column, or read a column a WithColumn in
+//     the same run defines (the value differs above and below it);
+//   - the inside of an Over, which is evaluated per partition;
+//   - Custom nodes and anything under them, which can't be compared
+//     or rebuilt.
+//
+// Largest subtrees go first, so S inside a repeated f(S) is only
+// hoisted if it also repeats elsewhere. Filters are left alone: a
+// hoisted column between the predicate and the scan would stop
+// PushPredicateToScan from pruning row groups with it. For the same
+// reason PushFilterBelowCSE lets a Filter from above sink past the
+// temporaries.
+// -----------------------------------------------------------------------------
+
+type commonSubexpressionRule struct{}
+
+func (commonSubexpressionRule) Name() string { return "CommonSubexpressions" }
+func (r *commonSubexpressionRule) Apply(p LogicalPlan) (LogicalPlan, bool) {
+	return walkRewrite(p, func(node LogicalPlan) (LogicalPlan, bool) {
+		switch n := node.(type) {
+		case *projectNode:
+			return cseProject(n)
+		case *withColumnNode:
+			return cseWithColumns(n)
+		}
+		return node, false
+	})
+}
+
+// cseDef is one hoisted subtree and the temporary column it becomes.
+type cseDef struct {
+	name string
+	expr Expr
+}
+
+func cseProject(n *projectNode) (LogicalPlan, bool) {
+	exprs, defs := hoistCommonSubexprs(n.exprs, nil, schemaColSet(n.input.Schema()))
+	if len(defs) == 0 {
+		return n, false
+	}
+	for i := range exprs {
+		if name := exprOutputName(n.exprs[i], i); exprOutputName(exprs[i], i) != name {
+			exprs[i] = exprs[i].Alias(name)
+		}
+	}
+	return newProjectNode(withCSEDefs(n.input, defs), exprs), true
+}
+
+// cseWithColumns treats top and the WithColumns directly under it as
+// one run over a common base input.
+func cseWithColumns(top *withColumnNode) (LogicalPlan, bool) {
+	var chain []*withColumnNode // top first
+	var base LogicalPlan = top
+	for {
+		w, ok := base.(*withColumnNode)
+		if !ok {
+			break
+		}
+		chain = append(chain, w)
+		base = w.input
+	}
+	defined := make(map[string]struct{}, len(chain))
+	exprs := make([]Expr, len(chain))
+	for i, w := range chain {
+		defined[w.name] = struct{}{}
+		exprs[i] = w.expr
+	}
+	taken := unionColSet(schemaColSet(base.Schema()), defined)
+	exprs, defs := hoistCommonSubexprs(exprs, defined, taken)
+	if len(defs) == 0 {
+		return top, false
+	}
+	out := withCSEDefs(base, defs)
+	for i := len(chain) - 1; i >= 0; i-- {
+		out = newWithColumnNode(out, chain[i].name, exprs[i])
+	}
+	for _, d := range defs {
+		out = newDropNode(out, d.name)
+	}
+	return out, true
+}
+
+// withCSEDefs stacks the temporaries on input. A later def may read
+// an earlier-hoisted, smaller one, never the reverse, so they go in
+// reverse hoisting order.
+func withCSEDefs(input LogicalPlan, defs []cseDef) LogicalPlan {
+	for i := len(defs) - 1; i >= 0; i-- {
+		input = newWithColumnNode(input, defs[i].name, defs[i].expr)
+	}
+	return input
+}
+
+// hoistCommonSubexprs repeatedly replaces the largest repeated subtree
+// of exprs with a fresh temporary column until none repeats. blocked
+// holds columns a hoisted subtree may not read; taken the names a
+// temporary may not use. Returns the rewritten exprs and the
+// temporaries in hoisting order.
+func hoistCommonSubexprs(exprs []Expr, blocked, taken map[string]struct{}) ([]Expr, []cseDef) {
+	exprs = append([]Expr(nil), exprs...)
+	blocked = copyColSet(blocked)
+	var defs []cseDef
+	for {
+		all := make([]Expr, 0, len(exprs)+len(defs))
+		all = append(all, exprs...)
+		for _, d := range defs {
+			all = append(all, d.expr)
+		}
+		rep, ok := largestRepeatedSubexpr(all, blocked)
+		if !ok {
+			return exprs, defs
+		}
+		name := freshCSEName(taken)
+		taken[name] = struct{}{}
+		// Later subtrees must not read a temporary: it would have to
+		// be computed before the one that reads it.
+		blocked[name] = struct{}{}
+		replace := func(n ExprNode) (ExprNode, bool) {
+			if sameSubexpr(n, rep) {
+				return Col(name).node, true
+			}
+			if _, ok := n.(*overNode); ok || !isBuiltinExpr(n) {
+				return n, true // not descended into when counting either
+			}
+			return nil, false
+		}
+		for i := range exprs {
+			exprs[i], _ = transformExpr(exprs[i], replace)
+		}
+		for i := range defs {
+			defs[i].expr, _ = transformExpr(defs[i].expr, replace)
+		}
+		defs = append(defs, cseDef{name: name, expr: Expr{node: rep}})
+	}
+}
+
+// largestRepeatedSubexpr finds the biggest hoistable subtree occurring
+// at least twice across exprs; ties go to the first one seen.
+func largestRepeatedSubexpr(exprs []Expr, blocked map[string]struct{}) (ExprNode, bool) {
+	type class struct {
+		rep   ExprNode
+		size  int
+		count int
+	}
+	var classes []*class
+	byString := make(map[string][]*class)
+	var visit func(n ExprNode)
+	visit = func(n ExprNode) {
+		if n == nil || !isBuiltinExpr(n) {
+			return
+		}
+		if cseCandidate(n, blocked) {
+			key := n.String()
+			var c *class
+			for _, cand := range byString[key] {
+				if sameSubexpr(cand.rep, n) {
+					c = cand
+					break
+				}
+			}
+			if c == nil {
+				c = &class{rep: n, size: exprSize(Expr{node: n})}
+				byString[key] = append(byString[key], c)
+				classes = append(classes, c)
+			}
+			c.count++
+		}
+		if _, ok := n.(*overNode); ok {
+			return
+		}
+		for _, child := range n.Children() {
+			visit(child.node)
+		}
+	}
+	for _, e := range exprs {
+		visit(e.node)
+	}
+	var best *class
+	for _, c := range classes {
+		if c.count >= 2 && (best == nil || c.size > best.size) {
+			best = c
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best.rep, true
+}
+
+// cseCandidate reports whether n may be hoisted on its own.
+func cseCandidate(n ExprNode, blocked map[string]struct{}) bool {
+	if len(n.Children()) == 0 {
+		return false
+	}
+	if _, ok := n.(*aliasNode); ok {
+		return false
+	}
+	e := Expr{node: n}
+	if !allBuiltinExpr(e) {
+		return false
+	}
+	refs := referencedColumns(e)
+	if len(refs) == 0 {
+		return false
+	}
+	for c := range refs {
+		if _, ok := blocked[c]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSubexpr(a, b ExprNode) bool {
+	return a == b || (a.String() == b.String() && reflect.DeepEqual(a, b))
+}
+
+// cseTempPrefix starts every temporary column name.
+const cseTempPrefix = "__cse_"
+
+// freshCSEName is the first "__cse_N" not in taken.
+func freshCSEName(taken map[string]struct{}) string {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("%s%d", cseTempPrefix, i)
+		if _, ok := taken[name]; !ok {
+			return name
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package gobi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// fixFrame is a few GPS fixes for three entities, interleaved so
+// each Over partition is spread across the frame.
+func fixFrame(t *testing.T) *Frame {
+	t.Helper()
+	type fix struct {
+		EID int64   `gobi:"eid"`
+		Lat float64 `gobi:"lat"`
+		Lon float64 `gobi:"lon"`
+	}
+	rows := make([]fix, 30)
+	for i := range rows {
+		rows[i] = fix{EID: int64(i % 3), Lat: 40 + float64(i)/100, Lon: -74 - float64(i*i)/1000}
+	}
+	f, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// assertMatchesRaw checks that the optimized and unoptimized runs of lf
+// agree, schema included.
+func assertMatchesRaw(t *testing.T, lf *LazyFrame) *Frame {
+	t.Helper()
+	got, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := lf.CollectRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, w := got.ColumnNames(), want.ColumnNames(); strings.Join(g, ",") != strings.Join(w, ",") {
+		t.Fatalf("columns = %v, want %v", g, w)
+	}
+	assertSameFrame(t, got, want)
+	return got
+}
+
+func TestOptimize_CSE_WithColumnRun(t *testing.T) {
+	prevLat := Col("lat").Shift(1).Over("eid")
+	lf := fixFrame(t).Lazy().
+		WithColumn("dist", HaversineExpr(
+			PointExpr{Lat: prevLat, Lon: Col("lon").Shift(1).Over("eid")},
+			PointExpr{Lat: Col("lat"), Lon: Col("lon")},
+			geometry.UnitKilometers,
+		)).
+		WithColumn("dlat", Col("lat").Sub(prevLat))
+
+	plan := lf.ExplainOptimized()
+	if strings.Count(plan, prevLat.String()) != 1 {
+		t.Fatalf("the shared window is evaluated more than once:\n%s", plan)
+	}
+	if !strings.Contains(plan, `WithColumn("__cse_0"`) || !strings.Contains(plan, `Drop("__cse_0")`) {
+		t.Fatalf("no hoisted temporary:\n%s", plan)
+	}
+	assertMatchesRaw(t, lf)
+}
+
+func TestOptimize_CSE_Select(t *testing.T) {
+	sq := Col("price").Mul(Col("price"))
+	lf := lazyFrame(t).Lazy().Select(
+		Col("id"),
+		sq.Add(Lit(1.0)).Alias("sq1"),
+		sq.Sub(Lit(1.0)).Alias("sq2"),
+		sq, // positional name, kept once it reads the temporary
+	)
+	plan := lf.ExplainOptimized()
+	if strings.Count(plan, sq.String()) != 1 {
+		t.Fatalf("price*price is evaluated more than once:\n%s", plan)
+	}
+	got := assertMatchesRaw(t, lf)
+	if names := strings.Join(got.ColumnNames(), ","); names != "id,sq1,sq2,expr_3" {
+		t.Errorf("columns = %s", names)
+	}
+}
+
+func TestOptimize_CSE_LeavesUnsafeAlone(t *testing.T) {
+	// "b" reads "a", which the run defines: (a + 1) means something
+	// different below the WithColumn that creates a, so nothing is
+	// hoisted even though it repeats.
+	lf := lazyFrame(t).Lazy().
+		WithColumn("a", Col("price").Mul(Lit(2.0))).
+		WithColumn("b", Col("a").Add(Lit(1.0)).Mul(Col("a").Add(Lit(1.0))))
+	if plan := lf.ExplainOptimized(); strings.Contains(plan, "__cse_") {
+		t.Fatalf("hoisted a subtree that reads a column of the run:\n%s", plan)
+	}
+	assertMatchesRaw(t, lf)
+
+	// A custom node can't be compared or rebuilt.
+	custom := Custom(&squareNode{inner: Col("price")})
+	lf = lazyFrame(t).Lazy().Select(custom.Alias("x"), custom.Alias("y"))
+	if plan := lf.ExplainOptimized(); strings.Contains(plan, "__cse_") {
+		t.Fatalf("hoisted a custom node:\n%s", plan)
+	}
+	assertMatchesRaw(t, lf)
+}
+
+func TestOptimize_CSE_FilterStillReachesScan(t *testing.T) {
+	// The Project's temporary sits between the Filter and the scan;
+	// PushFilterBelowCSE has to let the predicate past it.
+	df := lazyFrame(t)
+	fake := &predicateTestScan{schema: df.Schema()}
+	sq := Col("price").Mul(Col("price"))
+	plan := &filterNode{
+		input: newProjectNode(fake, []Expr{Col("price"), sq.Alias("a"), sq.Add(Lit(1.0)).Alias("b")}),
+		cond:  Col("price").Gt(Lit(20.0)),
+	}
+	Optimize(plan)
+	if fake.callCount == 0 {
+		t.Fatal("the predicate never reached the scan")
+	}
+}
@@ -92,6 +92,33 @@ func TestOptimize_FoldConstants_DivByZeroLeft(t *testing.T) {
 	}
 }
 
+func TestOptimize_FoldConstants_MathLiterals(t *testing.T) {
+	cases := []struct {
+		expr Expr
+		want string
+	}{
+		{Lit(-7).Abs(), "lit(7)"},
+		{Lit(-7).Mod(Lit(3)), "lit(-1)"},
+		{Lit(3.14159).Round(2), "lit(3.14)"},
+		{Lit(2).Pow(Lit(10)), "lit(1024)"},
+		// Folded operands feed the outer fold.
+		{Lit(2.0).Mul(Lit(8.0)).Sqrt(), "lit(4)"},
+		// Int64 mod by zero is a runtime null; leave it in the plan.
+		{Lit(1).Mod(Lit(0)), "mod(lit(1), lit(0))"},
+		{Col("x").Add(Lit(1.0).Neg()), `(col("x") + lit(-1))`},
+	}
+	for _, tc := range cases {
+		if got, _ := foldExpr(tc.expr); got.String() != tc.want {
+			t.Errorf("%s → %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+	// Int64 results stay Int64 literals.
+	got, _ := foldExpr(Lit(1234).Round(-2))
+	if lit, ok := got.node.(*literalNode); !ok || lit.value != int64(1200) {
+		t.Fatalf("round(1234, -2) → %#v, want lit(int64 1200)", got.node)
+	}
+}
+
 // -- RemoveTrivialTrueFilter --------------------------------------------
 
 func TestOptimize_RemoveTrivialTrueFilter(t *testing.T) {
@@ -657,3 +684,187 @@ func TestOptimize_ProjectionPushdown_ScanFrameUntouched(t *testing.T) {
 		cur = kids[0]
 	}
 }
+
+// -- EliminateDeadColumns ------------------------------------------------
+
+func TestOptimize_EliminateDeadColumns(t *testing.T) {
+	df := lazyFrame(t)
+
+	// Select leaves "tmp" out, so nothing computes it.
+	lf := df.Lazy().
+		WithColumn("tmp", Col("price").Mul(Lit(2.0))).
+		WithColumn("keep", Col("price").Add(Lit(1.0))).
+		Select(Col("id"), Col("keep"))
+	if plan := lf.ExplainOptimized(); strings.Contains(plan, `"tmp"`) || !strings.Contains(plan, `"keep"`) {
+		t.Fatalf("dead WithColumn survived or live one was removed:\n%s", plan)
+	}
+	assertMatchesRaw(t, lf)
+
+	// WithColumn + Drop of the same new column cancel out entirely.
+	lf = df.Lazy().WithColumn("tmp", Col("price").Mul(Lit(2.0))).DropColumn("tmp")
+	if _, ok := lf.Optimize().(*scanFrameNode); !ok {
+		t.Fatalf("WithColumn+Drop optimized to:\n%s", lf.ExplainOptimized())
+	}
+	assertMatchesRaw(t, lf)
+
+	// Overwriting an existing column: the Drop still removes the
+	// original.
+	lf = df.Lazy().WithColumn("price", Col("price").Mul(Lit(2.0))).DropColumn("price")
+	d, ok := lf.Optimize().(*dropNode)
+	if !ok {
+		t.Fatalf("overwrite+Drop optimized to:\n%s", lf.ExplainOptimized())
+	}
+	if _, ok := d.input.(*scanFrameNode); !ok {
+		t.Fatalf("Drop's input = %T, want the scan", d.input)
+	}
+	assertMatchesRaw(t, lf)
+}
+
+// -- PushFilterBelowRename -----------------------------------------------
+
+func TestOptimize_PushFilterBelowRename(t *testing.T) {
+	lf := lazyFrame(t).Lazy().
+		Rename("price", "cost").
+		Filter(Col("cost").Gt(Lit(20.0)))
+	rn, ok := lf.Optimize().(*renameNode)
+	if !ok {
+		t.Fatalf("root should be Rename after push:\n%s", lf.ExplainOptimized())
+	}
+	f, ok := rn.input.(*filterNode)
+	if !ok {
+		t.Fatalf("Rename's child = %T, want Filter", rn.input)
+	}
+	if want := Col("price").Gt(Lit(20.0)).String(); f.cond.String() != want {
+		t.Errorf("pushed predicate = %s, want %s", f.cond, want)
+	}
+	assertMatchesRaw(t, lf)
+
+	// Naming the old column is an error above the Rename; it must
+	// stay one.
+	bad := lazyFrame(t).Lazy().Rename("price", "cost").Filter(Col("price").Gt(Lit(20.0)))
+	if _, ok := bad.Optimize().(*filterNode); !ok {
+		t.Fatalf("filter on the renamed-away column moved:\n%s", bad.ExplainOptimized())
+	}
+}
+
+// -- PushFilterBelowJoin -------------------------------------------------
+
+func TestOptimize_PushFilterBelowJoin(t *testing.T) {
+	left := spillFrame(t, 2_000)
+	// id and rank clash with the left side and come out as
+	// id_right / rank_right.
+	right := spillFrame(t, 500).Lazy().Select(Col("key").Alias("k2"), Col("id"), Col("rank"))
+	leftPred := Col("value").Gt(Lit(50.0))
+	rightPred := Col("rank_right").Lt(Lit(int64(10_000)))
+	spanning := Col("rank").Lt(Col("rank_right"))
+
+	cases := []struct {
+		name            string
+		kind            JoinType
+		toLeft, toRight bool
+		above           int
+	}{
+		{"inner", JoinInner, true, true, 1},
+		{"left", JoinLeft, true, false, 2},
+		{"right", JoinRight, false, true, 2},
+		{"full", JoinFull, false, false, 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lf := left.Lazy().Join(right, "key", "k2", tc.kind).
+				Filter(leftPred.And(rightPred).And(spanning))
+			plan := lf.Optimize()
+			f, ok := plan.(*filterNode)
+			if !ok {
+				t.Fatalf("root = %T, want the spanning Filter", plan)
+			}
+			if n := len(splitConjuncts(f.cond)); n != tc.above {
+				t.Errorf("%d conjuncts above the join, want %d: %s", n, tc.above, f.cond)
+			}
+			j := f.input.(*joinNode)
+			lf2, leftPushed := firstFilter(j.input)
+			rf, rightPushed := firstFilter(j.right)
+			if leftPushed != tc.toLeft || rightPushed != tc.toRight {
+				t.Fatalf("pushed left=%v right=%v, want %v/%v:\n%s", leftPushed, rightPushed, tc.toLeft, tc.toRight, lf.ExplainOptimized())
+			}
+			if leftPushed && lf2.cond.String() != leftPred.String() {
+				t.Errorf("left predicate = %s, want %s", lf2.cond, leftPred)
+			}
+			// Read through the "_right" suffix as the right input's name.
+			if want := Col("rank").Lt(Lit(int64(10_000))).String(); rightPushed && rf.cond.String() != want {
+				t.Errorf("right predicate = %s, want %s", rf.cond, want)
+			}
+
+			got, err := lf.Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := lf.CollectRaw()
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys := []SortKey{{Column: "id"}, {Column: "id_right"}}
+			if got, err = got.SortBy(keys...); err != nil {
+				t.Fatal(err)
+			}
+			if want, err = want.SortBy(keys...); err != nil {
+				t.Fatal(err)
+			}
+			assertSameFrame(t, got, want)
+		})
+	}
+}
+
+// firstFilter follows p's first inputs down to a Filter, if any; a
+// pushed predicate may have travelled further than the join input.
+func firstFilter(p LogicalPlan) (*filterNode, bool) {
+	for {
+		if f, ok := p.(*filterNode); ok {
+			return f, true
+		}
+		kids := p.Children()
+		if len(kids) == 0 {
+			return nil, false
+		}
+		p = kids[0]
+	}
+}
+
+// -- PushFilterBelowAggregate --------------------------------------------
+
+func TestOptimize_PushFilterBelowAggregate(t *testing.T) {
+	lf := spillFrame(t, 2_000).Lazy().
+		GroupBy("key").
+		Agg(Aggregation{Column: "value", Kind: AggSum, Alias: "total"}).
+		Filter(Col("key").Lt(Lit(int64(50))).And(Col("total").Gt(Lit(500.0))))
+
+	// The HAVING-style conjunct stays above; the key one goes under.
+	f, ok := lf.Optimize().(*filterNode)
+	if !ok || f.cond.String() != Col("total").Gt(Lit(500.0)).String() {
+		t.Fatalf("optimized to:\n%s", lf.ExplainOptimized())
+	}
+	agg := f.input.(*aggregateNode)
+	below, ok := agg.input.(*filterNode)
+	if !ok || below.cond.String() != Col("key").Lt(Lit(int64(50))).String() {
+		t.Fatalf("aggregate input = %s, want the key predicate", agg.input)
+	}
+
+	got, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := lf.CollectRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = got.SortBy(SortKey{Column: "key"}); err != nil {
+		t.Fatal(err)
+	}
+	if want, err = want.SortBy(SortKey{Column: "key"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.NumRows() == 0 {
+		t.Fatal("no groups survive; the test predicate is too strict")
+	}
+	assertSameFrame(t, got, want)
+}
@@ -14,7 +14,7 @@ var globalMaxParallelism atomic.Int64
 // internal options struct in the order the caller supplies them, so later
 // options override earlier ones.
 //
-// Every parallel entry point (currently SJoin; more to come) accepts a
+// Every parallel entry point (SJoin, SJoinNearest; more to come) accepts a
 // trailing `...Option`. Callers that don't care can omit them entirely.
 type Option interface {
 	apply(*options)
@@ -25,6 +25,25 @@ type Option interface {
 // by SetMaxParallelism, which in turn defer to GOMAXPROCS.
 type options struct {
 	workers int // 0 = defer to package default; <0 also treated as unset
+
+	// joinType / joinTypeSet carry WithJoinType. The Set flag is needed
+	// because JoinInner is the zero JoinType, so "unset" and "inner"
+	// can't be told apart otherwise; each join entry point picks its
+	// own default when unset.
+	joinType    JoinType
+	joinTypeSet bool
+
+	distanceCol string // "" = the operation's default column name
+}
+
+// resolveOptions folds opts into an options struct in caller order.
+// resolveWorkers reads the workers field through the same path.
+func resolveOptions(opts ...Option) options {
+	var cfg options
+	for _, o := range opts {
+		o.apply(&cfg)
+	}
+	return cfg
 }
 
 // -- Workers ------------------------------------------------------------
@@ -42,6 +61,27 @@ func (o workersOpt) apply(s *options) { s.workers = o.n }
 //   - Workers(1) forces sequential execution.
 func Workers(n int) Option { return workersOpt{n: n} }
 
+// -- Join type ------------------------------------------------------------
+
+type joinTypeOpt struct{ kind JoinType }
+
+func (o joinTypeOpt) apply(s *options) { s.joinType, s.joinTypeSet = o.kind, true }
+
+// WithJoinType selects the join semantics for option-driven joins that
+// don't take a JoinType argument (SJoin, SJoinNearest). Each operation
+// documents which kinds it accepts and rejects the rest with an error.
+func WithJoinType(kind JoinType) Option { return joinTypeOpt{kind: kind} }
+
+// -- Distance column ------------------------------------------------------
+
+type distanceColOpt struct{ name string }
+
+func (o distanceColOpt) apply(s *options) { s.distanceCol = o.name }
+
+// DistanceColumn renames the distance column an operation appends
+// (SJoinNearest's DefaultNearestDistanceCol).
+func DistanceColumn(name string) Option { return distanceColOpt{name: name} }
+
 // -- Global default -----------------------------------------------------
 
 // SetMaxParallelism sets the default max worker count for every parallel
@@ -69,11 +109,7 @@ func MaxParallelism() int { return int(globalMaxParallelism.Load()) }
 //
 // The result is guaranteed to be >= 1.
 func resolveWorkers(opts ...Option) int {
-	var cfg options
-	for _, o := range opts {
-		o.apply(&cfg)
-	}
-	n := cfg.workers
+	n := resolveOptions(opts...).workers
 	if n <= 0 {
 		n = MaxParallelism()
 	}
@@ -1,6 +1,7 @@
 package parquetio_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/apache/arrow-go/v18/parquet/file"
 	"github.com/apache/arrow-go/v18/parquet/metadata"
 
+	"github.com/zoobst/gobi"
 	"github.com/zoobst/gobi/parquetio"
 )
 
@@ -132,3 +134,95 @@ func TestWriteOptions_BloomFilter_MultipleColumns(t *testing.T) {
 		t.Errorf("value_a got unexpected bloom filter (size=%d)", bf.Size())
 	}
 }
+
+// scatteredIDsFixture writes 400 rows in 4 row-groups of 100 whose
+// uid (int64) and user (string) columns are a permutation of 0..399
+// spread over every group, so each group's min/max spans nearly the
+// whole domain and only a bloom filter can tell the groups apart.
+// Row i (in row-group i/100) holds uid scatteredUID(i).
+func scatteredIDsFixture(t *testing.T, bloom bool) string {
+	t.Helper()
+	type row struct {
+		UID  int64  `gobi:"uid"`
+		User string `gobi:"user"`
+	}
+	rows := make([]row, 400)
+	for i := range rows {
+		uid := scatteredUID(i)
+		rows[i] = row{UID: uid, User: fmt.Sprintf("u%03d", uid)}
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &parquetio.WriteOptions{RowGroupRows: 100}
+	if bloom {
+		opts.BloomFilterColumns = []string{"uid", "user"}
+		opts.BloomFilterFPP = 0.001
+	}
+	path := filepath.Join(t.TempDir(), "scattered.parquet")
+	if err := parquetio.WriteFile(df, path, opts); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func scatteredUID(i int) int64 { return int64(i * 7919 % 400) }
+
+func TestPredicate_BloomFilterPrunesEqAndIsIn(t *testing.T) {
+	path := scatteredIDsFixture(t, true)
+	a, b := scatteredUID(5), scatteredUID(305) // row-groups 0 and 3
+	aUser := fmt.Sprintf("u%03d", a)
+	cases := []struct {
+		name string
+		pred gobi.Expr
+		want int64 // rows read = 100 per surviving row-group
+	}{
+		{"eq_int", gobi.Col("uid").Eq(gobi.Lit(a)), 100},
+		{"eq_string", gobi.Col("user").Eq(gobi.Lit(aUser)), 100},
+		{"eq_literal_left", gobi.Lit(int(a)).Eq(gobi.Col("uid")), 100},
+		{"isin_two_groups", gobi.Col("uid").IsIn([]int64{a, b}), 200},
+		// Inside every group's min/max, so only the filter can rule them out.
+		{"isin_absent", gobi.Col("user").IsIn([]string{"u0005", "u17x"}), 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: tc.pred})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := int64(out.NumRows()); got != tc.want {
+				t.Fatalf("read rows = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPredicate_NoBloomFilterNoEqPruning(t *testing.T) {
+	// Same data without bloom filters: min/max alone can't skip.
+	path := scatteredIDsFixture(t, false)
+	out, err := parquetio.ReadFile(path, &parquetio.ReadOptions{
+		Predicate: gobi.Col("uid").IsIn([]int64{17, 250}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 400 {
+		t.Fatalf("read rows = %d, want 400", out.NumRows())
+	}
+}
+
+func TestScanFile_BloomFilterParallelScan(t *testing.T) {
+	path := scatteredIDsFixture(t, true)
+	for _, workers := range []int{1, 4} {
+		lf := parquetio.ScanFile(path, &parquetio.ReadOptions{ScanWorkers: workers}).
+			Filter(gobi.Col("user").IsIn([]string{"u017", "u250", "u399"}))
+		out, err := lf.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.NumRows() != 3 {
+			t.Fatalf("workers=%d: rows = %d, want 3", workers, out.NumRows())
+		}
+	}
+}
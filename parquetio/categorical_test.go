@@ -0,0 +1,124 @@
+package parquetio_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/parquetio"
+)
+
+// buildStatusFixture writes 300 rows whose status column is "a", "b",
+// "c" in blocks of 100, one block per row-group, as String or — when
+// categorical — as gobi.Categorical.
+func buildStatusFixture(t *testing.T, categorical bool) string {
+	t.Helper()
+	type row struct {
+		Status string `gobi:"status"`
+		ID     int64  `gobi:"id"`
+	}
+	rows := make([]row, 300)
+	for i := range rows {
+		rows[i] = row{Status: string(rune('a' + i/100)), ID: int64(i)}
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if categorical {
+		if df, err = df.WithColumnExpr("status", gobi.Col("status").Cast(gobi.Categorical)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "status.parquet")
+	if err := parquetio.WriteFile(df, path, &parquetio.WriteOptions{RowGroupRows: 100}); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCategorical_RoundTripAndPushdown(t *testing.T) {
+	path := buildStatusFixture(t, true)
+	df, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := df.Column("status")
+	if !status.IsCategorical() {
+		t.Fatalf("status read back as %s, want categorical", status.DataType())
+	}
+	if vals, _ := status.Strings(); vals[0] != "a" || vals[150] != "b" || vals[299] != "c" {
+		t.Fatalf("status values = %q, %q, %q", vals[0], vals[150], vals[299])
+	}
+
+	// Footer statistics still prune on the dictionary column: only
+	// the "b" row-group is read.
+	pruned, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: gobi.Col("status").Eq(gobi.Lit("b"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned.NumRows() != 100 {
+		t.Fatalf("Eq(b) pushdown read %d rows, want 100", pruned.NumRows())
+	}
+
+	out, err := parquetio.ScanFile(path, nil).
+		Filter(gobi.Col("status").Ne(gobi.Lit("a"))).
+		GroupBy("status").
+		Agg(gobi.Aggregation{Kind: gobi.AggCount}).
+		Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := out.Column("status")
+	counts, _ := out.Column("count")
+	k, _ := keys.Strings()
+	n, _ := counts.Int64s()
+	if !keys.IsCategorical() || len(k) != 2 || k[0] != "b" || k[1] != "c" || n[0] != 100 || n[1] != 100 {
+		t.Fatalf("scan GroupBy = %v %v (%s)", k, n, keys.DataType())
+	}
+}
+
+func TestReadOptions_Categorical(t *testing.T) {
+	path := buildStatusFixture(t, false)
+	plain, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := plain.Column("status"); s.DataType().ID() != arrow.STRING {
+		t.Fatalf("plain read: status is %s, want String", s.DataType())
+	}
+
+	opts := &parquetio.ReadOptions{Categorical: []string{"status"}}
+	df, err := parquetio.ReadFile(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := df.Column("status")
+	if !status.IsCategorical() {
+		t.Fatalf("Categorical read: status is %s", status.DataType())
+	}
+	sch, err := parquetio.ReadSchema(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt := sch.Field(0).Type; !arrow.TypeEqual(dt, gobi.Categorical) {
+		t.Fatalf("ReadSchema status = %s, want %s", dt, gobi.Categorical)
+	}
+	scanned, err := parquetio.ScanFile(path, opts).Filter(gobi.Col("status").Eq(gobi.Lit("c"))).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scanned.NumRows() != 100 {
+		t.Fatalf("scan Eq(c) = %d rows, want 100", scanned.NumRows())
+	}
+
+	if _, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Categorical: []string{"nope"}}); !errors.Is(err, parquetio.ErrColumnNotFound) {
+		t.Fatalf("unknown column: err = %v, want ErrColumnNotFound", err)
+	}
+	if _, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Categorical: []string{"id"}}); err == nil {
+		t.Fatal("Categorical on an Int64 column: expected error")
+	}
+}
@@ -0,0 +1,35 @@
+package parquetio_test
+
+import (
+	"testing"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/parquetio"
+)
+
+// TestScanFile_Describe streams Describe over a three-row-group file:
+// the statistics come from one aggregate over the scan, reading only
+// the described column.
+func TestScanFile_Describe(t *testing.T) {
+	path := buildStatusFixture(t, false)
+	out, err := parquetio.ScanFile(path, nil).Describe(&gobi.DescribeOptions{Columns: []string{"id"}, Exact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := func(name string) float64 {
+		s, err := out.Column(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, _ := s.Float64s()
+		if len(v) == 0 {
+			i, _ := s.Int64s()
+			return float64(i[0])
+		}
+		return v[0]
+	}
+	if out.NumRows() != 1 || col("count") != 300 || col("min") != 0 || col("max") != 299 || col("p50") != 149.5 || col("n_unique") != 300 {
+		t.Fatalf("Describe(id) = count %v min %v max %v p50 %v n_unique %v",
+			col("count"), col("min"), col("max"), col("p50"), col("n_unique"))
+	}
+}
@@ -91,7 +91,8 @@ type ReadOptions struct {
 	// Predicate is a hint from the optimizer for row-group skipping.
 	// When set, ReadFile / ReadFileChunksFunc walk each row-group's
 	// footer statistics and skip whole groups whose (min, max) bounds
-	// prove no row could satisfy the predicate. The Filter operation
+	// — or, for Eq / IsIn on a column with a bloom filter, whose
+	// filter — prove no row could satisfy the predicate. The Filter operation
 	// above the read still runs — this is a coarse fast-path that
 	// avoids fetching irrelevant row-groups off disk.
 	//
@@ -125,6 +126,20 @@ type ReadOptions struct {
 	// scan flows through the Layer 6 executor via ScanFile +
 	// LazyFrame.Collect.
 	ScanWorkers int
+
+	// Categorical names String columns to read dictionary-encoded, as
+	// gobi.Categorical, without ever materializing a string per row:
+	// the reader hands Parquet's own dictionary pages through as the
+	// Arrow dictionary. Worth it for low-cardinality columns that feed
+	// GroupBy, Join or equality filters (see gobi.Categorical).
+	//
+	// Only needed for files from other writers. A categorical column
+	// written by WriteFile reads back categorical anyway — the Arrow
+	// schema stored in the file says so.
+	//
+	// Names not in the file return ErrColumnNotFound; a column that
+	// isn't a flat String column is an error too.
+	Categorical []string
 }
 
 // WriteOptions controls parquet write behavior. A nil pointer is
@@ -155,11 +170,12 @@ type WriteOptions struct {
 	// most; skew-free min/max distributions do not — parquet's row-
 	// group statistics already handle those.
 	//
-	// gobi's own reader does not yet consume bloom filters for row-
-	// group skipping (that lands with the query optimizer). Files
-	// produced here are still consumed correctly by DuckDB, Spark,
-	// Polars, and pyarrow, which do use bloom filters for predicate
-	// pushdown on equality filters.
+	// gobi's own reader consults the filters when a Predicate (or a
+	// Filter pushed into ScanFile) tests the column with Eq or IsIn:
+	// a row group is skipped when its filter rules out every value,
+	// even if the values sit inside its min/max range — the common
+	// case for hashed or randomly assigned IDs. DuckDB, Spark,
+	// Polars, and pyarrow use the same filters for their pushdown.
 	BloomFilterColumns []string
 
 	// BloomFilterFPP is the target false-positive probability for
@@ -167,6 +183,29 @@ type WriteOptions struct {
 	// (0.05). Lower FPP → larger filter on disk; reasonable range
 	// 0.01–0.1. Ignored when BloomFilterColumns is empty.
 	BloomFilterFPP float64
+
+	// BboxCovering adds a per-row bounding-box struct column for each
+	// geometry column and declares it as the GeoParquet 1.1
+	// "covering" in the "geo" metadata. The primary geometry column's
+	// covering is named "bbox" (others get "<column>_bbox"), a
+	// struct<xmin, ymin, xmax, ymax: double> that is null where the
+	// geometry is null or empty. Ignored for frames without geometry.
+	//
+	// gobi's readers leave covering columns out of the Frame unless
+	// ReadOptions.Columns names them, so a file round-trips to the
+	// columns that were written; DuckDB, GDAL and geopandas see the
+	// covering as an ordinary column.
+	//
+	// Parquet keeps min/max statistics for each covering leaf, so a
+	// spatial filter such as
+	//
+	//	Col("geometry").Geom().Intersects(gobi.LitGeom(region))
+	//
+	// pushed into ScanFile skips every row group whose extent misses
+	// region's envelope. That only pays off when nearby rows share row
+	// groups — sort the frame spatially (by a grid cell or Hilbert
+	// key) before writing.
+	BboxCovering bool
 }
 
 // ParseCodec resolves a codec by name (case-insensitive). Empty and "none"
@@ -240,6 +279,16 @@ func ReadSchema(path string, opts *ReadOptions) (*arrow.Schema, error) {
 			}
 		}
 		arrowSchema = arrow.NewSchema(projected, schemaMetadataPtr(arrowSchema))
+	} else if hidden := coveringColumns(rc.geoRaw); len(hidden) > 0 {
+		// Mirror resolveColumns: bbox coverings stay out of the
+		// default column set.
+		kept := make([]arrow.Field, 0, len(arrowSchema.Fields()))
+		for _, f := range arrowSchema.Fields() {
+			if !hidden[f.Name] {
+				kept = append(kept, f)
+			}
+		}
+		arrowSchema = arrow.NewSchema(kept, schemaMetadataPtr(arrowSchema))
 	}
 
 	// Attach the "geo" key if the file carried one.
@@ -272,7 +321,7 @@ func ScanFile(path string, opts *ReadOptions) *gobi.LazyFrame {
 	// closure below will surface the same error at Collect time.
 	sch, schemaErr := ReadSchema(path, opts)
 
-	label := buildScanLabel(path, opts)
+	label := buildScanLabel(path, opts, sch)
 
 	node := gobi.NewScanNode(label, sch, func() (*gobi.Frame, error) {
 		if schemaErr != nil {
@@ -316,6 +365,14 @@ func ScanFile(path string, opts *ReadOptions) *gobi.LazyFrame {
 		// Layered atop any existing predicate via AND — a caller-
 		// supplied Predicate stays applied, and the optimizer's
 		// contribution is added on top.
+		//
+		// The rule keeps the Filter above the scan and re-fires every
+		// pass, so a predicate whose conjuncts the scan already holds is
+		// declined (nil = no change); otherwise it would be AND-ed in
+		// again until the optimizer's pass cap.
+		if hasConjunct(opts, pred) {
+			return nil
+		}
 		var newOpts ReadOptions
 		if opts != nil {
 			newOpts = *opts
@@ -330,25 +387,64 @@ func ScanFile(path string, opts *ReadOptions) *gobi.LazyFrame {
 	return gobi.NewLazyFrame(node)
 }
 
+// hasConjunct reports whether every top-level conjunct of pred
+// already appears, by its String form, among those of opts.Predicate.
+func hasConjunct(opts *ReadOptions, pred gobi.Expr) bool {
+	if opts == nil || opts.Predicate.Node() == nil {
+		return false
+	}
+	have := map[string]bool{}
+	for _, c := range gobi.SplitConjuncts(opts.Predicate) {
+		have[c.String()] = true
+	}
+	for _, c := range gobi.SplitConjuncts(pred) {
+		if !have[c.String()] {
+			return false
+		}
+	}
+	return true
+}
+
 // buildScanLabel produces the human-readable Scan[parquet](...) label
 // used in Explain output. Includes column projection and predicate
 // pushdown state so it's obvious from Explain what the scan sees.
-func buildScanLabel(path string, opts *ReadOptions) string {
-	label := fmt.Sprintf("Scan[parquet](%q)", path)
-	if opts == nil {
-		return label
+//
+// When the pushed predicate holds spatial tests the file's bbox
+// coverings can prune, the label lists those geometry columns as
+// bbox_covering=[...] — the marker that row groups will be skipped by
+// extent, not just by scalar min/max.
+func buildScanLabel(path string, opts *ReadOptions, sch *arrow.Schema) string {
+	parts := []string{fmt.Sprintf("%q", path)}
+	if opts != nil && len(opts.Columns) > 0 {
+		parts = append(parts, fmt.Sprintf("cols=%v", opts.Columns))
+	}
+	if opts != nil && opts.Predicate.Node() != nil {
+		parts = append(parts, fmt.Sprintf("pred=%s", opts.Predicate))
+		if covered := coveredSpatialColumns(opts.Predicate, sch); len(covered) > 0 {
+			parts = append(parts, fmt.Sprintf("bbox_covering=%v", covered))
+		}
 	}
-	if len(opts.Columns) > 0 && opts.Predicate.Node() != nil {
-		return fmt.Sprintf("Scan[parquet](%q, cols=%v, pred=%s)",
-			path, opts.Columns, opts.Predicate)
+	return fmt.Sprintf("Scan[parquet](%s)", strings.Join(parts, ", "))
+}
+
+// coveredSpatialColumns intersects the geometry columns pred can prune
+// on with those that have a bbox covering in sch's "geo" metadata.
+func coveredSpatialColumns(pred gobi.Expr, sch *arrow.Schema) []string {
+	if sch == nil {
+		return nil
 	}
-	if len(opts.Columns) > 0 {
-		return fmt.Sprintf("Scan[parquet](%q, cols=%v)", path, opts.Columns)
+	raw, ok := sch.Metadata().GetValue(gobi.GeoParquetMetadataKey)
+	if !ok {
+		return nil
 	}
-	if opts.Predicate.Node() != nil {
-		return fmt.Sprintf("Scan[parquet](%q, pred=%s)", path, opts.Predicate)
+	coverings := bboxCoverings(raw)
+	var out []string
+	for _, col := range gobi.SpatialPruningColumns(pred) {
+		if _, ok := coverings[col]; ok {
+			out = append(out, col)
+		}
 	}
-	return label
+	return out
 }
 
 // readColumns returns opts.Columns, treating a nil *ReadOptions as empty.
@@ -566,11 +662,7 @@ func WriteFile(f *gobi.Frame, path string, opts *WriteOptions) error {
 	if opts == nil {
 		opts = &WriteOptions{}
 	}
-	codec := opts.Codec
-	if codec == "" {
-		codec = CodecSnappy
-	}
-	compression, err := codec.toArrow()
+	props, err := writerProperties(opts, 0)
 	if err != nil {
 		return err
 	}
@@ -578,6 +670,16 @@ func WriteFile(f *gobi.Frame, path string, opts *WriteOptions) error {
 	if err != nil {
 		return err
 	}
+	if opts.BboxCovering {
+		covered, err := gobi.AddGeoParquetBboxCovering(f, meta)
+		if err != nil {
+			return err
+		}
+		if covered != f {
+			defer covered.Release() // holds its own refs on f's columns
+		}
+		f = covered
+	}
 
 	out, err := os.Create(path)
 	if err != nil {
@@ -585,23 +687,10 @@ func WriteFile(f *gobi.Frame, path string, opts *WriteOptions) error {
 	}
 	defer out.Close()
 
-	writerProps := []parquet.WriterProperty{parquet.WithCompression(compression)}
-	if opts.RowGroupRows > 0 {
-		writerProps = append(writerProps, parquet.WithMaxRowGroupLength(opts.RowGroupRows))
-	}
-	if len(opts.BloomFilterColumns) > 0 {
-		if opts.BloomFilterFPP > 0 {
-			writerProps = append(writerProps, parquet.WithBloomFilterFPP(opts.BloomFilterFPP))
-		}
-		for _, col := range opts.BloomFilterColumns {
-			writerProps = append(writerProps, parquet.WithBloomFilterEnabledFor(col, true))
-		}
-	}
-
 	writer, err := pqarrow.NewFileWriter(
-		f.Schema(),
+		dropGeoKey(f.Schema()),
 		out,
-		parquet.NewWriterProperties(writerProps...),
+		props,
 		pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()),
 	)
 	if err != nil {
@@ -633,6 +722,38 @@ func WriteFile(f *gobi.Frame, path string, opts *WriteOptions) error {
 	return writer.Close()
 }
 
+// writerProperties maps opts onto parquet writer properties: page
+// compression (CodecSnappy when unset), the row-group cap and bloom
+// filters. defaultRowGroupRows caps row groups when opts.RowGroupRows
+// is 0; pass 0 to keep parquet-arrow's own default.
+func writerProperties(opts *WriteOptions, defaultRowGroupRows int64) (*parquet.WriterProperties, error) {
+	codec := opts.Codec
+	if codec == "" {
+		codec = CodecSnappy
+	}
+	compression, err := codec.toArrow()
+	if err != nil {
+		return nil, err
+	}
+	writerProps := []parquet.WriterProperty{parquet.WithCompression(compression)}
+	rowGroupRows := opts.RowGroupRows
+	if rowGroupRows <= 0 {
+		rowGroupRows = defaultRowGroupRows
+	}
+	if rowGroupRows > 0 {
+		writerProps = append(writerProps, parquet.WithMaxRowGroupLength(rowGroupRows))
+	}
+	if len(opts.BloomFilterColumns) > 0 {
+		if opts.BloomFilterFPP > 0 {
+			writerProps = append(writerProps, parquet.WithBloomFilterFPP(opts.BloomFilterFPP))
+		}
+		for _, col := range opts.BloomFilterColumns {
+			writerProps = append(writerProps, parquet.WithBloomFilterEnabledFor(col, true))
+		}
+	}
+	return parquet.NewWriterProperties(writerProps...), nil
+}
+
 // -----------------------------------------------------------------------------
 // Shared reader setup
 // -----------------------------------------------------------------------------
@@ -699,25 +820,26 @@ func openReaderFromRS(rs parquet.ReaderAtSeeker, closer io.Closer, opts *ReadOpt
 		return nil, err
 	}
 
-	geoRaw := ""
-	if kv := pf.MetaData().KeyValueMetadata(); kv != nil {
-		if v := kv.FindValue(gobi.GeoParquetMetadataKey); v != nil {
-			geoRaw = *v
-		}
-	}
+	geoRaw := geoMetadataRaw(pf)
 
-	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{
+	props := pqarrow.ArrowReadProperties{
 		Parallel:           true,
 		BatchSize:          chunkRows(opts),
 		PreAllocBinaryData: true,
-	}, pool)
+	}
+	if err := setReadDict(pf, &props, opts.Categorical); err != nil {
+		_ = pf.Close()
+		_ = closer.Close()
+		return nil, err
+	}
+	fr, err := pqarrow.NewFileReader(pf, props, pool)
 	if err != nil {
 		_ = pf.Close()
 		_ = closer.Close()
 		return nil, err
 	}
 
-	colIndices, err := resolveColumns(pf, fr, opts.Columns)
+	colIndices, err := resolveColumns(pf, opts.Columns, coveringColumns(geoRaw))
 	if err != nil {
 		_ = pf.Close()
 		_ = closer.Close()
@@ -748,7 +870,7 @@ func openReaderFromRS(rs parquet.ReaderAtSeeker, closer io.Closer, opts *ReadOpt
 	// causes correctness issues — a false positive (row-group kept
 	// that could have been skipped) just costs a bit of extra I/O.
 	// filterRowGroupsByPredicate handles a nil Predicate as a no-op.
-	rowGroups = filterRowGroupsByPredicate(pf, opts.Predicate, rowGroups)
+	rowGroups = filterRowGroupsByPredicate(pf, opts.Predicate, geoRaw, rowGroups)
 
 	return &readerContext{
 		closer:      closer,
@@ -760,43 +882,78 @@ func openReaderFromRS(rs parquet.ReaderAtSeeker, closer io.Closer, opts *ReadOpt
 	}, nil
 }
 
+// geoMetadataRaw returns the file-level "geo" blob, or "" if absent.
+func geoMetadataRaw(pf *file.Reader) string {
+	if kv := pf.MetaData().KeyValueMetadata(); kv != nil {
+		if v := kv.FindValue(gobi.GeoParquetMetadataKey); v != nil {
+			return *v
+		}
+	}
+	return ""
+}
+
 // resolveColumns maps opts.Columns (names) to leaf-parquet-column indices
 // for GetRecordReader / ReadRowGroups. When names is empty, returns an
 // explicit "all indices" slice — nil would work for GetRecordReader but
 // ReadRowGroups treats nil as "no columns," so we always emit a
 // concrete list to keep both paths symmetric.
 //
-// Parquet flat schemas (all top-level primitives) have arrow-field-index
-// == parquet-leaf-column-index. Nested types would need a different
-// mapping; gobi currently only emits flat schemas.
-func resolveColumns(pf *file.Reader, fr *pqarrow.FileReader, names []string) ([]int, error) {
-	numLeaves := pf.MetaData().Schema.NumColumns()
+// A top-level primitive is one leaf, but a struct column (a bbox
+// covering, say) spans one leaf per field, so each name expands to
+// every leaf under its root. pqarrow orders output fields by first
+// leaf, which keeps the caller's column order.
+//
+// hidden names top-level columns left out when names is empty: the
+// GeoParquet bbox coverings, which duplicate their geometry column
+// and are only consulted through footer statistics. Naming one in
+// names reads it like any other column.
+func resolveColumns(pf *file.Reader, names []string, hidden map[string]bool) ([]int, error) {
+	sch := pf.MetaData().Schema
+	numLeaves := sch.NumColumns()
 	if len(names) == 0 {
-		all := make([]int, numLeaves)
-		for i := range all {
-			all[i] = i
+		all := make([]int, 0, numLeaves)
+		for i := range numLeaves {
+			if !hidden[sch.ColumnRoot(i).Name()] {
+				all = append(all, i)
+			}
 		}
 		return all, nil
 	}
-	arrowSchema, err := fr.Schema()
-	if err != nil {
-		return nil, fmt.Errorf("parquetio: read arrow schema: %w", err)
-	}
-	nameToIdx := make(map[string]int, len(arrowSchema.Fields()))
-	for i, field := range arrowSchema.Fields() {
-		nameToIdx[field.Name] = i
+	leavesByRoot := make(map[string][]int, numLeaves)
+	for i := range numLeaves {
+		root := sch.ColumnRoot(i).Name()
+		leavesByRoot[root] = append(leavesByRoot[root], i)
 	}
 	out := make([]int, 0, len(names))
 	for _, name := range names {
-		idx, ok := nameToIdx[name]
+		leaves, ok := leavesByRoot[name]
 		if !ok {
 			return nil, fmt.Errorf("%w: %q", ErrColumnNotFound, name)
 		}
-		out = append(out, idx)
+		out = append(out, leaves...)
 	}
 	return out, nil
 }
 
+// setReadDict asks the Arrow reader to keep each named column
+// dictionary-encoded (ReadOptions.Categorical).
+func setReadDict(pf *file.Reader, props *pqarrow.ArrowReadProperties, names []string) error {
+	sch := pf.MetaData().Schema
+	for _, name := range names {
+		leaf := sch.ColumnIndexByName(name)
+		if leaf < 0 {
+			return fmt.Errorf("%w: %q", ErrColumnNotFound, name)
+		}
+		col := sch.Column(leaf)
+		if col.PhysicalType() != parquet.Types.ByteArray || col.MaxRepetitionLevel() > 0 {
+			return fmt.Errorf("parquetio: Categorical column %q is %s, want a flat String column",
+				name, col.PhysicalType())
+		}
+		props.SetReadDict(leaf, true)
+	}
+	return nil
+}
+
 func chunkRows(opts *ReadOptions) int64 {
 	if opts != nil && opts.ChunkRows > 0 {
 		return int64(opts.ChunkRows)
@@ -881,3 +1038,23 @@ func attachGeoKey(schema *arrow.Schema, raw string) (*arrow.Schema, error) {
 	md := arrow.NewMetadata(keys, values)
 	return arrow.NewSchema(schema.Fields(), &md), nil
 }
+
+// dropGeoKey returns schema without a "geo" metadata key. A Frame read
+// from a GeoParquet file carries the source's blob in its schema, and
+// pqarrow copies schema metadata into the footer — ahead of the blob
+// the writer appends, where readers would find the stale one first.
+func dropGeoKey(schema *arrow.Schema) *arrow.Schema {
+	if _, ok := schema.Metadata().GetValue(gobi.GeoParquetMetadataKey); !ok {
+		return schema
+	}
+	old := schema.Metadata()
+	var keys, values []string
+	for i, k := range old.Keys() {
+		if k != gobi.GeoParquetMetadataKey {
+			keys = append(keys, k)
+			values = append(values, old.Values()[i])
+		}
+	}
+	md := arrow.NewMetadata(keys, values)
+	return arrow.NewSchema(schema.Fields(), &md)
+}
@@ -1,24 +1,42 @@
 package parquetio
 
 import (
+	"math"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/parquet"
 	"github.com/apache/arrow-go/v18/parquet/file"
 	"github.com/apache/arrow-go/v18/parquet/metadata"
 
 	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geometry"
 )
 
-// rowGroupStats adapts a parquet row-group's footer metadata to
-// gobi.Stats, so gobi.CanPossiblyMatch can evaluate a predicate
-// against min/max/null-count bounds without importing parquet
-// internals into gobi.
+// rowGroupStats adapts a parquet row-group's footer metadata (and,
+// when present, its bloom filters) to gobi.Stats, so
+// gobi.CanPossiblyMatch can evaluate a predicate against
+// min/max/null-count bounds without importing parquet internals into
+// gobi.
 //
 // The name→ordinal map is built once when the reader is opened; per-
 // row-group instances share it via a pointer so predicate-pushdown
 // work is O(#predicates × #row-groups) rather than O(#columns ×
 // #row-groups).
+//
+// coverings maps a geometry column to its GeoParquet bbox covering,
+// which is what lets rowGroupStats satisfy gobi.BoundsStats. blooms
+// is the row group's bloom-filter reader (gobi.MembershipStats),
+// opened lazily on the first probe; filters caches each column's
+// filter so a long IsIn list reads it off disk once.
 type rowGroupStats struct {
-	rg       *metadata.RowGroupMetaData
+	rg        *metadata.RowGroupMetaData
 	colByName map[string]int
+	coverings map[string]gobi.GeoParquetBboxCovering
+
+	pf      *file.Reader
+	rgIdx   int
+	blooms  *metadata.RowGroupBloomFilterReader
+	filters map[int]metadata.BloomFilter
 }
 
 func (s *rowGroupStats) TotalRows() int64 { return s.rg.NumRows() }
@@ -55,6 +73,143 @@ func (s *rowGroupStats) NullCount(col string) (int64, bool) {
 	return stats.NullCount(), true
 }
 
+// MightContain implements gobi.MembershipStats from the column's
+// bloom filter. Columns written without one (see
+// WriteOptions.BloomFilterColumns) report ok=false, as does any value
+// bloomHash can't render in the column's physical encoding.
+func (s *rowGroupStats) MightContain(col string, v any) (bool, bool) {
+	idx, ok := s.colByName[col]
+	if !ok || s.pf == nil {
+		return false, false
+	}
+	bf, ok := s.bloomFilter(idx)
+	if !ok {
+		return false, false
+	}
+	h, ok := bloomHash(bf.Hasher(), s.pf.MetaData().Schema.Column(idx).PhysicalType(), v)
+	if !ok {
+		return false, false
+	}
+	return bf.CheckHash(h), true
+}
+
+// bloomFilter returns column idx's filter in this row group, reading
+// and caching it on first use. A missing filter is cached as nil.
+func (s *rowGroupStats) bloomFilter(idx int) (metadata.BloomFilter, bool) {
+	if bf, seen := s.filters[idx]; seen {
+		return bf, bf != nil
+	}
+	if s.filters == nil {
+		s.filters = map[int]metadata.BloomFilter{}
+	}
+	if s.blooms == nil {
+		rgBF, err := s.pf.GetBloomFilterReader().RowGroup(s.rgIdx)
+		if err != nil {
+			s.filters[idx] = nil
+			return nil, false
+		}
+		s.blooms = rgBF
+	}
+	bf, err := s.blooms.GetColumnBloomFilter(idx)
+	if err != nil {
+		bf = nil
+	}
+	s.filters[idx] = bf
+	return bf, bf != nil
+}
+
+// bloomHash hashes v the way the parquet writer hashed the column's
+// values: over the physical encoding's bytes. Integer literals are
+// narrowed to INT32 when they fit (a value that doesn't fit can't be
+// in the column, but ok=false keeps that call with the min/max
+// check). Unsigned columns share the signed physical types, so a
+// uint64 value hashes as its two's-complement bit pattern; against a
+// signed column that pattern can only produce a false positive.
+// Floats hash by bit pattern, so ±0 — equal under Eq but not bitwise
+// — is left unchecked.
+func bloomHash(h metadata.Hasher, phys parquet.Type, v any) (uint64, bool) {
+	switch phys {
+	case parquet.Types.Int32:
+		switch x := v.(type) {
+		case int32:
+			return metadata.GetHash(h, x), true
+		case int64:
+			if x >= math.MinInt32 && x <= math.MaxInt32 {
+				return metadata.GetHash(h, int32(x)), true
+			}
+		case uint64:
+			if x <= math.MaxUint32 {
+				return metadata.GetHash(h, int32(uint32(x))), true
+			}
+		}
+	case parquet.Types.Int64:
+		switch x := v.(type) {
+		case int32:
+			return metadata.GetHash(h, int64(x)), true
+		case int64:
+			return metadata.GetHash(h, x), true
+		case uint64:
+			return metadata.GetHash(h, int64(x)), true
+		}
+	case parquet.Types.Double:
+		f, ok := v.(float64)
+		if !ok {
+			switch i := v.(type) {
+			case int64:
+				f, ok = float64(i), true
+			case uint64:
+				f, ok = float64(i), true
+			}
+		}
+		if ok && f != 0 && !math.IsNaN(f) {
+			return metadata.GetHash(h, f), true
+		}
+	case parquet.Types.ByteArray:
+		if x, ok := v.(string); ok {
+			return metadata.GetHash(h, parquet.ByteArray(x)), true
+		}
+	}
+	return 0, false
+}
+
+// GeomBounds implements gobi.BoundsStats from the bbox covering of
+// col: the row group's extent is [min xmin, max xmax] × [min ymin,
+// max ymax]. Only the outer bound of each leaf is needed — every
+// geometry's box sits inside it — so a row group of scattered points
+// still prunes well when the file was written in spatial order.
+func (s *rowGroupStats) GeomBounds(col string) (geometry.Bounds, bool) {
+	cov, ok := s.coverings[col]
+	if !ok {
+		return geometry.Bounds{}, false
+	}
+	minX, _, ok1 := s.float64MinMax(cov.Xmin)
+	minY, _, ok2 := s.float64MinMax(cov.Ymin)
+	_, maxX, ok3 := s.float64MinMax(cov.Xmax)
+	_, maxY, ok4 := s.float64MinMax(cov.Ymax)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return geometry.Bounds{}, false
+	}
+	return geometry.Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY}, true
+}
+
+// float64MinMax reads the min/max of the leaf at path as float64. The
+// spec allows float32 covering leaves, so both widths are accepted.
+func (s *rowGroupStats) float64MinMax(path []string) (float64, float64, bool) {
+	lo, hi, ok := s.MinMax(strings.Join(path, "."))
+	if !ok {
+		return 0, 0, false
+	}
+	switch lo := lo.(type) {
+	case float64:
+		hi, ok := hi.(float64)
+		return lo, hi, ok
+	case float32:
+		hi, ok := hi.(float32)
+		return float64(lo), float64(hi), ok
+	}
+	return 0, 0, false
+}
+
 // decodeMinMax pulls Go-typed min/max scalars from a TypedStatistics.
 // Returns ok=false for types gobi.CanPossiblyMatch can't compare
 // (Int96, FixedLenByteArray outside strings, etc.).
@@ -78,17 +233,59 @@ func decodeMinMax(stats metadata.TypedStatistics) (any, any, bool) {
 	return nil, nil, false
 }
 
-// buildColByName maps top-level column names to their parquet
-// leaf-column indices. Used by rowGroupStats to look up ColumnChunk
-// entries by user-facing name.
+// buildColByName maps leaf column paths to their parquet leaf-column
+// indices. Used by rowGroupStats to look up ColumnChunk entries by
+// user-facing name.
 //
-// Flat schemas only: gobi doesn't emit nested types, so name-to-leaf
-// mapping is straightforward. Nested schemas would need a path walk.
+// A top-level primitive's path is just its name; a struct leaf is
+// dotted ("bbox.xmin"), which is how bbox covering leaves are found.
 func buildColByName(pf *file.Reader) map[string]int {
 	sch := pf.MetaData().Schema
 	out := make(map[string]int, sch.NumColumns())
 	for i := 0; i < sch.NumColumns(); i++ {
-		out[sch.Column(i).Name()] = i
+		out[sch.Column(i).ColumnPath().String()] = i
+	}
+	return out
+}
+
+// bboxCoverings extracts the per-column bbox coverings from a raw
+// "geo" blob. A missing or unparseable blob yields nil — pruning is
+// an optimization, so bad metadata only costs the skip.
+func bboxCoverings(geoRaw string) map[string]gobi.GeoParquetBboxCovering {
+	if geoRaw == "" {
+		return nil
+	}
+	meta, err := gobi.ParseGeoParquetMetadata(geoRaw)
+	if err != nil {
+		return nil
+	}
+	var out map[string]gobi.GeoParquetBboxCovering
+	for name, col := range meta.Columns {
+		if col.Covering == nil {
+			continue
+		}
+		if out == nil {
+			out = map[string]gobi.GeoParquetBboxCovering{}
+		}
+		out[name] = col.Covering.Bbox
+	}
+	return out
+}
+
+// coveringColumns returns the top-level column names holding bbox
+// coverings in a raw "geo" blob — the roots of their leaf paths.
+func coveringColumns(geoRaw string) map[string]bool {
+	var out map[string]bool
+	for _, cov := range bboxCoverings(geoRaw) {
+		for _, path := range [][]string{cov.Xmin, cov.Ymin, cov.Xmax, cov.Ymax} {
+			if len(path) == 0 {
+				continue
+			}
+			if out == nil {
+				out = map[string]bool{}
+			}
+			out[path[0]] = true
+		}
 	}
 	return out
 }
@@ -96,16 +293,18 @@ func buildColByName(pf *file.Reader) map[string]int {
 // filterRowGroupsByPredicate walks pf's row-groups and returns the
 // subset whose min/max stats don't prove the predicate impossible.
 // A nil or unusable predicate keeps every row-group (fallback to the
-// caller's original selection).
-func filterRowGroupsByPredicate(pf *file.Reader, pred gobi.Expr, candidates []int) []int {
+// caller's original selection). geoRaw is the file's "geo" blob, the
+// source of bbox coverings for spatial predicates.
+func filterRowGroupsByPredicate(pf *file.Reader, pred gobi.Expr, geoRaw string, candidates []int) []int {
 	if pred.Node() == nil {
 		return candidates
 	}
 	colByName := buildColByName(pf)
+	coverings := bboxCoverings(geoRaw)
 	kept := make([]int, 0, len(candidates))
 	for _, rgIdx := range candidates {
 		rg := pf.MetaData().RowGroup(rgIdx)
-		s := &rowGroupStats{rg: rg, colByName: colByName}
+		s := &rowGroupStats{rg: rg, colByName: colByName, coverings: coverings, pf: pf, rgIdx: rgIdx}
 		if gobi.CanPossiblyMatch(pred, s) {
 			kept = append(kept, rgIdx)
 		}
@@ -10,6 +10,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/memory"
 
 	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/geometry"
 	"github.com/zoobst/gobi/parquetio"
 )
 
@@ -191,3 +192,135 @@ func TestScanFile_PredicatePushdown_PrunesAllRowGroups(t *testing.T) {
 		t.Fatalf("cols = %d, want 2", out.NumCols())
 	}
 }
+
+// buildPointsFixture writes n points (x = i, y = i % 10) in EPSG 3857
+// with rowGroupRows-sized groups, so row-group k covers x in
+// [k·rowGroupRows, (k+1)·rowGroupRows − 1].
+func buildPointsFixture(t *testing.T, n, rowGroupRows int, covering bool) string {
+	t.Helper()
+	type idRow struct {
+		ID int64 `gobi:"id"`
+	}
+	rows := make([]idRow, n)
+	geoms := array.NewBinaryBuilder(memory.DefaultAllocator, arrow.BinaryTypes.Binary)
+	defer geoms.Release()
+	for i := range n {
+		rows[i].ID = int64(i)
+		geoms.Append(geometry.WKB(geometry.Point{X: float64(i), Y: float64(i % 10)}))
+	}
+	df, err := gobi.FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	df, err = df.WithColumn("geometry", gobi.SeriesFromArray(gobi.GeometryField("geometry", 3857), geoms.NewArray()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "points.parquet")
+	if err := parquetio.WriteFile(df, path, &parquetio.WriteOptions{
+		RowGroupRows: int64(rowGroupRows),
+		BboxCovering: covering,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// square is an axis-aligned EPSG 3857 polygon [x0, x1] × [y0, y1].
+func square(x0, y0, x1, y1 float64) geometry.Geometry {
+	return geometry.SimplePolygon([]geometry.Point{
+		{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}, {X: x0, Y: y0},
+	}, geometry.PseudoMercator)
+}
+
+func TestWriteFile_BboxCovering(t *testing.T) {
+	path := buildPointsFixture(t, 20, 10, true)
+	out, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The covering stays out of the default read: the file round-trips
+	// to the columns that were written.
+	if got := out.ColumnNames(); len(got) != 2 || got[1] != "geometry" {
+		t.Fatalf("columns = %v, want [id geometry]", got)
+	}
+	if sch, err := parquetio.ReadSchema(path, nil); err != nil || sch.NumFields() != 2 {
+		t.Fatalf("ReadSchema = %v, %v; want 2 fields", sch, err)
+	}
+	raw, _ := out.Schema().Metadata().GetValue(gobi.GeoParquetMetadataKey)
+	meta, err := gobi.ParseGeoParquetMetadata(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := meta.Columns["geometry"].Covering
+	if cov == nil || strings.Join(cov.Bbox.Xmax, ".") != "bbox.xmax" {
+		t.Fatalf("covering = %+v, want bbox.xmin..bbox.ymax", cov)
+	}
+	// Naming the covering reads it; projection through a struct column
+	// must pick up all of its leaves.
+	proj, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Columns: []string{"bbox", "id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := proj.ColumnNames(); len(got) != 2 || got[0] != "bbox" || got[1] != "id" {
+		t.Fatalf("projected columns = %v, want [bbox id]", got)
+	}
+}
+
+func TestScanFile_BboxCoveringPrunesRowGroups(t *testing.T) {
+	// 400 points in 4 row groups of 100; the window x in [150, 160]
+	// only overlaps row group 1.
+	path := buildPointsFixture(t, 400, 100, true)
+	region := gobi.LitGeom(square(150, -1, 160, 20))
+	pred := gobi.Col("geometry").Geom().Intersects(region)
+
+	raw, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: pred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.NumRows() != 100 {
+		t.Fatalf("read rows = %d, want 100 (row group 1 only)", raw.NumRows())
+	}
+
+	lf := parquetio.ScanFile(path, nil).Filter(pred)
+	explain := lf.ExplainOptimized()
+	if !strings.Contains(explain, "bbox_covering=[geometry]") {
+		t.Fatalf("optimized plan should show bbox pruning:\n%s", explain)
+	}
+	// Pushed once: the Filter keeps its copy, the scan holds one more.
+	if n := strings.Count(explain, "geom_intersects"); n != 2 {
+		t.Fatalf("predicate appears %d times, want 2:\n%s", n, explain)
+	}
+	out, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 11 {
+		t.Fatalf("rows = %d, want 11 (x = 150..160)", out.NumRows())
+	}
+
+	// DWithin widens the window: 45 m around x = 250 reaches row group 3.
+	near := gobi.Col("geometry").Geom().DWithin(gobi.LitGeom(geometry.Point{X: 255, Y: 0}), 45, geometry.UnitMeters)
+	if raw, err = parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: near}); err != nil {
+		t.Fatal(err)
+	}
+	if raw.NumRows() != 200 {
+		t.Fatalf("dwithin read rows = %d, want 200 (row groups 2 and 3)", raw.NumRows())
+	}
+}
+
+func TestScanFile_NoBboxCoveringReadsEverything(t *testing.T) {
+	path := buildPointsFixture(t, 400, 100, false)
+	pred := gobi.Col("geometry").Geom().Intersects(gobi.LitGeom(square(150, -1, 160, 20)))
+	lf := parquetio.ScanFile(path, nil).Filter(pred)
+	if explain := lf.ExplainOptimized(); strings.Contains(explain, "bbox_covering") {
+		t.Fatalf("no covering column, but plan claims bbox pruning:\n%s", explain)
+	}
+	raw, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: pred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.NumRows() != 400 {
+		t.Fatalf("read rows = %d, want 400", raw.NumRows())
+	}
+}
@@ -9,22 +9,29 @@ import (
 	"github.com/zoobst/gobi"
 )
 
-// partitionRowGroups peeks at path's row-group count and splits it
-// into `workers` contiguous ranges. Each range gets its own read
-// closure that runs ReadFileChunksFunc restricted to that range
-// via ReadOptions.RowGroups.
+// partitionRowGroups peeks at path's footer, drops the row-groups
+// opts.Predicate rules out, and splits the survivors into `workers`
+// contiguous ranges. Each range gets its own read closure that runs
+// ReadFileChunksFunc restricted to that range via
+// ReadOptions.RowGroups.
+//
+// Pruning before partitioning keeps the workers balanced: a selective
+// Eq / IsIn filter over bloom-filtered IDs may leave a handful of
+// groups scattered through the file, and splitting the raw range
+// would hand most workers nothing to do.
 //
 // Returns nil when parallel scan doesn't apply: file can't be
 // opened (bubble the real error at Collect via the serial path),
-// only one row-group present, or ScanWorkers explicitly set to 1.
+// fewer than two row-groups survive, or ScanWorkers explicitly set
+// to 1.
 //
 // Worker count resolution:
 //
-//	opts.ScanWorkers == 0 → runtime.GOMAXPROCS(0), capped at NumRowGroups
+//	opts.ScanWorkers == 0 → runtime.GOMAXPROCS(0), capped at surviving row-groups
 //	opts.ScanWorkers == 1 → nil (caller falls back to serial WithStreamRead)
-//	opts.ScanWorkers >= 2 → min(opts.ScanWorkers, NumRowGroups)
+//	opts.ScanWorkers >= 2 → min(opts.ScanWorkers, surviving row-groups)
 func partitionRowGroups(path string, opts *ReadOptions) []func(cb func(*gobi.Frame) error) error {
-	// Peek at NumRowGroups. If any step fails, fall back to serial
+	// Peek at the footer. If any step fails, fall back to serial
 	// — the serial WithStreamRead callback will surface the real
 	// error at Collect time.
 	f, err := os.Open(path)
@@ -36,18 +43,38 @@ func partitionRowGroups(path string, opts *ReadOptions) []func(cb func(*gobi.Fra
 		_ = f.Close()
 		return nil
 	}
-	numRG := pf.NumRowGroups()
+	candidates := make([]int, 0, pf.NumRowGroups())
+	if opts != nil && len(opts.RowGroups) > 0 {
+		for _, rg := range opts.RowGroups {
+			if rg < 0 || rg >= pf.NumRowGroups() {
+				// Serial path reports the bad index at Collect.
+				_ = pf.Close()
+				_ = f.Close()
+				return nil
+			}
+		}
+		candidates = append(candidates, opts.RowGroups...)
+	} else {
+		for i := range pf.NumRowGroups() {
+			candidates = append(candidates, i)
+		}
+	}
+	var pred gobi.Expr
+	if opts != nil {
+		pred = opts.Predicate
+	}
+	survivors := filterRowGroupsByPredicate(pf, pred, geoMetadataRaw(pf), candidates)
 	_ = pf.Close()
 	_ = f.Close()
 
-	workers := effectiveScanWorkers(opts, numRG)
+	workers := effectiveScanWorkers(opts, len(survivors))
 	if workers <= 1 {
 		return nil
 	}
 
 	subs := make([]func(cb func(*gobi.Frame) error) error, workers)
-	perWorker := numRG / workers
-	remainder := numRG % workers
+	perWorker := len(survivors) / workers
+	remainder := len(survivors) % workers
 
 	start := 0
 	for i := range workers {
@@ -55,7 +82,7 @@ func partitionRowGroups(path string, opts *ReadOptions) []func(cb func(*gobi.Fra
 		if i < remainder {
 			end++ // spread the odd row-groups across the first few workers
 		}
-		s, e := start, end
+		rgs := survivors[start:end]
 		subs[i] = func(cb func(*gobi.Frame) error) error {
 			// Copy opts so we can override RowGroups without racing
 			// with sibling workers (opts.ScanWorkers is int, safe
@@ -64,11 +91,11 @@ func partitionRowGroups(path string, opts *ReadOptions) []func(cb func(*gobi.Fra
 			if opts != nil {
 				workerOpts = *opts
 			}
-			rgs := make([]int, 0, e-s)
-			for j := s; j < e; j++ {
-				rgs = append(rgs, j)
-			}
 			workerOpts.RowGroups = rgs
+			// The groups were pruned above; the Predicate is only a
+			// skipping hint, so drop it rather than re-probe stats
+			// and bloom filters in every worker.
+			workerOpts.Predicate = gobi.Expr{}
 			// Streaming aggregate/filter/project downstream doesn't
 			// care about batch order across workers — no need to
 			// preserve it here.
@@ -0,0 +1,173 @@
+package parquetio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/zoobst/gobi"
+)
+
+// defaultSinkRowGroupRows caps row groups written by SinkFile when
+// WriteOptions.RowGroupRows is 0. The sink buffers the open row group
+// (encoded, not as arrow arrays) until it fills, so parquet-arrow's own
+// 64M-row default would hold most of a large output in memory; 1M rows
+// matches the "~1M rows" WriteFile documents.
+const defaultSinkRowGroupRows = 1 << 20
+
+// SinkFile runs lf and streams its output to a Parquet file at path,
+// one batch at a time, instead of Collect-ing it first:
+//
+//	err := parquetio.SinkFile(ctx,
+//		parquetio.ScanFile("trips.parquet", nil).
+//			Filter(gobi.Col("fare").Gt(gobi.Lit(100.0))),
+//		"expensive.parquet", nil)
+//
+// opts is honored as in WriteFile. Batches are appended to a buffered
+// row group that rolls over every RowGroupRows rows (1M when unset),
+// so row groups span batches rather than mirroring them. The
+// GeoParquet "geo" metadata — geometry types and bbox per geometry
+// column — is computed per batch, merged, and written to the footer at
+// the end; BboxCovering columns are added per batch.
+//
+// Peak memory is the pipeline's own (roughly one batch per scan worker
+// for a streaming plan) plus the open row group's encoded pages. On
+// any error — including cancellation of ctx — the partial file is
+// removed.
+func SinkFile(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions) error {
+	return SinkFileWith(ctx, lf, path, opts, nil)
+}
+
+// SinkFileWith is SinkFile with collect passed to
+// gobi.LazyFrame.SinkWith. Sorting a file larger than memory into
+// another one is
+//
+//	err := parquetio.SinkFileWith(ctx,
+//		parquetio.ScanFile("trips.parquet", nil).SortBy(gobi.SortKey{Column: "pickup_ts"}),
+//		"sorted.parquet", nil, &gobi.CollectOptions{MemoryLimit: 1 << 30})
+//
+// A nil collect is SinkFile.
+func SinkFileWith(ctx context.Context, lf *gobi.LazyFrame, path string, opts *WriteOptions, collect *gobi.CollectOptions) error {
+	if opts == nil {
+		opts = &WriteOptions{}
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = lf.SinkWith(ctx, &fileSink{out: out, opts: opts}, collect)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(path))
+	}
+	return nil
+}
+
+// fileSink is the gobi.BatchSink behind SinkFile.
+type fileSink struct {
+	out    *os.File
+	opts   *WriteOptions
+	writer *pqarrow.FileWriter
+	// meta accumulates the GeoParquet metadata across batches; nil
+	// when the schema has no geometry column.
+	meta *gobi.GeoParquetMetadata
+}
+
+// Open creates the parquet writer. The file schema comes from an empty
+// Frame run through the same prepare step as every batch, so bbox
+// covering columns appear in it exactly as they will in the data, and
+// meta starts out with each column's encoding, CRS and covering.
+func (s *fileSink) Open(schema *arrow.Schema) error {
+	empty, err := gobi.EmptyFrame(schema)
+	if err != nil {
+		return err
+	}
+	defer empty.Release()
+	covered, meta, err := s.prepare(empty)
+	if err != nil {
+		return err
+	}
+	if covered != empty {
+		defer covered.Release()
+	}
+	props, err := writerProperties(s.opts, defaultSinkRowGroupRows)
+	if err != nil {
+		return err
+	}
+	// The parquet writer closes a sink that is an io.Closer; hiding
+	// Close leaves the file to SinkFileWith, which checks the error.
+	s.writer, err = pqarrow.NewFileWriter(
+		dropGeoKey(covered.Schema()),
+		struct{ io.Writer }{s.out},
+		props,
+		pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()),
+	)
+	if err != nil {
+		return err
+	}
+	s.meta = meta
+	return nil
+}
+
+// Write appends one batch to the open row group.
+func (s *fileSink) Write(batch *gobi.Frame) error {
+	covered, meta, err := s.prepare(batch)
+	if err != nil {
+		return err
+	}
+	if covered != batch {
+		defer covered.Release()
+	}
+	if s.meta != nil {
+		s.meta.Merge(meta)
+	}
+	tbl := covered.Table()
+	defer tbl.Release()
+	tr := array.NewTableReader(tbl, -1)
+	defer tr.Release()
+	for tr.Next() {
+		if err := s.writer.WriteBuffered(tr.RecordBatch()); err != nil {
+			return err
+		}
+	}
+	return tr.Err()
+}
+
+// Close flushes the last row group and writes the footer, with the
+// merged "geo" metadata when the schema has geometry.
+func (s *fileSink) Close() error {
+	if s.meta != nil {
+		blob, err := marshalGeoMeta(s.meta)
+		if err != nil {
+			_ = s.writer.Close()
+			return err
+		}
+		if err := s.writer.AppendKeyValueMetadata(gobi.GeoParquetMetadataKey, blob); err != nil {
+			_ = s.writer.Close()
+			return err
+		}
+	}
+	return s.writer.Close()
+}
+
+// prepare builds f's GeoParquet metadata and, when BboxCovering is
+// set, adds the covering columns. The returned Frame is f itself when
+// nothing was added; otherwise the caller releases it.
+func (s *fileSink) prepare(f *gobi.Frame) (*gobi.Frame, *gobi.GeoParquetMetadata, error) {
+	meta, err := gobi.BuildGeoParquetMetadata(f)
+	if err != nil || !s.opts.BboxCovering {
+		return f, meta, err
+	}
+	covered, err := gobi.AddGeoParquetBboxCovering(f, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	return covered, meta, nil
+}
@@ -0,0 +1,174 @@
+package parquetio_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/zoobst/gobi"
+	"github.com/zoobst/gobi/parquetio"
+)
+
+func TestSinkFile(t *testing.T) {
+	src := buildPointsFixture(t, 400, 100, false)
+	// 64-row scan batches: the 250 surviving rows reach the sink in
+	// several batches, and the 150-row row groups span them.
+	lf := parquetio.ScanFile(src, &parquetio.ReadOptions{ChunkRows: 64}).
+		Filter(gobi.Col("id").Ge(gobi.Lit(int64(150))))
+	path := filepath.Join(t.TempDir(), "out.parquet")
+	if err := parquetio.SinkFile(context.Background(), lf, path, &parquetio.WriteOptions{
+		RowGroupRows: 150,
+		BboxCovering: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, _ := out.Column("id")
+	got, _ := ids.Int64s()
+	if len(got) != 250 || got[0] != 150 || got[249] != 399 {
+		t.Fatalf("ids = %d rows [%v … %v], want 150..399", len(got), got[0], got[len(got)-1])
+	}
+	if g, _ := out.Column("geometry"); !g.IsGeometry() {
+		t.Fatal("geometry column lost its geometry tag")
+	}
+	for rg, want := range []int{150, 100} {
+		part, err := parquetio.ReadFile(path, &parquetio.ReadOptions{RowGroups: []int{rg}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.NumRows() != want {
+			t.Fatalf("row group %d has %d rows, want %d", rg, part.NumRows(), want)
+		}
+	}
+	if _, err := parquetio.ReadFile(path, &parquetio.ReadOptions{RowGroups: []int{2}}); err == nil {
+		t.Fatal("expected exactly two row groups")
+	}
+
+	// The footer's bbox covers every batch, not just the last one.
+	raw, _ := out.Schema().Metadata().GetValue(gobi.GeoParquetMetadataKey)
+	meta, err := gobi.ParseGeoParquetMetadata(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := meta.Columns["geometry"]
+	if !slices.Equal(col.Bbox, []float64{150, 0, 399, 9}) || !slices.Equal(col.GeometryTypes, []string{"Point"}) {
+		t.Fatalf("geo metadata = %+v", col)
+	}
+	if col.Covering == nil || col.CRS == nil {
+		t.Fatalf("covering = %v, crs = %v", col.Covering, col.CRS)
+	}
+	// The covering columns prune like WriteFile's.
+	window := gobi.Col("geometry").Geom().Intersects(gobi.LitGeom(square(160, -1, 170, 20)))
+	pruned, err := parquetio.ReadFile(path, &parquetio.ReadOptions{Predicate: window})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned.NumRows() != 150 {
+		t.Fatalf("bbox pushdown read %d rows, want 150 (row group 0 only)", pruned.NumRows())
+	}
+}
+
+func TestSinkFile_EmptyAndError(t *testing.T) {
+	src := buildPointsFixture(t, 50, 50, false)
+	dir := t.TempDir()
+
+	// No rows: still a valid, schema-carrying GeoParquet file.
+	empty := filepath.Join(dir, "empty.parquet")
+	lf := parquetio.ScanFile(src, nil).Filter(gobi.Col("id").Lt(gobi.Lit(int64(0))))
+	if err := parquetio.SinkFile(context.Background(), lf, empty, nil); err != nil {
+		t.Fatal(err)
+	}
+	out, err := parquetio.ReadFile(empty, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 0 || out.NumCols() != 2 {
+		t.Fatalf("empty sink: %d rows, %d cols", out.NumRows(), out.NumCols())
+	}
+	if _, ok := out.Schema().Metadata().GetValue(gobi.GeoParquetMetadataKey); !ok {
+		t.Fatal("empty sink: no geo metadata")
+	}
+
+	// A cancelled run removes the partial file.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	partial := filepath.Join(dir, "partial.parquet")
+	if err := parquetio.SinkFile(ctx, parquetio.ScanFile(src, nil), partial, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Fatalf("partial file left behind: %v", err)
+	}
+}
+
+// TestSinkFileWith sorts a scan under a one-byte budget, so the sort
+// spills every batch as a run and merges them on the way to the file.
+func TestSinkFileWith(t *testing.T) {
+	src := buildPointsFixture(t, 400, 100, false)
+	dir := t.TempDir()
+	spillDir := t.TempDir()
+	lf := parquetio.ScanFile(src, &parquetio.ReadOptions{ChunkRows: 64}).
+		SortBy(gobi.SortKey{Column: "id", Descending: true})
+	path := filepath.Join(dir, "sorted.parquet")
+	collect := &gobi.CollectOptions{MemoryLimit: 1, SpillDir: spillDir}
+	if err := parquetio.SinkFileWith(context.Background(), lf, path, nil, collect); err != nil {
+		t.Fatal(err)
+	}
+	out, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col, err := out.Column("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := col.Int64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 400 {
+		t.Fatalf("rows = %d, want 400", len(ids))
+	}
+	for i, v := range ids {
+		if v != int64(399-i) {
+			t.Fatalf("id[%d] = %d, want %d", i, v, 399-i)
+		}
+	}
+	if entries, _ := os.ReadDir(spillDir); len(entries) != 0 {
+		t.Errorf("%d spill files left behind", len(entries))
+	}
+}
+
+// TestWriteFile_ReplacesSourceGeoMetadata writes a filtered scan of a
+// GeoParquet file: the footer must describe the rows written, not the
+// source file's "geo" blob that rides along in the scan's schema.
+func TestWriteFile_ReplacesSourceGeoMetadata(t *testing.T) {
+	src := buildPointsFixture(t, 400, 100, false)
+	df, err := parquetio.ScanFile(src, nil).Filter(gobi.Col("id").Ge(gobi.Lit(int64(150)))).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "out.parquet")
+	if err := parquetio.WriteFile(df, path, nil); err != nil {
+		t.Fatal(err)
+	}
+	out, err := parquetio.ReadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := out.Schema().Metadata().GetValue(gobi.GeoParquetMetadataKey)
+	meta, err := gobi.ParseGeoParquetMetadata(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := meta.Columns["geometry"].Bbox; !slices.Equal(got, []float64{150, 0, 399, 9}) {
+		t.Fatalf("bbox = %v, want the written rows' [150 0 399 9]", got)
+	}
+}
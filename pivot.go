@@ -2,6 +2,7 @@ package gobi
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -9,6 +10,25 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
+// PivotOptions tunes Frame.PivotWith. A nil *PivotOptions means
+// AggCount with null for missing cells — pass an Agg explicitly for
+// anything else.
+type PivotOptions struct {
+	// Agg is the built-in reduction for cells several input rows map
+	// to. Use AggFirst / AggLast if you're sure there's no collision.
+	Agg AggKind
+	// Fn, when non-nil, overrides Agg with a custom Aggregator, exactly
+	// as Aggregation.Fn does in GroupBy.Agg.
+	Fn Aggregator
+	// Fill, when non-nil, replaces every null cell of the result — both
+	// cells with no input rows and cells whose aggregate is null, like
+	// pandas' pivot_table(fill_value=...). It must be a Go value
+	// appendable to the value column's type; Go ints convert for Int64
+	// and ints or floats for Float64 columns, so Fill: 0 works for
+	// either.
+	Fill any
+}
+
 // Pivot reshapes a long-form Frame into a wide-form one.
 //
 //   - index: column whose distinct values become the rows.
@@ -24,10 +44,9 @@ import (
 //	<index column> | <col_value_1> | <col_value_2> | ...
 //
 // Column values are stringified for use as arrow field names — the
-// header row is always a string. The value column's arrow type
-// follows aggOutputType(Aggregation{Kind: agg, ...}); it's Int64 for
-// Count/NUnique and Float64 for the rest, matching what GroupBy.Agg
-// would produce.
+// header row is always a string. The value column's arrow type is
+// what GroupBy.Agg produces for agg: Int64 for Count/NUnique, the
+// source type for First/Last/Mode, Float64 for the rest.
 //
 // Cells with no matching input rows are emitted as null. Output rows
 // are ordered by the sorted index value; output columns are ordered
@@ -35,167 +54,226 @@ import (
 // deterministic across runs.
 //
 // Equivalent to `pandas.DataFrame.pivot_table(index, columns, values,
-// aggfunc)` / polars' `DataFrame.pivot`. Multi-column pivot indices
-// aren't supported yet — pass a single index column.
+// aggfunc)` / polars' `DataFrame.pivot`. PivotWith takes several
+// index and value columns, a custom Aggregator and a fill value;
+// Unpivot is the inverse.
 func (f *Frame) Pivot(index, columns, values string, agg AggKind) (*Frame, error) {
+	return f.PivotWith([]string{index}, columns, []string{values}, &PivotOptions{Agg: agg})
+}
+
+// PivotWith is the general form of Pivot: one output row per distinct
+// combination of the index columns, and one output column per
+// (value column, distinct columns-column value) pair. With a single
+// value column the new columns are named by the header alone, as in
+// Pivot; with several they are "<value>_<header>", grouped by value
+// column in the order given:
+//
+//	// sensor readings → one row per (site, day), temp_* and rh_* per sensor
+//	wide, err := long.PivotWith([]string{"site", "day"}, "sensor",
+//	    []string{"temp", "rh"}, &gobi.PivotOptions{Agg: gobi.AggMean})
+//
+// Each value column is reduced with opts.Fn if set, else opts.Agg,
+// through GroupBy.Agg — so the cell types, null handling and custom
+// Aggregator contract are GroupBy's. Index columns keep their types;
+// rows come out sorted by the index columns as SortBy orders them
+// (ascending, nulls last) and headers sorted as strings. A header that
+// collides with an index column name is an error.
+func (f *Frame) PivotWith(index []string, columns string, values []string, opts *PivotOptions) (*Frame, error) {
 	if f == nil {
 		return nil, fmt.Errorf("gobi: Frame.Pivot on nil frame")
 	}
-	if index == "" || columns == "" || values == "" {
+	if opts == nil {
+		opts = &PivotOptions{}
+	}
+	if len(index) == 0 || columns == "" || len(values) == 0 {
 		return nil, fmt.Errorf("gobi: Pivot: index, columns, and values are all required")
 	}
-	if index == columns || index == values || columns == values {
-		return nil, fmt.Errorf("gobi: Pivot: index, columns, and values must name distinct columns")
+	seen := map[string]bool{columns: true}
+	for _, name := range append(slices.Clone(index), values...) {
+		if name == "" {
+			return nil, fmt.Errorf("gobi: Pivot: index, columns, and values are all required")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("gobi: Pivot: index, columns, and values must name distinct columns (%q repeats)", name)
+		}
+		seen[name] = true
 	}
 
-	// Reduce to long form: (index, columns, values_agg). Reuse the
-	// GroupBy machinery so aggregation semantics + null handling
+	// Reduce to long form: (index..., columns, values_agg...). Reuse
+	// the GroupBy machinery so aggregation semantics + null handling
 	// stay consistent with the rest of the API.
-	gb, err := f.GroupBy(index, columns)
+	gb, err := f.GroupBy(append(slices.Clone(index), columns)...)
 	if err != nil {
 		return nil, err
 	}
-	long, err := gb.Agg(Aggregation{Column: values, Kind: agg, Alias: values})
+	aggs := make([]Aggregation, len(values))
+	for i, v := range values {
+		aggs[i] = Aggregation{Column: v, Kind: opts.Agg, Fn: opts.Fn, Alias: v}
+	}
+	long, err := gb.Agg(aggs...)
 	if err != nil {
 		return nil, err
 	}
-
-	idxCol, err := long.Column(index)
-	if err != nil {
+	// GroupBy orders multi-column keys by their encoded bytes, which
+	// puts negative numbers after positive ones. A stable sort on the
+	// index columns puts long in value order; the first-seen order
+	// below then carries it into the output rows.
+	idxKeys := make([]SortKey, len(index))
+	for i, name := range index {
+		idxKeys[i] = SortKey{Column: name}
+	}
+	if long, err = long.SortBy(idxKeys...); err != nil {
 		return nil, err
 	}
+
+	idxCols := make([]Series, len(index))
+	for i, name := range index {
+		if idxCols[i], err = long.Column(name); err != nil {
+			return nil, err
+		}
+	}
 	colCol, err := long.Column(columns)
 	if err != nil {
 		return nil, err
 	}
-	valCol, err := long.Column(values)
-	if err != nil {
-		return nil, err
+	valCols := make([]Series, len(values))
+	for i, name := range values {
+		if valCols[i], err = long.Column(name); err != nil {
+			return nil, err
+		}
 	}
 
 	// Bucket the long-form rows into a nested map:
 	//   idxKey  → colHeader → row-index in long
-	// idxKey is the composite-key byte encoding of the index scalar,
-	// so numeric bit-equal values collapse identically. colHeader is
-	// the stringified column-column value — becomes an arrow field
-	// name in the output.
+	// idxKey is the composite-key byte encoding of the index
+	// scalars, so numeric bit-equal values collapse identically.
+	// colHeader is the stringified column-column value — becomes
+	// (part of) an arrow field name in the output.
 	nLong := long.NumRows()
-	type cellPos struct {
-		row int // row index in the long frame
-	}
-	byIdx := make(map[string]map[string]cellPos)
-	// idxOrder captures first-seen order for stable output shape
-	// before we sort it.
+	byIdx := make(map[string]map[string]int)
+	// idxOrder captures first-seen order, which is the output row
+	// order.
 	var idxOrder []string
-	// Preserve the original index scalar per idxKey so we can emit
-	// it in the output's first column with the source's arrow type
-	// intact.
-	idxScalar := make(map[string]any)
+	// Remember one long row per idxKey so the index scalars can be
+	// emitted with the source's arrow types intact.
+	idxRow := make(map[string]int)
 	// Same for column headers, so the sorted output columns look
 	// sensible regardless of input type.
 	colHeaders := make(map[string]struct{})
 
-	idxKeys := []Series{idxCol}
 	var idxScratch []byte
 	for row := 0; row < nLong; row++ {
-		buf, err := composeCompositeKeyInto(idxScratch[:0], idxKeys, row)
+		buf, err := composeCompositeKeyInto(idxScratch[:0], idxCols, row)
 		if err != nil {
 			return nil, err
 		}
 		idxScratch = buf
 		ik := string(buf)
 		if _, ok := byIdx[ik]; !ok {
-			byIdx[ik] = make(map[string]cellPos)
+			byIdx[ik] = make(map[string]int)
 			idxOrder = append(idxOrder, ik)
-			v, err := readScalarAt(idxCol, row)
-			if err != nil {
-				return nil, err
-			}
-			idxScalar[ik] = v
+			idxRow[ik] = row
 		}
 		header, err := pivotColumnHeader(colCol, row)
 		if err != nil {
 			return nil, err
 		}
-		byIdx[ik][header] = cellPos{row: row}
+		byIdx[ik][header] = row
 		colHeaders[header] = struct{}{}
 	}
 
-	// Deterministic output shape: sort idx keys and column headers.
-	sort.Strings(idxOrder)
+	// Deterministic output shape: sort the column headers.
 	headers := make([]string, 0, len(colHeaders))
 	for h := range colHeaders {
 		headers = append(headers, h)
 	}
 	sort.Strings(headers)
 
-	// Build output builders.
-	pool := memory.DefaultAllocator
-	idxFieldType := idxCol.DataType()
-	idxBuilder, err := builderForType(pool, idxFieldType)
-	if err != nil {
-		return nil, fmt.Errorf("gobi: Pivot: %w", err)
+	// Output fields: the index columns as GroupBy emitted them, then
+	// one field per (value, header) carrying the aggregated column's
+	// type and metadata.
+	fields := make([]arrow.Field, 0, len(index)+len(values)*len(headers))
+	for _, s := range idxCols {
+		fields = append(fields, s.field)
+	}
+	fills := make([]any, len(values))
+	for vi, vs := range valCols {
+		if opts.Fill != nil {
+			fills[vi] = fillValueFor(opts.Fill, vs.DataType())
+		}
+		for _, h := range headers {
+			fld := vs.field
+			fld.Name = h
+			if len(values) > 1 {
+				fld.Name = values[vi] + "_" + h
+			}
+			fld.Nullable = opts.Fill == nil
+			fields = append(fields, fld)
+		}
+	}
+	names := make(map[string]bool, len(fields))
+	for _, fld := range fields {
+		if names[fld.Name] {
+			return nil, fmt.Errorf("gobi: Pivot: output column %q is produced twice; rename the index column or the header value", fld.Name)
+		}
+		names[fld.Name] = true
 	}
-	defer idxBuilder.Release()
 
-	valOutType := aggOutputType(Aggregation{Kind: agg, Column: values})
-	valBuilders := make([]array.Builder, len(headers))
-	for i := range headers {
-		b, err := builderForType(pool, valOutType)
+	// Build output builders.
+	pool := memory.DefaultAllocator
+	builders := make([]array.Builder, len(fields))
+	defer releaseBuilders(builders)
+	for i, fld := range fields {
+		b, err := builderForType(pool, fld.Type)
 		if err != nil {
 			return nil, fmt.Errorf("gobi: Pivot: %w", err)
 		}
-		valBuilders[i] = b
+		builders[i] = b
 	}
-	defer releaseBuilders(valBuilders)
 
 	// Emit rows.
 	for _, ik := range idxOrder {
-		if err := appendCustomValue(idxBuilder, idxScalar[ik]); err != nil {
-			return nil, fmt.Errorf("gobi: Pivot: writing index value: %w", err)
-		}
-		row := byIdx[ik]
-		for hi, h := range headers {
-			pos, ok := row[h]
-			if !ok {
-				valBuilders[hi].AppendNull()
-				continue
-			}
-			v, err := readScalarAt(valCol, pos.row)
+		for i, s := range idxCols {
+			v, err := readScalarAt(s, idxRow[ik])
 			if err != nil {
 				return nil, err
 			}
-			if v == nil {
-				valBuilders[hi].AppendNull()
-				continue
+			if err := appendCustomValue(builders[i], v); err != nil {
+				return nil, fmt.Errorf("gobi: Pivot: writing index value: %w", err)
 			}
-			if err := appendCustomValue(valBuilders[hi], v); err != nil {
-				return nil, fmt.Errorf("gobi: Pivot: writing cell (%s, %s): %w",
-					ik, h, err)
+		}
+		row := byIdx[ik]
+		bi := len(idxCols)
+		for vi, vs := range valCols {
+			for _, h := range headers {
+				var v any
+				if pos, ok := row[h]; ok {
+					if v, err = readScalarAt(vs, pos); err != nil {
+						return nil, err
+					}
+				}
+				if v == nil {
+					v = fills[vi]
+				}
+				if err := appendCustomValue(builders[bi], v); err != nil {
+					return nil, fmt.Errorf("gobi: Pivot: writing cell %s: %w",
+						fields[bi].Name, err)
+				}
+				bi++
 			}
 		}
 	}
 
 	// Materialize columns + assemble the output Frame.
-	fields := make([]arrow.Field, 0, 1+len(headers))
-	cols := make([]arrow.Column, 0, 1+len(headers))
-	fields = append(fields, arrow.Field{Name: index, Type: idxFieldType, Nullable: false})
-	idxArr := idxBuilder.NewArray()
-	defer idxArr.Release()
-	idxChunked := arrow.NewChunked(idxFieldType, []arrow.Array{idxArr})
-	cols = append(cols, *arrow.NewColumn(fields[0], idxChunked))
-	idxChunked.Release()
-	for i, h := range headers {
-		fld := arrow.Field{Name: h, Type: valOutType, Nullable: aggOutputNullable(Aggregation{Kind: agg})}
-		fields = append(fields, fld)
-		a := valBuilders[i].NewArray()
-		defer a.Release()
-		valChunked := arrow.NewChunked(valOutType, []arrow.Array{a})
-		cols = append(cols, *arrow.NewColumn(fld, valChunked))
-		valChunked.Release()
-	}
-	schema := arrow.NewSchema(fields, nil)
-	return NewFrame(schema, cols)
+	cols := make([]arrow.Column, len(fields))
+	for i, fld := range fields {
+		a := builders[i].NewArray()
+		chunked := arrow.NewChunked(fld.Type, []arrow.Array{a})
+		a.Release()
+		cols[i] = *arrow.NewColumn(fld, chunked)
+		chunked.Release()
+	}
+	return NewFrame(arrow.NewSchema(fields, nil), cols)
 }
 
 // pivotColumnHeader stringifies the columns-column value at row for
@@ -2,8 +2,10 @@ package gobi
 
 import (
 	"math"
+	"slices"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 )
 
@@ -91,3 +93,166 @@ func TestFrame_Pivot_RejectsSameColumns(t *testing.T) {
 		t.Fatal("expected error for empty index name")
 	}
 }
+
+// sensorFrame is a long-form sensor export: (site, day, sensor, temp,
+// rh) with a duplicate (north, 1, s1) reading and a null rh.
+func sensorFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		Site   string  `gobi:"site"`
+		Day    int64   `gobi:"day"`
+		Sensor string  `gobi:"sensor"`
+		Temp   float64 `gobi:"temp"`
+		RH     *int64  `gobi:"rh"`
+	}
+	rh := func(x int64) *int64 { return &x }
+	df, err := FromStructs([]row{
+		{"north", 1, "s1", 10, rh(40)},
+		{"north", 1, "s2", 12, rh(42)},
+		{"north", 1, "s1", 14, rh(44)},
+		{"north", 2, "s1", 11, nil},
+		{"south", 1, "s2", 20, rh(60)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return df
+}
+
+func TestFrame_PivotWith_MultiIndexMultiValue(t *testing.T) {
+	out, err := sensorFrame(t).PivotWith([]string{"site", "day"}, "sensor",
+		[]string{"temp", "rh"}, &PivotOptions{Agg: AggMean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"site":    {"north", "north", "south"},
+		"day":     {"1", "2", "1"},
+		"temp_s1": {"12", "11", "null"},
+		"temp_s2": {"12", "null", "20"},
+		"rh_s1":   {"42", "null", "null"},
+		"rh_s2":   {"42", "null", "60"},
+	}
+	if names := out.ColumnNames(); !slices.Equal(names, []string{"site", "day", "temp_s1", "temp_s2", "rh_s1", "rh_s2"}) {
+		t.Fatalf("columns = %v", names)
+	}
+	for name, w := range want {
+		if got := valuesOrNull(t, out, name); !slices.Equal(got, w) {
+			t.Errorf("%s = %v, want %v", name, got, w)
+		}
+	}
+}
+
+func TestFrame_PivotWith_CustomAggAndFill(t *testing.T) {
+	df := sensorFrame(t)
+	// A custom Aggregator sets the cell type (Int64 here); the fill
+	// reaches both empty cells and the Int64 column through Go's int.
+	out, err := df.PivotWith([]string{"site"}, "day", []string{"temp"},
+		&PivotOptions{Fn: AggCountDistinct(), Fill: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustColumn(t, out, "1").DataType(); got.ID() != arrow.INT64 {
+		t.Fatalf("cell type = %s, want int64", got)
+	}
+	if got := valuesOrNull(t, out, "1"); !slices.Equal(got, []string{"3", "1"}) {
+		t.Fatalf("day 1 = %v", got)
+	}
+	if got := valuesOrNull(t, out, "2"); !slices.Equal(got, []string{"1", "0"}) {
+		t.Fatalf("day 2 = %v (fill 0 for south)", got)
+	}
+
+	// First keeps the source type — String here.
+	withName, err := df.WithColumnExpr("label", Col("sensor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = withName.PivotWith([]string{"site", "day"}, "sensor", []string{"rh", "label"},
+		&PivotOptions{Agg: AggFirst})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustColumn(t, out, "label_s2").DataType(); got.ID() != arrow.STRING {
+		t.Fatalf("First of a String column = %s", got)
+	}
+	// Fill replaces a null aggregate (north/2/s1) as well as the empty
+	// south/1/s1 cell.
+	filled, err := df.PivotWith([]string{"site", "day"}, "sensor", []string{"rh"},
+		&PivotOptions{Agg: AggFirst, Fill: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := valuesOrNull(t, filled, "s1"); !slices.Equal(got, []string{"40", "-1", "-1"}) {
+		t.Fatalf("filled s1 = %v", got)
+	}
+	if mustColumn(t, filled, "s1").field.Nullable {
+		t.Fatal("a filled pivot column should be non-nullable")
+	}
+}
+
+func TestFrame_PivotWith_IndexOrder(t *testing.T) {
+	type row struct {
+		G string  `gobi:"g"`
+		N int64   `gobi:"n"`
+		K string  `gobi:"k"`
+		V float64 `gobi:"v"`
+	}
+	// Encoded key bytes would put 0 and 2 ahead of -3 and -1, and the
+	// length-prefixed "b" ahead of "aa"; rows must follow the values.
+	df, err := FromStructs([]row{
+		{"b", 2, "x", 1}, {"aa", -1, "y", 2}, {"b", 0, "x", 3},
+		{"aa", -3, "x", 4}, {"b", -1, "y", 5}, {"aa", 2, "x", 6},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := df.PivotWith([]string{"n"}, "k", []string{"v"}, &PivotOptions{Agg: AggSum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, w := range map[string][]string{
+		"n": {"-3", "-1", "0", "2"},
+		"x": {"4", "null", "3", "7"},
+		"y": {"null", "7", "null", "null"},
+	} {
+		if got := valuesOrNull(t, out, name); !slices.Equal(got, w) {
+			t.Errorf("single index: %s = %v, want %v", name, got, w)
+		}
+	}
+
+	out, err = df.PivotWith([]string{"g", "n"}, "k", []string{"v"}, &PivotOptions{Agg: AggSum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, w := range map[string][]string{
+		"g": {"aa", "aa", "aa", "b", "b", "b"},
+		"n": {"-3", "-1", "2", "-1", "0", "2"},
+		"x": {"4", "null", "6", "null", "3", "1"},
+		"y": {"null", "2", "null", "5", "null", "null"},
+	} {
+		if got := valuesOrNull(t, out, name); !slices.Equal(got, w) {
+			t.Errorf("multi index: %s = %v, want %v", name, got, w)
+		}
+	}
+}
+
+func TestFrame_PivotWith_Rejects(t *testing.T) {
+	df := sensorFrame(t)
+	if _, err := df.PivotWith([]string{"site", "site"}, "sensor", []string{"temp"}, nil); err == nil {
+		t.Fatal("repeated index column: expected error")
+	}
+	if _, err := df.PivotWith([]string{"site"}, "sensor", nil, nil); err == nil {
+		t.Fatal("no value columns: expected error")
+	}
+	if _, err := df.PivotWith([]string{"site"}, "sensor", []string{"nope"}, nil); err == nil {
+		t.Fatal("unknown value column: expected error")
+	}
+	// Sensor header "s1" collides with an index column named "s1".
+	renamed, err := df.WithColumnExpr("s1", Col("site"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := renamed.PivotWith([]string{"s1"}, "sensor", []string{"temp"}, &PivotOptions{Agg: AggSum}); err == nil {
+		t.Fatal("header colliding with the index column: expected error")
+	}
+}
@@ -268,15 +268,51 @@ func (n *sortNode) PartitionMetadata() *PartitionMetadata {
 	}
 }
 func (n *sortNode) String() string {
-	parts := make([]string, len(n.keys))
-	for i, k := range n.keys {
+	return fmt.Sprintf("Sort(%s)", formatSortKeys(n.keys))
+}
+
+// formatSortKeys renders keys the way Sort and TopK print them:
+// "a, b DESC".
+func formatSortKeys(keys []SortKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
 		if k.Descending {
 			parts[i] = fmt.Sprintf("%s DESC", k.Column)
 		} else {
 			parts[i] = k.Column
 		}
 	}
-	return fmt.Sprintf("Sort(%s)", strings.Join(parts, ", "))
+	return strings.Join(parts, ", ")
+}
+
+// -----------------------------------------------------------------------------
+// topKNode: the first n rows of a sort — Limit(Sort(x)) fused
+// -----------------------------------------------------------------------------
+
+// topKNode is never built by the LazyFrame API directly; the FuseTopK
+// rule rewrites Limit(n, Sort(x, keys)) into it so the executor can
+// keep n rows in a bounded heap instead of sorting all of x. Output
+// is exactly that of the Sort + Limit it replaces, ties included.
+type topKNode struct {
+	input LogicalPlan
+	keys  []SortKey
+	n     int
+}
+
+func (n *topKNode) Schema() *arrow.Schema   { return n.input.Schema() }
+func (n *topKNode) Children() []LogicalPlan { return []LogicalPlan{n.input} }
+
+// TopK's output is a sorted prefix — the Sort's metadata, which the
+// Limit on top would have passed through unchanged because the sort
+// is enforced.
+func (n *topKNode) PartitionMetadata() *PartitionMetadata {
+	return &PartitionMetadata{
+		SortedBy:     append([]SortKey(nil), n.keys...),
+		SortEnforced: true,
+	}
+}
+func (n *topKNode) String() string {
+	return fmt.Sprintf("TopK(%d; %s)", n.n, formatSortKeys(n.keys))
 }
 
 // -----------------------------------------------------------------------------
@@ -309,11 +345,19 @@ func newAggregateNode(input LogicalPlan, keys []string, aggs []Aggregation) *agg
 
 	// Agg output columns. Count / NUnique → Int64 non-null;
 	// First / Last / Mode preserve the source column's arrow type;
-	// custom Aggregator uses its declared Type; everything else →
+	// custom Aggregator goes through customAggField; everything else →
 	// Float64.
 	for _, a := range aggs {
+		if a.Fn != nil {
+			var in arrow.Field
+			if fm, ok := inSchema.FieldsByName(a.Column); ok && len(fm) > 0 {
+				in = fm[0]
+			}
+			fields = append(fields, customAggField(a, in))
+			continue
+		}
 		t := aggOutputType(a)
-		if a.Fn == nil && (a.Kind == AggFirst || a.Kind == AggLast || a.Kind == AggMode) {
+		if a.Kind == AggFirst || a.Kind == AggLast || a.Kind == AggMode {
 			if fm, ok := inSchema.FieldsByName(a.Column); ok && len(fm) > 0 {
 				t = fm[0].Type
 			}
@@ -417,27 +461,36 @@ func aggOutputNullable(a Aggregation) bool {
 }
 
 // -----------------------------------------------------------------------------
-// joinNode: combine two plans on a key
+// joinNode: combine two plans on one or more key columns
 // -----------------------------------------------------------------------------
 
 type joinNode struct {
 	input     LogicalPlan
 	right     LogicalPlan
-	leftKey   string
-	rightKey  string
+	leftKeys  []string
+	rightKeys []string
 	kind      JoinType
 	outSchema *arrow.Schema
 }
 
+// newJoinNode is the single-key shorthand for newJoinNodeOn.
 func newJoinNode(left, right LogicalPlan, leftKey, rightKey string, kind JoinType) *joinNode {
+	return newJoinNodeOn(left, right, []string{leftKey}, []string{rightKey}, kind)
+}
+
+// newJoinNodeOn builds a join on the composite key leftKeys[i] =
+// rightKeys[i]. Mismatched slice lengths aren't rejected here (plan
+// construction has no error path); Frame.JoinOn reports them at
+// Collect time.
+func newJoinNodeOn(left, right LogicalPlan, leftKeys, rightKeys []string, kind JoinType) *joinNode {
 	lSchema := left.Schema()
 	rSchema := right.Schema()
-	outSchema := buildJoinSchema(lSchema, rSchema, rightKey, kind)
+	outSchema := buildJoinSchema(lSchema, rSchema, rightKeys, kind)
 	return &joinNode{
 		input:     left,
 		right:     right,
-		leftKey:   leftKey,
-		rightKey:  rightKey,
+		leftKeys:  leftKeys,
+		rightKeys: rightKeys,
 		kind:      kind,
 		outSchema: outSchema,
 	}
@@ -477,19 +530,129 @@ func (n *joinNode) PartitionMetadata() *PartitionMetadata {
 	return nil
 }
 func (n *joinNode) String() string {
-	return fmt.Sprintf("Join(%s, left.%s = right.%s)", joinKindLabel(n.kind), n.leftKey, n.rightKey)
+	conds := make([]string, len(n.leftKeys))
+	for i := range n.leftKeys {
+		r := ""
+		if i < len(n.rightKeys) {
+			r = n.rightKeys[i]
+		}
+		conds[i] = fmt.Sprintf("left.%s = right.%s", n.leftKeys[i], r)
+	}
+	return fmt.Sprintf("Join(%s, %s)", joinKindLabel(n.kind), strings.Join(conds, " AND "))
+}
+
+// -----------------------------------------------------------------------------
+// asofJoinNode: attach the nearest-in-time right row to each left row
+// -----------------------------------------------------------------------------
+
+type asofJoinNode struct {
+	input     LogicalPlan
+	right     LogicalPlan
+	leftOn    string
+	rightOn   string
+	by        []string
+	opts      AsofOptions
+	outSchema *arrow.Schema
+}
+
+func newAsofJoinNode(left, right LogicalPlan, leftOn, rightOn string, by []string, opts AsofOptions) *asofJoinNode {
+	// Output layout is JoinOn's with by as the key and left-join
+	// semantics, so the join schema builder applies unchanged.
+	outSchema := buildJoinSchema(left.Schema(), right.Schema(), by, JoinLeft)
+	return &asofJoinNode{
+		input:     left,
+		right:     right,
+		leftOn:    leftOn,
+		rightOn:   rightOn,
+		by:        by,
+		opts:      opts,
+		outSchema: outSchema,
+	}
+}
+
+func (n *asofJoinNode) Schema() *arrow.Schema   { return n.outSchema }
+func (n *asofJoinNode) Children() []LogicalPlan { return []LogicalPlan{n.input, n.right} }
+
+// An as-of join emits exactly one row per left row, in left order,
+// and never rewrites a left column — so unlike joinNode, the left
+// side's full claim (SortedBy included) carries over untouched.
+func (n *asofJoinNode) PartitionMetadata() *PartitionMetadata {
+	return n.input.PartitionMetadata()
+}
+
+func (n *asofJoinNode) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "JoinAsof(%s, left.%s ~ right.%s", asofStrategyLabel(n.opts.Strategy), n.leftOn, n.rightOn)
+	if len(n.by) > 0 {
+		fmt.Fprintf(&sb, ", by=%v", n.by)
+	}
+	if n.opts.Tolerance > 0 {
+		fmt.Fprintf(&sb, ", tolerance=%s", n.opts.Tolerance)
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// -----------------------------------------------------------------------------
+// sjoinNode: spatial join on a geometry predicate
+// -----------------------------------------------------------------------------
+
+type sjoinNode struct {
+	input     LogicalPlan
+	right     LogicalPlan
+	leftGeom  string
+	rightGeom string
+	pred      SpatialPredicate
+	kind      JoinType
+	opts      []Option // as passed to LazyFrame.SJoin; Workers is resolved at compile time
+	outSchema *arrow.Schema
+}
+
+func newSJoinNode(left, right LogicalPlan, leftGeom, rightGeom string, pred SpatialPredicate, kind JoinType, opts []Option) *sjoinNode {
+	// Same layout as Frame.SJoin: JoinOn's with the right geometry as
+	// the dropped "key" (kept for Right / Full).
+	outSchema := buildJoinSchema(left.Schema(), right.Schema(), sjoinOutputKeys(rightGeom, kind), kind)
+	return &sjoinNode{
+		input:     left,
+		right:     right,
+		leftGeom:  leftGeom,
+		rightGeom: rightGeom,
+		pred:      orIntersects(pred),
+		kind:      kind,
+		opts:      opts,
+		outSchema: outSchema,
+	}
+}
+
+func (n *sjoinNode) Schema() *arrow.Schema   { return n.outSchema }
+func (n *sjoinNode) Children() []LogicalPlan { return []LogicalPlan{n.input, n.right} }
+
+// The left-driven kinds emit rows in left order with left columns
+// untouched — each probe row's matches come out together, probe
+// batches in input order — so the left claim, SortedBy included,
+// carries over. Right / Full append right-only rows: no claim.
+func (n *sjoinNode) PartitionMetadata() *PartitionMetadata {
+	if canStreamJoin(n.kind) {
+		return n.input.PartitionMetadata()
+	}
+	return nil
+}
+
+func (n *sjoinNode) String() string {
+	return fmt.Sprintf("SJoin(%s, %s(left.%s, right.%s))",
+		joinKindLabel(n.kind), n.pred, n.leftGeom, n.rightGeom)
 }
 
 // buildJoinSchema mirrors Frame.Join's output construction: left
-// fields first, then right fields except the right join key, with
+// fields first, then right fields except the right join keys, with
 // _right suffix on collisions. Semi/Anti drop the right side
 // entirely.
 //
-// The left join key is always retained in the output — Frame.Join
-// coalesces it against the right side's key values for Right/Full
-// joins — so this function only needs rightKey to know what to
+// The left join keys are always retained in the output — Frame.JoinOn
+// coalesces them against the right side's key values for Right/Full
+// joins — so this function only needs rightKeys to know what to
 // exclude from the right side.
-func buildJoinSchema(lSchema, rSchema *arrow.Schema, rightKey string, kind JoinType) *arrow.Schema {
+func buildJoinSchema(lSchema, rSchema *arrow.Schema, rightKeys []string, kind JoinType) *arrow.Schema {
 	leftFields := lSchema.Fields()
 	leftNames := make(map[string]struct{}, len(leftFields))
 	for _, f := range leftFields {
@@ -503,7 +666,7 @@ func buildJoinSchema(lSchema, rSchema *arrow.Schema, rightKey string, kind JoinT
 		return arrow.NewSchema(out, schemaMetadataPtr(lSchema))
 	}
 	for _, f := range rSchema.Fields() {
-		if f.Name == rightKey {
+		if slices.Contains(rightKeys, f.Name) {
 			continue
 		}
 		if _, clash := leftNames[f.Name]; clash {
@@ -1,5 +1,11 @@
 package gobi
 
+import (
+	"math"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
 // Stats reports column-level bounds used by CanPossiblyMatch to
 // prove predicates unsatisfiable over a data range (typically a
 // parquet row-group). Implementations are supplied by source
@@ -21,6 +27,34 @@ type Stats interface {
 	TotalRows() int64
 }
 
+// BoundsStats is an optional extension of Stats for sources that can
+// bound a geometry column's extent over the range — a GeoParquet file
+// with a bbox covering column does so from the covering leaves' row-
+// group min/max. CanPossiblyMatch type-asserts for it; sources
+// without one never prune spatial predicates.
+type BoundsStats interface {
+	Stats
+	// GeomBounds returns an envelope containing every non-null
+	// geometry of col over the range. ok=false when unknown.
+	GeomBounds(col string) (b geometry.Bounds, ok bool)
+}
+
+// MembershipStats is an optional extension of Stats for sources that
+// can answer "might this range hold value v" — a parquet row group
+// with a bloom filter on the column. Min/max bounds are useless for a
+// high-cardinality ID whose values are spread across every row group;
+// a membership structure rules out individual values instead.
+// CanPossiblyMatch type-asserts for it on Eq and IsIn.
+type MembershipStats interface {
+	Stats
+	// MightContain reports whether col may hold v over the range.
+	// v is a Go scalar as in Stats.MinMax (int64, float64, string,
+	// bool, ...). ok=false when col has no membership structure or v
+	// can't be checked against it; the caller then keeps the range.
+	// might=false is definitive: no row in the range equals v.
+	MightContain(col string, v any) (might, ok bool)
+}
+
 // CanPossiblyMatch reports whether pred could be satisfied by any
 // row in the range described by stats. Returns true when uncertain
 // — false positives are safe (over-read); false negatives break
@@ -32,6 +66,18 @@ type Stats interface {
 //   - col == literal, col != literal
 //   - col <, <=, >, >= literal
 //   - literal on either side (auto-normalized)
+//   - col.IsIn(values): possible iff some value lies within col's
+//     bounds
+//   - col.NotIn(values): possible unless the range holds one value
+//     and it is in the list
+//   - col.Between(lo, hi) with literal bounds: possible iff [lo, hi]
+//     overlaps col's bounds
+//   - for col == literal and IsIn, when stats implements
+//     MembershipStats, additionally iff it might contain the value
+//   - col.Geom().<predicate>(LitGeom(g)) for every spatial predicate
+//     except Disjoint, when stats implements BoundsStats: each one
+//     implies the two envelopes meet (DWithin after widening by its
+//     distance), so a range whose extent misses g's envelope is out
 //
 // Anything else (NOT, arithmetic, custom nodes) is treated as
 // "possibly matches." Used by parquetio for row-group skipping;
@@ -64,6 +110,13 @@ func canMatchNode(n ExprNode, s Stats) bool {
 		return true
 	case *aliasNode:
 		return canMatchNode(n.inner, s)
+	case *geomBinaryNode:
+		return canMatchSpatial(n, s)
+	case *isInNode:
+		return canMatchIsIn(n, s)
+	case *betweenNode:
+		// The lowered Ge/And/Le tree is already in the shapes above.
+		return canMatchNode(n.lowered, s)
 	}
 	// notNode, custom nodes, arithmetic — bail conservatively.
 	return true
@@ -77,6 +130,11 @@ func canMatchComparison(op binOpKind, left, right ExprNode, s Stats) bool {
 	if !ok {
 		return true
 	}
+	if opNorm == bopEq {
+		// col == lit: possible iff min <= lit <= max and, with
+		// MembershipStats, the range might hold lit.
+		return canMatchValues(col.name, []any{lit.value}, s)
+	}
 	minV, maxV, ok := s.MinMax(col.name)
 	if !ok || minV == nil || maxV == nil {
 		return true
@@ -90,14 +148,6 @@ func canMatchComparison(op binOpKind, left, right ExprNode, s Stats) bool {
 
 	litV := lit.value
 	switch opNorm {
-	case bopEq:
-		// col == lit: possible iff min <= lit <= max.
-		loCmp, ok1 := cmpVal(litV, minV)
-		hiCmp, ok2 := cmpVal(litV, maxV)
-		if !ok1 || !ok2 {
-			return true
-		}
-		return loCmp >= 0 && hiCmp <= 0
 	case bopNe:
 		// col != lit: possible unless the range is a single value
 		// equal to lit.
@@ -142,6 +192,165 @@ func canMatchComparison(op binOpKind, left, right ExprNode, s Stats) bool {
 	return true
 }
 
+// canMatchSpatial handles a spatial predicate between a column and a
+// LitGeom, on either side. Every predicate but SPDisjoint (which
+// Geom().Disjoint spells as a NOT over Intersects, so only an explicit
+// Predicate(SPDisjoint, ...) reaches here) is false for a pair whose
+// envelopes are apart, so the test only needs the column's extent
+// over the range.
+func canMatchSpatial(n *geomBinaryNode, s Stats) bool {
+	col, lit, ok := spatialColumnAndLiteral(n)
+	if !ok {
+		return true
+	}
+	bs, ok := s.(BoundsStats)
+	if !ok {
+		return true
+	}
+	extent, ok := bs.GeomBounds(col.name)
+	if !ok || extent.Empty() {
+		return true
+	}
+	m, err := n.pred.matcher()
+	if err != nil || m.disjoint {
+		// A bad predicate must surface at Eval, not vanish here; and
+		// pairs far apart are exactly the ones Disjoint keeps.
+		return true
+	}
+	window := lit.geom.Bounds()
+	if window.Empty() {
+		return true
+	}
+	window = window.Extend(window.MinX-m.expand, window.MinY-m.expand).
+		Extend(window.MaxX+m.expand, window.MaxY+m.expand)
+	return extent.Intersects(window)
+}
+
+// spatialColumnAndLiteral matches a non-distance geomBinaryNode of the
+// form col ⊙ LitGeom or LitGeom ⊙ col.
+func spatialColumnAndLiteral(n *geomBinaryNode) (*colRefNode, *geomLiteralNode, bool) {
+	if n.distance {
+		return nil, nil, false
+	}
+	if col, ok := n.left.(*colRefNode); ok {
+		if lit, ok := n.right.(*geomLiteralNode); ok && lit.geom != nil {
+			return col, lit, true
+		}
+	}
+	if col, ok := n.right.(*colRefNode); ok {
+		if lit, ok := n.left.(*geomLiteralNode); ok && lit.geom != nil {
+			return col, lit, true
+		}
+	}
+	return nil, nil, false
+}
+
+// SpatialPruningColumns lists, sorted and deduplicated, the geometry
+// columns whose spatial predicates in pred CanPossiblyMatch can test
+// against BoundsStats — the same shapes it recognizes, reached
+// through AND, OR and Alias. Sources use it to say in Explain output
+// which per-row bbox coverings a scan will consult.
+func SpatialPruningColumns(pred Expr) []string {
+	set := map[string]struct{}{}
+	var walk func(ExprNode)
+	walk = func(n ExprNode) {
+		switch n := n.(type) {
+		case *binOpNode:
+			if n.op == bopAnd || n.op == bopOr {
+				walk(n.left)
+				walk(n.right)
+			}
+		case *aliasNode:
+			walk(n.inner)
+		case *geomBinaryNode:
+			if col, _, ok := spatialColumnAndLiteral(n); ok {
+				set[col.name] = struct{}{}
+			}
+		}
+	}
+	if pred.node != nil {
+		walk(pred.node)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return sortedKeys(set)
+}
+
+// inBounds reports whether v could lie within [minV, maxV]; true
+// when the types don't compare.
+func inBounds(v, minV, maxV any) bool {
+	loCmp, ok1 := cmpVal(v, minV)
+	hiCmp, ok2 := cmpVal(v, maxV)
+	if !ok1 || !ok2 {
+		return true
+	}
+	return loCmp >= 0 && hiCmp <= 0
+}
+
+// mightContain asks s's membership structure about v, if it has one.
+func mightContain(s Stats, col string, v any) bool {
+	ms, ok := s.(MembershipStats)
+	if !ok {
+		return true
+	}
+	might, ok := ms.MightContain(col, v)
+	return !ok || might
+}
+
+// canMatchIsIn keeps the range if any value passes the same bounds
+// and membership checks as col == value. NotIn is the mirror of Ne:
+// only a range pinned to a single listed value is ruled out.
+func canMatchIsIn(n *isInNode, s Stats) bool {
+	col, ok := n.input.(*colRefNode)
+	if !ok || n.err != nil {
+		return true
+	}
+	if !n.negate {
+		return canMatchValues(col.name, n.values, s)
+	}
+	minV, maxV, ok := s.MinMax(col.name)
+	if !ok || minV == nil || maxV == nil {
+		return true
+	}
+	if spread, ok := cmpVal(minV, maxV); !ok || spread != 0 {
+		return true
+	}
+	// cmpVal compares numbers as float64; past 2^53 two distinct
+	// integers can compare equal, which here would wrongly prune.
+	if f, ok := toFloat64(minV); ok && math.Abs(f) >= 1<<53 {
+		return true
+	}
+	for _, v := range n.values {
+		if c, ok := cmpVal(minV, v); ok && c == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// canMatchValues reports whether col might equal any of values over
+// the range. Each value goes through the column's min/max first —
+// cheap, and usually enough to rule out most of a long list before a
+// bloom filter is probed — then through MembershipStats, which works
+// even when min/max statistics are missing.
+func canMatchValues(col string, values []any, s Stats) bool {
+	if nc, ok := s.NullCount(col); ok && nc >= s.TotalRows() {
+		return true // all-null range: a maybe, as for comparisons
+	}
+	minV, maxV, haveBounds := s.MinMax(col)
+	haveBounds = haveBounds && minV != nil && maxV != nil
+	for _, v := range values {
+		if haveBounds && !inBounds(v, minV, maxV) {
+			continue
+		}
+		if mightContain(s, col, v) {
+			return true
+		}
+	}
+	return false
+}
+
 // normalizeCmp attempts to interpret a binary op as `col OP lit`.
 // If the literal is on the left it flips the operator (`lit > col`
 // → `col < lit`). Returns ok=false when both sides are non-literals
@@ -1,6 +1,11 @@
 package gobi
 
-import "testing"
+import (
+	"slices"
+	"testing"
+
+	"github.com/zoobst/gobi/geometry"
+)
 
 // fakeStats implements the Stats interface with a fixed table of
 // per-column bounds so CanPossiblyMatch tests don't need real
@@ -181,3 +186,145 @@ func TestCanPossiblyMatch_NilStats(t *testing.T) {
 		t.Fatal("nil stats should be conservative")
 	}
 }
+
+// -- Spatial pruning via BoundsStats ------------------------------------
+
+// boundsStats adds a fixed geometry extent per column to fakeStats.
+type boundsStats struct {
+	fakeStats
+	extent map[string]geometry.Bounds
+}
+
+func (s *boundsStats) GeomBounds(col string) (geometry.Bounds, bool) {
+	b, ok := s.extent[col]
+	return b, ok
+}
+
+func TestCanPossiblyMatch_SpatialAgainstExtent(t *testing.T) {
+	// Row group covers x in [0, 10], y in [0, 10].
+	s := &boundsStats{extent: map[string]geometry.Bounds{
+		"geometry": {MinX: 0, MinY: 0, MaxX: 10, MaxY: 10},
+	}}
+	geom := Col("geometry").Geom()
+	far := LitGeom(geometry.Point{X: 20, Y: 5})
+	near := LitGeom(geometry.Point{X: 9, Y: 5})
+	cases := []struct {
+		name string
+		pred Expr
+		want bool
+	}{
+		{"intersects_far", geom.Intersects(far), false},
+		{"intersects_near", geom.Intersects(near), true},
+		{"within_far", geom.Within(far), false},
+		{"literal_on_left", far.Geom().Contains(Col("geometry")), false},
+		// DWithin widens the window by its distance.
+		{"dwithin_short", geom.DWithin(far, 5, geometry.UnitMeters), false},
+		{"dwithin_long", geom.DWithin(far, 10, geometry.UnitMeters), true},
+		{"and_prunes", Col("x").Gt(Lit(int64(0))).And(geom.Intersects(far)), false},
+		{"or_keeps", geom.Intersects(far).Or(geom.Intersects(near)), true},
+		// Disjoint is NOT(intersects): conservative.
+		{"disjoint", geom.Disjoint(far), true},
+		{"other_column", Col("zone").Geom().Intersects(far), true},
+		{"column_pair", geom.Intersects(Col("zone")), true},
+	}
+	for _, tc := range cases {
+		if got := CanPossiblyMatch(tc.pred, s); got != tc.want {
+			t.Errorf("%s: CanPossiblyMatch(%s) = %v, want %v", tc.name, tc.pred, got, tc.want)
+		}
+	}
+	// Plain Stats can't bound a geometry: never prunes.
+	if !CanPossiblyMatch(geom.Intersects(far), intStats("x", 0, 10)) {
+		t.Fatal("stats without GeomBounds should be conservative")
+	}
+}
+
+func TestSpatialPruningColumns(t *testing.T) {
+	region := LitGeom(geometry.Point{X: 1, Y: 1})
+	pred := Col("b").Geom().Intersects(region).
+		And(Col("a").Geom().DWithin(region, 5, geometry.UnitMeters).Or(Col("b").Geom().Within(region))).
+		And(Col("c").Geom().Disjoint(region))
+	if got := SpatialPruningColumns(pred); !slices.Equal(got, []string{"a", "b"}) {
+		t.Fatalf("SpatialPruningColumns = %v, want [a b]", got)
+	}
+	if got := SpatialPruningColumns(Col("x").Gt(Lit(int64(1)))); got != nil {
+		t.Fatalf("SpatialPruningColumns = %v, want nil", got)
+	}
+}
+
+// -- Membership pruning via MembershipStats -----------------------------
+
+// bloomStats adds an exact membership set per column to fakeStats.
+type bloomStats struct {
+	fakeStats
+	members map[string]map[any]bool
+}
+
+func (s *bloomStats) MightContain(col string, v any) (bool, bool) {
+	m, ok := s.members[col]
+	if !ok {
+		return false, false
+	}
+	return m[v], true
+}
+
+func TestCanPossiblyMatch_MembershipStats(t *testing.T) {
+	// id spans [0, 100] but only holds 3, 50 and 97.
+	s := &bloomStats{
+		fakeStats: *intStats("id", 0, 100),
+		members:   map[string]map[any]bool{"id": {int64(3): true, int64(50): true, int64(97): true}},
+	}
+	cases := []struct {
+		name string
+		pred Expr
+		want bool
+	}{
+		{"eq_member", Col("id").Eq(Lit(int64(50))), true},
+		{"eq_absent", Col("id").Eq(Lit(int64(51))), false},
+		{"eq_literal_left", Lit(int64(51)).Eq(Col("id")), false},
+		{"isin_one_member", Col("id").IsIn([]int64{1, 2, 97}), true},
+		{"isin_none", Col("id").IsIn([]int64{1, 2, 4}), false},
+		// Out of bounds: pruned by min/max before the filter is asked.
+		{"isin_out_of_bounds", Col("id").IsIn([]int64{-5, 500}), false},
+		{"isin_empty", Col("id").IsIn([]int64{}), false},
+		// No membership structure for the column: min/max only.
+		{"other_column", Col("x").Eq(Lit(int64(51))), true},
+		{"ne_ignores_membership", Col("id").Ne(Lit(int64(51))), true},
+	}
+	for _, tc := range cases {
+		if got := CanPossiblyMatch(tc.pred, s); got != tc.want {
+			t.Errorf("%s: CanPossiblyMatch(%s) = %v, want %v", tc.name, tc.pred, got, tc.want)
+		}
+	}
+	// IsIn on plain Stats still prunes on bounds alone.
+	if CanPossiblyMatch(Col("x").IsIn([]int64{20, 30}), intStats("x", 0, 10)) {
+		t.Fatal("IsIn entirely outside [0,10] should be pruned")
+	}
+	if !CanPossiblyMatch(Col("x").IsIn([]int64{5, 30}), intStats("x", 0, 10)) {
+		t.Fatal("IsIn with a value inside [0,10] should be kept")
+	}
+}
+
+func TestCanPossiblyMatch_NotInAndBetween(t *testing.T) {
+	cases := []struct {
+		name  string
+		pred  Expr
+		stats *fakeStats
+		want  bool
+	}{
+		{"between_overlaps", Col("x").Between(Lit(int64(5)), Lit(int64(20))), intStats("x", 0, 10), true},
+		{"between_below", Col("x").Between(Lit(int64(-9)), Lit(int64(-1))), intStats("x", 0, 10), false},
+		{"between_above", Col("x").Between(Lit(int64(11)), Lit(int64(20))), intStats("x", 0, 10), false},
+		{"between_touches", Col("x").Between(Lit(int64(10)), Lit(int64(20))), intStats("x", 0, 10), true},
+		{"between_col_bound", Col("x").Between(Lit(int64(11)), Col("y")), intStats("x", 0, 10), false},
+		{"not_in_spread", Col("x").NotIn([]int64{0, 10}), intStats("x", 0, 10), true},
+		{"not_in_constant_listed", Col("x").NotIn([]int64{3, 7}), intStats("x", 7, 7), false},
+		{"not_in_constant_unlisted", Col("x").NotIn([]int64{3}), intStats("x", 7, 7), true},
+		// Past 2^53 float comparison can't tell neighbours apart.
+		{"not_in_large", Col("x").NotIn([]int64{1<<53 + 1}), intStats("x", 1<<53, 1<<53), true},
+	}
+	for _, tc := range cases {
+		if got := CanPossiblyMatch(tc.pred, tc.stats); got != tc.want {
+			t.Errorf("%s: CanPossiblyMatch(%s) = %v, want %v", tc.name, tc.pred, got, tc.want)
+		}
+	}
+}
@@ -70,14 +70,14 @@ func (s Series) Float32s() ([]float32, error) {
 }
 
 // Strings returns the Series values as []string. Nulls come through as "".
-// Works for both String and LargeString columns.
+// Works for String, LargeString and categorical columns.
 func (s Series) Strings() ([]string, error) {
 	if s.col == nil {
 		return nil, fmt.Errorf("Series.Strings: nil column")
 	}
 	got := s.DataType().ID()
-	if got != arrow.STRING && got != arrow.LARGE_STRING {
-		return nil, fmt.Errorf("%w: Series.Strings requires String/LargeString, got %s",
+	if got != arrow.STRING && got != arrow.LARGE_STRING && !s.IsCategorical() {
+		return nil, fmt.Errorf("%w: Series.Strings requires String/LargeString/categorical, got %s",
 			ErrColumnTypeMismatch, s.DataType())
 	}
 	out := make([]string, s.Len())
@@ -97,6 +97,16 @@ func (s Series) Strings() ([]string, error) {
 					out[idx+i] = a.Value(i)
 				}
 			}
+		case *array.Dictionary:
+			c, ok := asCatChunk(a)
+			if !ok {
+				return nil, fmt.Errorf("Series.Strings: unexpected dictionary type %s", a.DataType())
+			}
+			for i := range n {
+				if !c.IsNull(i) {
+					out[idx+i] = c.value(i)
+				}
+			}
 		default:
 			return nil, fmt.Errorf("Series.Strings: unexpected chunk type %T", chunk)
 		}
@@ -0,0 +1,328 @@
+package gobi
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// -----------------------------------------------------------------------------
+// Null filling and interpolation
+//
+// The Series methods here do the work; Expr.FillNull / FillNullWith /
+// Interpolate / InterpolateBy (expr_fill.go) wrap them so they compose
+// with Over and run in lazy plans, and Frame.DropNulls sits alongside.
+// -----------------------------------------------------------------------------
+
+// FillStrategy picks how Series.FillNullWith / Expr.FillNullWith
+// chooses a replacement for each null.
+type FillStrategy uint8
+
+const (
+	// FillForward carries the last non-null value forward (pandas
+	// ffill). Nulls before the first value stay null.
+	FillForward FillStrategy = iota
+	// FillBackward carries the next non-null value backward (bfill).
+	// Nulls after the last value stay null.
+	FillBackward
+	// FillMean replaces every null with the mean of the non-null
+	// values. Numeric only; the result is Float64.
+	FillMean
+)
+
+func (s FillStrategy) String() string {
+	switch s {
+	case FillForward:
+		return "forward"
+	case FillBackward:
+		return "backward"
+	case FillMean:
+		return "mean"
+	}
+	return "unknown"
+}
+
+// FillNull returns s with every null replaced by value. value must be
+// appendable to s's type the way a custom aggregator's output is
+// (float64 for Float64, string for String, []byte for Binary, …); Go
+// ints convert for Int64 and ints or float32 for Float64 columns, so
+// FillNull(0) works on either. A nil value returns s unchanged. The
+// output keeps s's field, marked non-nullable.
+func (s Series) FillNull(value any) (Series, error) {
+	if s.col == nil {
+		return Series{}, fmt.Errorf("gobi: Series.FillNull on empty series")
+	}
+	if value == nil || s.col.Data().NullN() == 0 {
+		return s, nil
+	}
+	value = fillValueFor(value, s.DataType())
+	b, err := builderForType(memory.DefaultAllocator, s.DataType())
+	if err != nil {
+		return Series{}, fmt.Errorf("gobi: Series.FillNull: %w", err)
+	}
+	defer b.Release()
+	row := 0
+	for _, chunk := range s.col.Data().Chunks() {
+		for i := range chunk.Len() {
+			if chunk.IsNull(i) {
+				if err := appendCustomValue(b, value); err != nil {
+					return Series{}, fmt.Errorf("gobi: Series.FillNull(%v) on %s column %q: %w",
+						value, s.DataType(), s.name, err)
+				}
+			} else if err := appendPrimitiveAt(s, row, b); err != nil {
+				return Series{}, err
+			}
+			row++
+		}
+	}
+	f := s.field
+	f.Nullable = false
+	return seriesFromBuilder(f, b), nil
+}
+
+// FillNullWith returns s with nulls replaced by strategy. Forward and
+// backward fill work on every type and keep s's field; FillMean needs
+// a numeric column and returns Float64.
+//
+// The fill runs over s in its current order. To fill per entity in
+// time order — each vehicle's last known position, say — use the Expr
+// form under OverOrdered:
+//
+//	Col("lat").FillNullWith(gobi.FillForward).OverOrdered([]string{"vehicle"}, gobi.SortKey{Column: "ts"})
+func (s Series) FillNullWith(strategy FillStrategy) (Series, error) {
+	if s.col == nil {
+		return Series{}, fmt.Errorf("gobi: Series.FillNullWith on empty series")
+	}
+	switch strategy {
+	case FillForward, FillBackward:
+		nulls := s.Nulls()
+		src := make([]int, len(nulls))
+		last := -1
+		for k := range nulls {
+			i := k
+			if strategy == FillBackward {
+				i = len(nulls) - 1 - k
+			}
+			if !nulls[i] {
+				last = i
+			}
+			src[i] = last
+		}
+		out, err := gatherRows(s, src)
+		if err != nil {
+			return Series{}, fmt.Errorf("gobi: Series.FillNullWith(%s): %w", strategy, err)
+		}
+		return out, nil
+	case FillMean:
+		if !s.isNumeric() {
+			return Series{}, fmt.Errorf("%w: FillNullWith(mean) on %s column %q",
+				ErrNotNumeric, s.DataType(), s.name)
+		}
+		vals, valid, err := materializeF64(s)
+		if err != nil {
+			return Series{}, err
+		}
+		var sum float64
+		n := 0
+		for i, v := range vals {
+			if valid[i] {
+				sum += v
+				n++
+			}
+		}
+		if n == 0 {
+			return buildFloat64Series(s.name, vals, valid), nil
+		}
+		for i := range vals {
+			if !valid[i] {
+				vals[i], valid[i] = sum/float64(n), true
+			}
+		}
+		return buildFloat64Series(s.name, vals, nil), nil
+	}
+	return Series{}, fmt.Errorf("gobi: FillNullWith: unknown strategy %d", strategy)
+}
+
+// Interpolate fills each run of nulls that has a non-null value on
+// both sides by linear interpolation over row position — the k-th of
+// m missing rows between a and b gets a + (b-a)·k/(m+1). Leading and
+// trailing nulls stay null (no extrapolation). s must be numeric; the
+// result is Float64. NaN is a value here, not a gap.
+//
+// Use InterpolateBy when rows are unevenly spaced in time.
+func (s Series) Interpolate() (Series, error) {
+	return s.interpolate(nil, "Interpolate")
+}
+
+// InterpolateBy is Interpolate weighted by the by column instead of
+// row position: a missing row at time t between anchors (t0, a) and
+// (t1, b) gets a + (b-a)·(t-t0)/(t1-t0). by is a Timestamp or Date
+// column (or any numeric one, e.g. distance along a track) of the same
+// length, and s should be in by order — the anchors are the nearest
+// non-null rows before and after, by position. A row whose by value is
+// null stays null and is never an anchor; anchors sharing a by value
+// with the gap (t1 == t0) give a.
+//
+//	// heal GPS dropouts, weighting by when each fix was due
+//	Col("lat").InterpolateBy(Col("ts")).OverOrdered([]string{"vehicle"}, gobi.SortKey{Column: "ts"})
+func (s Series) InterpolateBy(by Series) (Series, error) {
+	if by.col == nil || by.Len() != s.Len() {
+		return Series{}, fmt.Errorf("%w: InterpolateBy: by has %d rows, series has %d",
+			ErrColumnLenMismatch, by.Len(), s.Len())
+	}
+	var keys []float64
+	var keyValid []bool
+	if by.IsDateTime() {
+		ns, valid, err := timeKeysNanos(by)
+		if err != nil {
+			return Series{}, err
+		}
+		keys, keyValid = make([]float64, len(ns)), valid
+		for i, v := range ns {
+			keys[i] = float64(v)
+		}
+	} else if by.isNumeric() {
+		var err error
+		if keys, keyValid, err = materializeF64(by); err != nil {
+			return Series{}, err
+		}
+	} else {
+		return Series{}, fmt.Errorf("%w: InterpolateBy: by column %q is %s, not a Timestamp, Date or numeric column",
+			ErrColumnTypeMismatch, by.name, by.DataType())
+	}
+	return s.interpolate(func(i int) (float64, bool) { return keys[i], keyValid[i] }, "InterpolateBy")
+}
+
+// interpolate is the shared linear fill. pos returns a row's
+// coordinate (and whether it has one); nil means row position.
+func (s Series) interpolate(pos func(i int) (float64, bool), op string) (Series, error) {
+	if s.col == nil {
+		return Series{}, fmt.Errorf("gobi: Series.%s on empty series", op)
+	}
+	if !s.isNumeric() {
+		return Series{}, fmt.Errorf("%w: %s on %s column %q", ErrNotNumeric, op, s.DataType(), s.name)
+	}
+	if pos == nil {
+		pos = func(i int) (float64, bool) { return float64(i), true }
+	}
+	vals, valid, err := materializeF64(s)
+	if err != nil {
+		return Series{}, err
+	}
+	// prev is the last anchor: a row with both a value and a position.
+	prev := -1
+	for i := range vals {
+		if !valid[i] {
+			continue
+		}
+		if _, ok := pos(i); !ok {
+			continue
+		}
+		if prev >= 0 && i-prev > 1 {
+			t0, _ := pos(prev)
+			t1, _ := pos(i)
+			for j := prev + 1; j < i; j++ {
+				t, ok := pos(j)
+				if !ok || valid[j] {
+					continue
+				}
+				frac := 0.0
+				if t1 != t0 {
+					frac = (t - t0) / (t1 - t0)
+				}
+				vals[j], valid[j] = vals[prev]+(vals[i]-vals[prev])*frac, true
+			}
+		}
+		prev = i
+	}
+	return buildFloat64Series(s.name, vals, valid), nil
+}
+
+// DropNulls returns the rows of f with no null in any subset column —
+// every column when subset is empty. NaN is a value, not a null.
+// LazyFrame.DropNulls is the streaming form.
+func (f *Frame) DropNulls(subset ...string) (*Frame, error) {
+	if f == nil {
+		return nil, fmt.Errorf("gobi: Frame.DropNulls on nil frame")
+	}
+	if len(subset) == 0 {
+		subset = f.ColumnNames()
+	}
+	if len(subset) == 0 {
+		return f, nil
+	}
+	return f.FilterExpr(notNullPredicate(subset))
+}
+
+// notNullPredicate is the IsNotNull conjunction over cols that
+// DropNulls filters on.
+func notNullPredicate(cols []string) Expr {
+	cond := Col(cols[0]).IsNotNull()
+	for _, c := range cols[1:] {
+		cond = cond.And(Col(c).IsNotNull())
+	}
+	return cond
+}
+
+// gatherRows builds a Series of s's type and field whose row i is
+// s[src[i]], or null where src[i] < 0. It is Shift's and the fills'
+// take kernel.
+func gatherRows(s Series, src []int) (Series, error) {
+	b, err := builderForType(memory.DefaultAllocator, s.DataType())
+	if err != nil {
+		return Series{}, err
+	}
+	defer b.Release()
+	for _, j := range src {
+		if j < 0 {
+			b.AppendNull()
+			continue
+		}
+		if err := appendPrimitiveAt(s, j, b); err != nil {
+			return Series{}, err
+		}
+	}
+	f := s.field
+	f.Nullable = true
+	return seriesFromBuilder(f, b), nil
+}
+
+// seriesFromBuilder finishes b into a single-chunk Series carrying
+// field.
+func seriesFromBuilder(field arrow.Field, b interface{ NewArray() arrow.Array }) Series {
+	arr := b.NewArray()
+	defer arr.Release()
+	chunked := arrow.NewChunked(arr.DataType(), []arrow.Array{arr})
+	col := arrow.NewColumn(field, chunked)
+	chunked.Release()
+	return NewSeries(col)
+}
+
+// fillValueFor adapts a Go fill value to a column type: int / int32
+// become int64 for an Int64 column, and any Go integer or float32
+// becomes float64 for a Float64 one. Anything else passes through for
+// appendCustomValue to check.
+func fillValueFor(fill any, dt arrow.DataType) any {
+	switch dt.ID() {
+	case arrow.INT64:
+		switch x := fill.(type) {
+		case int:
+			return int64(x)
+		case int32:
+			return int64(x)
+		}
+	case arrow.FLOAT64:
+		switch x := fill.(type) {
+		case int:
+			return float64(x)
+		case int32:
+			return float64(x)
+		case int64:
+			return float64(x)
+		case float32:
+			return float64(x)
+		}
+	}
+	return fill
+}
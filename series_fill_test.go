@@ -0,0 +1,188 @@
+package gobi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// trackFrame is a gappy GPS track for two vehicles, rows interleaved
+// and out of time order. Fixes are due every 10s but arrive unevenly.
+func trackFrame(t *testing.T) *Frame {
+	t.Helper()
+	type row struct {
+		Vehicle string    `gobi:"vehicle"`
+		TS      time.Time `gobi:"ts"`
+		Lat     *float64  `gobi:"lat"`
+		Fix     *string   `gobi:"fix"`
+	}
+	f64 := func(v float64) *float64 { return &v }
+	str := func(v string) *string { return &v }
+	t0 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return t0.Add(time.Duration(sec) * time.Second) }
+	f, err := FromStructs([]row{
+		{"a", at(0), f64(10), str("gps")},
+		{"b", at(0), nil, nil},
+		{"a", at(10), nil, nil},
+		{"b", at(10), f64(50), str("gps")},
+		{"a", at(40), f64(40), str("dr")},
+		{"a", at(30), nil, nil},
+		{"b", at(20), nil, nil},
+		{"b", at(60), f64(10), str("gps")},
+		{"a", at(50), nil, nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// fillStrings renders s's values with %v, nulls as "null".
+func fillStrings(s Series) []string {
+	out := make([]string, s.Len())
+	for i := range out {
+		out[i] = "null"
+		if v, err := readScalarAt(s, i); err == nil && v != nil {
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+func TestSeries_FillNull(t *testing.T) {
+	f := trackFrame(t)
+	lat := mustColumn(t, f, "lat")
+	filled, err := lat.FillNull(0) // Go int on a Float64 column
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fillStrings(filled); !slices.Equal(got, []string{"10", "0", "0", "50", "40", "0", "0", "10", "0"}) {
+		t.Fatalf("FillNull(0) = %v", got)
+	}
+	if filled.field.Nullable || filled.Name() != "lat" {
+		t.Fatalf("field = %+v, want non-nullable lat", filled.field)
+	}
+
+	fix, err := mustColumn(t, f, "fix").FillNull("none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := fix.Strings(); !slices.Equal(got, []string{"gps", "none", "none", "gps", "dr", "none", "none", "gps", "none"}) {
+		t.Fatalf("FillNull(\"none\") = %v", got)
+	}
+	if _, err := mustColumn(t, f, "fix").FillNull(1.5); err == nil {
+		t.Fatal("FillNull(1.5) on a String column: expected error")
+	}
+	if same, err := lat.FillNull(nil); err != nil || same.col != lat.col {
+		t.Fatalf("FillNull(nil) should return the series unchanged, err = %v", err)
+	}
+}
+
+func TestSeries_FillNullWith(t *testing.T) {
+	f := trackFrame(t)
+	lat := mustColumn(t, f, "lat")
+	cases := []struct {
+		strategy FillStrategy
+		want     []string
+	}{
+		{FillForward, []string{"10", "10", "10", "50", "40", "40", "40", "10", "10"}},
+		{FillBackward, []string{"10", "50", "50", "50", "40", "10", "10", "10", "null"}},
+		{FillMean, []string{"10", "27.5", "27.5", "50", "40", "27.5", "27.5", "10", "27.5"}},
+	}
+	for _, c := range cases {
+		out, err := lat.FillNullWith(c.strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fillStrings(out); !slices.Equal(got, c.want) {
+			t.Fatalf("FillNullWith(%s) = %v, want %v", c.strategy, got, c.want)
+		}
+	}
+
+	// Forward fill works on any type and keeps the field.
+	fix, err := mustColumn(t, f, "fix").FillNullWith(FillForward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := fix.Strings(); !slices.Equal(got, []string{"gps", "gps", "gps", "gps", "dr", "dr", "dr", "gps", "gps"}) {
+		t.Fatalf("ffill String = %v", got)
+	}
+	if _, err := mustColumn(t, f, "fix").FillNullWith(FillMean); !errors.Is(err, ErrNotNumeric) {
+		t.Fatalf("mean of String: err = %v, want ErrNotNumeric", err)
+	}
+}
+
+func TestSeries_Interpolate(t *testing.T) {
+	f, err := trackFrame(t).SortBy(SortKey{Column: "vehicle"}, SortKey{Column: "ts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a: ts 0,10,30,40,50  lat 10,_,_,40,_
+	// b: ts 0,10,20,60     lat _,50,_,10
+	lat := mustColumn(t, f, "lat")
+
+	byPos, err := lat.Interpolate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Positionally, b's leading null sits between a's last value and
+	// b's first, so the run bridges vehicles — what OverOrdered is for.
+	if got := fillStrings(byPos); !slices.Equal(got, []string{"10", "20", "30", "40", "43.333333333333336", "46.666666666666664", "50", "30", "10"}) {
+		t.Fatalf("Interpolate = %v", got)
+	}
+
+	// Within vehicle a, positionally the two gap rows split 10..40
+	// evenly; by time, ts=10 is 1/4 of the way from ts=0 to ts=40 and
+	// ts=30 is 3/4. The trailing null at ts=50 stays null either way.
+	a, err := f.FilterExpr(Col("vehicle").Eq(Lit("a")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aPos, err := mustColumn(t, a, "lat").Interpolate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fillStrings(aPos); !slices.Equal(got, []string{"10", "20", "30", "40", "null"}) {
+		t.Fatalf("Interpolate(a) = %v", got)
+	}
+	byTime, err := mustColumn(t, a, "lat").InterpolateBy(mustColumn(t, a, "ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fillStrings(byTime); !slices.Equal(got, []string{"10", "17.5", "32.5", "40", "null"}) {
+		t.Fatalf("InterpolateBy(ts) = %v", got)
+	}
+
+	if _, err := mustColumn(t, f, "fix").Interpolate(); !errors.Is(err, ErrNotNumeric) {
+		t.Fatalf("Interpolate String: err = %v, want ErrNotNumeric", err)
+	}
+	if _, err := lat.InterpolateBy(mustColumn(t, f, "fix")); !errors.Is(err, ErrColumnTypeMismatch) {
+		t.Fatalf("InterpolateBy String: err = %v, want ErrColumnTypeMismatch", err)
+	}
+	if _, err := lat.InterpolateBy(mustColumn(t, a, "ts")); !errors.Is(err, ErrColumnLenMismatch) {
+		t.Fatalf("InterpolateBy short key: err = %v, want ErrColumnLenMismatch", err)
+	}
+}
+
+func TestFrame_DropNulls(t *testing.T) {
+	f := trackFrame(t)
+	out, err := f.DropNulls("lat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := floatsOrNaN(t, out, "lat"); !slices.Equal(got, []float64{10, 50, 40, 10}) {
+		t.Fatalf("DropNulls(lat) lat = %v", got)
+	}
+	all, err := f.DropNulls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all.NumRows() != 4 || all.NumCols() != f.NumCols() {
+		t.Fatalf("DropNulls() = %d×%d, want 4×%d", all.NumRows(), all.NumCols(), f.NumCols())
+	}
+	if _, err := f.DropNulls("speed"); !errors.Is(err, ErrColumnNotFound) {
+		t.Fatalf("DropNulls(missing): err = %v, want ErrColumnNotFound", err)
+	}
+}
@@ -3,9 +3,7 @@ package gobi
 import (
 	"fmt"
 
-	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
-	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
 // Shift returns a new Series with values shifted by n positions.
@@ -38,32 +36,14 @@ func (s Series) Shift(n int) (Series, error) {
 		}
 		src[i] = j
 	}
-	pool := memory.DefaultAllocator
-	b, err := builderForType(pool, s.DataType())
-	if err != nil {
-		return Series{}, fmt.Errorf("gobi: Series.Shift: %w", err)
-	}
-	defer b.Release()
-	for _, j := range src {
-		if j < 0 {
-			b.AppendNull()
-			continue
-		}
-		if err := appendPrimitiveAt(s, j, b); err != nil {
-			return Series{}, err
-		}
-	}
-	arr := b.NewArray()
-	defer arr.Release()
-	chunked := arrow.NewChunked(arr.DataType(), []arrow.Array{arr})
 	// Preserve the source's field (name, metadata, nullable flag) —
 	// nullability becomes irrelevant since we've almost certainly
 	// introduced nulls at the edges.
-	f := s.field
-	f.Nullable = true
-	col := arrow.NewColumn(f, chunked)
-	chunked.Release()
-	return NewSeries(col), nil
+	out, err := gatherRows(s, src)
+	if err != nil {
+		return Series{}, fmt.Errorf("gobi: Series.Shift: %w", err)
+	}
+	return out, nil
 }
 
 // Diff returns a Series holding the element-wise difference between
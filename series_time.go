@@ -260,8 +260,13 @@ func viewTimestamp(s Series) (tsView, bool) {
 	if len(chunks) != 1 {
 		return tsView{}, false
 	}
-	loc := timeLocation(s)
-	switch a := chunks[0].(type) {
+	return viewTimestampChunk(chunks[0], timeLocation(s))
+}
+
+// viewTimestampChunk is viewTimestamp for one chunk of a multi-chunk
+// column; loc is the column's timeLocation.
+func viewTimestampChunk(chunk arrow.Array, loc *time.Location) (tsView, bool) {
+	switch a := chunk.(type) {
 	case *array.Timestamp:
 		return tsView{arr: a, kind: 1, tsVals: a.TimestampValues(), tsUnit: a.DataType().(*arrow.TimestampType).Unit, loc: loc}, true
 	case *array.Date32:
@@ -0,0 +1,92 @@
+package gobi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// -----------------------------------------------------------------------------
+// Streaming sinks
+//
+// Collect concatenates a pipeline's whole output into one Frame, so
+// writing a filtered scan of a 50 GB file back to disk with
+// Collect + WriteFile needs the result to fit in memory. A sink
+// terminal instead drives the compiled operator tree straight into an
+// incremental writer: each batch is written and released before the
+// next is pulled, so a streaming pipeline holds roughly one batch per
+// scan worker however large the output.
+//
+// The file formats live in the IO packages (which import gobi, so gobi
+// can't name them): parquetio.SinkFile, csvio.SinkFile,
+// geojsonio.SinkFile and gpkgio.SinkFile each wrap a BatchSink and
+// hand it to LazyFrame.Sink. Implement BatchSink to stream into
+// anything else — a database, a message queue, an HTTP upload.
+// -----------------------------------------------------------------------------
+
+// BatchSink receives a pipeline's output one batch at a time. Sink
+// calls Open once with the plan's output schema, Write once per
+// non-empty batch in output order, and Close exactly once — after the
+// last batch, or after a failure, so the sink can release what Open
+// acquired. Write's Frame is released once Write returns; a sink that
+// keeps it must Retain it.
+type BatchSink interface {
+	Open(schema *arrow.Schema) error
+	Write(batch *Frame) error
+	Close() error
+}
+
+// Sink runs lf like Collect — default optimizer, compiled operator
+// tree — and writes the result to sink batch by batch instead of
+// concatenating it. See Stream for which operators pass batches
+// straight through and which buffer their input.
+//
+// The first error wins: a compile error (sink is left unopened), an
+// Open / Write error, a failing operator, or ctx.Err() on
+// cancellation. After a successful Open, Close runs on every path and
+// its error is joined to any earlier one. A sink that wrote partial
+// output before the failure is responsible for discarding it; the
+// IO-package SinkFile functions remove the partial file.
+func (lf *LazyFrame) Sink(ctx context.Context, sink BatchSink) error {
+	return lf.SinkWith(ctx, sink, nil)
+}
+
+// SinkWith is Sink with options — a memory budget past which Sort,
+// Aggregate and Join spill to disk; see CollectWith. Sorting a file
+// larger than memory into another file is
+//
+//	lf.SortBy(key).SinkWith(ctx, sink, &gobi.CollectOptions{MemoryLimit: 1 << 30})
+func (lf *LazyFrame) SinkWith(ctx context.Context, sink BatchSink, opts *CollectOptions) error {
+	op, err := CompileWith(Optimize(lf.plan), opts)
+	if err != nil {
+		return err
+	}
+	if err := sink.Open(op.Schema()); err != nil {
+		op.Close()
+		return err
+	}
+	var runErr error
+	streamBatches(ctx, op, func(batch *Frame, err error) bool {
+		if err != nil {
+			runErr = err
+			return false
+		}
+		err = sink.Write(batch)
+		batch.Release()
+		if err != nil {
+			runErr = err
+			return false
+		}
+		return true
+	})
+	return errors.Join(runErr, sink.Close())
+}
+
+// EmptyFrame returns a zero-row Frame with schema — one empty chunk
+// per column. Sinks use it to lay down headers, table DDL or file
+// schemas from BatchSink.Open's schema before any rows arrive, through
+// the same code path that later handles the batches.
+func EmptyFrame(schema *arrow.Schema) (*Frame, error) {
+	return emptyFrame(schema)
+}
@@ -0,0 +1,82 @@
+package gobi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// recordingSink counts what LazyFrame.Sink hands it and optionally
+// fails the nth Write.
+type recordingSink struct {
+	schema        *arrow.Schema
+	batches, rows int
+	closed        int
+	failAt        int
+}
+
+func (s *recordingSink) Open(schema *arrow.Schema) error { s.schema = schema; return nil }
+
+func (s *recordingSink) Write(batch *Frame) error {
+	s.batches++
+	if s.batches == s.failAt {
+		return errors.New("disk full")
+	}
+	s.rows += batch.NumRows()
+	return nil
+}
+
+func (s *recordingSink) Close() error { s.closed++; return nil }
+
+func TestLazy_Sink(t *testing.T) {
+	type row struct {
+		ID int64 `gobi:"id"`
+		V  int64 `gobi:"v"`
+	}
+	rows := make([]row, 3*defaultBatchRows+10)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), V: int64(i % 10)}
+	}
+	df, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := df.Lazy().Filter(Col("v").Lt(Lit(int64(3)))).SelectCols("id")
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &recordingSink{}
+	if err := lf.Sink(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	if s.schema.NumFields() != 1 || s.schema.Field(0).Name != "id" {
+		t.Fatalf("Open schema = %v", s.schema)
+	}
+	if s.batches < 2 || s.rows != want.NumRows() || s.closed != 1 {
+		t.Fatalf("sink saw %d batches, %d rows, %d closes; Collect has %d rows",
+			s.batches, s.rows, s.closed, want.NumRows())
+	}
+
+	// A failing Write stops the pipeline and still closes the sink.
+	s = &recordingSink{failAt: 2}
+	if err := lf.Sink(context.Background(), s); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("err = %v, want disk full", err)
+	}
+	if s.batches != 2 || s.closed != 1 {
+		t.Fatalf("after failure: %d writes, %d closes", s.batches, s.closed)
+	}
+
+	// A plan with no rows opens and closes the sink without writing.
+	s = &recordingSink{}
+	if err := df.Lazy().Filter(Col("v").Gt(Lit(int64(99)))).Sink(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	if s.schema == nil || s.batches != 0 || s.closed != 1 {
+		t.Fatalf("empty plan: schema %v, %d writes, %d closes", s.schema, s.batches, s.closed)
+	}
+}
@@ -2,6 +2,8 @@ package gobi
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"sync"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -16,63 +18,250 @@ import (
 // scheduling overhead swamping the useful work.
 const SJoinMinParallelRows = 1024
 
+// SpatialPredicate is a binary spatial predicate for SJoin and
+// Geom().Predicate, evaluated as pred(left, right): one of the
+// SpatialOp constants, or SPDWithin for the parameterised distance
+// test. A nil SpatialPredicate means SPIntersects.
+//
+// It is an interface so SPDWithin can carry its distance while the
+// named predicates stay constants; every implementation is
+// comparable, so predicates still work in == comparisons and
+// switches.
+type SpatialPredicate interface {
+	String() string
+	// matcher validates the predicate and resolves it to its per-pair
+	// test.
+	matcher() (spatialMatcher, error)
+}
+
+// SpatialOp is a named spatial predicate — a SpatialPredicate without
+// parameters.
+type SpatialOp uint8
+
 const (
 	// SPIntersects matches when the left and right geometries share any point.
-	SPIntersects SpatialPredicate = iota
+	SPIntersects SpatialOp = iota
 	// SPContains matches when the left geometry fully contains the right.
 	SPContains
 	// SPWithin matches when the left geometry lies fully within the right.
 	SPWithin
+	// SPTouches matches when the geometries share boundary points but no
+	// interior points (geometry.Touches).
+	SPTouches
+	// SPOverlaps matches when same-dimension geometries share interior
+	// points but neither contains the other (geometry.Overlaps).
+	SPOverlaps
+	// SPCrosses matches when the geometries share some but not all
+	// interior points, typically a line crossing a polygon or another
+	// line (geometry.Crosses).
+	SPCrosses
+	// SPCovers matches when no point of the right geometry lies outside
+	// the left one (geometry.Covers). Boundary points count, so a zone
+	// covers a point on its edge.
+	SPCovers
+	// SPCoveredBy matches when no point of the left geometry lies outside
+	// the right one (geometry.CoveredBy) — SPCovers with sides swapped.
+	SPCoveredBy
+	// SPDisjoint matches when the geometries share no point — the
+	// negation of SPIntersects. The R-tree only finds pairs whose
+	// bounds overlap, which is where disjoint pairs are *not*, so
+	// SJoin evaluates it per left row as every non-null right row
+	// minus the intersecting ones: O(left × right) work and, usually,
+	// output. To keep the left rows that match nothing — "points not
+	// in any zone" — use SPIntersects with WithJoinType(JoinAnti)
+	// instead, which stays on the R-tree.
+	SPDisjoint
 )
 
-// SpatialPredicate names a binary spatial predicate for SJoin.
-type SpatialPredicate uint8
-
-func (p SpatialPredicate) String() string {
-	switch p {
+func (op SpatialOp) String() string {
+	switch op {
 	case SPIntersects:
 		return "intersects"
 	case SPContains:
 		return "contains"
 	case SPWithin:
 		return "within"
+	case SPTouches:
+		return "touches"
+	case SPOverlaps:
+		return "overlaps"
+	case SPCrosses:
+		return "crosses"
+	case SPCovers:
+		return "covers"
+	case SPCoveredBy:
+		return "covered_by"
+	case SPDisjoint:
+		return "disjoint"
 	default:
 		return "unknown"
 	}
 }
 
-func (p SpatialPredicate) toGeometry() geometry.Predicate {
-	switch p {
+func (op SpatialOp) matcher() (spatialMatcher, error) {
+	switch op {
+	case SPIntersects:
+		return spatialMatcher{test: geometry.Intersects}, nil
 	case SPContains:
-		return geometry.PredContains
+		return spatialMatcher{test: geometry.Contains}, nil
 	case SPWithin:
-		return geometry.PredWithin
-	default:
-		return geometry.PredIntersects
+		return spatialMatcher{test: geometry.Within}, nil
+	case SPTouches:
+		return spatialMatcher{test: geometry.Touches}, nil
+	case SPOverlaps:
+		return spatialMatcher{test: geometry.Overlaps}, nil
+	case SPCrosses:
+		return spatialMatcher{test: geometry.Crosses}, nil
+	case SPCovers:
+		return spatialMatcher{test: geometry.Covers}, nil
+	case SPCoveredBy:
+		return spatialMatcher{test: geometry.CoveredBy}, nil
+	case SPDisjoint:
+		return spatialMatcher{
+			test:     func(l, r geometry.Geometry) bool { return !geometry.Intersects(l, r) },
+			disjoint: true,
+		}, nil
+	}
+	return spatialMatcher{}, fmt.Errorf("gobi: unknown spatial predicate %d", op)
+}
+
+// SPDWithin matches when the left and right geometries are within
+// distance of each other (inclusive), in unit — "every store within
+// 500 m of a station". Distances are planar, as in geometry.DWithin,
+// so the geometries should be in a projected metric CRS. SJoin
+// rejects a negative distance or an unknown unit.
+func SPDWithin(distance float64, unit geometry.Unit) SpatialPredicate {
+	return dwithinPredicate{distance: distance, unit: unit}
+}
+
+// dwithinPredicate is SPDWithin's SpatialPredicate.
+type dwithinPredicate struct {
+	distance float64
+	unit     geometry.Unit
+}
+
+func (p dwithinPredicate) String() string {
+	unit := p.unit
+	if unit == "" {
+		unit = geometry.UnitMeters
+	}
+	return fmt.Sprintf("dwithin(%g %s)", p.distance, unit)
+}
+
+func (p dwithinPredicate) matcher() (spatialMatcher, error) {
+	perM, err := geometry.MetersPerUnit(p.unit)
+	if err != nil {
+		return spatialMatcher{}, err
 	}
+	if p.distance < 0 || math.IsNaN(p.distance) {
+		return spatialMatcher{}, fmt.Errorf("gobi: SPDWithin distance must be >= 0, got %g", p.distance)
+	}
+	// Compare in coordinate units (GeomDistance with meters is the
+	// raw planar value) so the unit converts once, not per pair.
+	d := p.distance * perM
+	return spatialMatcher{
+		test: func(l, r geometry.Geometry) bool {
+			ok, _ := geometry.DWithin(l, r, d, geometry.UnitMeters)
+			return ok
+		},
+		expand: d,
+	}, nil
+}
+
+// orIntersects is pred, or SPIntersects for a nil pred.
+func orIntersects(pred SpatialPredicate) SpatialPredicate {
+	if pred == nil {
+		return SPIntersects
+	}
+	return pred
+}
+
+// spatialMatcher is a SpatialPredicate resolved for the scan loop.
+type spatialMatcher struct {
+	test func(l, r geometry.Geometry) bool
+	// expand widens each left bbox before the R-tree search, in
+	// coordinate units. Non-zero only for SPDWithin, whose matches
+	// needn't overlap the left bbox at all.
+	expand float64
+	// disjoint marks SPDisjoint: the matches are the right rows the
+	// R-tree search finds *not* intersecting, plus every row it
+	// doesn't find.
+	disjoint bool
 }
 
 // SJoin performs a spatial join of f (left) and right by evaluating pred on
-// each pair of geometries from leftGeomCol and rightGeomCol. Only rows where
-// pred holds are emitted. The output frame contains all columns from the
-// left frame, followed by all columns from the right frame except its
-// geometry column (analogous to Frame.Join dropping the right join key).
-// Right-side column names that collide with left-side names get a "_right"
-// suffix.
+// each pair of geometries from leftGeomCol and rightGeomCol. The output
+// frame contains all columns from the left frame, followed by all columns
+// from the right frame except its geometry column (analogous to Frame.Join
+// dropping the right join key). Right-side column names that collide with
+// left-side names get a "_right" suffix.
+//
+// The join is inner by default: only pairs where pred holds are emitted.
+// WithJoinType selects any other JoinType, with Join's semantics:
+//
+//   - JoinLeft keeps every left row; unmatched ones get null right columns.
+//   - JoinRight keeps every right row, in right-row order; unmatched ones
+//     get null left columns.
+//   - JoinFull is JoinLeft followed by the unmatched right rows.
+//   - JoinSemi emits each left row with at least one match, once, left
+//     columns only.
+//   - JoinAnti emits each left row with no match, left columns only.
+//
+// So "points not in any zone" is a single call:
+//
+//	outside, err := points.SJoin(zones, "geometry", "geometry",
+//	    gobi.SPIntersects, gobi.WithJoinType(gobi.JoinAnti))
+//
+// For JoinRight and JoinFull the right geometry column is kept (with the
+// usual "_right" suffix on a collision), since unmatched right rows would
+// otherwise lose their geometry. A null geometry on either side never
+// matches.
 //
 // Under the hood, an R-tree is built over the right frame's geometry bounds
-// so each left row scans only overlapping candidates. Parallelism follows
-// the priority order documented on resolveWorkers: Workers(n) > package
+// so each left row scans only overlapping candidates (for SPDWithin, the
+// left bbox widened by the distance). Parallelism follows the priority
+// order documented on resolveWorkers: Workers(n) > package
 // SetMaxParallelism > GOMAXPROCS.
 func (f *Frame) SJoin(right *Frame, leftGeomCol, rightGeomCol string, pred SpatialPredicate, opts ...Option) (*Frame, error) {
-	lGeom, err := f.Column(leftGeomCol)
+	m, err := orIntersects(pred).matcher()
 	if err != nil {
 		return nil, err
 	}
-	if !lGeom.IsGeometry() {
-		return nil, fmt.Errorf("%w: left column %q is not a geometry column",
-			ErrNotGeometry, leftGeomCol)
+	kind, err := sjoinKind(opts...)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := buildSJoinIndex(right, rightGeomCol)
+	if err != nil {
+		return nil, err
+	}
+	return f.sjoinWithIndex(right, leftGeomCol, rightGeomCol, idx, m, kind, resolveWorkers(opts...))
+}
+
+// sjoinKind resolves SJoin's WithJoinType option: JoinInner when
+// unset, any known JoinType otherwise.
+func sjoinKind(opts ...Option) (JoinType, error) {
+	cfg := resolveOptions(opts...)
+	if !cfg.joinTypeSet {
+		return JoinInner, nil
+	}
+	if cfg.joinType > JoinAnti {
+		return 0, fmt.Errorf("gobi: SJoin: unknown join type %d", cfg.joinType)
 	}
+	return cfg.joinType, nil
+}
+
+// sjoinIndex is the right side of a spatial join: its geometries
+// decoded once and an R-tree over their bounds. Built once and probed
+// per left frame, so streamingSJoinExec can reuse it across probe
+// batches the way streamingJoinExec reuses its hash index.
+type sjoinIndex struct {
+	geoms []geometry.Geometry
+	tree  *geometry.RTree
+}
+
+// buildSJoinIndex decodes right's geometry column and indexes it.
+func buildSJoinIndex(right *Frame, rightGeomCol string) (*sjoinIndex, error) {
 	rGeom, err := right.Column(rightGeomCol)
 	if err != nil {
 		return nil, err
@@ -81,9 +270,6 @@ func (f *Frame) SJoin(right *Frame, leftGeomCol, rightGeomCol string, pred Spati
 		return nil, fmt.Errorf("%w: right column %q is not a geometry column",
 			ErrNotGeometry, rightGeomCol)
 	}
-
-	// Decode every geometry column once. Right geometries feed the R-tree;
-	// left geometries are cached so we never re-decode them across rows.
 	rightGeoms, err := decodeGeometryColumn(rGeom)
 	if err != nil {
 		return nil, err
@@ -95,33 +281,139 @@ func (f *Frame) SJoin(right *Frame, leftGeomCol, rightGeomCol string, pred Spati
 		}
 		rightBounds[i] = g.Bounds()
 	}
-	tree := geometry.NewRTree(rightBounds)
+	return &sjoinIndex{geoms: rightGeoms, tree: geometry.NewRTree(rightBounds)}, nil
+}
 
+// sjoinWithIndex is the index-agnostic core of SJoin: it probes idx
+// (built over right) with f's geometries and shapes the output for
+// kind.
+//
+// Right and Full joins account for every right row, so they're only
+// correct when f is the whole left side; the streaming exec routes
+// those kinds through its materializing fallback (see canStreamJoin).
+func (f *Frame) sjoinWithIndex(right *Frame, leftGeomCol, rightGeomCol string,
+	idx *sjoinIndex, m spatialMatcher, kind JoinType, workers int,
+) (*Frame, error) {
+	lGeom, err := f.Column(leftGeomCol)
+	if err != nil {
+		return nil, err
+	}
+	if !lGeom.IsGeometry() {
+		return nil, fmt.Errorf("%w: left column %q is not a geometry column",
+			ErrNotGeometry, leftGeomCol)
+	}
+	// Left geometries are cached so we never re-decode them across
+	// candidate pairs.
 	leftGeoms, err := decodeGeometryColumn(lGeom)
 	if err != nil {
 		return nil, err
 	}
 
-	geomPred := pred.toGeometry()
-
-	workers := resolveWorkers(opts...)
-	leftIdxs, rightIdxs := sjoinScan(leftGeoms, rightGeoms, tree, geomPred, workers)
+	leftIdxs, rightIdxs := sjoinScan(leftGeoms, idx.geoms, idx.tree, m, workers)
+	leftIdxs, rightIdxs = sjoinShape(kind, len(leftGeoms), len(idx.geoms), leftIdxs, rightIdxs)
 
+	switch kind {
+	case JoinSemi, JoinAnti:
+		return f.buildLeftOnlyOutput(leftIdxs)
+	case JoinRight, JoinFull:
+		// "" never names a column, so nothing is dropped.
+		return assembleJoinedFrame(f, right, leftIdxs, rightIdxs, "")
+	}
 	return assembleJoinedFrame(f, right, leftIdxs, rightIdxs, rightGeomCol)
 }
 
+// sjoinOutputKeys returns the right-side columns a spatial join of
+// kind drops — the right geometry, except for Right / Full joins
+// which keep it. Used to derive the plan schema via buildJoinSchema.
+func sjoinOutputKeys(rightGeomCol string, kind JoinType) []string {
+	if kind == JoinRight || kind == JoinFull {
+		return nil
+	}
+	return []string{rightGeomCol}
+}
+
+// sjoinShape turns the matched (left, right) pairs sjoinScan produces —
+// in left-row order — into the index pairs for kind, using -1 for the
+// null-padded side. For JoinSemi / JoinAnti only leftIdxs is meaningful.
+func sjoinShape(kind JoinType, nLeft, nRight int, leftIdxs, rightIdxs []int) ([]int, []int) {
+	switch kind {
+	case JoinLeft, JoinFull:
+		outL := make([]int, 0, len(leftIdxs)+nLeft)
+		outR := make([]int, 0, cap(outL))
+		i := 0
+		for lRow := range nLeft {
+			if i < len(leftIdxs) && leftIdxs[i] == lRow {
+				for ; i < len(leftIdxs) && leftIdxs[i] == lRow; i++ {
+					outL = append(outL, lRow)
+					outR = append(outR, rightIdxs[i])
+				}
+				continue
+			}
+			outL = append(outL, lRow)
+			outR = append(outR, -1)
+		}
+		if kind == JoinFull {
+			matched := make([]bool, nRight)
+			for _, r := range rightIdxs {
+				matched[r] = true
+			}
+			for rRow, ok := range matched {
+				if !ok {
+					outL = append(outL, -1)
+					outR = append(outR, rRow)
+				}
+			}
+		}
+		return outL, outR
+	case JoinRight:
+		// Bucket the pairs by right row; within a bucket they stay in
+		// left-row order.
+		byRight := make([][]int, nRight)
+		for i, r := range rightIdxs {
+			byRight[r] = append(byRight[r], leftIdxs[i])
+		}
+		outL := make([]int, 0, len(leftIdxs)+nRight)
+		outR := make([]int, 0, cap(outL))
+		for rRow, lefts := range byRight {
+			if len(lefts) == 0 {
+				outL = append(outL, -1)
+				outR = append(outR, rRow)
+				continue
+			}
+			for _, lRow := range lefts {
+				outL = append(outL, lRow)
+				outR = append(outR, rRow)
+			}
+		}
+		return outL, outR
+	case JoinSemi, JoinAnti:
+		matched := make([]bool, nLeft)
+		for _, l := range leftIdxs {
+			matched[l] = true
+		}
+		out := make([]int, 0, nLeft)
+		for lRow, ok := range matched {
+			if ok == (kind == JoinSemi) {
+				out = append(out, lRow)
+			}
+		}
+		return out, nil
+	}
+	return leftIdxs, rightIdxs
+}
+
 // sjoinScan runs the per-left-row candidate loop. Small inputs or workers==1
 // use the sequential path; larger inputs shard across the requested worker
 // count and merge results in left-row order.
 func sjoinScan(
 	leftGeoms, rightGeoms []geometry.Geometry,
 	tree *geometry.RTree,
-	geomPred geometry.Predicate,
+	m spatialMatcher,
 	workers int,
 ) (leftIdxs, rightIdxs []int) {
 	n := len(leftGeoms)
 	if workers <= 1 || n < SJoinMinParallelRows {
-		return sjoinScanRange(leftGeoms, rightGeoms, tree, geomPred, 0, n, nil)
+		return sjoinScanRange(leftGeoms, rightGeoms, tree, m, 0, n, nil)
 	}
 
 	workers = min(workers, n)
@@ -143,7 +435,7 @@ func sjoinScan(
 		wg.Go(func() {
 			// Each worker owns its scratch buffer for R-tree candidates.
 			var scratch []int32
-			l, r := sjoinScanRange(leftGeoms, rightGeoms, tree, geomPred, s, e, scratch)
+			l, r := sjoinScanRange(leftGeoms, rightGeoms, tree, m, s, e, scratch)
 			shards[idx] = shard{l: l, r: r}
 		})
 	}
@@ -168,7 +460,7 @@ func sjoinScan(
 func sjoinScanRange(
 	leftGeoms, rightGeoms []geometry.Geometry,
 	tree *geometry.RTree,
-	geomPred geometry.Predicate,
+	m spatialMatcher,
 	start, end int,
 	scratch []int32,
 ) (leftIdxs, rightIdxs []int) {
@@ -182,13 +474,24 @@ func sjoinScanRange(
 		if lg == nil {
 			continue
 		}
-		scratch = tree.SearchInto(scratch, lg.Bounds())
+		q := lg.Bounds()
+		if m.expand > 0 {
+			q = geometry.Bounds{
+				MinX: q.MinX - m.expand, MinY: q.MinY - m.expand,
+				MaxX: q.MaxX + m.expand, MaxY: q.MaxY + m.expand,
+			}
+		}
+		scratch = tree.SearchInto(scratch, q)
+		if m.disjoint {
+			leftIdxs, rightIdxs = appendDisjoint(leftIdxs, rightIdxs, lRow, lg, rightGeoms, scratch)
+			continue
+		}
 		for _, rIdx := range scratch {
 			rg := rightGeoms[rIdx]
 			if rg == nil {
 				continue
 			}
-			if !geometry.Test(geomPred, lg, rg) {
+			if !m.test(lg, rg) {
 				continue
 			}
 			leftIdxs = append(leftIdxs, lRow)
@@ -198,6 +501,30 @@ func sjoinScanRange(
 	return leftIdxs, rightIdxs
 }
 
+// appendDisjoint appends lRow's SPDisjoint matches, in right-row
+// order: every non-null right geometry except the candidates (the
+// R-tree hits for lg's bounds) that intersect lg. A right geometry
+// outside lg's bounds can't intersect it.
+func appendDisjoint(leftIdxs, rightIdxs []int, lRow int, lg geometry.Geometry, rightGeoms []geometry.Geometry, candidates []int32) ([]int, []int) {
+	hit := make(map[int32]struct{})
+	for _, rIdx := range candidates {
+		if rg := rightGeoms[rIdx]; rg != nil && geometry.Intersects(lg, rg) {
+			hit[rIdx] = struct{}{}
+		}
+	}
+	for rIdx, rg := range rightGeoms {
+		if rg == nil {
+			continue
+		}
+		if _, ok := hit[int32(rIdx)]; ok {
+			continue
+		}
+		leftIdxs = append(leftIdxs, lRow)
+		rightIdxs = append(rightIdxs, rIdx)
+	}
+	return leftIdxs, rightIdxs
+}
+
 // decodeGeometryColumn walks every chunk of a geometry Series and returns
 // the decoded geometries (nil where the WKB was null).
 func decodeGeometryColumn(s Series) ([]geometry.Geometry, error) {
@@ -224,7 +551,8 @@ func decodeGeometryColumn(s Series) ([]geometry.Geometry, error) {
 }
 
 // assembleJoinedFrame builds the output frame by taking the requested rows
-// from each side. The right frame's geometry column is dropped, and any
+// from each side; either side's indexes may be -1 for a null row. The
+// right frame's geometry column is dropped (pass "" to keep it), and any
 // remaining right column whose name collides with a left column gets a
 // "_right" suffix.
 func assembleJoinedFrame(f, right *Frame, leftIdxs, rightIdxs []int, rightGeomCol string) (*Frame, error) {
@@ -239,9 +567,14 @@ func assembleJoinedFrame(f, right *Frame, leftIdxs, rightIdxs []int, rightGeomCo
 	outFields := make([]arrow.Field, 0, len(f.series)+len(right.series))
 	outColumns := make([]arrow.Column, 0, cap(outFields))
 
-	// Left columns via take.
+	// Left columns via take; outer (right / full) spatial joins pad the
+	// left side with -1, which only the null-aware take understands.
+	take := takeArray
+	if slices.Contains(leftIdxs, -1) {
+		take = takeArrayWithNulls
+	}
 	for _, s := range f.series {
-		arr, err := takeArray(pool, s, leftIdxs)
+		arr, err := take(pool, s, leftIdxs)
 		if err != nil {
 			return nil, err
 		}
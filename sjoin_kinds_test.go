@@ -0,0 +1,199 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// zonesAndPoints returns two adjacent zones plus an unreachable one,
+// and points inside A, on the shared A|B edge, and outside both.
+func zonesAndPoints(t *testing.T) (*Frame, *Frame) {
+	t.Helper()
+	zones := polygonsFrame(t,
+		[]string{"A", "B", "empty"},
+		[]geometry.Polygon{
+			unitSquare(-5, 5, 5), // x in [-10, 0]
+			unitSquare(5, 5, 5),  // x in [0, 10]
+			unitSquare(1000, 0, 1),
+		},
+	)
+	points := pointsFrame(t,
+		[]string{"inA", "edge", "outside"},
+		[]geometry.Point{{X: -5, Y: 5}, {X: 0, Y: 5}, {X: 50, Y: 5}},
+	)
+	return points, zones
+}
+
+// sjoinPairs renders each output row as "name|region", with "-" for a
+// null on either side.
+func sjoinPairs(t *testing.T, f *Frame) []string {
+	t.Helper()
+	names := getStringColumn(t, f, "name")
+	regions := getStringColumn(t, f, "region")
+	out := make([]string, f.NumRows())
+	for i := range out {
+		l, r := "-", "-"
+		if !names.IsNull(i) {
+			l = names.Value(i)
+		}
+		if !regions.IsNull(i) {
+			r = regions.Value(i)
+		}
+		out[i] = l + "|" + r
+	}
+	return out
+}
+
+func TestSJoin_JoinKinds(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	cases := []struct {
+		kind JoinType
+		want []string
+	}{
+		{JoinInner, []string{"inA|A", "edge|A", "edge|B"}},
+		{JoinLeft, []string{"inA|A", "edge|A", "edge|B", "outside|-"}},
+		// Right-row order; each zone's matches in left-row order.
+		{JoinRight, []string{"inA|A", "edge|A", "edge|B", "-|empty"}},
+		{JoinFull, []string{"inA|A", "edge|A", "edge|B", "outside|-", "-|empty"}},
+	}
+	for _, tc := range cases {
+		t.Run(joinKindLabel(tc.kind), func(t *testing.T) {
+			out, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(tc.kind))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sjoinPairs(t, out); !slices.Equal(got, tc.want) {
+				t.Fatalf("pairs = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSJoin_RightKeepsRightGeometry(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	out, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(JoinRight))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"name", "geometry", "region", "geometry_right"}
+	if got := out.ColumnNames(); !slices.Equal(got, want) {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+	if n := mustColumn(t, out, "geometry_right").NullCount(); n != 0 {
+		t.Fatalf("geometry_right null count = %d, want 0", n)
+	}
+}
+
+func TestSJoin_SemiAnti(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	semi, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(JoinSemi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "edge" matches twice but appears once; only left columns.
+	if got := semi.ColumnNames(); !slices.Equal(got, []string{"name", "geometry"}) {
+		t.Fatalf("semi columns = %v", got)
+	}
+	names := getStringColumn(t, semi, "name")
+	if semi.NumRows() != 2 || names.Value(0) != "inA" || names.Value(1) != "edge" {
+		t.Fatalf("semi rows = %d, want [inA edge]", semi.NumRows())
+	}
+
+	anti, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(JoinAnti))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anti.NumRows() != 1 || getStringColumn(t, anti, "name").Value(0) != "outside" {
+		t.Fatalf("anti rows = %d, want [outside]", anti.NumRows())
+	}
+}
+
+func TestSJoin_ExtendedPredicates(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	edgeA := polygonsFrame(t, []string{"A"}, []geometry.Polygon{unitSquare(-5, 5, 5)})
+	cases := []struct {
+		name  string
+		left  *Frame
+		right *Frame
+		pred  SpatialPredicate
+		want  []string
+	}{
+		// A and B share only the x = 0 edge; "inA" sits in A's interior.
+		{"touches", points, zones, SPTouches, []string{"edge|A", "edge|B"}},
+		{"covered_by", points, zones, SPCoveredBy, []string{"inA|A", "edge|A", "edge|B"}},
+		{"covers", zones, edgeA, SPCovers, []string{"A|A"}},
+		// 50 is 40 from B's edge at x = 10.
+		{"dwithin", points, zones, SPDWithin(40, geometry.UnitMeters), []string{"inA|A", "inA|B", "edge|A", "edge|B", "outside|B"}},
+		{"dwithin_km", points, zones, SPDWithin(0.039, geometry.UnitKilometers), []string{"inA|A", "inA|B", "edge|A", "edge|B"}},
+		// Every non-intersecting zone, far-away "empty" included.
+		{"disjoint", points, zones, SPDisjoint, []string{"inA|B", "inA|empty", "edge|empty", "outside|A", "outside|B", "outside|empty"}},
+		{"nil_is_intersects", points, zones, nil, []string{"inA|A", "edge|A", "edge|B"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.left.SJoin(tc.right, "geometry", "geometry", tc.pred)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lName, rName := "name", "region"
+			if tc.left == zones {
+				lName, rName = "region", "region_right"
+			}
+			l, r := getStringColumn(t, out, lName), getStringColumn(t, out, rName)
+			got := make([]string, out.NumRows())
+			for i := range got {
+				got[i] = l.Value(i) + "|" + r.Value(i)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("%s pairs = %v, want %v", tc.pred, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSJoin_CrossesAndOverlaps(t *testing.T) {
+	zones := polygonsFrame(t, []string{"A"}, []geometry.Polygon{unitSquare(0, 0, 5)})
+	lines := roadsFrame(t, []string{"through", "inside", "away"}, []geometry.LineString{
+		segment(-10, 0, 10, 0),
+		segment(-1, 0, 1, 0),
+		segment(20, 0, 30, 0),
+	})
+	out, err := lines.SJoin(zones, "geometry", "geometry", SPCrosses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 1 || getStringColumn(t, out, "road").Value(0) != "through" {
+		t.Fatalf("crosses rows = %d, want [through]", out.NumRows())
+	}
+
+	shifted := polygonsFrame(t, []string{"S", "far"}, []geometry.Polygon{unitSquare(3, 3, 5), unitSquare(100, 0, 1)})
+	out, err = zones.SJoin(shifted, "geometry", "geometry", SPOverlaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.NumRows() != 1 || getStringColumn(t, out, "region_right").Value(0) != "S" {
+		t.Fatalf("overlaps rows = %d, want [S]", out.NumRows())
+	}
+}
+
+func TestSJoin_PredicateAndKindErrors(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	if _, err := points.SJoin(zones, "geometry", "geometry", SPDWithin(-1, geometry.UnitMeters)); err == nil {
+		t.Fatal("expected error for negative SPDWithin distance")
+	}
+	if _, err := points.SJoin(zones, "geometry", "geometry", SPDWithin(1, "furlongs")); !errors.Is(err, geometry.ErrInvalidUnit) {
+		t.Fatalf("err = %v, want ErrInvalidUnit", err)
+	}
+	if _, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(JoinType(99))); err == nil {
+		t.Fatal("expected error for unknown join type")
+	}
+	if _, err := points.SJoin(zones, "geometry", "geometry", SpatialOp(99)); err == nil {
+		t.Fatal("expected error for unknown spatial predicate")
+	}
+	if got := SPDWithin(500, geometry.UnitMeters).String(); got != "dwithin(500 m)" {
+		t.Fatalf("String = %q", got)
+	}
+}
@@ -0,0 +1,149 @@
+package gobi
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestLazySJoin_MatchesEager(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	for _, kind := range []JoinType{JoinInner, JoinLeft, JoinRight, JoinFull, JoinSemi, JoinAnti} {
+		t.Run(joinKindLabel(kind), func(t *testing.T) {
+			eager, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(kind))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lf := points.Lazy().SJoin(zones.Lazy(), "geometry", "geometry", SPIntersects, WithJoinType(kind))
+			lazy, err := lf.Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(lazy.ColumnNames(), eager.ColumnNames()) {
+				t.Fatalf("lazy columns %v != eager %v", lazy.ColumnNames(), eager.ColumnNames())
+			}
+			planFields, gotFields := lf.plan.Schema().Fields(), lazy.Schema().Fields()
+			for i := range planFields {
+				if planFields[i].Name != gotFields[i].Name || !arrow.TypeEqual(planFields[i].Type, gotFields[i].Type) {
+					t.Fatalf("plan schema %v != collected %v", lf.plan.Schema(), lazy.Schema())
+				}
+			}
+			if kind == JoinSemi || kind == JoinAnti {
+				got := getStringColumn(t, lazy, "name")
+				want := getStringColumn(t, eager, "name")
+				if got.Len() != want.Len() {
+					t.Fatalf("lazy rows %d != eager %d", got.Len(), want.Len())
+				}
+				return
+			}
+			if got, want := sjoinPairs(t, lazy), sjoinPairs(t, eager); !slices.Equal(got, want) {
+				t.Fatalf("lazy %v != eager %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLazySJoin_ExplainPhysical(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	got := points.Lazy().SJoin(zones.Lazy(), "geometry", "geometry", SPDWithin(5, "m")).ExplainPhysical()
+	if !strings.Contains(got, "StreamingSJoin(inner, dwithin(5 m)(left.geometry, right.geometry))") {
+		t.Fatalf("ExplainPhysical:\n%s", got)
+	}
+	got = points.Lazy().SJoin(zones.Lazy(), "geometry", "geometry", SPIntersects, WithJoinType(JoinFull)).ExplainPhysical()
+	if !strings.Contains(got, "MaterializeSJoin(full, intersects(left.geometry, right.geometry))") {
+		t.Fatalf("ExplainPhysical:\n%s", got)
+	}
+}
+
+func TestLazySJoin_BadPredicateSurfacesAtCollect(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	_, err := points.Lazy().SJoin(zones.Lazy(), "geometry", "geometry", SPDWithin(-1, "m")).Collect()
+	if err == nil {
+		t.Fatal("expected error for negative SPDWithin distance")
+	}
+}
+
+func TestStreamingSJoin_MultiBatchProbe(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	for _, kind := range []JoinType{JoinInner, JoinLeft, JoinAnti} {
+		node := newSJoinNode(&scanFrameNode{frame: points}, &scanFrameNode{frame: zones},
+			"geometry", "geometry", SPIntersects, kind, nil)
+		m, err := SPIntersects.matcher()
+		if err != nil {
+			t.Fatal(err)
+		}
+		exec := &streamingSJoinExec{
+			left:      newScanFrameExec(points, 1), // one probe batch per point
+			right:     newScanFrameExec(zones, 100),
+			leftGeom:  "geometry",
+			rightGeom: "geometry",
+			matcher:   m,
+			kind:      kind,
+			workers:   1,
+			outSchema: node.outSchema,
+		}
+		got, err := Execute(context.Background(), exec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := points.SJoin(zones, "geometry", "geometry", SPIntersects, WithJoinType(kind))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.NumRows() != want.NumRows() {
+			t.Fatalf("kind=%s: streamed %d rows, eager %d", joinKindLabel(kind), got.NumRows(), want.NumRows())
+		}
+	}
+}
+
+func TestOptimize_ProjectionPushdown_ThroughSJoin(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	fakeL := &projectableTestScan{schema: points.Schema(), sourceFrame: points}
+	fakeR := &projectableTestScan{schema: zones.Schema(), sourceFrame: zones}
+	plan := newProjectNode(
+		newSJoinNode(fakeL, fakeR, "geometry", "geometry", SPIntersects, JoinInner, nil),
+		[]Expr{Col("region")},
+	)
+	out, err := (&LazyFrame{plan: plan}).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Left needs only its geometry; right needs region + geometry.
+	if !slices.Equal(fakeL.appliedCols, []string{"geometry"}) {
+		t.Fatalf("left appliedCols = %v, want [geometry]", fakeL.appliedCols)
+	}
+	if !slices.Equal(fakeR.appliedCols, []string{"geometry", "region"}) {
+		t.Fatalf("right appliedCols = %v, want [geometry region]", fakeR.appliedCols)
+	}
+	if out.NumRows() != 3 {
+		t.Fatalf("rows = %d, want 3", out.NumRows())
+	}
+}
+
+func TestOptimize_ProjectionPushdown_SJoinKeepsCollidingLeftColumn(t *testing.T) {
+	points, zones := zonesAndPoints(t)
+	renamed, err := zones.Rename("region", "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeL := &projectableTestScan{schema: points.Schema(), sourceFrame: points}
+	fakeR := &projectableTestScan{schema: renamed.Schema(), sourceFrame: renamed}
+	plan := newProjectNode(
+		newSJoinNode(fakeL, fakeR, "geometry", "geometry", SPIntersects, JoinInner, nil),
+		[]Expr{Col("name_right")},
+	)
+	out, err := (&LazyFrame{plan: plan}).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Dropping the left "name" would turn "name_right" back into "name".
+	if !slices.Contains(fakeL.appliedCols, "name") {
+		t.Fatalf("left appliedCols = %v, want name kept", fakeL.appliedCols)
+	}
+	if got := getStringColumn(t, out, "name_right"); got.Len() != 3 || got.Value(0) != "A" {
+		t.Fatalf("name_right = %v, want [A A B]", got)
+	}
+}
@@ -0,0 +1,340 @@
+package gobi
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// DefaultNearestDistanceCol is the name of the distance column
+// SJoinNearest appends unless overridden with DistanceColumn.
+const DefaultNearestDistanceCol = "distance"
+
+// SJoinNearest joins each left row to its k nearest right rows by true
+// geometry distance — "for each GPS ping, the closest road segment
+// within 50 m":
+//
+//	out, err := pings.SJoinNearest(roads, "geometry", "geometry",
+//	    1, 50, geometry.UnitMeters)
+//
+// Candidates come from an R-tree over the right geometries' bounds
+// (RTree.Nearest, widened until no unvisited box can beat the current
+// k-th best); each candidate is then refined with geometry.GeomDistance,
+// so a long road whose bbox is close but whose line is far doesn't win
+// over a short one that is actually nearer. Distances are planar in
+// the geometries' coordinate units, interpreted as meters and then
+// converted to unit — project lon/lat data to a metric CRS first (see
+// GeomDistance).
+//
+// maxDistance, in unit, drops candidates further away; <= 0 means
+// unbounded. Matches come out nearest first per left row, ties broken
+// by right row order. Fewer than k rows are emitted when fewer right
+// rows qualify.
+//
+// Output columns follow SJoin — left columns, then right columns minus
+// the right geometry column with "_right" on collisions — plus a
+// Float64 distance column (DefaultNearestDistanceCol; rename with
+// DistanceColumn). By default the join is inner: left rows with no
+// match within maxDistance are dropped. Pass WithJoinType(JoinLeft) to
+// keep them with null right columns and a null distance. Workers(n)
+// shards the left rows as in SJoin.
+func (f *Frame) SJoinNearest(right *Frame, leftGeomCol, rightGeomCol string,
+	k int, maxDistance float64, unit geometry.Unit, opts ...Option,
+) (*Frame, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("gobi: SJoinNearest k must be > 0, got %d", k)
+	}
+	cfg := resolveOptions(opts...)
+	kind := JoinInner
+	if cfg.joinTypeSet {
+		kind = cfg.joinType
+	}
+	if kind != JoinInner && kind != JoinLeft {
+		return nil, fmt.Errorf("gobi: SJoinNearest supports inner and left joins, got %s", joinKindLabel(kind))
+	}
+	distCol := DefaultNearestDistanceCol
+	if cfg.distanceCol != "" {
+		distCol = cfg.distanceCol
+	}
+	perM, err := geometry.MetersPerUnit(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	lGeom, err := f.Column(leftGeomCol)
+	if err != nil {
+		return nil, err
+	}
+	if !lGeom.IsGeometry() {
+		return nil, fmt.Errorf("%w: left column %q is not a geometry column",
+			ErrNotGeometry, leftGeomCol)
+	}
+	rGeom, err := right.Column(rightGeomCol)
+	if err != nil {
+		return nil, err
+	}
+	if !rGeom.IsGeometry() {
+		return nil, fmt.Errorf("%w: right column %q is not a geometry column",
+			ErrNotGeometry, rightGeomCol)
+	}
+
+	rightGeoms, err := decodeGeometryColumn(rGeom)
+	if err != nil {
+		return nil, err
+	}
+	rightBounds := make([]geometry.Bounds, len(rightGeoms))
+	for i, g := range rightGeoms {
+		if g == nil {
+			continue
+		}
+		rightBounds[i] = g.Bounds()
+	}
+	tree := geometry.NewRTree(rightBounds)
+
+	leftGeoms, err := decodeGeometryColumn(lGeom)
+	if err != nil {
+		return nil, err
+	}
+
+	// Distances run in coordinate units (GeomDistance with meters is
+	// the raw planar value); convert the cutoff in and results out.
+	maxCoord := 0.0
+	if maxDistance > 0 {
+		maxCoord = maxDistance * perM
+	}
+	scan := nearestScan{
+		rightGeoms:  rightGeoms,
+		rightBounds: rightBounds,
+		tree:        tree,
+		k:           k,
+		maxDist:     maxCoord,
+		keepUnmatch: kind == JoinLeft,
+	}
+	leftIdxs, rightIdxs, dists, err := scan.run(leftGeoms, resolveWorkers(opts...))
+	if err != nil {
+		return nil, err
+	}
+	for i := range dists {
+		dists[i] /= perM
+	}
+
+	joined, err := assembleJoinedFrame(f, right, leftIdxs, rightIdxs, rightGeomCol)
+	if err != nil {
+		return nil, err
+	}
+	defer joined.Release()
+	// WithColumn would silently replace a same-named input column.
+	if _, err := joined.Column(distCol); err == nil {
+		return nil, fmt.Errorf("gobi: SJoinNearest distance column %q collides with an existing column; rename it with DistanceColumn", distCol)
+	}
+	return joined.WithColumn(distCol, nearestDistanceSeries(distCol, dists, rightIdxs))
+}
+
+// nearestScan is the per-left-row kNN loop's shared, read-only state.
+type nearestScan struct {
+	rightGeoms  []geometry.Geometry
+	rightBounds []geometry.Bounds
+	tree        *geometry.RTree
+	k           int
+	maxDist     float64 // coordinate units; <= 0 = unbounded
+	keepUnmatch bool    // JoinLeft: emit (lRow, -1) for rows with no match
+}
+
+// nearestHit is one refined candidate for a left row.
+type nearestHit struct {
+	right int
+	dist  float64
+}
+
+// run shards leftGeoms across workers exactly like sjoinScan and
+// concatenates the per-shard results in left-row order. dists is
+// parallel to rightIdxs (0 where rightIdxs is -1). The error is the
+// first shard's, in left-row order.
+func (s *nearestScan) run(leftGeoms []geometry.Geometry, workers int) (leftIdxs, rightIdxs []int, dists []float64, err error) {
+	n := len(leftGeoms)
+	if workers <= 1 || n < SJoinMinParallelRows {
+		return s.scanRange(leftGeoms, 0, n)
+	}
+
+	workers = min(workers, n)
+	chunk := (n + workers - 1) / workers
+
+	type shard struct {
+		l, r []int
+		d    []float64
+		err  error
+	}
+	shards := make([]shard, workers)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		start := w * chunk
+		end := min(start+chunk, n)
+		if start >= end {
+			continue
+		}
+		idx, st, e := w, start, end
+		wg.Go(func() {
+			l, r, d, err := s.scanRange(leftGeoms, st, e)
+			shards[idx] = shard{l: l, r: r, d: d, err: err}
+		})
+	}
+	wg.Wait()
+
+	var total int
+	for _, sh := range shards {
+		if sh.err != nil {
+			return nil, nil, nil, sh.err
+		}
+		total += len(sh.l)
+	}
+	leftIdxs = make([]int, 0, total)
+	rightIdxs = make([]int, 0, total)
+	dists = make([]float64, 0, total)
+	for _, sh := range shards {
+		leftIdxs = append(leftIdxs, sh.l...)
+		rightIdxs = append(rightIdxs, sh.r...)
+		dists = append(dists, sh.d...)
+	}
+	return leftIdxs, rightIdxs, dists, nil
+}
+
+// scanRange resolves left rows [start, end).
+func (s *nearestScan) scanRange(leftGeoms []geometry.Geometry, start, end int) (leftIdxs, rightIdxs []int, dists []float64, err error) {
+	nHint := (end - start) * s.k
+	leftIdxs = make([]int, 0, nHint)
+	rightIdxs = make([]int, 0, nHint)
+	dists = make([]float64, 0, nHint)
+
+	var hits []nearestHit
+	seen := make(map[int32]struct{})
+	for lRow := start; lRow < end; lRow++ {
+		if hits, err = s.nearest(leftGeoms[lRow], hits[:0], seen); err != nil {
+			return nil, nil, nil, fmt.Errorf("gobi: SJoinNearest: left row %d: %w", lRow, err)
+		}
+		if len(hits) == 0 {
+			if s.keepUnmatch {
+				leftIdxs = append(leftIdxs, lRow)
+				rightIdxs = append(rightIdxs, -1)
+				dists = append(dists, 0)
+			}
+			continue
+		}
+		for _, h := range hits {
+			leftIdxs = append(leftIdxs, lRow)
+			rightIdxs = append(rightIdxs, h.right)
+			dists = append(dists, h.dist)
+		}
+	}
+	return leftIdxs, rightIdxs, dists, nil
+}
+
+// nearest returns up to k right rows closest to lg, nearest first.
+//
+// RTree.Nearest orders items by bbox distance from a query point, which
+// for a non-point lg (or a right geometry much smaller than its box)
+// is only a lower bound on the true distance. So the search asks for
+// m candidates, refines them all, and doubles m until the last box
+// returned — the closest any unvisited item can be — is already no
+// nearer than the k-th best refined distance (or past maxDist). For lg
+// with a non-degenerate bbox, querying from its center and subtracting
+// the half-diagonal keeps that bound valid.
+func (s *nearestScan) nearest(lg geometry.Geometry, hits []nearestHit, seen map[int32]struct{}) ([]nearestHit, error) {
+	if lg == nil || s.tree.Len() == 0 {
+		return hits, nil
+	}
+	clear(seen)
+	b := lg.Bounds()
+	cx, cy := (b.MinX+b.MaxX)/2, (b.MinY+b.MaxY)/2
+	halfDiag := math.Hypot(b.MaxX-b.MinX, b.MaxY-b.MinY) / 2
+
+	m := max(s.k, 4)
+	for {
+		ids := s.tree.Nearest(cx, cy, m)
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			rg := s.rightGeoms[id]
+			if rg == nil {
+				continue
+			}
+			d, err := geometry.GeomDistance(lg, rg, geometry.UnitMeters)
+			if err != nil {
+				return nil, err
+			}
+			if s.maxDist > 0 && d > s.maxDist {
+				continue
+			}
+			hits = append(hits, nearestHit{right: int(id), dist: d})
+		}
+		if len(ids) < m {
+			break // tree exhausted
+		}
+		bound := pointBoundsDistance(s.rightBounds[ids[len(ids)-1]], cx, cy) - halfDiag
+		if s.maxDist > 0 && bound > s.maxDist {
+			break
+		}
+		if len(hits) >= s.k {
+			slices.SortFunc(hits, cmpNearestHit)
+			if bound >= hits[s.k-1].dist {
+				break
+			}
+		}
+		m *= 2
+	}
+	slices.SortFunc(hits, cmpNearestHit)
+	if len(hits) > s.k {
+		hits = hits[:s.k]
+	}
+	return hits, nil
+}
+
+func cmpNearestHit(a, b nearestHit) int {
+	if a.dist != b.dist {
+		return cmpFloat(a.dist, b.dist)
+	}
+	return a.right - b.right
+}
+
+// pointBoundsDistance is the Euclidean distance from (x, y) to the
+// closest point of b — zero inside. The RTree's own bbox metric,
+// unsquared.
+func pointBoundsDistance(b geometry.Bounds, x, y float64) float64 {
+	var dx, dy float64
+	if x < b.MinX {
+		dx = b.MinX - x
+	} else if x > b.MaxX {
+		dx = x - b.MaxX
+	}
+	if y < b.MinY {
+		dy = b.MinY - y
+	} else if y > b.MaxY {
+		dy = y - b.MaxY
+	}
+	return math.Hypot(dx, dy)
+}
+
+// nearestDistanceSeries builds the distance column: dists[i], or null
+// where the row is an unmatched left row (rightIdxs[i] == -1).
+func nearestDistanceSeries(name string, dists []float64, rightIdxs []int) Series {
+	b := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer b.Release()
+	b.Reserve(len(dists))
+	for i, d := range dists {
+		if rightIdxs[i] < 0 {
+			b.AppendNull()
+			continue
+		}
+		b.UnsafeAppend(d)
+	}
+	return SeriesFromArray(arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}, b.NewArray())
+}
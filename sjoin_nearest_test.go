@@ -0,0 +1,195 @@
+package gobi
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/zoobst/gobi/geometry"
+)
+
+// roadsFrame builds a frame of (road string, geometry Binary/WKB LineString).
+func roadsFrame(t *testing.T, roads []string, lines []geometry.LineString) *Frame {
+	t.Helper()
+	pool := memory.DefaultAllocator
+
+	roadB := array.NewStringBuilder(pool)
+	defer roadB.Release()
+	roadB.AppendValues(roads, nil)
+
+	geomB := array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
+	defer geomB.Release()
+	for _, l := range lines {
+		geomB.Append(geometry.WKB(l))
+	}
+
+	fields := []arrow.Field{
+		{Name: "road", Type: arrow.BinaryTypes.String, Nullable: false},
+		GeometryField("geometry", 3857),
+	}
+	schema := arrow.NewSchema(fields, nil)
+	arrs := []arrow.Array{roadB.NewArray(), geomB.NewArray()}
+	defer func() {
+		for _, a := range arrs {
+			a.Release()
+		}
+	}()
+	cols := make([]arrow.Column, len(fields))
+	for i, a := range arrs {
+		chunked := arrow.NewChunked(a.DataType(), []arrow.Array{a})
+		cols[i] = *arrow.NewColumn(fields[i], chunked)
+	}
+	f, err := NewFrame(schema, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func segment(x0, y0, x1, y1 float64) geometry.LineString {
+	return geometry.LineString{Points: []geometry.Point{{X: x0, Y: y0}, {X: x1, Y: y1}}}
+}
+
+// nearestRoads returns roads whose bbox order and true order disagree
+// for a ping at the origin: "diagonal" runs from (5, 1000) to
+// (1000, 5), so its bbox corner is ~7 away while the line itself is
+// ~711 away; "short" is a stub exactly 50 away; "far" is far.
+func nearestRoads(t *testing.T) *Frame {
+	t.Helper()
+	return roadsFrame(t, []string{"diagonal", "short", "far"}, []geometry.LineString{
+		segment(5, 1000, 1000, 5),
+		segment(50, -10, 50, 10),
+		segment(5000, 0, 5000, 10),
+	})
+}
+
+func nearestDistances(t *testing.T, f *Frame) []float64 {
+	t.Helper()
+	s := mustColumn(t, f, DefaultNearestDistanceCol)
+	vals, err := s.Float64s()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, null := range s.Nulls() {
+		if null {
+			vals[i] = -1
+		}
+	}
+	return vals
+}
+
+func TestSJoinNearest_RefinesPastBoundingBoxes(t *testing.T) {
+	pings := pointsFrame(t, []string{"p"}, []geometry.Point{{X: 0, Y: 0}})
+	out, err := pings.SJoinNearest(nearestRoads(t), "geometry", "geometry", 1, 0, geometry.UnitMeters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.ColumnNames(), []string{"name", "geometry", "road", "distance"}; !slices.Equal(got, want) {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+	if got := getStringColumn(t, out, "road").Value(0); got != "short" {
+		t.Fatalf("nearest road = %q, want short (diagonal is only bbox-near)", got)
+	}
+	if got := nearestDistances(t, out); !slices.Equal(got, []float64{50}) {
+		t.Fatalf("distance = %v, want [50]", got)
+	}
+}
+
+func TestSJoinNearest_KOrderingAndUnit(t *testing.T) {
+	pings := pointsFrame(t, []string{"p"}, []geometry.Point{{X: 0, Y: 0}})
+	out, err := pings.SJoinNearest(nearestRoads(t), "geometry", "geometry", 2, 0, geometry.UnitKilometers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roads := getStringColumn(t, out, "road")
+	if out.NumRows() != 2 || roads.Value(0) != "short" || roads.Value(1) != "diagonal" {
+		t.Fatalf("rows = %d, want [short diagonal]", out.NumRows())
+	}
+	d := nearestDistances(t, out)
+	if d[0] != 0.05 || math.Abs(d[1]-1005/math.Sqrt2/1000) > 1e-9 {
+		t.Fatalf("distance = %v, want [0.05 ~0.7107] km", d)
+	}
+}
+
+func TestSJoinNearest_MaxDistanceInnerAndLeft(t *testing.T) {
+	pings := pointsFrame(t, []string{"near", "lost"}, []geometry.Point{{X: 0, Y: 0}, {X: -9000, Y: 0}})
+	roads := nearestRoads(t)
+
+	inner, err := pings.SJoinNearest(roads, "geometry", "geometry", 3, 100, geometry.UnitMeters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.NumRows() != 1 || getStringColumn(t, inner, "name").Value(0) != "near" {
+		t.Fatalf("inner rows = %d, want only the near ping's one match", inner.NumRows())
+	}
+
+	left, err := pings.SJoinNearest(roads, "geometry", "geometry", 3, 100, geometry.UnitMeters,
+		WithJoinType(JoinLeft), DistanceColumn("distance"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left.NumRows() != 2 {
+		t.Fatalf("left rows = %d, want 2", left.NumRows())
+	}
+	if !getStringColumn(t, left, "road").IsNull(1) {
+		t.Fatal("unmatched ping should have a null road")
+	}
+	if got := nearestDistances(t, left); !slices.Equal(got, []float64{50, -1}) {
+		t.Fatalf("distance = %v, want [50 null]", got)
+	}
+}
+
+func TestSJoinNearest_WorkersMatchSerial(t *testing.T) {
+	n := SJoinMinParallelRows + 37
+	names := make([]string, n)
+	pts := make([]geometry.Point, n)
+	for i := range n {
+		names[i] = "p"
+		pts[i] = geometry.Point{X: float64(i%97) * 40, Y: float64(i%13) * 30}
+	}
+	pings := pointsFrame(t, names, pts)
+	roads := nearestRoads(t)
+
+	serial, err := pings.SJoinNearest(roads, "geometry", "geometry", 2, 0, geometry.UnitMeters, Workers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := pings.SJoinNearest(roads, "geometry", "geometry", 2, 0, geometry.UnitMeters, Workers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(nearestDistances(t, serial), nearestDistances(t, parallel)) {
+		t.Fatal("Workers(4) distances differ from Workers(1)")
+	}
+	sr, pr := getStringColumn(t, serial, "road"), getStringColumn(t, parallel, "road")
+	for i := range sr.Len() {
+		if sr.Value(i) != pr.Value(i) {
+			t.Fatalf("row %d: road %q vs %q", i, sr.Value(i), pr.Value(i))
+		}
+	}
+}
+
+func TestSJoinNearest_Errors(t *testing.T) {
+	pings := pointsFrame(t, []string{"p"}, []geometry.Point{{X: 0, Y: 0}})
+	roads := nearestRoads(t)
+	if _, err := pings.SJoinNearest(roads, "geometry", "geometry", 0, 0, geometry.UnitMeters); err == nil {
+		t.Fatal("expected error for k = 0")
+	}
+	if _, err := pings.SJoinNearest(roads, "geometry", "geometry", 1, 0, geometry.Unit("furlongs")); !errors.Is(err, geometry.ErrInvalidUnit) {
+		t.Fatalf("err = %v, want ErrInvalidUnit", err)
+	}
+	if _, err := pings.SJoinNearest(roads, "geometry", "geometry", 1, 0, geometry.UnitMeters, WithJoinType(JoinFull)); err == nil {
+		t.Fatal("expected error for JoinFull")
+	}
+	if _, err := pings.SJoinNearest(roads, "geometry", "geometry", 1, 0, geometry.UnitMeters, DistanceColumn("road")); err == nil {
+		t.Fatal("expected error for distance column colliding with road")
+	}
+	if _, err := pings.SJoinNearest(roads, "name", "geometry", 1, 0, geometry.UnitMeters); !errors.Is(err, ErrNotGeometry) {
+		t.Fatalf("err = %v, want ErrNotGeometry", err)
+	}
+}
@@ -127,6 +127,25 @@ func newRowComparator(s Series, descending bool) (rowComparator, error) {
 			return nullAwareCompare(a.IsNull(i), a.IsNull(j),
 				cmpOrd(int64(a.Value(i)), int64(a.Value(j))), descending)
 		}, nil
+	case *array.Dictionary:
+		// Categorical: rank the dictionary once, then compare codes
+		// through the rank table — by value, or code order when the
+		// type is Ordered.
+		c, ok := asCatChunk(a)
+		if !ok {
+			break
+		}
+		ranks := c.ranks()
+		rank := func(i int) int {
+			if c.IsNull(i) {
+				return 0
+			}
+			return ranks[c.GetValueIndex(i)]
+		}
+		return func(i, j int) int {
+			return nullAwareCompare(a.IsNull(i), a.IsNull(j),
+				cmpOrd(int64(rank(i)), int64(rank(j))), descending)
+		}, nil
 	}
 	return nil, fmt.Errorf("unsupported sort key type %s", s.DataType())
 }
@@ -0,0 +1,441 @@
+package gobi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"iter"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/util"
+)
+
+// -----------------------------------------------------------------------------
+// Out-of-core execution
+//
+// Collect's blocking operators hold their working set in memory: Sort
+// buffers its whole input, a hash Aggregate its group table, a hash
+// Join its build side. CollectOptions.MemoryLimit gives each of them a
+// budget. Under it they run exactly as before; past it they write
+// intermediate state to Arrow IPC stream files under SpillDir and
+// finish from disk:
+//
+//   - Sort cuts its input into sorted runs and k-way merges them
+//     (exec_sort_external.go).
+//   - Aggregate freezes its group table and hash-partitions rows of
+//     unseen keys to disk, aggregating each partition afterwards
+//     (exec_aggregate_spill.go).
+//   - Join grace-partitions both sides on the join key and joins
+//     partition by partition (exec_join_grace.go).
+//
+// Partitions that are still over budget spill again, up to
+// maxSpillDepth levels; past that they run in memory. The budget is
+// per operator, not per plan — a plan with a sort over a join can
+// hold up to two budgets — and it covers the operator's buffered
+// Arrow data (or, for Aggregate, an estimate of its group table), not
+// Go heap overhead. Spill files are removed as soon as they've been
+// read back, and on Close after an error or early stop.
+// -----------------------------------------------------------------------------
+
+// CollectOptions tunes how CollectWith, StreamWith and SinkWith run a
+// plan. The zero value (and a nil pointer) runs exactly like Collect.
+type CollectOptions struct {
+	// MemoryLimit is the per-operator memory budget in bytes for Sort,
+	// Aggregate and Join. Once an operator's buffered input passes it,
+	// the operator spills to SpillDir. 0 means no limit — never spill.
+	MemoryLimit int64
+	// SpillDir is where spill files go. Empty means os.TempDir().
+	SpillDir string
+}
+
+// spillConfig is CollectOptions as the compiler threads it through
+// the operators it builds.
+type spillConfig struct {
+	limit int64
+	dir   string
+}
+
+func newSpillConfig(opts *CollectOptions) spillConfig {
+	if opts == nil || opts.MemoryLimit <= 0 {
+		return spillConfig{}
+	}
+	return spillConfig{limit: opts.MemoryLimit, dir: opts.SpillDir}
+}
+
+func (c spillConfig) enabled() bool { return c.limit > 0 }
+
+// child is the config for a partition spilled at depth: the same
+// budget, or none once the recursion bottoms out.
+func (c spillConfig) child(depth int) spillConfig {
+	if depth+1 >= maxSpillDepth {
+		return spillConfig{}
+	}
+	return c
+}
+
+const (
+	// spillPartitions is the fan-out of a partitioning spill. 16
+	// partitions shrink an input 16× per level, so two levels handle
+	// a working set 256× the budget while keeping the open spill
+	// files per operator small.
+	spillPartitions = 16
+	// maxSpillDepth caps recursive re-partitioning. A partition that
+	// is over budget after this many levels is dominated by a few hot
+	// keys — partitioning again wouldn't split it — so it runs in
+	// memory instead.
+	maxSpillDepth = 3
+)
+
+// CollectWith is Collect with options — a memory budget past which
+// Sort, Aggregate and Join spill to disk:
+//
+//	f, err := lf.SortBy(gobi.SortKey{Column: "ts"}).
+//		CollectWith(&gobi.CollectOptions{MemoryLimit: 512 << 20})
+//
+// A nil opts is Collect. Output is identical to Collect's with one
+// exception once an operator actually spills: a spilled Join emits
+// rows partition by partition rather than in probe order. Sort and
+// Aggregate output is unchanged — the external sort's merge is
+// stable, and a spilled Aggregate merges its partitions back into
+// key order.
+func (lf *LazyFrame) CollectWith(opts *CollectOptions) (*Frame, error) {
+	plan := Optimize(lf.plan)
+	op, err := CompileWith(plan, opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Execute(context.Background(), op)
+	if err != nil {
+		return nil, err
+	}
+	// Propagate the plan-proved PartitionMetadata claim onto the
+	// returned Frame so downstream `frame.Lazy()` chains inherit the
+	// alignment context. Without this, Over / GroupBy / Join on the
+	// re-lifted LazyFrame fall through to the general (unaligned)
+	// path even though the plan already proved contiguity —
+	// silently pessimizing hot paths across a Collect boundary.
+	// CollectRaw's collectPlan already does the same (see
+	// PartitionMetadata's docstring); this brings Collect into
+	// agreement.
+	//
+	// Callers who want to strip the claim can call
+	// f.WithPartitionMeta(nil) — cheap opt-out for the rare case
+	// where the caller mutates the frame in ways that invalidate
+	// the claim before re-lifting.
+	if f != nil {
+		if meta := plan.PartitionMetadata(); meta != nil {
+			f.WithPartitionMeta(meta)
+		}
+	}
+	return f, nil
+}
+
+// StreamWith is Stream with options; see CollectWith.
+func (lf *LazyFrame) StreamWith(ctx context.Context, opts *CollectOptions) iter.Seq2[*Frame, error] {
+	return func(yield func(*Frame, error) bool) {
+		op, err := CompileWith(Optimize(lf.plan), opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		streamBatches(ctx, op, yield)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Spill files
+// -----------------------------------------------------------------------------
+
+// spillFile is one temporary Arrow IPC stream on disk. The stream
+// format (not the file format) because it allows a dictionary to
+// change between batches, which categorical batches routinely do.
+type spillFile struct {
+	path   string
+	schema *arrow.Schema
+	f      *os.File
+	w      *ipc.Writer
+	rows   int64
+}
+
+func createSpillFile(dir string, schema *arrow.Schema) (*spillFile, error) {
+	f, err := os.CreateTemp(dir, "gobi-spill-*.arrows")
+	if err != nil {
+		return nil, fmt.Errorf("gobi: spill: %w", err)
+	}
+	return &spillFile{
+		path:   f.Name(),
+		schema: schema,
+		f:      f,
+		w:      ipc.NewWriter(f, ipc.WithSchema(schema)),
+	}, nil
+}
+
+func (s *spillFile) write(rec arrow.RecordBatch) error {
+	if rec.NumRows() == 0 {
+		return nil
+	}
+	if err := s.w.Write(rec); err != nil {
+		return fmt.Errorf("gobi: spill: %w", err)
+	}
+	s.rows += rec.NumRows()
+	return nil
+}
+
+// writeFrame writes f in batches of at most defaultBatchRows rows.
+func (s *spillFile) writeFrame(f *Frame) error {
+	n := f.NumRows()
+	for start := 0; start < n; start += defaultBatchRows {
+		slice := f.slice(int64(start), int64(min(start+defaultBatchRows, n)))
+		rec := frameToBatch(slice)
+		slice.Release()
+		err := s.write(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// open finishes writing and returns an operator that streams the
+// file back. The file is deleted when that operator closes; the
+// spillFile itself must not be used afterwards.
+func (s *spillFile) open() (ExecOperator, error) {
+	err := errors.Join(s.w.Close(), s.f.Close())
+	s.w, s.f = nil, nil
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("gobi: spill: %w", err), os.Remove(s.path))
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("gobi: spill: %w", err), os.Remove(s.path))
+	}
+	r, err := ipc.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, errors.Join(fmt.Errorf("gobi: spill: %w", err), os.Remove(s.path))
+	}
+	return &spillReaderExec{schema: s.schema, path: s.path, f: f, r: r}, nil
+}
+
+// remove discards a file that will never be read back.
+func (s *spillFile) remove() {
+	if s.w != nil {
+		_ = s.w.Close()
+		_ = s.f.Close()
+		s.w, s.f = nil, nil
+	}
+	_ = os.Remove(s.path)
+}
+
+// removeSpillFiles removes every non-nil file in files.
+func removeSpillFiles(files []*spillFile) {
+	for _, s := range files {
+		if s != nil {
+			s.remove()
+		}
+	}
+}
+
+// openSpillPartition opens files[i] for reading, or an empty operator
+// with schema when that partition received no rows.
+func openSpillPartition(files []*spillFile, i int, schema *arrow.Schema) (ExecOperator, error) {
+	s := files[i]
+	files[i] = nil
+	if s == nil {
+		return &emptyExecOp{schema: schema}, nil
+	}
+	return s.open()
+}
+
+// spillReaderExec streams a spill file back as an ExecOperator.
+type spillReaderExec struct {
+	schema *arrow.Schema
+	path   string
+	f      *os.File
+	r      *ipc.Reader
+	closed bool
+}
+
+func (e *spillReaderExec) Schema() *arrow.Schema { return e.schema }
+
+func (e *spillReaderExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !e.r.Next() {
+		if err := e.r.Err(); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("gobi: spill: %w", err)
+		}
+		return nil, io.EOF
+	}
+	// The reader reuses its record on the next call; the batch we
+	// hand out needs its own reference.
+	rec := e.r.RecordBatch()
+	rec.Retain()
+	return rec, nil
+}
+
+func (e *spillReaderExec) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	e.r.Release()
+	return errors.Join(e.f.Close(), os.Remove(e.path))
+}
+
+// -----------------------------------------------------------------------------
+// Partitioning and accounting
+// -----------------------------------------------------------------------------
+
+// spillPartitioner routes key bytes to one of spillPartitions
+// partitions. Each operator draws its own seed, so a partition that
+// spills again re-splits on a different hash instead of landing
+// entirely in one child partition.
+type spillPartitioner struct {
+	seed maphash.Seed
+}
+
+func newSpillPartitioner() spillPartitioner {
+	return spillPartitioner{seed: maphash.MakeSeed()}
+}
+
+func (p spillPartitioner) partition(key []byte) int {
+	return int(maphash.Bytes(p.seed, key) % spillPartitions)
+}
+
+// writePartitioned splits f's rows by partition — part[row] — and
+// appends each partition's rows to its file in files, creating files
+// on first use.
+func writePartitioned(f *Frame, part []int, files []*spillFile, dir string) error {
+	rows := make([][]int, len(files))
+	for row, p := range part {
+		rows[p] = append(rows[p], row)
+	}
+	for p, idx := range rows {
+		if len(idx) == 0 {
+			continue
+		}
+		if files[p] == nil {
+			s, err := createSpillFile(dir, f.Schema())
+			if err != nil {
+				return err
+			}
+			files[p] = s
+		}
+		sub, err := f.take(idx)
+		if err != nil {
+			return err
+		}
+		rec := frameToBatch(sub)
+		sub.Release()
+		err = files[p].write(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchBytes estimates the memory rec's rows occupy. Unlike
+// util.TotalRecordSize it counts only the rows the batch covers: the
+// executor's batches are mostly slices of larger arrays, and charging
+// each slice for the whole parent buffer would trip the budget after
+// a handful of batches.
+func batchBytes(rec arrow.RecordBatch) int64 {
+	var n int64
+	for _, col := range rec.Columns() {
+		n += arrayBytes(col)
+	}
+	return n
+}
+
+func arrayBytes(arr arrow.Array) int64 {
+	rows := int64(arr.Len())
+	validity := (rows + 7) / 8
+	switch a := arr.(type) {
+	case *array.String:
+		return validity + offsetBytes(a.ValueOffsets())
+	case *array.Binary:
+		return validity + offsetBytes(a.ValueOffsets())
+	case *array.LargeString:
+		return validity + offsetBytes(a.ValueOffsets())
+	case *array.LargeBinary:
+		return validity + offsetBytes(a.ValueOffsets())
+	case *array.Boolean:
+		return validity + (rows+7)/8
+	case *array.Dictionary:
+		return arrayBytes(a.Indices()) + arrayBytes(a.Dictionary())
+	}
+	if fw, ok := arr.DataType().(arrow.FixedWidthDataType); ok {
+		return validity + rows*int64(fw.BitWidth()/8)
+	}
+	return util.TotalArraySize(arr)
+}
+
+// offsetBytes is a variable-width array's offsets plus the value
+// bytes they span.
+func offsetBytes[T int32 | int64](offsets []T) int64 {
+	if len(offsets) == 0 {
+		return 0
+	}
+	width := int64(4)
+	if _, ok := any(offsets[0]).(int64); ok {
+		width = 8
+	}
+	return width*int64(len(offsets)) + int64(offsets[len(offsets)-1]-offsets[0])
+}
+
+// batchSliceExec replays buffered batches as an operator, handing its
+// reference to each batch to the caller.
+type batchSliceExec struct {
+	schema  *arrow.Schema
+	batches []arrow.RecordBatch
+}
+
+func (e *batchSliceExec) Schema() *arrow.Schema { return e.schema }
+
+func (e *batchSliceExec) Next(ctx context.Context) (arrow.RecordBatch, error) {
+	if len(e.batches) == 0 {
+		return nil, io.EOF
+	}
+	b := e.batches[0]
+	e.batches[0] = nil
+	e.batches = e.batches[1:]
+	return b, nil
+}
+
+func (e *batchSliceExec) Close() error {
+	releaseBatches(e.batches)
+	e.batches = nil
+	return nil
+}
+
+func releaseBatches(batches []arrow.RecordBatch) {
+	for _, b := range batches {
+		if b != nil {
+			b.Release()
+		}
+	}
+}
+
+// formatBytes renders a byte count for ExplainPhysical.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
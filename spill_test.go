@@ -0,0 +1,312 @@
+package gobi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// spillRows is enough rows for three executor batches, so a tiny
+// budget forces every spilling operator through its disk path.
+const spillRows = 150_000
+
+type spillRow struct {
+	ID    int64   `gobi:"id"`
+	Key   *int64  `gobi:"key"`
+	Rank  int64   `gobi:"rank"`
+	Name  string  `gobi:"name"`
+	Value float64 `gobi:"value"`
+}
+
+// spillFrame builds n rows. key (every 97th null) and name grow with
+// the row number, so later batches keep bringing new groups; rank is
+// a scrambled sort key with ties.
+func spillFrame(t *testing.T, n int) *Frame {
+	t.Helper()
+	rows := make([]spillRow, n)
+	for i := range rows {
+		k := int64(i / 10)
+		rows[i] = spillRow{
+			ID:    int64(i),
+			Rank:  int64((i * 7919) % 20011),
+			Name:  fmt.Sprintf("n%05d", i/30),
+			Value: float64(i%1000) / 8,
+		}
+		if i%97 != 0 {
+			rows[i].Key = &k
+		}
+	}
+	f, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// spillBudget is a limit-byte budget in a temp dir that is checked
+// empty once the test ends.
+func spillBudget(t *testing.T, limit int64) *CollectOptions {
+	t.Helper()
+	dir := t.TempDir()
+	t.Cleanup(func() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("%d spill files left behind", len(entries))
+		}
+	})
+	return &CollectOptions{MemoryLimit: limit, SpillDir: dir}
+}
+
+// collectSpilled compiles lf under opts, runs it, and returns the
+// result along with the root operator for inspection.
+func collectSpilled(t *testing.T, lf *LazyFrame, opts *CollectOptions) (*Frame, ExecOperator) {
+	t.Helper()
+	op, err := CompileWith(Optimize(lf.plan), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Execute(context.Background(), op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, op
+}
+
+func assertSameFrame(t *testing.T, got, want *Frame) {
+	t.Helper()
+	if got.NumRows() != want.NumRows() {
+		t.Fatalf("rows = %d, want %d", got.NumRows(), want.NumRows())
+	}
+	for _, name := range want.ColumnNames() {
+		g, w := valuesOrNull(t, got, name), valuesOrNull(t, want, name)
+		for i := range w {
+			if g[i] != w[i] {
+				t.Fatalf("%s[%d] = %s, want %s", name, i, g[i], w[i])
+			}
+		}
+	}
+}
+
+func TestCollectWith_ExternalSort(t *testing.T) {
+	f := spillFrame(t, spillRows)
+	keys := []SortKey{{Column: "rank", Descending: true}, {Column: "key"}}
+	want, err := f.Lazy().SortBy(keys...).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, op := collectSpilled(t, f.Lazy().SortBy(keys...), spillBudget(t, 1))
+	if e, ok := op.(*externalSortExec); !ok || e.merge == nil {
+		t.Fatalf("root = %T, want a merging externalSortExec", op)
+	}
+	// The merge is stable, so even the ties on (rank, key) come out
+	// in input order: id matches row for row.
+	assertSameFrame(t, got, want)
+}
+
+func TestCollectWith_ExternalSortCategorical(t *testing.T) {
+	lf := spillFrame(t, spillRows).Lazy().WithColumn("cat", Col("name").Cast(Categorical))
+	want, err := lf.SortBy(SortKey{Column: "cat"}).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := collectSpilled(t, lf.SortBy(SortKey{Column: "cat"}), spillBudget(t, 1))
+	assertSameFrame(t, got, want)
+}
+
+func TestCollectWith_UnderBudgetMatchesCollect(t *testing.T) {
+	f := spillFrame(t, spillRows)
+	lf := f.Lazy().GroupBy("name").Agg(Aggregation{Column: "value", Kind: AggSum, Alias: "sum"})
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, op := collectSpilled(t, lf, &CollectOptions{MemoryLimit: 1 << 30, SpillDir: t.TempDir()})
+	if e, ok := op.(*spillingAggregateExec); !ok || e.spilling {
+		t.Fatalf("root = %T, want a spillingAggregateExec that never spilled", op)
+	}
+	// Nothing spilled, so even the group order is Collect's.
+	assertSameFrame(t, got, want)
+}
+
+func TestCollectWith_SpillingAggregate(t *testing.T) {
+	f := spillFrame(t, spillRows)
+	aggs := []Aggregation{
+		{Kind: AggCount, Alias: "n"},
+		{Column: "value", Kind: AggSum, Alias: "sum"},
+		{Column: "value", Kind: AggMean, Alias: "mean"},
+		{Column: "value", Kind: AggMax, Alias: "max"},
+		{Column: "id", Kind: AggFirst, Alias: "first"},
+	}
+	cases := []struct {
+		name string
+		keys []string
+	}{
+		{"int", []string{"key"}},
+		{"string", []string{"name"}},
+		{"composite", []string{"key", "name"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lf := f.Lazy().GroupBy(tc.keys...).Agg(aggs...)
+			want, err := lf.Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, op := collectSpilled(t, lf, spillBudget(t, 1))
+			if e, ok := op.(*spillingAggregateExec); !ok || !e.spilling {
+				t.Fatalf("root = %T, want a spillingAggregateExec that spilled", op)
+			}
+			// The runs are merged back into the unbudgeted group
+			// order, so the frames match row for row.
+			assertSameFrame(t, got, want)
+		})
+	}
+}
+
+func TestCollectWith_SpillingAggregateChargesState(t *testing.T) {
+	// Ten long string keys, each seeing thousands of distinct values:
+	// a handful of groups, but each holds a dense HLL, a t-digest and
+	// an exact set. Only the accumulators' own sizes push the table
+	// over 256 KiB; a flat per-group estimate would keep it in memory.
+	type stateRow struct {
+		Label string  `gobi:"label"`
+		ID    int64   `gobi:"id"`
+		Value float64 `gobi:"value"`
+	}
+	rows := make([]stateRow, 60_000)
+	for i := range rows {
+		rows[i] = stateRow{
+			Label: fmt.Sprintf("%d%s", i%10, strings.Repeat("k", 200)),
+			ID:    int64(i),
+			Value: float64(i%1000) / 8,
+		}
+	}
+	f, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := f.Lazy().GroupBy("label").Agg(
+		Aggregation{Column: "id", Fn: AggApproxNUnique(), Alias: "approx"},
+		Aggregation{Column: "value", Fn: AggApproxQuantile(0.9), Alias: "p90"},
+		Aggregation{Column: "id", Fn: AggCountDistinct(), Alias: "distinct"},
+	)
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, op := collectSpilled(t, lf, spillBudget(t, 256<<10))
+	if e, ok := op.(*spillingAggregateExec); !ok || !e.spilling {
+		t.Fatalf("root = %T, want a spillingAggregateExec that spilled", op)
+	}
+	assertSameFrame(t, got, want)
+}
+
+func TestCollectWith_GraceJoin(t *testing.T) {
+	left := spillFrame(t, 30_000)
+	type rightRow struct {
+		RID   int64  `gobi:"rid"`
+		Key   *int64 `gobi:"rkey"`
+		Label string `gobi:"label"`
+	}
+	// Right keys 0..3999 (left has 0..2999) with a null every 101st
+	// row, so every kind has matched and unmatched rows on both sides.
+	rows := make([]rightRow, 20_000)
+	for i := range rows {
+		k := int64(i % 4000)
+		rows[i] = rightRow{RID: int64(i), Label: fmt.Sprintf("r%d", i)}
+		if i%101 != 0 {
+			rows[i].Key = &k
+		}
+	}
+	right, err := FromStructs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []struct {
+		name string
+		kind JoinType
+	}{
+		{"inner", JoinInner}, {"left", JoinLeft}, {"right", JoinRight},
+		{"full", JoinFull}, {"semi", JoinSemi}, {"anti", JoinAnti},
+	}
+	for _, tc := range kinds {
+		t.Run(tc.name, func(t *testing.T) {
+			lf := left.Lazy().Join(right.Lazy(), "key", "rkey", tc.kind)
+			want, err := lf.Collect()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 64 KiB: the build side spills, its partitions fit.
+			got, op := collectSpilled(t, lf, spillBudget(t, 64<<10))
+			if e, ok := op.(*graceJoinExec); !ok || !e.partitioned {
+				t.Fatalf("root = %T, want a partitioned graceJoinExec", op)
+			}
+			sortKeys := []SortKey{{Column: "id"}}
+			if tc.kind != JoinSemi && tc.kind != JoinAnti {
+				sortKeys = append(sortKeys, SortKey{Column: "rid"})
+			}
+			if got, err = got.SortBy(sortKeys...); err != nil {
+				t.Fatal(err)
+			}
+			if want, err = want.SortBy(sortKeys...); err != nil {
+				t.Fatal(err)
+			}
+			assertSameFrame(t, got, want)
+		})
+	}
+}
+
+func TestCollectWith_GraceJoinRecursive(t *testing.T) {
+	// A budget of one byte re-partitions down to maxSpillDepth and
+	// finishes the last level in memory.
+	left := spillFrame(t, 5_000)
+	right := spillFrame(t, 2_000).Lazy().Select(Col("key").Alias("k2"), Col("id").Alias("rid"))
+	lf := left.Lazy().Join(right, "key", "k2", JoinInner)
+	want, err := lf.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := collectSpilled(t, lf, spillBudget(t, 1))
+	keys := []SortKey{{Column: "id"}, {Column: "rid"}}
+	if got, err = got.SortBy(keys...); err != nil {
+		t.Fatal(err)
+	}
+	if want, err = want.SortBy(keys...); err != nil {
+		t.Fatal(err)
+	}
+	assertSameFrame(t, got, want)
+}
+
+func TestStreamWith_EarlyStopRemovesSpillFiles(t *testing.T) {
+	lf := spillFrame(t, spillRows).Lazy().SortBy(SortKey{Column: "name"})
+	for batch, err := range lf.StreamWith(context.Background(), spillBudget(t, 1)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		batch.Release()
+		break
+	}
+}
+
+func TestExplainPhysicalWith_Spill(t *testing.T) {
+	f := spillFrame(t, spillRows)
+	lf := f.Lazy().
+		Join(f.Lazy().Select(Col("key").Alias("k2")), "key", "k2", JoinLeft).
+		GroupBy("name").Agg(Aggregation{Column: "value", Kind: AggSum, Alias: "sum"}).
+		SortBy(SortKey{Column: "sum"})
+	plain := lf.ExplainPhysical()
+	if strings.Contains(plain, "Spilling") {
+		t.Errorf("ExplainPhysical without a budget mentions spilling:\n%s", plain)
+	}
+	got := lf.ExplainPhysicalWith(&CollectOptions{MemoryLimit: 64 << 20})
+	for _, want := range []string{"SpillingSort", "SpillingAggregate", "SpillingJoin", "[spill>64.0MiB]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ExplainPhysicalWith missing %q:\n%s", want, got)
+		}
+	}
+}
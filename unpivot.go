@@ -0,0 +1,171 @@
+package gobi
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Unpivot reshapes a wide-form Frame into a long-form one — the
+// inverse of Pivot, pandas' melt / polars' unpivot. Every input row
+// becomes one output row per value column:
+//
+//	<idCols...> | <varName> | <valueName>
+//
+// varName holds the name of the value column the row came from (as a
+// String) and valueName its value. Output rows are value-column-major:
+// all n input rows for valueCols[0], then all n for valueCols[1], and
+// so on, each block in input order.
+//
+//	// site | day | temp_s1 | temp_s2   →   site | day | sensor | temp
+//	long, err := wide.Unpivot([]string{"site", "day"},
+//	    []string{"temp_s1", "temp_s2"}, "sensor", "temp")
+//
+// An empty valueCols means every column not in idCols; empty varName /
+// valueName default to "variable" / "value". Nulls are kept.
+//
+// The value columns must unify to one type: identical types pass
+// through (with their field metadata, when every value column agrees
+// on it, so geometry columns stay geometry columns); a mix of integer
+// columns widens to Int64 and any other numeric mix to Float64. Other
+// mixes — String with Int64, say — return ErrColumnTypeMismatch; Cast
+// them to a common type first.
+//
+// The id columns are repeated by reference — the output's id column is
+// the input's chunks listed once per value column — so widening a
+// frame with many id columns costs nothing per id column.
+func (f *Frame) Unpivot(idCols, valueCols []string, varName, valueName string) (*Frame, error) {
+	if f == nil {
+		return nil, fmt.Errorf("gobi: Frame.Unpivot on nil frame")
+	}
+	if varName == "" {
+		varName = "variable"
+	}
+	if valueName == "" {
+		valueName = "value"
+	}
+	isID := make(map[string]bool, len(idCols))
+	for _, name := range idCols {
+		if _, err := f.Column(name); err != nil {
+			return nil, err
+		}
+		if isID[name] {
+			return nil, fmt.Errorf("gobi: Unpivot: id column %q repeats", name)
+		}
+		isID[name] = true
+	}
+	if len(valueCols) == 0 {
+		for _, name := range f.ColumnNames() {
+			if !isID[name] {
+				valueCols = append(valueCols, name)
+			}
+		}
+	}
+	if len(valueCols) == 0 {
+		return nil, fmt.Errorf("gobi: Unpivot: no value columns")
+	}
+	if varName == valueName || isID[varName] || isID[valueName] {
+		return nil, fmt.Errorf("gobi: Unpivot: output names %q and %q must differ from each other and from the id columns", varName, valueName)
+	}
+
+	vals := make([]Series, len(valueCols))
+	for i, name := range valueCols {
+		if isID[name] || slices.Index(valueCols, name) != i {
+			return nil, fmt.Errorf("gobi: Unpivot: value column %q repeats or is also an id column", name)
+		}
+		s, err := f.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = s
+	}
+	valueField, err := unpivotValueField(vals, valueName)
+	if err != nil {
+		return nil, err
+	}
+
+	n := f.NumRows()
+	fields := make([]arrow.Field, 0, len(idCols)+2)
+	cols := make([]arrow.Column, 0, len(idCols)+2)
+	addColumn := func(fld arrow.Field, chunks []arrow.Array) {
+		chunked := arrow.NewChunked(fld.Type, chunks)
+		fields = append(fields, fld)
+		cols = append(cols, *arrow.NewColumn(fld, chunked))
+		chunked.Release()
+	}
+
+	for _, name := range idCols {
+		s, _ := f.Column(name)
+		src := s.col.Data().Chunks()
+		chunks := make([]arrow.Array, 0, len(src)*len(vals))
+		for range vals {
+			chunks = append(chunks, src...)
+		}
+		addColumn(s.field, chunks)
+	}
+
+	vb := array.NewStringBuilder(memory.DefaultAllocator)
+	defer vb.Release()
+	vb.Reserve(n * len(vals))
+	for _, name := range valueCols {
+		for range n {
+			vb.Append(name)
+		}
+	}
+	varArr := vb.NewArray()
+	defer varArr.Release()
+	addColumn(arrow.Field{Name: varName, Type: arrow.BinaryTypes.String}, []arrow.Array{varArr})
+
+	var valueChunks []arrow.Array
+	for _, s := range vals {
+		cast, err := castSeries(s, valueField.Type)
+		if err != nil {
+			return nil, fmt.Errorf("gobi: Unpivot: column %q: %w", s.Name(), err)
+		}
+		valueChunks = append(valueChunks, cast.col.Data().Chunks()...)
+	}
+	addColumn(valueField, valueChunks)
+
+	return NewFrame(arrow.NewSchema(fields, nil), cols)
+}
+
+// unpivotValueField picks the value column's output field: the shared
+// type when every value column has it, else the numeric supertype.
+func unpivotValueField(vals []Series, name string) (arrow.Field, error) {
+	first := vals[0].field
+	same, sameMeta := true, true
+	allInt, allNumeric := true, true
+	for _, s := range vals {
+		dt := s.DataType()
+		same = same && arrow.TypeEqual(dt, first.Type)
+		sameMeta = sameMeta && s.field.Metadata.Equal(first.Metadata)
+		switch dt.ID() {
+		case arrow.INT64, arrow.INT32, arrow.UINT32:
+		case arrow.FLOAT64, arrow.FLOAT32, arrow.UINT64:
+			allInt = false
+		default:
+			allInt, allNumeric = false, false
+		}
+	}
+	switch {
+	case same:
+		fld := arrow.Field{Name: name, Type: first.Type, Nullable: true}
+		if sameMeta {
+			fld.Metadata = first.Metadata
+		}
+		return fld, nil
+	case allInt:
+		return arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Int64, Nullable: true}, nil
+	case allNumeric:
+		return arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true}, nil
+	}
+	types := make([]string, len(vals))
+	for i, s := range vals {
+		types[i] = s.Name() + ": " + s.DataType().String()
+	}
+	return arrow.Field{}, fmt.Errorf("%w: Unpivot: value columns have no common type (%v); Cast them first",
+		ErrColumnTypeMismatch, types)
+}
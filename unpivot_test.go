@@ -0,0 +1,139 @@
+package gobi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/zoobst/gobi/geometry"
+)
+
+func TestFrame_Unpivot_LongForm(t *testing.T) {
+	type row struct {
+		Site string   `gobi:"site"`
+		S1   *float64 `gobi:"temp_s1"`
+		S2   int64    `gobi:"temp_s2"`
+		S3   int32    `gobi:"temp_s3"`
+	}
+	v := func(x float64) *float64 { return &x }
+	wide, err := FromStructs([]row{{"north", v(10.5), 12, 7}, {"south", nil, 20, 8}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, err := wide.Unpivot([]string{"site"}, []string{"temp_s1", "temp_s2"}, "sensor", "temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := long.ColumnNames(); !slices.Equal(names, []string{"site", "sensor", "temp"}) {
+		t.Fatalf("columns = %v", names)
+	}
+	// Float64 ∪ Int64 widens to Float64; rows are value-column-major.
+	if got := mustColumn(t, long, "temp").DataType(); got.ID() != arrow.FLOAT64 {
+		t.Fatalf("value type = %s, want float64", got)
+	}
+	want := map[string][]string{
+		"site":   {"north", "south", "north", "south"},
+		"sensor": {"temp_s1", "temp_s1", "temp_s2", "temp_s2"},
+		"temp":   {"10.5", "null", "12", "20"},
+	}
+	for name, w := range want {
+		if got := valuesOrNull(t, long, name); !slices.Equal(got, w) {
+			t.Errorf("%s = %v, want %v", name, got, w)
+		}
+	}
+
+	// Integer-only mixes widen to Int64; empty valueCols means every
+	// non-id column and the names default.
+	ints, err := wide.Unpivot([]string{"site"}, []string{"temp_s2", "temp_s3"}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mustColumn(t, ints, "value").DataType(); got.ID() != arrow.INT64 {
+		t.Fatalf("Int64 ∪ Int32 = %s, want int64", got)
+	}
+	if got := valuesOrNull(t, ints, "value"); !slices.Equal(got, []string{"12", "20", "7", "8"}) {
+		t.Fatalf("value = %v", got)
+	}
+	all, err := wide.Unpivot([]string{"site"}, nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strValues(t, all, "variable"); !slices.Equal(got, []string{"temp_s1", "temp_s1", "temp_s2", "temp_s2", "temp_s3", "temp_s3"}) {
+		t.Fatalf("variable = %v", got)
+	}
+}
+
+// TestFrame_Unpivot_RoundTripsPivot melts the pivoted sensor frame and
+// pivots it back, dropping the null cells Pivot introduced.
+func TestFrame_Unpivot_RoundTripsPivot(t *testing.T) {
+	long := sensorFrame(t)
+	wide, err := long.PivotWith([]string{"site", "day"}, "sensor", []string{"temp"}, &PivotOptions{Agg: AggMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+	melted, err := wide.Unpivot([]string{"site", "day"}, nil, "sensor", "temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := melted.PivotWith([]string{"site", "day"}, "sensor", []string{"temp"}, &PivotOptions{Agg: AggMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range wide.ColumnNames() {
+		if got, want := valuesOrNull(t, again, name), valuesOrNull(t, wide, name); !slices.Equal(got, want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	// The id columns share the input's chunks, one copy per value column.
+	if got := len(mustColumn(t, melted, "site").col.Data().Chunks()); got != 2 {
+		t.Fatalf("site chunks = %d, want 2", got)
+	}
+}
+
+func TestFrame_Unpivot_TypesAndErrors(t *testing.T) {
+	type row struct {
+		ID   int64  `gobi:"id"`
+		Name string `gobi:"name"`
+		N    int64  `gobi:"n"`
+	}
+	df, err := FromStructs([]row{{1, "a", 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := df.Unpivot([]string{"id"}, []string{"name", "n"}, "", ""); !errors.Is(err, ErrColumnTypeMismatch) {
+		t.Fatalf("String ∪ Int64: err = %v", err)
+	}
+	for _, tc := range []struct {
+		ids, vals     []string
+		varName, name string
+	}{
+		{[]string{"id"}, []string{"id"}, "", ""},
+		{[]string{"id"}, []string{"n", "n"}, "", ""},
+		{[]string{"id"}, []string{"n"}, "id", ""},
+		{[]string{"id"}, []string{"n"}, "x", "x"},
+		{[]string{"nope"}, []string{"n"}, "", ""},
+		{[]string{"id", "name", "n"}, nil, "", ""},
+	} {
+		if _, err := df.Unpivot(tc.ids, tc.vals, tc.varName, tc.name); err == nil {
+			t.Errorf("Unpivot(%v, %v, %q, %q): expected error", tc.ids, tc.vals, tc.varName, tc.name)
+		}
+	}
+
+	// Geometry value columns keep their field metadata.
+	g := geomSeries(t, "a", 3857, []geometry.Geometry{geometry.Point{X: 1, Y: 2}})
+	geo, err := df.WithColumn("a", g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if geo, err = geo.WithColumn("b", g); err != nil {
+		t.Fatal(err)
+	}
+	out, err := geo.Unpivot([]string{"id"}, []string{"a", "b"}, "which", "geometry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := mustColumn(t, out, "geometry"); !s.IsGeometry() || geometryCRSFromField(s.field) != 3857 || s.Len() != 2 {
+		t.Fatalf("geometry value field = %v", s.field)
+	}
+}